@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE public.urls ADD COLUMN expires_at TIMESTAMP;
+ALTER TABLE public.urls ADD COLUMN expired BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX urls_expires_at_idx ON public.urls(expires_at) WHERE expires_at IS NOT NULL AND expired = FALSE;
+
+-- +goose Down
+DROP INDEX IF EXISTS urls_expires_at_idx;
+ALTER TABLE public.urls DROP COLUMN expired;
+ALTER TABLE public.urls DROP COLUMN expires_at;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE clicks (
+  uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  url_uuid UUID NOT NULL REFERENCES urls(uuid) ON DELETE CASCADE,
+  referrer VARCHAR(2048) NOT NULL DEFAULT '',
+  user_agent VARCHAR(512) NOT NULL DEFAULT '',
+  ip_hash VARCHAR(64) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX clicks_url_uuid_created_at_idx ON public.clicks(url_uuid, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS clicks_url_uuid_created_at_idx;
+DROP TABLE clicks;
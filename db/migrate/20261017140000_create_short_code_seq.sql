@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE SEQUENCE IF NOT EXISTS short_code_seq START WITH 1 INCREMENT BY 1;
+
+-- +goose Down
+DROP SEQUENCE IF EXISTS short_code_seq;
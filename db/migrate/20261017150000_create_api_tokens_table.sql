@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE api_tokens (
+  uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_uuid UUID NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  prefix VARCHAR(16) NOT NULL,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  scopes TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  revoked_at TIMESTAMP
+);
+CREATE INDEX api_tokens_user_uuid_idx ON public.api_tokens(user_uuid) WHERE revoked_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS api_tokens_user_uuid_idx;
+DROP TABLE api_tokens;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE delete_tasks (
+  uuid UUID PRIMARY KEY,
+  user_uuid UUID NOT NULL,
+  short_codes TEXT[] NOT NULL DEFAULT '{}',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  failed_at TIMESTAMP
+);
+CREATE INDEX delete_tasks_run_at_idx ON public.delete_tasks(run_at) WHERE failed_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS delete_tasks_run_at_idx;
+DROP TABLE delete_tasks;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE public.delete_tasks ADD COLUMN trace_parent TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE public.delete_tasks DROP COLUMN trace_parent;
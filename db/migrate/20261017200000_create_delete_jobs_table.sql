@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE delete_jobs (
+  uuid UUID PRIMARY KEY,
+  user_uuid UUID NOT NULL,
+  status TEXT NOT NULL,
+  short_codes TEXT[] NOT NULL DEFAULT '{}',
+  deleted TEXT[] NOT NULL DEFAULT '{}',
+  skipped JSONB NOT NULL DEFAULT '[]',
+  error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  finished_at TIMESTAMP
+);
+CREATE INDEX delete_jobs_finished_at_idx ON public.delete_jobs(finished_at) WHERE finished_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS delete_jobs_finished_at_idx;
+DROP TABLE delete_jobs;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- NOTE: SQLite has no UUID extension, UUIDs are generated by the application
+SELECT 1;
+
+-- +goose Down
+SELECT 1;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE urls (
+  uuid TEXT PRIMARY KEY NOT NULL,
+  long_url TEXT NOT NULL,
+  short_code TEXT UNIQUE NOT NULL,
+  created_at TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+  updated_at TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+-- +goose Down
+DROP TABLE urls;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- NOTE: SQLite does not enforce VARCHAR lengths, long_url stays TEXT
+SELECT 1;
+
+-- +goose Down
+SELECT 1;
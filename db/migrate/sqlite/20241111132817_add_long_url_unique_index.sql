@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE UNIQUE INDEX urls_long_url_key ON urls(long_url);
+
+-- +goose Down
+DROP INDEX IF EXISTS urls_long_url_key;
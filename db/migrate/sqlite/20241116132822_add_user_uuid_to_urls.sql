@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE urls ADD COLUMN user_uuid TEXT;
+CREATE INDEX urls_user_uuid_idx ON urls(user_uuid);
+
+-- +goose Down
+DROP INDEX IF EXISTS urls_user_uuid_idx;
+ALTER TABLE urls DROP COLUMN user_uuid;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE urls ADD COLUMN deleted_at TEXT;
+CREATE INDEX urls_user_uuid_deleted_at_idx ON urls(user_uuid, deleted_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS urls_user_uuid_deleted_at_idx;
+ALTER TABLE urls DROP COLUMN deleted_at;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE urls ADD COLUMN expires_at TEXT;
+ALTER TABLE urls ADD COLUMN expired BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX urls_expires_at_idx ON urls(expires_at) WHERE expires_at IS NOT NULL AND expired = FALSE;
+
+-- +goose Down
+DROP INDEX IF EXISTS urls_expires_at_idx;
+ALTER TABLE urls DROP COLUMN expired;
+ALTER TABLE urls DROP COLUMN expires_at;
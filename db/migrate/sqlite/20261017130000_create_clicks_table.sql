@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE clicks (
+  uuid TEXT PRIMARY KEY NOT NULL,
+  url_uuid TEXT NOT NULL REFERENCES urls(uuid) ON DELETE CASCADE,
+  referrer TEXT NOT NULL DEFAULT '',
+  user_agent TEXT NOT NULL DEFAULT '',
+  ip_hash TEXT NOT NULL,
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+CREATE INDEX clicks_url_uuid_created_at_idx ON clicks(url_uuid, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS clicks_url_uuid_created_at_idx;
+DROP TABLE clicks;
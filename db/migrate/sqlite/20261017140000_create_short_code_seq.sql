@@ -0,0 +1,10 @@
+-- +goose Up
+-- NOTE: SQLite has no sequences, a single row counter is incremented with UPDATE ... RETURNING
+CREATE TABLE IF NOT EXISTS short_code_seq (
+  id INTEGER PRIMARY KEY CHECK (id = 1),
+  value INTEGER NOT NULL
+);
+INSERT INTO short_code_seq (id, value) VALUES (1, 0);
+
+-- +goose Down
+DROP TABLE IF EXISTS short_code_seq;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE api_tokens (
+  uuid TEXT PRIMARY KEY NOT NULL,
+  user_uuid TEXT NOT NULL,
+  name TEXT NOT NULL,
+  prefix TEXT NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  scopes TEXT NOT NULL DEFAULT '',
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+  revoked_at TEXT
+);
+CREATE INDEX api_tokens_user_uuid_idx ON api_tokens(user_uuid) WHERE revoked_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS api_tokens_user_uuid_idx;
+DROP TABLE api_tokens;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX urls_user_uuid_created_at_idx ON urls(user_uuid, created_at DESC, uuid DESC) WHERE deleted_at IS NULL AND expired = FALSE;
+
+-- +goose Down
+DROP INDEX IF EXISTS urls_user_uuid_created_at_idx;
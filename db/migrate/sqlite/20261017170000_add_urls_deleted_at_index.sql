@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX urls_deleted_at_idx ON urls(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS urls_deleted_at_idx;
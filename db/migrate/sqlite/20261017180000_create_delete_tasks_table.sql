@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE delete_tasks (
+  uuid TEXT PRIMARY KEY NOT NULL,
+  user_uuid TEXT NOT NULL,
+  short_codes TEXT NOT NULL DEFAULT '',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  run_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+  failed_at TEXT
+);
+CREATE INDEX delete_tasks_run_at_idx ON delete_tasks(run_at) WHERE failed_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS delete_tasks_run_at_idx;
+DROP TABLE delete_tasks;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE delete_jobs (
+  uuid TEXT PRIMARY KEY NOT NULL,
+  user_uuid TEXT NOT NULL,
+  status TEXT NOT NULL,
+  short_codes TEXT NOT NULL DEFAULT '[]',
+  deleted TEXT NOT NULL DEFAULT '[]',
+  skipped TEXT NOT NULL DEFAULT '[]',
+  error TEXT NOT NULL DEFAULT '',
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+  finished_at TEXT
+);
+CREATE INDEX delete_jobs_finished_at_idx ON delete_jobs(finished_at) WHERE finished_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS delete_jobs_finished_at_idx;
+DROP TABLE delete_jobs;
@@ -0,0 +1,9 @@
+// Package sqlite contains the SQLite dialect of the goose migrations in db/migrate
+package sqlite
+
+import "embed"
+
+// Migrations are the SQLite migrations, every file mirrors the PostgreSQL migration with the same version
+//
+//go:embed *.sql
+var Migrations embed.FS
@@ -0,0 +1,26 @@
+package sqlite
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Migrations_MirrorPostgres(t *testing.T) {
+	postgres, err := filepath.Glob("../*.sql")
+	require.NoError(t, err)
+
+	sqlite, err := fs.Glob(Migrations, "*.sql")
+	require.NoError(t, err)
+
+	expected := make([]string, 0, len(postgres))
+	for _, path := range postgres {
+		expected = append(expected, filepath.Base(path))
+	}
+
+	assert.NotEmpty(t, sqlite)
+	assert.Equal(t, expected, sqlite)
+}
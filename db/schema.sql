@@ -34,6 +34,92 @@ SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: api_tokens; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.api_tokens (
+    uuid uuid DEFAULT public.uuid_generate_v4() NOT NULL,
+    user_uuid uuid NOT NULL,
+    name character varying(255) NOT NULL,
+    prefix character varying(16) NOT NULL,
+    token_hash character varying(64) NOT NULL,
+    scopes text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    revoked_at timestamp without time zone
+);
+
+
+ALTER TABLE public.api_tokens OWNER TO postgres;
+
+--
+-- Name: clicks; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.clicks (
+    uuid uuid DEFAULT public.uuid_generate_v4() NOT NULL,
+    url_uuid uuid NOT NULL,
+    referrer character varying(2048) DEFAULT ''::character varying NOT NULL,
+    user_agent character varying(512) DEFAULT ''::character varying NOT NULL,
+    ip_hash character varying(64) NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
+ALTER TABLE public.clicks OWNER TO postgres;
+
+--
+-- Name: delete_jobs; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.delete_jobs (
+    uuid uuid NOT NULL,
+    user_uuid uuid NOT NULL,
+    status text NOT NULL,
+    short_codes text[] DEFAULT '{}'::text[] NOT NULL,
+    deleted text[] DEFAULT '{}'::text[] NOT NULL,
+    skipped jsonb DEFAULT '[]'::jsonb NOT NULL,
+    error text DEFAULT ''::text NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    finished_at timestamp without time zone
+);
+
+
+ALTER TABLE public.delete_jobs OWNER TO postgres;
+
+--
+-- Name: delete_tasks; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.delete_tasks (
+    uuid uuid NOT NULL,
+    user_uuid uuid NOT NULL,
+    short_codes text[] DEFAULT '{}'::text[] NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    last_error text DEFAULT ''::text NOT NULL,
+    run_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    failed_at timestamp without time zone,
+    trace_parent text DEFAULT ''::text NOT NULL
+);
+
+
+ALTER TABLE public.delete_tasks OWNER TO postgres;
+
+--
+-- Name: short_code_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE public.short_code_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER SEQUENCE public.short_code_seq OWNER TO postgres;
+
 --
 -- Name: urls; Type: TABLE; Schema: public; Owner: postgres
 --
@@ -45,12 +131,54 @@ CREATE TABLE public.urls (
     created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
     updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
     user_uuid uuid,
-    deleted_at timestamp without time zone
+    deleted_at timestamp without time zone,
+    expires_at timestamp without time zone,
+    expired boolean DEFAULT false NOT NULL
 );
 
 
 ALTER TABLE public.urls OWNER TO postgres;
 
+--
+-- Name: api_tokens api_tokens_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.api_tokens
+    ADD CONSTRAINT api_tokens_pkey PRIMARY KEY (uuid);
+
+
+--
+-- Name: api_tokens api_tokens_token_hash_key; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.api_tokens
+    ADD CONSTRAINT api_tokens_token_hash_key UNIQUE (token_hash);
+
+
+--
+-- Name: clicks clicks_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.clicks
+    ADD CONSTRAINT clicks_pkey PRIMARY KEY (uuid);
+
+
+--
+-- Name: delete_jobs delete_jobs_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.delete_jobs
+    ADD CONSTRAINT delete_jobs_pkey PRIMARY KEY (uuid);
+
+
+--
+-- Name: delete_tasks delete_tasks_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.delete_tasks
+    ADD CONSTRAINT delete_tasks_pkey PRIMARY KEY (uuid);
+
+
 --
 -- Name: urls urls_long_url_key; Type: CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -75,6 +203,55 @@ ALTER TABLE ONLY public.urls
     ADD CONSTRAINT urls_short_code_key UNIQUE (short_code);
 
 
+--
+-- Name: api_tokens_user_uuid_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX api_tokens_user_uuid_idx ON public.api_tokens USING btree (user_uuid) WHERE (revoked_at IS NULL);
+
+
+--
+-- Name: clicks_url_uuid_created_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX clicks_url_uuid_created_at_idx ON public.clicks USING btree (url_uuid, created_at);
+
+
+--
+-- Name: delete_jobs_finished_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX delete_jobs_finished_at_idx ON public.delete_jobs USING btree (finished_at) WHERE (finished_at IS NOT NULL);
+
+
+--
+-- Name: delete_tasks_run_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX delete_tasks_run_at_idx ON public.delete_tasks USING btree (run_at) WHERE (failed_at IS NULL);
+
+
+--
+-- Name: urls_deleted_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX urls_deleted_at_idx ON public.urls USING btree (deleted_at) WHERE (deleted_at IS NOT NULL);
+
+
+--
+-- Name: urls_expires_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX urls_expires_at_idx ON public.urls USING btree (expires_at) WHERE ((expires_at IS NOT NULL) AND (expired = false));
+
+
+--
+-- Name: urls_user_uuid_created_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX urls_user_uuid_created_at_idx ON public.urls USING btree (user_uuid, created_at DESC, uuid DESC) WHERE ((deleted_at IS NULL) AND (expired = false));
+
+
 --
 -- Name: urls_user_uuid_deleted_at_idx; Type: INDEX; Schema: public; Owner: postgres
 --
@@ -89,6 +266,14 @@ CREATE INDEX urls_user_uuid_deleted_at_idx ON public.urls USING btree (user_uuid
 CREATE INDEX urls_user_uuid_idx ON public.urls USING btree (user_uuid);
 
 
+--
+-- Name: clicks clicks_url_uuid_fkey; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.clicks
+    ADD CONSTRAINT clicks_url_uuid_fkey FOREIGN KEY (url_uuid) REFERENCES public.urls(uuid) ON DELETE CASCADE;
+
+
 --
 -- PostgreSQL database dump complete
 --
@@ -2,31 +2,242 @@
 SELECT 1;
 
 -- name: CreateURL :one
-INSERT INTO urls (uuid, long_url, short_code, user_uuid)
-VALUES ($1, $2, $3, $4)
+INSERT INTO urls (uuid, long_url, short_code, user_uuid, expires_at)
+VALUES ($1, $2, $3, $4, $5)
 ON CONFLICT (long_url) DO UPDATE SET short_code = urls.short_code
 RETURNING uuid, long_url, short_code;
 
 -- name: GetURLByShortCode :one
-SELECT uuid, long_url, short_code, deleted_at FROM urls WHERE short_code = $1;
+SELECT uuid, long_url, short_code, user_uuid, deleted_at, expires_at, expired FROM urls WHERE short_code = $1;
+
+-- name: GetURLsByLongURLs :many
+SELECT uuid, long_url, short_code, user_uuid FROM urls WHERE long_url = ANY(@long_urls::text[]);
+
+-- name: NextShortCodeSequence :one
+SELECT nextval('short_code_seq')::bigint;
 
 -- name: GetURLsByUserID :many
 WITH counter AS (
   SELECT COUNT(*) AS total
   FROM urls
-  WHERE user_uuid = $1 AND deleted_at IS NULL
+  WHERE user_uuid = $1 AND deleted_at IS NULL AND expired = FALSE
 )
 SELECT
   u.uuid,
   u.long_url,
   u.short_code,
+  u.created_at,
   counter.total
 FROM urls AS u
 RIGHT JOIN counter ON TRUE
-WHERE u.user_uuid = $1 AND deleted_at IS NULL
-ORDER BY u.created_at DESC LIMIT $2 OFFSET $3;
+WHERE u.user_uuid = $1 AND deleted_at IS NULL AND expired = FALSE
+ORDER BY u.created_at DESC, u.uuid DESC LIMIT $2 OFFSET $3;
+
+-- name: GetURLsByUserIDAfter :many
+SELECT uuid, long_url, short_code, created_at
+FROM urls
+WHERE user_uuid = @user_uuid AND deleted_at IS NULL AND expired = FALSE
+  AND (created_at, uuid) < (@created_at::timestamp, @uuid::uuid)
+ORDER BY created_at DESC, uuid DESC
+LIMIT @lim;
+
+-- name: ExportURLsByUserID :many
+SELECT
+  u.uuid,
+  u.long_url,
+  u.short_code,
+  u.created_at,
+  u.deleted_at,
+  u.expires_at,
+  u.expired,
+  c.clicks
+FROM urls AS u
+CROSS JOIN LATERAL (
+  SELECT COUNT(*) AS clicks FROM clicks WHERE url_uuid = u.uuid
+) AS c
+WHERE u.user_uuid = @user_uuid
+  AND (sqlc.narg('created_at')::timestamp IS NULL OR (u.created_at, u.uuid) < (sqlc.narg('created_at'), @uuid::uuid))
+ORDER BY u.created_at DESC, u.uuid DESC
+LIMIT @lim;
+
+-- name: DeleteURLsByUserIDAndShortCodes :many
+UPDATE urls
+SET deleted_at = $3
+WHERE user_uuid = $1 AND short_code = ANY($2::varchar[]) AND deleted_at IS NULL
+RETURNING short_code;
+
+-- name: DeleteURLsByUserIDs :many
+UPDATE urls AS u
+SET deleted_at = @deleted_at
+FROM unnest(@user_uuids::uuid[], @short_codes::varchar[]) AS d(user_uuid, short_code)
+WHERE u.user_uuid = d.user_uuid AND u.short_code = d.short_code AND u.deleted_at IS NULL
+RETURNING u.user_uuid, u.short_code;
 
--- name: DeleteURLsByUserIDAndShortCodes :exec
+-- name: RestoreURLsByUserIDAndShortCodes :many
 UPDATE urls
-SET deleted_at = NOW()
-WHERE user_uuid = $1 AND short_code = ANY($2::varchar[]) AND deleted_at IS NULL;
+SET deleted_at = NULL
+WHERE user_uuid = $1 AND short_code = ANY($2::varchar[]) AND deleted_at >= @deleted_since
+RETURNING short_code;
+
+-- name: PurgeURLs :execrows
+DELETE FROM urls
+WHERE deleted_at < $1;
+
+-- name: ExpireURLs :execrows
+UPDATE urls
+SET expired = TRUE
+WHERE expires_at <= @now AND expired = FALSE;
+
+-- name: CreateClick :exec
+INSERT INTO clicks (url_uuid, referrer, user_agent, ip_hash, created_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetClicksSummary :one
+SELECT
+  COUNT(*) AS total,
+  COUNT(DISTINCT ip_hash) AS unique_visitors
+FROM clicks
+WHERE url_uuid = $1;
+
+-- name: GetDailyClicks :many
+SELECT
+  date_trunc('day', created_at)::timestamp AS day,
+  COUNT(*) AS total,
+  COUNT(DISTINCT ip_hash) AS unique_visitors
+FROM clicks
+WHERE url_uuid = $1 AND created_at >= $2
+GROUP BY day
+ORDER BY day;
+
+-- name: UpdateURL :one
+UPDATE urls
+SET long_url = $3, updated_at = NOW()
+WHERE short_code = $1 AND user_uuid = $2 AND deleted_at IS NULL
+RETURNING uuid, long_url, short_code, user_uuid, updated_at;
+
+-- name: CreateAPIToken :one
+INSERT INTO api_tokens (uuid, user_uuid, name, prefix, token_hash, scopes)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING created_at;
+
+-- name: GetAPITokenByHash :one
+SELECT uuid, user_uuid, name, prefix, scopes, created_at
+FROM api_tokens
+WHERE token_hash = $1 AND revoked_at IS NULL;
+
+-- name: GetAPITokensByUserID :many
+SELECT uuid, user_uuid, name, prefix, scopes, created_at
+FROM api_tokens
+WHERE user_uuid = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: RevokeAPIToken :execrows
+UPDATE api_tokens
+SET revoked_at = NOW()
+WHERE uuid = $1 AND user_uuid = $2 AND revoked_at IS NULL;
+
+-- name: FindURLsByUserID :many
+SELECT
+  u.uuid,
+  u.long_url,
+  u.short_code,
+  u.created_at,
+  u.deleted_at,
+  u.expires_at,
+  u.expired,
+  c.clicks
+FROM urls AS u
+CROSS JOIN LATERAL (
+  SELECT COUNT(*) AS clicks FROM clicks WHERE url_uuid = u.uuid
+) AS c
+WHERE u.user_uuid = @user_uuid
+  AND CASE
+    WHEN @deleted::boolean THEN u.deleted_at IS NOT NULL
+    WHEN @expired::boolean THEN u.deleted_at IS NULL AND u.expired
+    ELSE u.deleted_at IS NULL AND NOT u.expired
+  END
+  AND (@pattern::text = '' OR u.long_url ILIKE @pattern OR u.short_code ILIKE @pattern)
+  AND (sqlc.narg('created_after')::timestamp IS NULL OR u.created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamp IS NULL OR u.created_at < sqlc.narg('created_before'))
+ORDER BY
+  CASE WHEN @sort::text = 'clicks' THEN c.clicks END ASC,
+  CASE WHEN @sort::text = '-clicks' THEN c.clicks END DESC,
+  CASE WHEN @sort::text = 'created_at' THEN u.created_at END ASC,
+  u.created_at DESC,
+  u.uuid DESC
+LIMIT @lim OFFSET @off;
+
+-- name: CountURLsByUserID :one
+SELECT COUNT(*)
+FROM urls AS u
+WHERE u.user_uuid = @user_uuid
+  AND CASE
+    WHEN @deleted::boolean THEN u.deleted_at IS NOT NULL
+    WHEN @expired::boolean THEN u.deleted_at IS NULL AND u.expired
+    ELSE u.deleted_at IS NULL AND NOT u.expired
+  END
+  AND (@pattern::text = '' OR u.long_url ILIKE @pattern OR u.short_code ILIKE @pattern)
+  AND (sqlc.narg('created_after')::timestamp IS NULL OR u.created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamp IS NULL OR u.created_at < sqlc.narg('created_before'));
+
+-- name: EnqueueDeleteTask :exec
+INSERT INTO delete_tasks (uuid, user_uuid, short_codes, run_at, created_at, trace_parent)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ClaimDeleteTasks :many
+UPDATE delete_tasks
+SET run_at = @lease_until, attempts = attempts + 1
+WHERE uuid IN (
+  SELECT uuid FROM delete_tasks
+  WHERE failed_at IS NULL AND run_at <= @now
+  ORDER BY run_at, created_at
+  LIMIT @lim
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING uuid, user_uuid, short_codes, attempts, last_error, run_at, created_at, trace_parent;
+
+-- name: CompleteDeleteTasks :exec
+DELETE FROM delete_tasks
+WHERE uuid = ANY($1::uuid[]);
+
+-- name: RetryDeleteTask :exec
+UPDATE delete_tasks
+SET run_at = $2, last_error = $3
+WHERE uuid = $1;
+
+-- name: FailDeleteTask :exec
+UPDATE delete_tasks
+SET failed_at = NOW(), last_error = $2
+WHERE uuid = $1;
+
+-- name: CountDeleteTasks :one
+SELECT
+  COUNT(*) FILTER (WHERE failed_at IS NULL) AS pending,
+  COUNT(*) FILTER (WHERE failed_at IS NOT NULL) AS failed
+FROM delete_tasks;
+
+-- name: SaveDeleteJob :exec
+INSERT INTO delete_jobs (uuid, user_uuid, status, short_codes, deleted, skipped, error, created_at, finished_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (uuid) DO UPDATE
+SET status = EXCLUDED.status, deleted = EXCLUDED.deleted, skipped = EXCLUDED.skipped,
+  error = EXCLUDED.error, finished_at = EXCLUDED.finished_at;
+
+-- name: GetDeleteJob :one
+SELECT uuid, user_uuid, status, short_codes, deleted, skipped, error, created_at, finished_at
+FROM delete_jobs
+WHERE uuid = @id;
+
+-- name: PurgeDeleteJobs :execrows
+DELETE FROM delete_jobs
+WHERE finished_at < $1;
+
+-- name: CountURLs :one
+SELECT COUNT(*)
+FROM urls
+WHERE deleted_at IS NULL AND expired = FALSE;
+
+-- name: CountUsers :one
+SELECT COUNT(DISTINCT user_uuid)
+FROM urls
+WHERE deleted_at IS NULL AND expired = FALSE AND user_uuid <> '00000000-0000-0000-0000-000000000000';
@@ -16,6 +16,7 @@ import (
 	"shortly/internal/app/dto"
 	"shortly/internal/app/repository"
 	"shortly/internal/app/router"
+	"shortly/internal/app/service"
 	"shortly/internal/app/worker"
 	"shortly/internal/logger"
 )
@@ -27,11 +28,15 @@ func Example_handleCreateShortLink() {
 	}
 	appLogger := logger.NewLogger()
 	appRepo := repository.NewInMemoryRepository()
-	deleteWorker := worker.NewDeleteWorker(ctx, cfg, appRepo, appLogger)
-	deleteWorker.Start()
-	defer deleteWorker.Stop()
+	scheduler := worker.NewScheduler(ctx, cfg, appLogger)
+	deleteWorker := worker.NewDeleteWorker(ctx, cfg, appRepo, scheduler, appLogger)
+	_ = scheduler.Start()
+	defer scheduler.Stop()
 
-	appRouter := router.NewRouter(cfg, appRepo, deleteWorker, appLogger)
+	clickRecorder := worker.NewClickRecorder(ctx, cfg, appRepo, scheduler, appLogger)
+	rand := service.NewSecureRandom()
+	shortener := service.NewURLService(cfg, appRepo, rand, service.NewShortCodeGenerator(cfg, appRepo, rand), deleteWorker)
+	appRouter := router.NewRouter(cfg, appRepo, shortener, deleteWorker, clickRecorder, appLogger)
 	appServer := httptest.NewServer(appRouter)
 	defer appServer.Close()
 
@@ -58,11 +63,15 @@ func Example_handleGetShortLink() {
 	}
 	appLogger := logger.NewLogger()
 	appRepo := repository.NewInMemoryRepository()
-	deleteWorker := worker.NewDeleteWorker(ctx, cfg, appRepo, appLogger)
-	deleteWorker.Start()
-	defer deleteWorker.Stop()
+	scheduler := worker.NewScheduler(ctx, cfg, appLogger)
+	deleteWorker := worker.NewDeleteWorker(ctx, cfg, appRepo, scheduler, appLogger)
+	_ = scheduler.Start()
+	defer scheduler.Stop()
 
-	appRouter := router.NewRouter(cfg, appRepo, deleteWorker, appLogger)
+	clickRecorder := worker.NewClickRecorder(ctx, cfg, appRepo, scheduler, appLogger)
+	rand := service.NewSecureRandom()
+	shortener := service.NewURLService(cfg, appRepo, rand, service.NewShortCodeGenerator(cfg, appRepo, rand), deleteWorker)
+	appRouter := router.NewRouter(cfg, appRepo, shortener, deleteWorker, clickRecorder, appLogger)
 	appServer := httptest.NewServer(appRouter)
 	defer appServer.Close()
 
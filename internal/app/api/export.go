@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"shortly/internal/app/dto"
+)
+
+// HandleExportUserURLs handles streaming export of all user links as CSV, JSON or NDJSON
+func (h *URLHandler) HandleExportUserURLs(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+
+	export, err := dto.NewExportWriter(format, w)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if format == "" {
+		format = dto.ExportFormatJSON
+	}
+
+	controller := http.NewResponseController(w)
+	started := false
+
+	// NOTE: headers are sent with the first page, so a failure to load it is still reported with a status
+	start := func() {
+		started = true
+		w.Header().Set("Content-Type", export.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"urls.%s\"", format))
+		w.WriteHeader(http.StatusOK)
+	}
+
+	err = h.service.ExportUserURLs(r.Context(), func(urls []dto.UserURLResponse) error {
+		if !started {
+			start()
+		}
+
+		for _, url := range urls {
+			if err := export.Write(url); err != nil {
+				return err
+			}
+		}
+
+		extendDeadlines(controller)
+		return controller.Flush()
+	})
+
+	if err != nil && !started {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// NOTE: the body is already partially sent, aborting the response tells the client the export is incomplete
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	if !started {
+		start()
+	}
+
+	export.Close()
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/middleware/compress"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/service"
+	"shortly/internal/app/worker"
+)
+
+func Test_HandleExportUserURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := gomock.Any()
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	items := []repository.URLListItem{
+		{URL: repository.URL{LongURL: "https://example.com", ShortCode: "abcd0001", CreatedAt: createdAt}, Clicks: 3},
+	}
+
+	type result struct {
+		body        string
+		code        int
+		contentType string
+		disposition string
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		before   func()
+		expected result
+	}{
+		{
+			name:  "CSV",
+			query: "?format=csv",
+			before: func() {
+				repo.EXPECT().ExportURLsByUserID(ctx, UserUUID, repository.URLCursor{}, int64(service.ExportPageSize)).Return(items, nil)
+			},
+			expected: result{
+				body: "short_url,short_code,original_url,clicks,created_at,expires_at,deleted_at,expired\n" +
+					"http://localhost:8080/abcd0001,abcd0001,https://example.com,3,2026-10-17T12:00:00Z,,,false\n",
+				code:        http.StatusOK,
+				contentType: "text/csv",
+				disposition: `attachment; filename="urls.csv"`,
+			},
+		},
+		{
+			name:  "JSON by default",
+			query: "",
+			before: func() {
+				repo.EXPECT().ExportURLsByUserID(ctx, UserUUID, repository.URLCursor{}, int64(service.ExportPageSize)).Return(nil, nil)
+			},
+			expected: result{
+				body:        "[]\n",
+				code:        http.StatusOK,
+				contentType: "application/json",
+				disposition: `attachment; filename="urls.json"`,
+			},
+		},
+		{
+			name:   "Unsupported format",
+			query:  "?format=xml",
+			before: func() {},
+			expected: result{
+				body:        `{"error":"unsupported export format, use csv, json or ndjson"}` + "\n",
+				code:        http.StatusBadRequest,
+				contentType: "application/json",
+			},
+		},
+		{
+			name:  "Storage error",
+			query: "?format=ndjson",
+			before: func() {
+				repo.EXPECT().ExportURLsByUserID(ctx, UserUUID, repository.URLCursor{}, int64(service.ExportPageSize)).
+					Return(nil, errors.ErrFailedToLoadUserUrls)
+			},
+			expected: result{
+				body:        `{"error":"failed to load user URLs"}` + "\n",
+				code:        http.StatusInternalServerError,
+				contentType: "application/json",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			request := httptest.NewRequest(http.MethodGet, "/api/user/urls/export"+tt.query, nil)
+			request = request.WithContext(context.WithValue(request.Context(), dto.CurrentUser, UserUUID))
+
+			w := httptest.NewRecorder()
+			handler.HandleExportUserURLs(w, request)
+
+			assert.Equal(t, tt.expected.code, w.Code)
+			assert.Equal(t, tt.expected.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expected.disposition, w.Header().Get("Content-Disposition"))
+			assert.Equal(t, tt.expected.body, w.Body.String())
+		})
+	}
+}
+
+func Test_HandleExportUserURLs_Stream(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewInMemoryRepository()
+	rand := service.NewSecureRandom()
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), nil)
+	handler := NewURLHandler(cfg, srv, nil)
+
+	UserUUID := uuid.New()
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	urls := make([]repository.URL, 0, 2*service.ExportPageSize+1)
+	for i := 0; i < cap(urls); i++ {
+		urls = append(urls, repository.URL{UUID: uuid.New(), LongURL: uuid.NewString(), ShortCode: uuid.NewString(), UserUUID: UserUUID})
+	}
+	_, err := repo.CreateURLs(ctx, urls)
+	require.NoError(t, err)
+	_, err = repo.DeleteURLsByUserID(ctx, UserUUID, []string{urls[0].ShortCode})
+	require.NoError(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/user/urls/export?format=ndjson", nil).WithContext(ctx)
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	w := httptest.NewRecorder()
+	compress.Middleware(http.HandlerFunc(handler.HandleExportUserURLs)).ServeHTTP(w, request)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.True(t, w.Flushed)
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	rows, err := dto.NewImportReader(dto.ImportFormatNDJSON, bytes.NewReader(body))
+	require.NoError(t, err)
+
+	count := 0
+	for {
+		_, err = rows.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		count++
+	}
+
+	assert.Equal(t, len(urls), count)
+	assert.Contains(t, string(body), `"deleted_at"`)
+}
+
+func Test_HandleExportUserURLs_Aborted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), worker.NewMockWorker(ctrl))
+	handler := NewURLHandler(cfg, srv, nil)
+
+	UserUUID := uuid.New()
+
+	page := make([]repository.URLListItem, service.ExportPageSize)
+	for i := range page {
+		page[i] = repository.URLListItem{URL: repository.URL{UUID: uuid.New(), ShortCode: "abcd0001", CreatedAt: time.Now()}}
+	}
+
+	gomock.InOrder(
+		repo.EXPECT().ExportURLsByUserID(gomock.Any(), UserUUID, gomock.Any(), gomock.Any()).Return(page, nil),
+		repo.EXPECT().ExportURLsByUserID(gomock.Any(), UserUUID, gomock.Any(), gomock.Any()).Return(nil, errors.ErrFailedToLoadUserUrls),
+	)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/user/urls/export?format=csv", nil)
+	request = request.WithContext(context.WithValue(request.Context(), dto.CurrentUser, UserUUID))
+
+	w := httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.HandleExportUserURLs(w, request)
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
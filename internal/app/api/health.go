@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"shortly/internal/app/dto"
+	"shortly/internal/app/health"
 	"shortly/internal/app/service"
 )
 
@@ -25,19 +26,31 @@ func (h *HealthHandler) HandleLiveness(w http.ResponseWriter, _ *http.Request) {
 	json.NewEncoder(w).Encode(dto.HealthResponse{Result: "alive"})
 }
 
-// HandleReadiness handles application readiness check
+// HandleReadiness handles application readiness check, it reports the status of every component
+// and fails with 503 Service Unavailable only when a critical component is down
 func (h *HealthHandler) HandleReadiness(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+	report := h.service.Report(r.Context())
 
-	err := h.service.Ping(r.Context())
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
-		return
+	response := dto.ReadinessResponse{
+		Status:     report.Status,
+		Components: make(map[string]string, len(report.Components)),
+	}
+	for name, component := range report.Components {
+		response.Components[name] = component.Status
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dto.HealthResponse{Result: "ready"})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(healthStatusCode(report))
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleHealthDetails handles the detailed health report with the errors and timings of the components
+func (h *HealthHandler) HandleHealthDetails(w http.ResponseWriter, r *http.Request) {
+	report := h.service.Report(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(healthStatusCode(report))
+	json.NewEncoder(w).Encode(report)
 }
 
 // HandlePing handles ping request
@@ -54,3 +67,12 @@ func (h *HealthHandler) HandlePing(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(dto.HealthResponse{Result: "pong"})
 }
+
+// healthStatusCode returns 503 Service Unavailable for an application down, a degraded one keeps serving traffic
+func healthStatusCode(report dto.HealthReport) int {
+	if report.Status == health.StatusDown {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
@@ -5,11 +5,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
 	"shortly/internal/app/dto"
+	"shortly/internal/app/health"
 	"shortly/internal/app/service"
 )
 
@@ -68,46 +70,74 @@ func TestHealthHandler_HandleReadiness(t *testing.T) {
 	handler := NewHealthHandler(mockService)
 
 	type result struct {
-		response dto.HealthResponse
-		error    dto.ErrorResponse
+		response dto.ReadinessResponse
 		code     int
 		status   string
 	}
 
 	tests := []struct {
 		name     string
-		before   func()
+		report   dto.HealthReport
 		expected result
 	}{
 		{
 			name: "Success",
-			before: func() {
-				mockService.EXPECT().Ping(gomock.Any()).Return(nil)
+			report: dto.HealthReport{
+				Status: health.StatusUp,
+				Components: map[string]dto.ComponentHealth{
+					"database":      {Status: health.StatusUp, Critical: true},
+					"delete_worker": {Status: health.StatusUp},
+				},
 			},
 			expected: result{
-				response: dto.HealthResponse{Result: "ready"},
-				error:    dto.ErrorResponse{},
-				code:     http.StatusOK,
-				status:   "200 OK",
+				response: dto.ReadinessResponse{
+					Status:     health.StatusUp,
+					Components: map[string]string{"database": health.StatusUp, "delete_worker": health.StatusUp},
+				},
+				code:   http.StatusOK,
+				status: "200 OK",
 			},
 		},
 		{
-			name: "Service Error",
-			before: func() {
-				mockService.EXPECT().Ping(gomock.Any()).Return(assert.AnError)
+			name: "Degraded",
+			report: dto.HealthReport{
+				Status: health.StatusDegraded,
+				Components: map[string]dto.ComponentHealth{
+					"database":      {Status: health.StatusUp, Critical: true},
+					"delete_worker": {Status: health.StatusDegraded, Error: "delete queue saturated"},
+				},
 			},
 			expected: result{
-				response: dto.HealthResponse{},
-				error:    dto.ErrorResponse{Error: assert.AnError.Error()},
-				code:     http.StatusInternalServerError,
-				status:   "500 Internal Server Error",
+				response: dto.ReadinessResponse{
+					Status:     health.StatusDegraded,
+					Components: map[string]string{"database": health.StatusUp, "delete_worker": health.StatusDegraded},
+				},
+				code:   http.StatusOK,
+				status: "200 OK",
+			},
+		},
+		{
+			name: "Down",
+			report: dto.HealthReport{
+				Status: health.StatusDown,
+				Components: map[string]dto.ComponentHealth{
+					"database": {Status: health.StatusDown, Critical: true, Error: assert.AnError.Error()},
+				},
+			},
+			expected: result{
+				response: dto.ReadinessResponse{
+					Status:     health.StatusDown,
+					Components: map[string]string{"database": health.StatusDown},
+				},
+				code:   http.StatusServiceUnavailable,
+				status: "503 Service Unavailable",
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.before()
+			mockService.EXPECT().Report(gomock.Any()).Return(tt.report)
 
 			req := httptest.NewRequest("GET", "/ready", nil)
 			w := httptest.NewRecorder()
@@ -117,23 +147,76 @@ func TestHealthHandler_HandleReadiness(t *testing.T) {
 			resp := w.Result()
 			defer resp.Body.Close()
 
-			if tt.expected.error.Error != "" {
-				var actual dto.ErrorResponse
-				err := json.NewDecoder(resp.Body).Decode(&actual)
-				assert.NoError(t, err)
-				assert.Equal(t, tt.expected.error.Error, actual.Error)
-			} else {
-				var actual dto.HealthResponse
-				err := json.NewDecoder(resp.Body).Decode(&actual)
-				assert.NoError(t, err)
-				assert.Equal(t, tt.expected.response.Result, actual.Result)
-			}
+			var actual dto.ReadinessResponse
+			err := json.NewDecoder(resp.Body).Decode(&actual)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.response, actual)
+			assert.NotContains(t, w.Body.String(), "error")
 			assert.Equal(t, tt.expected.status, resp.Status)
 			assert.Equal(t, tt.expected.code, resp.StatusCode)
 		})
 	}
 }
 
+func TestHealthHandler_HandleHealthDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockHealthChecker(ctrl)
+	handler := NewHealthHandler(mockService)
+
+	checkedAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		report dto.HealthReport
+		code   int
+	}{
+		{
+			name: "Degraded",
+			report: dto.HealthReport{
+				Status: health.StatusDegraded,
+				Components: map[string]dto.ComponentHealth{
+					"database":      {Status: health.StatusUp, Critical: true, DurationMs: 3, CheckedAt: checkedAt},
+					"delete_worker": {Status: health.StatusDegraded, Error: "delete queue saturated", CheckedAt: checkedAt},
+				},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "Down",
+			report: dto.HealthReport{
+				Status: health.StatusDown,
+				Components: map[string]dto.ComponentHealth{
+					"database": {Status: health.StatusDown, Critical: true, Error: assert.AnError.Error(), CheckedAt: checkedAt},
+				},
+			},
+			code: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.EXPECT().Report(gomock.Any()).Return(tt.report)
+
+			req := httptest.NewRequest("GET", "/health/details", nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleHealthDetails(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			var actual dto.HealthReport
+			err := json.NewDecoder(resp.Body).Decode(&actual)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.report, actual)
+			assert.Equal(t, tt.code, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		})
+	}
+}
+
 func TestHealthHandler_HandlePing(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"shortly/internal/app/dto"
+)
+
+// ImportProgressTimeout is the time the client has to send and receive every import chunk, server timeouts are extended by it
+const ImportProgressTimeout = time.Minute
+
+// HandleImportShortLinks handles streaming CSV or NDJSON short link import, the NDJSON report has a line per row
+func (h *URLHandler) HandleImportShortLinks(w http.ResponseWriter, r *http.Request) {
+	rows, err := dto.NewImportReader(r.Header.Get("Content-Type"), r.Body)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// NOTE: the report is streamed while the body is still being read, HTTP/1.x needs full duplex for that
+	controller := http.NewResponseController(w)
+	controller.EnableFullDuplex()
+	extendDeadlines(controller)
+
+	w.Header().Set("Content-Type", dto.ImportFormatNDJSON)
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+
+	h.service.ImportShortLinks(r.Context(), rows, func(results []dto.ImportResult) error {
+		for _, result := range results {
+			if err := encoder.Encode(result); err != nil {
+				return err
+			}
+		}
+
+		extendDeadlines(controller)
+		return controller.Flush()
+	})
+}
+
+// extendDeadlines gives the client another ImportProgressTimeout, writers without deadlines are left as is
+func extendDeadlines(controller *http.ResponseController) {
+	deadline := time.Now().Add(ImportProgressTimeout)
+
+	controller.SetReadDeadline(deadline)
+	controller.SetWriteDeadline(deadline)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/service"
+	"shortly/internal/app/worker"
+)
+
+func Test_HandleImportShortLinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := gomock.Any()
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	type result struct {
+		body        string
+		code        int
+		contentType string
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		before      func()
+		expected    result
+	}{
+		{
+			name:        "Success",
+			contentType: "text/csv",
+			body:        "original_url,alias,correlation_id\nhttps://example.com,,1\nnot a url,,2\n",
+			before: func() {
+				repo.EXPECT().GetURLsByLongURLs(ctx, []string{"https://example.com"}).Return(nil, nil)
+				rand.EXPECT().UUID().Return(UUID, nil)
+				rand.EXPECT().Hex().Return("abcd1234", nil)
+				repo.EXPECT().GetURLByShortCode(ctx, "abcd1234").Return(nil, false)
+				urls := []repository.URL{
+					{UUID: UUID, LongURL: "https://example.com", ShortCode: "abcd1234", UserUUID: UserUUID},
+				}
+				repo.EXPECT().CreateURLs(ctx, urls).Return(urls, nil)
+			},
+			expected: result{
+				body: `{"line":2,"correlation_id":"1","status":"created","short_url":"http://localhost:8080/abcd1234"}` + "\n" +
+					`{"line":3,"correlation_id":"2","status":"invalid","error":"invalid URL"}` + "\n",
+				code:        http.StatusOK,
+				contentType: dto.ImportFormatNDJSON,
+			},
+		},
+		{
+			name:        "Unsupported format",
+			contentType: "application/json",
+			body:        `[{"original_url":"https://example.com"}]`,
+			before:      func() {},
+			expected: result{
+				body:        `{"error":"unsupported import format, use text/csv or application/x-ndjson"}` + "\n",
+				code:        http.StatusUnsupportedMediaType,
+				contentType: "application/json",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			request := httptest.NewRequest(http.MethodPost, "/api/shorten/import", strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", tt.contentType)
+			request = request.WithContext(context.WithValue(request.Context(), dto.CurrentUser, UserUUID))
+
+			w := httptest.NewRecorder()
+			handler.HandleImportShortLinks(w, request)
+
+			assert.Equal(t, tt.expected.code, w.Code)
+			assert.Equal(t, tt.expected.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expected.body, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/service"
+)
+
+// InternalHandler is a handler for internal routes served to the trusted subnet
+type InternalHandler struct {
+	service service.InternalStats
+}
+
+// NewInternalHandler creates a new InternalHandler
+func NewInternalHandler(service service.InternalStats) *InternalHandler {
+	return &InternalHandler{service: service}
+}
+
+// HandleGetStats handles the retrieval of the number of links and users of the service
+func (h *InternalHandler) HandleGetStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	stats, err := h.service.GetStats(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/service"
+)
+
+func TestInternalHandler_HandleGetStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockInternalStats(ctrl)
+	handler := NewInternalHandler(mockService)
+
+	stats := &dto.InternalStatsResponse{
+		URLs:  3,
+		Users: 2,
+		Backends: map[string]dto.BackendStats{
+			"memory": {URLs: 3, Users: 2},
+			"file":   {URLs: 1, Users: 1},
+		},
+	}
+
+	type result struct {
+		response *dto.InternalStatsResponse
+		error    dto.ErrorResponse
+		code     int
+	}
+
+	tests := []struct {
+		name     string
+		before   func()
+		expected result
+	}{
+		{
+			name: "Success",
+			before: func() {
+				mockService.EXPECT().GetStats(gomock.Any()).Return(stats, nil)
+			},
+			expected: result{
+				response: stats,
+				code:     http.StatusOK,
+			},
+		},
+		{
+			name: "Service Error",
+			before: func() {
+				mockService.EXPECT().GetStats(gomock.Any()).Return(nil, errors.ErrFailedToLoadStats)
+			},
+			expected: result{
+				error: dto.ErrorResponse{Error: errors.ErrFailedToLoadStats.Error()},
+				code:  http.StatusInternalServerError,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/internal/stats", nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleGetStats(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if tt.expected.error.Error != "" {
+				var actual dto.ErrorResponse
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+				assert.Equal(t, tt.expected.error, actual)
+			} else {
+				var actual dto.InternalStatsResponse
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+				assert.Equal(t, *tt.expected.response, actual)
+			}
+			assert.Equal(t, tt.expected.code, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		})
+	}
+}
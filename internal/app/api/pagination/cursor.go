@@ -0,0 +1,46 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shortly/internal/app/errors"
+)
+
+// cursorSeparator separates the creation time and the UUID of an encoded cursor
+const cursorSeparator = "|"
+
+// EncodeCursor encodes the keyset position of the last item on a page into an opaque cursor
+func EncodeCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + cursorSeparator + id.String()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor decodes an opaque cursor into the keyset position it was encoded from
+func DecodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errors.ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), cursorSeparator)
+	if !ok {
+		return time.Time{}, uuid.Nil, errors.ErrInvalidCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errors.ErrInvalidCursor
+	}
+
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errors.ErrInvalidCursor
+	}
+
+	return t, u, nil
+}
@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"shortly/internal/app/errors"
+)
+
+func Test_EncodeCursor(t *testing.T) {
+	id, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 123456000, time.FixedZone("MSK", 3*60*60))
+
+	cursor := EncodeCursor(createdAt, id)
+	assert.NotContains(t, cursor, "=")
+
+	decodedAt, decodedID, err := DecodeCursor(cursor)
+	assert.NoError(t, err)
+	assert.True(t, createdAt.Equal(decodedAt))
+	assert.Equal(t, id, decodedID)
+}
+
+func Test_DecodeCursor(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name     string
+		cursor   string
+		expected error
+	}{
+		{
+			name:     "Valid",
+			cursor:   encode("2026-10-17T12:00:00Z|6455bd07-e431-4851-af3c-4f703f720001"),
+			expected: nil,
+		},
+		{
+			name:     "Not base64",
+			cursor:   "not a cursor",
+			expected: errors.ErrInvalidCursor,
+		},
+		{
+			name:     "No separator",
+			cursor:   encode("2026-10-17T12:00:00Z"),
+			expected: errors.ErrInvalidCursor,
+		},
+		{
+			name:     "Invalid time",
+			cursor:   encode("yesterday|6455bd07-e431-4851-af3c-4f703f720001"),
+			expected: errors.ErrInvalidCursor,
+		},
+		{
+			name:     "Invalid UUID",
+			cursor:   encode("2026-10-17T12:00:00Z|abc"),
+			expected: errors.ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := DecodeCursor(tt.cursor)
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
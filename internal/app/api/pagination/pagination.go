@@ -1,8 +1,11 @@
 package pagination
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 )
 
 // DefaultPage is the default page number
@@ -18,13 +21,20 @@ const MaxPerPage int64 = 1000
 type Pagination struct {
 	Page int64
 	Per  int64
+	// Cursor is the opaque position to continue after, it takes precedence over Page
+	Cursor string
 }
 
 // NewPagination creates a new pagination instance
 func NewPagination(r *http.Request) *Pagination {
-	page := parseQueryParam(r, "page", DefaultPage)
-	per := parseQueryParam(r, "per", DefaultPerPage)
+	p := New(parseQueryParam(r, "page", DefaultPage), parseQueryParam(r, "per", DefaultPerPage))
+	p.Cursor = r.URL.Query().Get("cursor")
 
+	return p
+}
+
+// New creates a new pagination instance of the page, out of range values fall back to the defaults and the limit
+func New(page, per int64) *Pagination {
 	if page < 1 {
 		page = DefaultPage
 	}
@@ -61,3 +71,45 @@ func parseQueryParam(r *http.Request, key string, defaultValue int64) int64 {
 func (p *Pagination) Offset() int64 {
 	return (p.Page - 1) * p.Per
 }
+
+// Links returns the Link header value with the first, previous, next and last pages of the request URL
+func (p *Pagination) Links(u *url.URL, total int64) string {
+	last := max((total+p.Per-1)/p.Per, 1)
+
+	links := []string{p.link(u, 1, "first")}
+
+	if p.Page > 1 {
+		links = append(links, p.link(u, min(p.Page-1, last), "prev"))
+	}
+
+	if p.Page < last {
+		links = append(links, p.link(u, p.Page+1, "next"))
+	}
+
+	links = append(links, p.link(u, last, "last"))
+
+	return strings.Join(links, ", ")
+}
+
+// NextLink returns the Link header value with the next page of the request URL following the cursor
+func (p *Pagination) NextLink(u *url.URL, cursor string) string {
+	query := u.Query()
+	query.Del("page")
+	query.Set("cursor", cursor)
+	query.Set("per", strconv.FormatInt(p.Per, 10))
+
+	target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+
+	return fmt.Sprintf(`<%s>; rel="next"`, target.String())
+}
+
+// link returns a link to the page of the request URL, other query parameters are kept
+func (p *Pagination) link(u *url.URL, page int64, rel string) string {
+	query := u.Query()
+	query.Set("page", strconv.FormatInt(page, 10))
+	query.Set("per", strconv.FormatInt(p.Per, 10))
+
+	target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+}
@@ -61,6 +61,15 @@ func Test_NewPagination(t *testing.T) {
 				Per:  25,
 			},
 		},
+		{
+			name: "Cursor",
+			path: "/?cursor=abc&per=10",
+			expected: &Pagination{
+				Page:   1,
+				Per:    10,
+				Cursor: "abc",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -69,6 +78,39 @@ func Test_NewPagination(t *testing.T) {
 			paginator := NewPagination(request)
 			assert.Equal(t, tt.expected.Page, paginator.Page)
 			assert.Equal(t, tt.expected.Per, paginator.Per)
+			assert.Equal(t, tt.expected.Cursor, paginator.Cursor)
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     int64
+		per      int64
+		expected *Pagination
+	}{
+		{
+			name:     "Valid values",
+			page:     3,
+			per:      50,
+			expected: &Pagination{Page: 3, Per: 50},
+		},
+		{
+			name:     "Zero values",
+			expected: &Pagination{Page: DefaultPage, Per: DefaultPerPage},
+		},
+		{
+			name:     "Per exceeds MaxPerPage",
+			page:     1,
+			per:      MaxPerPage + 1,
+			expected: &Pagination{Page: 1, Per: MaxPerPage},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, New(tt.page, tt.per))
 		})
 	}
 }
@@ -119,3 +161,84 @@ func Test_Pagination_Offset(t *testing.T) {
 		})
 	}
 }
+
+func Test_Links(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		total    int64
+		expected string
+	}{
+		{
+			name:     "Single page",
+			path:     "/api/v2/user/urls",
+			total:    3,
+			expected: `</api/v2/user/urls?page=1&per=25>; rel="first", </api/v2/user/urls?page=1&per=25>; rel="last"`,
+		},
+		{
+			name:  "First page",
+			path:  "/api/v2/user/urls?per=10",
+			total: 25,
+			expected: `</api/v2/user/urls?page=1&per=10>; rel="first", ` +
+				`</api/v2/user/urls?page=2&per=10>; rel="next", ` +
+				`</api/v2/user/urls?page=3&per=10>; rel="last"`,
+		},
+		{
+			name:  "Middle page keeps filters",
+			path:  "/api/v2/user/urls?page=2&per=10&q=go",
+			total: 25,
+			expected: `</api/v2/user/urls?page=1&per=10&q=go>; rel="first", ` +
+				`</api/v2/user/urls?page=1&per=10&q=go>; rel="prev", ` +
+				`</api/v2/user/urls?page=3&per=10&q=go>; rel="next", ` +
+				`</api/v2/user/urls?page=3&per=10&q=go>; rel="last"`,
+		},
+		{
+			name:  "Page beyond the last",
+			path:  "/api/v2/user/urls?page=5&per=10",
+			total: 0,
+			expected: `</api/v2/user/urls?page=1&per=10>; rel="first", ` +
+				`</api/v2/user/urls?page=1&per=10>; rel="prev", ` +
+				`</api/v2/user/urls?page=1&per=10>; rel="last"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", tt.path, nil)
+			paginator := NewPagination(request)
+			assert.Equal(t, tt.expected, paginator.Links(request.URL, tt.total))
+		})
+	}
+}
+
+func Test_NextLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "First page",
+			path:     "/api/user/urls?per=10",
+			expected: `</api/user/urls?cursor=next&per=10>; rel="next"`,
+		},
+		{
+			name:     "Page number is replaced",
+			path:     "/api/user/urls?page=3&per=10",
+			expected: `</api/user/urls?cursor=next&per=10>; rel="next"`,
+		},
+		{
+			name:     "Cursor is replaced",
+			path:     "/api/user/urls?cursor=previous",
+			expected: `</api/user/urls?cursor=next&per=25>; rel="next"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", tt.path, nil)
+			paginator := NewPagination(request)
+			assert.Equal(t, tt.expected, paginator.NextLink(request.URL, "next"))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/service"
+)
+
+// StatsHandler is a handler for short link statistics
+type StatsHandler struct {
+	service *service.AnalyticsService
+}
+
+// NewStatsHandler creates a new StatsHandler
+func NewStatsHandler(service *service.AnalyticsService) *StatsHandler {
+	return &StatsHandler{service: service}
+}
+
+// HandleGetURLStats handles short link statistics retrieval
+func (h *StatsHandler) HandleGetURLStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	shortCode := chi.URLParam(r, "id")
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+
+	stats, err := h.service.GetURLStats(r.Context(), shortCode, days)
+	if err != nil {
+		if errors.Is(err, errors.ErrShortLinkNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/service"
+	"shortly/internal/app/worker"
+)
+
+func Test_HandleGetURLStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockRepository(ctrl)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewStatsHandler(analytics)
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	type result struct {
+		response dto.URLStatsResponse
+		error    dto.ErrorResponse
+		code     int
+		status   string
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		before   func()
+		expected result
+	}{
+		{
+			name: "Success",
+			path: "/api/user/urls/abcd1234/stats?days=7",
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(&repository.URL{
+					UUID:      UUID,
+					LongURL:   "https://google.com",
+					ShortCode: "abcd1234",
+					UserUUID:  UserUUID,
+				}, true)
+				repo.EXPECT().GetClickStats(gomock.Any(), UUID, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ uuid.UUID, since time.Time) (*repository.ClickStats, error) {
+						today := time.Now().UTC().Truncate(24 * time.Hour)
+						assert.Equal(t, today.AddDate(0, 0, -6), since)
+
+						return &repository.ClickStats{
+							Total:  5,
+							Unique: 3,
+							Daily:  []repository.DailyClicks{{Date: day, Total: 5, Unique: 3}},
+						}, nil
+					})
+			},
+			expected: result{
+				response: dto.URLStatsResponse{
+					ShortURL:       "http://localhost:8080/abcd1234",
+					OriginalURL:    "https://google.com",
+					TotalClicks:    5,
+					UniqueVisitors: 3,
+					Daily:          []dto.DailyClicksResponse{{Date: "2025-03-01", Clicks: 5, UniqueVisitors: 3}},
+				},
+				status: "200 OK",
+				code:   http.StatusOK,
+			},
+		},
+		{
+			name: "Not found",
+			path: "/api/user/urls/abcd1234/stats",
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrShortLinkNotFound.Error()},
+				status: "404 Not Found",
+				code:   http.StatusNotFound,
+			},
+		},
+		{
+			name: "Error",
+			path: "/api/user/urls/abcd1234/stats",
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(&repository.URL{
+					UUID:      UUID,
+					ShortCode: "abcd1234",
+					UserUUID:  UserUUID,
+				}, true)
+				repo.EXPECT().GetClickStats(gomock.Any(), UUID, gomock.Any()).Return(nil, assert.AnError)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrFailedToLoadStats.Error()},
+				status: "500 Internal Server Error",
+				code:   http.StatusInternalServerError,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Get("/api/user/urls/{id}/stats", handler.HandleGetURLStats)
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if tt.expected.error.Error != "" {
+				var actual dto.ErrorResponse
+				err := json.NewDecoder(resp.Body).Decode(&actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.error.Error, actual.Error)
+			} else {
+				var actual dto.URLStatsResponse
+				err := json.NewDecoder(resp.Body).Decode(&actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.response, actual)
+			}
+			assert.Equal(t, tt.expected.status, resp.Status)
+			assert.Equal(t, tt.expected.code, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/service"
+)
+
+// TokenHandler is a handler for API token operations
+type TokenHandler struct {
+	service *service.TokenService
+}
+
+// NewTokenHandler creates a new TokenHandler
+func NewTokenHandler(service *service.TokenService) *TokenHandler {
+	return &TokenHandler{service: service}
+}
+
+// HandleCreateToken handles API token creation
+func (h *TokenHandler) HandleCreateToken(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params dto.CreateAPITokenRequest
+
+	if err := params.Validate(r.Body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	token, err := h.service.Create(r.Context(), params)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(token)
+}
+
+// HandleGetTokens handles retrieval of the user API tokens
+func (h *TokenHandler) HandleGetTokens(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	tokens, err := h.service.List(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// HandleRevokeToken handles API token revocation
+func (h *TokenHandler) HandleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: errors.ErrAPITokenNotFound.Error()})
+		return
+	}
+
+	if err = h.service.Revoke(r.Context(), id); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if errors.Is(err, errors.ErrAPITokenNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/service"
+)
+
+func Test_HandleCreateToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	handler := NewTokenHandler(service.NewTokenService(repo, rand))
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	TokenUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	type result struct {
+		error  dto.ErrorResponse
+		scopes []string
+		code   int
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		before   func()
+		expected result
+	}{
+		{
+			name: "Success",
+			body: `{"name": "ci", "scopes": ["links:read"]}`,
+			before: func() {
+				rand.EXPECT().UUID().Return(TokenUUID, nil)
+				repo.EXPECT().CreateAPIToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, token repository.APIToken) (*repository.APIToken, error) {
+						token.CreatedAt = time.Now()
+						return &token, nil
+					})
+			},
+			expected: result{
+				scopes: []string{dto.ScopeLinksRead},
+				code:   http.StatusCreated,
+			},
+		},
+		{
+			name:   "Invalid scope",
+			body:   `{"name": "ci", "scopes": ["admin"]}`,
+			before: func() {},
+			expected: result{
+				error: dto.ErrorResponse{Error: errors.ErrInvalidScope.Error()},
+				code:  http.StatusBadRequest,
+			},
+		},
+		{
+			name: "Error",
+			body: `{"name": "ci"}`,
+			before: func() {
+				rand.EXPECT().UUID().Return(TokenUUID, nil)
+				repo.EXPECT().CreateAPIToken(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+			},
+			expected: result{
+				error: dto.ErrorResponse{Error: assert.AnError.Error()},
+				code:  http.StatusInternalServerError,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/tokens", strings.NewReader(tt.body))
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			handler.HandleCreateToken(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expected.code, resp.StatusCode)
+
+			if tt.expected.error.Error != "" {
+				var actual dto.ErrorResponse
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+				assert.Equal(t, tt.expected.error, actual)
+				return
+			}
+
+			var actual dto.CreateAPITokenResponse
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+			assert.Equal(t, TokenUUID.String(), actual.UUID)
+			assert.Equal(t, tt.expected.scopes, actual.Scopes)
+			assert.True(t, strings.HasPrefix(actual.Token, service.APITokenPrefix))
+		})
+	}
+}
+
+func Test_HandleGetTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	handler := NewTokenHandler(service.NewTokenService(repo, service.NewMockSecureRandomGenerator(ctrl)))
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	TokenUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	tests := []struct {
+		name     string
+		before   func()
+		expected int
+	}{
+		{
+			name: "Success",
+			before: func() {
+				repo.EXPECT().GetAPITokensByUserID(gomock.Any(), UserUUID).Return([]repository.APIToken{
+					{UUID: TokenUUID, Name: "ci", Prefix: "shk_abcdefgh", Hash: "hash", Scopes: dto.Scopes},
+				}, nil)
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name: "Error",
+			before: func() {
+				repo.EXPECT().GetAPITokensByUserID(gomock.Any(), UserUUID).Return(nil, assert.AnError)
+			},
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/tokens", nil)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			handler.HandleGetTokens(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expected, resp.StatusCode)
+
+			if tt.expected == http.StatusOK {
+				var actual []map[string]interface{}
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+				assert.Len(t, actual, 1)
+				assert.Equal(t, "shk_abcdefgh", actual[0]["prefix"])
+				assert.NotContains(t, actual[0], "token")
+				assert.NotContains(t, actual[0], "hash")
+			}
+		})
+	}
+}
+
+func Test_HandleRevokeToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	handler := NewTokenHandler(service.NewTokenService(repo, service.NewMockSecureRandomGenerator(ctrl)))
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	TokenUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	tests := []struct {
+		name     string
+		path     string
+		before   func()
+		expected int
+	}{
+		{
+			name: "Success",
+			path: "/api/user/tokens/" + TokenUUID.String(),
+			before: func() {
+				repo.EXPECT().RevokeAPIToken(gomock.Any(), UserUUID, TokenUUID).Return(nil)
+			},
+			expected: http.StatusNoContent,
+		},
+		{
+			name: "Not found",
+			path: "/api/user/tokens/" + TokenUUID.String(),
+			before: func() {
+				repo.EXPECT().RevokeAPIToken(gomock.Any(), UserUUID, TokenUUID).Return(errors.ErrAPITokenNotFound)
+			},
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "Malformed ID",
+			path:     "/api/user/tokens/not-a-uuid",
+			before:   func() {},
+			expected: http.StatusNotFound,
+		},
+		{
+			name: "Error",
+			path: "/api/user/tokens/" + TokenUUID.String(),
+			before: func() {
+				repo.EXPECT().RevokeAPIToken(gomock.Any(), UserUUID, TokenUUID).Return(assert.AnError)
+			},
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			req := httptest.NewRequest(http.MethodDelete, tt.path, nil)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Delete("/api/user/tokens/{id}", handler.HandleRevokeToken)
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expected, resp.StatusCode)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -15,13 +17,14 @@ import (
 
 // URLHandler is a handler for URL operations
 type URLHandler struct {
-	cfg     *config.Config
-	service *service.URLService
+	cfg       *config.Config
+	service   *service.URLService
+	analytics *service.AnalyticsService
 }
 
 // NewURLHandler creates a new URLHandler
-func NewURLHandler(cfg *config.Config, service *service.URLService) *URLHandler {
-	return &URLHandler{cfg: cfg, service: service}
+func NewURLHandler(cfg *config.Config, service *service.URLService, analytics *service.AnalyticsService) *URLHandler {
+	return &URLHandler{cfg: cfg, service: service, analytics: analytics}
 }
 
 // HandleCreateShortLink handles short link creation
@@ -36,7 +39,7 @@ func (h *URLHandler) HandleCreateShortLink(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	shortURL, err := h.service.CreateShortLink(r.Context(), params.URL)
+	shortURL, err := h.service.CreateShortLink(r.Context(), params)
 	if err != nil {
 		if errors.Is(err, errors.ErrURLAlreadyExists) {
 			w.WriteHeader(http.StatusConflict)
@@ -44,6 +47,18 @@ func (h *URLHandler) HandleCreateShortLink(w http.ResponseWriter, r *http.Reques
 			return
 		}
 
+		if errors.Is(err, errors.ErrAliasAlreadyExists) {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		if errors.Is(err, errors.ErrInvalidAlias) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
 		return
@@ -65,14 +80,42 @@ func (h *URLHandler) HandleBatchCreateShortLink(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	results, err := h.service.CreateShortLinks(r.Context(), params)
+	atomic, err := strconv.ParseBool(r.URL.Query().Get("atomic"))
+	if err != nil && r.URL.Query().Has("atomic") {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: errors.ErrInvalidAtomic.Error()})
+		return
+	}
+
+	results, err := h.service.CreateShortLinks(r.Context(), params, atomic)
 	if err != nil {
+		if errors.Is(err, errors.ErrAliasAlreadyExists) {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		if errors.Is(err, errors.ErrInvalidAlias) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	// NOTE: the batch is created when at least one item is, every item is reported anyway
+	status := http.StatusConflict
+	for _, result := range results {
+		if result.Error == "" && !result.Conflict {
+			status = http.StatusCreated
+			break
+		}
+	}
+
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(results)
 }
 
@@ -95,6 +138,12 @@ func (h *URLHandler) HandleGetShortLink(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if result.IsExpired(time.Now()) {
+		w.WriteHeader(http.StatusGone)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: errors.ErrShortLinkExpired.Error()})
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(dto.GetShortLinkResponse{Result: result.LongURL})
 }
@@ -105,30 +154,92 @@ func (h *URLHandler) HandleGetUserURLs(w http.ResponseWriter, r *http.Request) {
 
 	paginator := pagination.NewPagination(r)
 
-	urls, _, err := h.service.GetUserURLs(r.Context(), paginator)
+	urls, next, err := h.service.GetUserURLs(r.Context(), paginator)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		if errors.Is(err, errors.ErrInvalidCursor) {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
 		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	if next != "" {
+		w.Header().Set("Link", paginator.NextLink(r.URL, next))
+	}
+
 	if len(urls) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
-	// TODO: use paginated response
-	// response := dto.PaginatedResponse{
-	//	Data:  urls,
-	//	Page:  paginator.Page,
-	//	Per:   paginator.Per,
-	//	Total: total,
-	// }
-
+	// NOTE: the plain list is kept for existing clients, the paginated envelope is served by HandleListUserURLs
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(urls)
 }
 
+// HandleListUserURLs handles paginated, filtered and sorted user URLs retrieval
+func (h *URLHandler) HandleListUserURLs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params dto.ListUserURLsRequest
+
+	if err := params.Validate(r.URL.Query()); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	paginator := pagination.NewPagination(r)
+
+	urls, total, err := h.service.FindUserURLs(r.Context(), paginator, params)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.Header().Set("Link", paginator.Links(r.URL, int64(total)))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.PaginatedResponse{
+		Data:  urls,
+		Page:  int(paginator.Page),
+		Per:   int(paginator.Per),
+		Total: total,
+	})
+}
+
+// HandleUpdateUserURL handles short link original URL update
+func (h *URLHandler) HandleUpdateUserURL(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params dto.UpdateShortLinkRequest
+
+	if err := params.Validate(r.Body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result, err := h.service.UpdateUserURL(r.Context(), chi.URLParam(r, "id"), params)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrShortLinkNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, errors.ErrURLAlreadyExists):
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
 // HandleBatchDeleteUserURLs handles short link deletion
 func (h *URLHandler) HandleBatchDeleteUserURLs(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -141,14 +252,60 @@ func (h *URLHandler) HandleBatchDeleteUserURLs(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	err := h.service.DeleteUserURLs(r.Context(), params)
+	job, err := h.service.DeleteUserURLs(r.Context(), params)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	w.Header().Set("Location", "/api/user/jobs/"+job.ID.String())
 	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// HandleGetDeleteJob handles delete job status retrieval
+func (h *URLHandler) HandleGetDeleteJob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	job, err := h.service.GetDeleteJob(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, errors.ErrDeleteJobNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(job)
+}
+
+// HandleRestoreUserURLs handles restoration of deleted user URLs
+func (h *URLHandler) HandleRestoreUserURLs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params dto.BatchRestoreShortLinkRequest
+
+	if err := params.Validate(r.Body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result, err := h.service.RestoreUserURLs(r.Context(), params)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
 }
 
 // DeprecatedHandleCreateShortLink handles short link creation (text/plain endpoint)
@@ -162,7 +319,7 @@ func (h *URLHandler) DeprecatedHandleCreateShortLink(w http.ResponseWriter, r *h
 		return
 	}
 
-	shortURL, err := h.service.CreateShortLink(r.Context(), params.URL)
+	shortURL, err := h.service.CreateShortLink(r.Context(), params)
 	if err != nil {
 		if errors.Is(err, errors.ErrURLAlreadyExists) {
 			w.WriteHeader(http.StatusConflict)
@@ -199,5 +356,16 @@ func (h *URLHandler) DeprecatedHandleGetShortLink(w http.ResponseWriter, r *http
 		return
 	}
 
+	if result.IsExpired(time.Now()) {
+		http.Error(w, errors.ErrShortLinkExpired.Error(), http.StatusGone)
+		return
+	}
+
+	h.analytics.Track(result, dto.ClickEvent{
+		Referrer:   r.Referer(),
+		UserAgent:  r.UserAgent(),
+		RemoteAddr: r.RemoteAddr,
+	})
+
 	http.Redirect(w, r, result.LongURL, http.StatusTemporaryRedirect)
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	"shortly/internal/app/api/pagination"
 	"shortly/internal/app/config"
 	"shortly/internal/app/dto"
 	"shortly/internal/app/errors"
@@ -34,8 +35,10 @@ func Test_HandleCreateShortLink(t *testing.T) {
 	repo := repository.NewMockDatabase(ctrl)
 	rand := service.NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	srv := service.NewURLService(cfg, repo, rand, appWorker)
-	handler := NewURLHandler(cfg, srv)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
 
 	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
 
@@ -103,6 +106,34 @@ func Test_HandleCreateShortLink(t *testing.T) {
 				code:     http.StatusConflict,
 			},
 		},
+		{
+			name:   "Alias already exists",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"url":"https://example.com","alias":"spring-sale"}`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID, nil)
+				repo.EXPECT().GetURLByShortCode(ctx, "spring-sale").Return(&repository.URL{
+					LongURL:   "https://example.com/other",
+					ShortCode: "spring-sale",
+				}, true)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: "alias already exists"},
+				status: "409 Conflict",
+				code:   http.StatusConflict,
+			},
+		},
+		{
+			name:   "Invalid alias",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"url":"https://example.com","alias":"spring sale"}`),
+			before: func() {},
+			expected: result{
+				error:  dto.ErrorResponse{Error: "invalid alias"},
+				status: "400 Bad Request",
+				code:   http.StatusBadRequest,
+			},
+		},
 		{
 			name:   "Empty body",
 			method: http.MethodPost,
@@ -215,8 +246,10 @@ func Benchmark_HandleCreateShortLink(b *testing.B) {
 	repo := repository.NewMockDatabase(ctrl)
 	rand := service.NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	srv := service.NewURLService(cfg, repo, rand, appWorker)
-	handler := NewURLHandler(cfg, srv)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
 
 	rand.EXPECT().UUID().Return(uuid.Must(uuid.NewRandom()), nil).AnyTimes()
 	rand.EXPECT().Hex().Return("abcd1234", nil).AnyTimes()
@@ -247,8 +280,10 @@ func Test_HandleBatchCreateShortLink(t *testing.T) {
 	repo := repository.NewMockDatabase(ctrl)
 	rand := service.NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	srv := service.NewURLService(cfg, repo, rand, appWorker)
-	handler := NewURLHandler(cfg, srv)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
 
 	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
 	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
@@ -263,6 +298,7 @@ func Test_HandleBatchCreateShortLink(t *testing.T) {
 	tests := []struct {
 		name     string
 		method   string
+		query    string
 		body     io.Reader
 		before   func()
 		expected result
@@ -294,7 +330,7 @@ func Test_HandleBatchCreateShortLink(t *testing.T) {
 						ShortCode: "abcd0002",
 					},
 				}
-				repo.EXPECT().CreateURLs(ctx, urls)
+				repo.EXPECT().CreateURLs(ctx, urls).Return(urls, nil)
 			},
 			expected: result{
 				response: dto.BatchCreateShortLinkResponses{
@@ -305,6 +341,79 @@ func Test_HandleBatchCreateShortLink(t *testing.T) {
 				code:   http.StatusCreated,
 			},
 		},
+		{
+			name:   "Original URL already shortened",
+			method: http.MethodPost,
+			body:   strings.NewReader(`[{"correlation_id": "0001", "original_url": "https://github.com"}]`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().Hex().Return("abcd0001", nil)
+				repo.EXPECT().GetURLByShortCode(ctx, "abcd0001").Return(nil, false)
+
+				urls := []repository.URL{{UUID: UUID1, LongURL: "https://github.com", ShortCode: "abcd0001"}}
+				repo.EXPECT().CreateURLs(ctx, urls).Return([]repository.URL{{LongURL: "https://github.com", ShortCode: "gith0001"}}, nil)
+			},
+			expected: result{
+				response: dto.BatchCreateShortLinkResponses{
+					{CorrelationID: "0001", ShortURL: "http://localhost:8080/gith0001", Conflict: true},
+				},
+				status: "409 Conflict",
+				code:   http.StatusConflict,
+			},
+		},
+		{
+			name:   "Partial success",
+			method: http.MethodPost,
+			body: strings.NewReader(`[
+				{"correlation_id": "0001", "original_url": "https://github.com", "alias": "docs"},
+				{"correlation_id": "0002", "original_url": "https://google.com", "alias": "docs"}
+			]`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				repo.EXPECT().GetURLByShortCode(ctx, "docs").Return(nil, false)
+
+				urls := []repository.URL{{UUID: UUID1, LongURL: "https://github.com", ShortCode: "docs"}}
+				repo.EXPECT().CreateURLs(ctx, urls).Return(urls, nil)
+			},
+			expected: result{
+				response: dto.BatchCreateShortLinkResponses{
+					{CorrelationID: "0001", ShortURL: "http://localhost:8080/docs"},
+					{CorrelationID: "0002", Error: "alias already exists"},
+				},
+				status: "201 Created",
+				code:   http.StatusCreated,
+			},
+		},
+		{
+			name:   "Atomic batch with a taken alias",
+			method: http.MethodPost,
+			query:  "?atomic=true",
+			body: strings.NewReader(`[
+				{"correlation_id": "0001", "original_url": "https://github.com", "alias": "docs"},
+				{"correlation_id": "0002", "original_url": "https://google.com", "alias": "docs"}
+			]`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				repo.EXPECT().GetURLByShortCode(ctx, "docs").Return(nil, false)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: "alias already exists"},
+				status: "409 Conflict",
+				code:   http.StatusConflict,
+			},
+		},
+		{
+			name:   "Invalid atomic",
+			method: http.MethodPost,
+			query:  "?atomic=maybe",
+			body:   strings.NewReader(`[{"correlation_id": "0001", "original_url": "https://github.com"}]`),
+			before: func() {},
+			expected: result{
+				error:  dto.ErrorResponse{Error: "atomic must be true or false"},
+				status: "400 Bad Request",
+				code:   http.StatusBadRequest,
+			},
+		},
 		{
 			name:   "No correlation ID",
 			method: http.MethodPost,
@@ -371,7 +480,7 @@ func Test_HandleBatchCreateShortLink(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.before()
 
-			req := httptest.NewRequest(http.MethodPost, "/api/shorten/batch", tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/shorten/batch"+tt.query, tt.body)
 			w := httptest.NewRecorder()
 
 			handler.HandleBatchCreateShortLink(w, req)
@@ -407,8 +516,10 @@ func Test_HandleGetShortLink(t *testing.T) {
 	repo := repository.NewMockDatabase(ctrl)
 	rand := service.NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	srv := service.NewURLService(cfg, repo, rand, appWorker)
-	handler := NewURLHandler(cfg, srv)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
 
 	type result struct {
 		response dto.CreateShortLinkResponse
@@ -454,6 +565,22 @@ func Test_HandleGetShortLink(t *testing.T) {
 				code:   http.StatusGone,
 			},
 		},
+		{
+			name: "Expired",
+			path: "/api/shorten/abcd1234",
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(ctx, "abcd1234").Return(&repository.URL{
+					LongURL:   "https://example.com",
+					ShortCode: "abcd1234",
+					ExpiresAt: time.Now().Add(-time.Minute),
+				}, true)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrShortLinkExpired.Error()},
+				status: "410 Gone",
+				code:   http.StatusGone,
+			},
+		},
 		{
 			name: "Not Found",
 			path: "/api/shorten/not-a-short-code",
@@ -510,8 +637,10 @@ func Benchmark_HandleGetShortLink(b *testing.B) {
 	repo := repository.NewMockDatabase(ctrl)
 	rand := service.NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	srv := service.NewURLService(cfg, repo, rand, appWorker)
-	handler := NewURLHandler(cfg, srv)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
 
 	repo.EXPECT().GetURLByShortCode(ctx, "abcd1234").Return(&repository.URL{
 		LongURL:   "https://example.com",
@@ -540,8 +669,10 @@ func Test_HandleGetUserURLs(t *testing.T) {
 	repo := repository.NewMockDatabase(ctrl)
 	rand := service.NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	srv := service.NewURLService(cfg, repo, rand, appWorker)
-	handler := NewURLHandler(cfg, srv)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
 
 	limit := int64(25)
 	offset := int64(0)
@@ -550,10 +681,14 @@ func Test_HandleGetUserURLs(t *testing.T) {
 	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
 	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
 
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	cursor := pagination.EncodeCursor(createdAt, UUID1)
+
 	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
 
 	type result struct {
 		response []dto.GetUserURLsResponse
+		link     string
 		error    dto.ErrorResponse
 		code     int
 		status   string
@@ -561,13 +696,15 @@ func Test_HandleGetUserURLs(t *testing.T) {
 
 	tests := []struct {
 		name     string
+		target   string
 		before   func()
 		expected result
 	}{
 		{
-			name: "Success",
+			name:   "Success",
+			target: "/api/user/urls",
 			before: func() {
-				repo.EXPECT().GetURLsByUserID(ctx, UserUUID, limit, offset).Return([]repository.URL{
+				repo.EXPECT().GetURLsByUserID(gomock.Any(), UserUUID, limit, offset).Return([]repository.URL{
 					{
 						UUID:      UUID1,
 						LongURL:   "https://google.com",
@@ -596,9 +733,10 @@ func Test_HandleGetUserURLs(t *testing.T) {
 			},
 		},
 		{
-			name: "No URLs",
+			name:   "No URLs",
+			target: "/api/user/urls",
 			before: func() {
-				repo.EXPECT().GetURLsByUserID(ctx, UserUUID, limit, offset).Return(nil, 0, nil)
+				repo.EXPECT().GetURLsByUserID(gomock.Any(), UserUUID, limit, offset).Return(nil, 0, nil)
 			},
 			expected: result{
 				response: []dto.GetUserURLsResponse(nil),
@@ -607,9 +745,70 @@ func Test_HandleGetUserURLs(t *testing.T) {
 			},
 		},
 		{
-			name: "Error",
+			name:   "Next page",
+			target: "/api/user/urls?page=1&per=1",
+			before: func() {
+				repo.EXPECT().GetURLsByUserID(gomock.Any(), UserUUID, int64(1), offset).Return([]repository.URL{
+					{
+						UUID:      UUID1,
+						LongURL:   "https://google.com",
+						ShortCode: "abcd0001",
+						CreatedAt: createdAt,
+					},
+				}, 2, nil)
+			},
+			expected: result{
+				response: []dto.GetUserURLsResponse{
+					{
+						ShortURL:    "http://localhost:8080/abcd0001",
+						OriginalURL: "https://google.com",
+					},
+				},
+				link:   `</api/user/urls?cursor=` + cursor + `&per=1>; rel="next"`,
+				status: "200 OK",
+				code:   http.StatusOK,
+			},
+		},
+		{
+			name:   "Last page by cursor",
+			target: "/api/user/urls?per=1&cursor=" + cursor,
+			before: func() {
+				after := repository.URLCursor{CreatedAt: createdAt, UUID: UUID1}
+				repo.EXPECT().GetURLsByUserIDAfter(gomock.Any(), UserUUID, after, int64(2)).Return([]repository.URL{
+					{
+						UUID:      UUID2,
+						LongURL:   "https://github.com",
+						ShortCode: "abcd0002",
+						CreatedAt: createdAt.Add(-time.Hour),
+					},
+				}, nil)
+			},
+			expected: result{
+				response: []dto.GetUserURLsResponse{
+					{
+						ShortURL:    "http://localhost:8080/abcd0002",
+						OriginalURL: "https://github.com",
+					},
+				},
+				status: "200 OK",
+				code:   http.StatusOK,
+			},
+		},
+		{
+			name:   "Invalid cursor",
+			target: "/api/user/urls?cursor=invalid",
+			before: func() {},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrInvalidCursor.Error()},
+				status: "400 Bad Request",
+				code:   http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "Error",
+			target: "/api/user/urls",
 			before: func() {
-				repo.EXPECT().GetURLsByUserID(ctx, UserUUID, limit, offset).Return(nil, 0, errors.ErrFailedToLoadUserUrls)
+				repo.EXPECT().GetURLsByUserID(gomock.Any(), UserUUID, limit, offset).Return(nil, 0, errors.ErrFailedToLoadUserUrls)
 			},
 			expected: result{
 				error:  dto.ErrorResponse{Error: errors.ErrFailedToLoadUserUrls.Error()},
@@ -623,7 +822,7 @@ func Test_HandleGetUserURLs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.before()
 
-			req := httptest.NewRequest(http.MethodGet, "/api/user/urls", nil)
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
 			req = req.WithContext(ctx)
 			w := httptest.NewRecorder()
 
@@ -648,6 +847,168 @@ func Test_HandleGetUserURLs(t *testing.T) {
 				err := json.NewDecoder(resp.Body).Decode(&actual)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expected.response, actual)
+				assert.Equal(t, tt.expected.link, resp.Header.Get("Link"))
+			}
+
+			assert.Equal(t, tt.expected.status, resp.Status)
+			assert.Equal(t, tt.expected.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_HandleListUserURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	type result struct {
+		response dto.PaginatedResponse
+		link     string
+		error    dto.ErrorResponse
+		code     int
+		status   string
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		before   func()
+		expected result
+	}{
+		{
+			name:   "Success",
+			target: "/api/v2/user/urls?q=google&sort=-clicks&page=2&per=1",
+			before: func() {
+				repo.EXPECT().FindURLsByUserID(gomock.Any(), repository.URLFilter{
+					UserUUID: UserUUID,
+					Query:    "google",
+					Sort:     repository.SortClicksDesc,
+					Limit:    1,
+					Offset:   1,
+				}).Return([]repository.URLListItem{
+					{
+						URL: repository.URL{
+							UUID:      UUID1,
+							LongURL:   "https://google.com",
+							ShortCode: "abcd0001",
+							CreatedAt: createdAt,
+						},
+						Clicks: 3,
+					},
+				}, 3, nil)
+			},
+			expected: result{
+				response: dto.PaginatedResponse{
+					Data: []interface{}{
+						map[string]interface{}{
+							"short_url":    "http://localhost:8080/abcd0001",
+							"short_code":   "abcd0001",
+							"original_url": "https://google.com",
+							"clicks":       float64(3),
+							"created_at":   "2026-10-01T12:00:00Z",
+						},
+					},
+					Page:  2,
+					Per:   1,
+					Total: 3,
+				},
+				link: `</api/v2/user/urls?page=1&per=1&q=google&sort=-clicks>; rel="first", ` +
+					`</api/v2/user/urls?page=1&per=1&q=google&sort=-clicks>; rel="prev", ` +
+					`</api/v2/user/urls?page=3&per=1&q=google&sort=-clicks>; rel="next", ` +
+					`</api/v2/user/urls?page=3&per=1&q=google&sort=-clicks>; rel="last"`,
+				status: "200 OK",
+				code:   http.StatusOK,
+			},
+		},
+		{
+			name:   "No URLs",
+			target: "/api/v2/user/urls",
+			before: func() {
+				repo.EXPECT().FindURLsByUserID(gomock.Any(), repository.URLFilter{
+					UserUUID: UserUUID,
+					Sort:     repository.SortCreatedAtDesc,
+					Limit:    25,
+				}).Return(nil, 0, nil)
+			},
+			expected: result{
+				response: dto.PaginatedResponse{
+					Data:  []interface{}{},
+					Page:  1,
+					Per:   25,
+					Total: 0,
+				},
+				link: `</api/v2/user/urls?page=1&per=25>; rel="first", ` +
+					`</api/v2/user/urls?page=1&per=25>; rel="last"`,
+				status: "200 OK",
+				code:   http.StatusOK,
+			},
+		},
+		{
+			name:   "Invalid sort",
+			target: "/api/v2/user/urls?sort=original_url",
+			before: func() {},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrInvalidSort.Error()},
+				status: "400 Bad Request",
+				code:   http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "Error",
+			target: "/api/v2/user/urls",
+			before: func() {
+				repo.EXPECT().FindURLsByUserID(gomock.Any(), gomock.Any()).Return(nil, 0, errors.ErrFailedToLoadUserUrls)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrFailedToLoadUserUrls.Error()},
+				status: "500 Internal Server Error",
+				code:   http.StatusInternalServerError,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			handler.HandleListUserURLs(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			switch tt.expected.code {
+			case http.StatusInternalServerError, http.StatusBadRequest:
+				var actual dto.ErrorResponse
+				err := json.NewDecoder(resp.Body).Decode(&actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.error.Error, actual.Error)
+
+			default:
+				var actual dto.PaginatedResponse
+				err := json.NewDecoder(resp.Body).Decode(&actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.response, actual)
+				assert.Equal(t, tt.expected.link, resp.Header.Get("Link"))
 			}
 
 			assert.Equal(t, tt.expected.status, resp.Status)
@@ -656,6 +1017,144 @@ func Test_HandleGetUserURLs(t *testing.T) {
 	}
 }
 
+func Test_HandleUpdateUserURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+	url := repository.URL{
+		LongURL:   "https://example.com",
+		ShortCode: "abcd0001",
+		UserUUID:  UserUUID,
+	}
+
+	type result struct {
+		response dto.GetUserURLsResponse
+		error    dto.ErrorResponse
+		code     int
+		status   string
+	}
+
+	tests := []struct {
+		name     string
+		body     io.Reader
+		before   func()
+		expected result
+	}{
+		{
+			name: "Success",
+			body: strings.NewReader(`{"url":"https://example.com"}`),
+			before: func() {
+				repo.EXPECT().UpdateURL(gomock.Any(), url).Return(&repository.URL{
+					UUID:      UUID,
+					LongURL:   "https://example.com",
+					ShortCode: "abcd0001",
+					UserUUID:  UserUUID,
+				}, nil)
+			},
+			expected: result{
+				response: dto.GetUserURLsResponse{
+					ShortURL:    "http://localhost:8080/abcd0001",
+					OriginalURL: "https://example.com",
+				},
+				status: "200 OK",
+				code:   http.StatusOK,
+			},
+		},
+		{
+			name:   "Invalid URL",
+			body:   strings.NewReader(`{"url":""}`),
+			before: func() {},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrOriginalURLEmpty.Error()},
+				status: "400 Bad Request",
+				code:   http.StatusBadRequest,
+			},
+		},
+		{
+			name: "Not found",
+			body: strings.NewReader(`{"url":"https://example.com"}`),
+			before: func() {
+				repo.EXPECT().UpdateURL(gomock.Any(), url).Return(nil, errors.ErrShortLinkNotFound)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrShortLinkNotFound.Error()},
+				status: "404 Not Found",
+				code:   http.StatusNotFound,
+			},
+		},
+		{
+			name: "Conflict",
+			body: strings.NewReader(`{"url":"https://example.com"}`),
+			before: func() {
+				repo.EXPECT().UpdateURL(gomock.Any(), url).Return(nil, errors.ErrURLAlreadyExists)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrURLAlreadyExists.Error()},
+				status: "409 Conflict",
+				code:   http.StatusConflict,
+			},
+		},
+		{
+			name: "Error",
+			body: strings.NewReader(`{"url":"https://example.com"}`),
+			before: func() {
+				repo.EXPECT().UpdateURL(gomock.Any(), url).Return(nil, assert.AnError)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrFailedToUpdateURL.Error()},
+				status: "500 Internal Server Error",
+				code:   http.StatusInternalServerError,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/user/urls/abcd0001", tt.body)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Patch("/api/user/urls/{id}", handler.HandleUpdateUserURL)
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if tt.expected.error.Error != "" {
+				var actual dto.ErrorResponse
+				err := json.NewDecoder(resp.Body).Decode(&actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.error.Error, actual.Error)
+			} else {
+				var actual dto.GetUserURLsResponse
+				err := json.NewDecoder(resp.Body).Decode(&actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.response, actual)
+			}
+			assert.Equal(t, tt.expected.status, resp.Status)
+			assert.Equal(t, tt.expected.code, resp.StatusCode)
+		})
+	}
+}
+
 func Test_HandleBatchDeleteUserURLs(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -666,15 +1165,19 @@ func Test_HandleBatchDeleteUserURLs(t *testing.T) {
 	repo := repository.NewMockDatabase(ctrl)
 	rand := service.NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	srv := service.NewURLService(cfg, repo, rand, appWorker)
-	handler := NewURLHandler(cfg, srv)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
 
 	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	JobUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
 
 	type result struct {
-		error  dto.ErrorResponse
-		code   int
-		status string
+		error    dto.ErrorResponse
+		code     int
+		status   string
+		location string
 	}
 
 	tests := []struct {
@@ -689,14 +1192,31 @@ func Test_HandleBatchDeleteUserURLs(t *testing.T) {
 			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
 			body: strings.NewReader(`["abcd0001", "abcd0002"]`),
 			before: func() {
-				appWorker.EXPECT().Add(dto.BatchDeleteParams{
+				appWorker.EXPECT().Add(gomock.Any(), dto.BatchDeleteParams{
 					UserID:     UserUUID,
 					ShortCodes: []string{"abcd0001", "abcd0002"},
-				})
+				}).Return(worker.DeleteJob{ID: JobUUID, UserID: UserUUID, Status: dto.JobStatusQueued}, nil)
 			},
 			expected: result{
-				status: "202 Accepted",
-				code:   http.StatusAccepted,
+				status:   "202 Accepted",
+				code:     http.StatusAccepted,
+				location: "/api/user/jobs/6455bd07-e431-4851-af3c-4f703f720001",
+			},
+		},
+		{
+			name: "Queue error",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			body: strings.NewReader(`["abcd0001"]`),
+			before: func() {
+				appWorker.EXPECT().Add(gomock.Any(), dto.BatchDeleteParams{
+					UserID:     UserUUID,
+					ShortCodes: []string{"abcd0001"},
+				}).Return(worker.DeleteJob{}, errors.ErrFailedToDeleteURLs)
+			},
+			expected: result{
+				error:  dto.ErrorResponse{Error: errors.ErrFailedToDeleteURLs.Error()},
+				status: "500 Internal Server Error",
+				code:   http.StatusInternalServerError,
 			},
 		},
 		{
@@ -745,6 +1265,12 @@ func Test_HandleBatchDeleteUserURLs(t *testing.T) {
 				assert.Equal(t, tt.expected.error.Error, actual.Error)
 
 			default:
+				var actual dto.DeleteJobResponse
+				err := json.NewDecoder(resp.Body).Decode(&actual)
+				assert.NoError(t, err)
+				assert.Equal(t, JobUUID, actual.ID)
+				assert.Equal(t, dto.JobStatusQueued, actual.Status)
+				assert.Equal(t, tt.expected.location, resp.Header.Get("Location"))
 				assert.Equal(t, tt.expected.status, resp.Status)
 				assert.Equal(t, tt.expected.code, resp.StatusCode)
 			}
@@ -752,6 +1278,185 @@ func Test_HandleBatchDeleteUserURLs(t *testing.T) {
 	}
 }
 
+func Test_HandleGetDeleteJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	handler := NewURLHandler(cfg, srv, nil)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	JobUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	type result struct {
+		body string
+		code int
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		id       string
+		before   func()
+		expected result
+	}{
+		{
+			name: "Success",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			id:   JobUUID.String(),
+			before: func() {
+				appWorker.EXPECT().Job(gomock.Any(), UserUUID, JobUUID).Return(worker.DeleteJob{
+					ID:         JobUUID,
+					UserID:     UserUUID,
+					Status:     dto.JobStatusDone,
+					ShortCodes: []string{"abcd0001", "abcd0002"},
+					Deleted:    []string{"abcd0001"},
+					Skipped:    []dto.SkippedShortCode{{ShortCode: "abcd0002", Reason: dto.SkipReasonNotOwned}},
+					CreatedAt:  createdAt,
+					FinishedAt: createdAt,
+				}, true)
+			},
+			expected: result{
+				body: `{"id":"6455bd07-e431-4851-af3c-4f703f720001","status":"done","requested":2,"deleted":1,` +
+					`"not_owned":1,"not_found":0,"already_deleted":0,"skipped":[{"short_code":"abcd0002","reason":"not_owned"}],` +
+					`"created_at":"2026-10-17T12:00:00Z","finished_at":"2026-10-17T12:00:00Z"}` + "\n",
+				code: http.StatusOK,
+			},
+		},
+		{
+			name: "Not found",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			id:   JobUUID.String(),
+			before: func() {
+				appWorker.EXPECT().Job(gomock.Any(), UserUUID, JobUUID).Return(worker.DeleteJob{}, false)
+			},
+			expected: result{
+				body: `{"error":"delete job not found"}` + "\n",
+				code: http.StatusNotFound,
+			},
+		},
+		{
+			name:   "Anonymous user",
+			ctx:    context.Background(),
+			id:     JobUUID.String(),
+			before: func() {},
+			expected: result{
+				body: `{"error":"invalid user id"}` + "\n",
+				code: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/jobs/"+tt.id, nil)
+			req = req.WithContext(context.WithValue(tt.ctx, chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.HandleGetDeleteJob(w, req)
+
+			assert.Equal(t, tt.expected.code, w.Code)
+			assert.Equal(t, tt.expected.body, w.Body.String())
+		})
+	}
+}
+
+func Test_HandleRestoreUserURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := gomock.Any()
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	type result struct {
+		body string
+		code int
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		body     io.Reader
+		before   func()
+		expected result
+	}{
+		{
+			name: "Success",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			body: strings.NewReader(`["abcd0001", "abcd0002"]`),
+			before: func() {
+				repo.EXPECT().RestoreURLsByUserID(ctx, UserUUID, []string{"abcd0001", "abcd0002"}, gomock.Any()).
+					Return([]string{"abcd0002"}, nil)
+			},
+			expected: result{
+				body: `{"restored":["abcd0002"]}` + "\n",
+				code: http.StatusOK,
+			},
+		},
+		{
+			name:   "Empty",
+			ctx:    context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			body:   strings.NewReader("[]"),
+			before: func() {},
+			expected: result{
+				body: `{"error":"short code is required"}` + "\n",
+				code: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "Storage error",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			body: strings.NewReader(`["abcd0001"]`),
+			before: func() {
+				repo.EXPECT().RestoreURLsByUserID(ctx, UserUUID, []string{"abcd0001"}, gomock.Any()).Return(nil, assert.AnError)
+			},
+			expected: result{
+				body: `{"error":"failed to restore URLs"}` + "\n",
+				code: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/urls/restore", tt.body)
+			req = req.WithContext(tt.ctx)
+			w := httptest.NewRecorder()
+
+			handler.HandleRestoreUserURLs(w, req)
+
+			assert.Equal(t, tt.expected.code, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expected.body, w.Body.String())
+		})
+	}
+}
+
 func Test_DeprecatedHandleCreateShortLink(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -763,8 +1468,10 @@ func Test_DeprecatedHandleCreateShortLink(t *testing.T) {
 	repo := repository.NewMockDatabase(ctrl)
 	rand := service.NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	srv := service.NewURLService(cfg, repo, rand, appWorker)
-	handler := NewURLHandler(cfg, srv)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
 
 	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
 
@@ -904,8 +1611,12 @@ func Test_DeprecatedHandleGetShortLink(t *testing.T) {
 	repo := repository.NewMockDatabase(ctrl)
 	rand := service.NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	srv := service.NewURLService(cfg, repo, rand, appWorker)
-	handler := NewURLHandler(cfg, srv)
+	srv := service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	handler := NewURLHandler(cfg, srv, analytics)
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
 
 	type result struct {
 		status   int
@@ -924,9 +1635,16 @@ func Test_DeprecatedHandleGetShortLink(t *testing.T) {
 			path: "/abcd1234",
 			before: func() {
 				repo.EXPECT().GetURLByShortCode(ctx, "abcd1234").Return(&repository.URL{
+					UUID:      UUID,
 					LongURL:   "https://example.com",
 					ShortCode: "abcd1234",
 				}, true)
+				recorder.EXPECT().Record(gomock.Any()).Do(func(click repository.Click) {
+					assert.Equal(t, UUID, click.URLUUID)
+					assert.Equal(t, "https://google.com", click.Referrer)
+					assert.Equal(t, "Mozilla/5.0", click.UserAgent)
+					assert.Len(t, click.IPHash, 64)
+				})
 			},
 			expected: result{
 				status: http.StatusTemporaryRedirect,
@@ -948,6 +1666,21 @@ func Test_DeprecatedHandleGetShortLink(t *testing.T) {
 				response: errors.ErrShortLinkDeleted.Error(),
 			},
 		},
+		{
+			name: "Expired",
+			path: "/abcd1234",
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(ctx, "abcd1234").Return(&repository.URL{
+					LongURL:   "https://example.com",
+					ShortCode: "abcd1234",
+					Expired:   true,
+				}, true)
+			},
+			expected: result{
+				status:   http.StatusGone,
+				response: errors.ErrShortLinkExpired.Error(),
+			},
+		},
 		{
 			name: "Not Found",
 			path: "/not-a-short-code",
@@ -966,6 +1699,8 @@ func Test_DeprecatedHandleGetShortLink(t *testing.T) {
 			tt.before()
 
 			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Referer", "https://google.com")
+			req.Header.Set("User-Agent", "Mozilla/5.0")
 			w := httptest.NewRecorder()
 
 			r := chi.NewRouter()
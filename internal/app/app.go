@@ -6,10 +6,14 @@ import (
 	"time"
 
 	"shortly/internal/app/config"
+	"shortly/internal/app/errors"
 	"shortly/internal/app/repository"
 	"shortly/internal/app/repository/persistence"
 	"shortly/internal/app/router"
+	"shortly/internal/app/rpc"
 	"shortly/internal/app/server"
+	"shortly/internal/app/service"
+	"shortly/internal/app/tracing"
 	"shortly/internal/app/version"
 	"shortly/internal/app/worker"
 	"shortly/internal/logger"
@@ -20,36 +24,79 @@ type Application struct {
 	cfg                *config.Config
 	logger             *logger.Logger
 	persistenceManager persistence.Manager
-	deleteWorker       worker.Worker
+	scheduler          worker.Scheduler
 	server             server.Server
 	pprofServer        server.PprofServer
+	grpcServer         server.GRPCServer
+	tracing            tracing.Provider
 }
 
 // NewApplication creates a new application instance
 func NewApplication(ctx context.Context) (*Application, error) {
-	cfg := config.LoadConfig()
-	appLogger := logger.NewLogger()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	appLogger, err := logger.New(logger.Options{
+		Format: cfg.LogFormat,
+		Level:  cfg.LogLevel,
+		Output: cfg.LogOutput,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// NOTE: the default logger is used by code logging through a context outside of requests
+	logger.SetDefault(appLogger)
+
+	tracingProvider, err := tracing.Setup(ctx, cfg, appLogger)
+	if err != nil {
+		appLogger.Error().Err(err).Msg("Failed to initialize tracing")
+		return nil, err
+	}
 
 	appRepository, err := initRepository(ctx, cfg, appLogger)
 	if err != nil {
 		return nil, err
 	}
-	persistenceManager := persistence.NewPersistenceManager(cfg, appRepository, appLogger)
 
-	deleteWorker := worker.NewDeleteWorker(ctx, cfg, appRepository, appLogger)
-	deleteWorker.Start()
+	scheduler := worker.NewScheduler(ctx, cfg, appLogger)
+	persistenceManager := persistence.NewPersistenceManager(cfg, appRepository, scheduler, appLogger)
+	deleteWorker := worker.NewDeleteWorker(ctx, cfg, appRepository, scheduler, appLogger)
+
+	expirySweeper := worker.NewExpirySweeper(cfg, appRepository, appLogger)
+	scheduler.Schedule(expirySweeper.Schedule(), expirySweeper)
+
+	purger := worker.NewPurger(cfg, appRepository, appLogger)
+	scheduler.Schedule(purger.Schedule(), purger)
+
+	clickRecorder := worker.NewClickRecorder(ctx, cfg, appRepository, scheduler, appLogger)
+
+	// NOTE: the HTTP and gRPC servers share the URL service, so a single generator hands out the short codes
+	rand := service.NewSecureRandom()
+	generator := service.NewShortCodeGenerator(cfg, appRepository, rand)
+	shortener := service.NewURLService(cfg, appRepository, rand, generator, deleteWorker)
 
-	appRouter := router.NewRouter(cfg, appRepository, deleteWorker, appLogger)
+	appRouter := router.NewRouter(cfg, appRepository, shortener, deleteWorker, clickRecorder, appLogger)
 	appServer := server.NewServer(cfg, appRouter)
 	pprofServer := server.NewPprofServer(cfg)
 
+	rpcServer, err := rpc.NewServer(cfg, shortener, appLogger)
+	if err != nil {
+		appLogger.Error().Err(err).Msg("Failed to initialize gRPC server")
+		return nil, err
+	}
+	grpcServer := server.NewGRPCServer(cfg, rpcServer)
+
 	return &Application{
 		cfg:                cfg,
 		logger:             appLogger,
 		persistenceManager: persistenceManager,
-		deleteWorker:       deleteWorker,
+		scheduler:          scheduler,
 		server:             appServer,
 		pprofServer:        pprofServer,
+		grpcServer:         grpcServer,
+		tracing:            tracingProvider,
 	}, nil
 }
 
@@ -59,7 +106,12 @@ func (a *Application) Run(ctx context.Context) error {
 		return err
 	}
 
-	serverErrors := make(chan error, 2)
+	// NOTE: jobs start once the storage is loaded, so the restored delete queue is processed
+	if err := a.scheduler.Start(); err != nil {
+		return err
+	}
+
+	serverErrors := make(chan error, 3)
 	go func() {
 		if err := a.server.Run(); err != nil && err != http.ErrServerClosed {
 			serverErrors <- err
@@ -72,6 +124,12 @@ func (a *Application) Run(ctx context.Context) error {
 		}
 	}()
 
+	go func() {
+		if err := a.grpcServer.Run(); err != nil {
+			serverErrors <- err
+		}
+	}()
+
 	appVersion := version.NewVersion()
 
 	a.logger.Info().Msgf("Build version: %s", appVersion.Version())
@@ -81,26 +139,50 @@ func (a *Application) Run(ctx context.Context) error {
 	a.logger.Info().Msgf("Application starting in %s", a.cfg.AppEnv)
 	a.logger.Info().Msgf("Listening on %s", a.cfg.Addr)
 	a.logger.Info().Msgf("Profiler on %s", a.cfg.ProfilerAddr)
+	a.logger.Info().Msgf("gRPC listening on %s", a.cfg.GRPCAddr)
 
 	select {
 	case <-ctx.Done():
 		a.logger.Info().Msg("Shutting down server...")
 
-		a.deleteWorker.Stop()
+		// NOTE: the run context is already cancelled here, the shutdown gets its own deadline to drain in-flight calls
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+
+		// NOTE: the servers are stopped first, so no request queues work or writes links once the queues are drained
+		// and the storage is saved, a failed shutdown still lets the jobs finish and the storage be saved
+		var errs []error
+		if err := a.server.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := a.pprofServer.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := a.grpcServer.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := a.scheduler.Stop(); err != nil {
+			a.logger.Error().Err(err).Msg("Failed to stop background jobs")
+		}
 
 		if err := a.persistenceManager.Save(); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 
-		shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
+		// NOTE: spans are flushed last, so the spans of the drained requests and jobs are exported,
+		// the queues may drain past the server deadline, so the flush gets its own
+		flushCtx, cancelFlush := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancelFlush()
 
-		if err := a.server.Shutdown(shutdownCtx); err != nil {
-			return err
+		if err := a.tracing.Shutdown(flushCtx); err != nil {
+			a.logger.Error().Err(err).Msg("Failed to flush traces")
 		}
 
-		if err := a.pprofServer.Shutdown(shutdownCtx); err != nil {
-			return err
+		if len(errs) > 0 {
+			return errors.Join(errs...)
 		}
 
 		a.logger.Info().Msg("Server gracefully stopped")
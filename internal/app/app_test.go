@@ -2,16 +2,22 @@ package app
 
 import (
 	"context"
+	"os"
+	"os/signal"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 
 	"shortly/internal/app/config"
 	"shortly/internal/app/repository"
 	"shortly/internal/app/repository/persistence"
 	"shortly/internal/app/server"
+	"shortly/internal/app/tracing"
 	"shortly/internal/app/worker"
 	"shortly/internal/logger"
 )
@@ -43,6 +49,9 @@ func Test_NewApplication(t *testing.T) {
 				assert.NotNil(t, app.logger)
 				assert.NotNil(t, app.server)
 				assert.NotNil(t, app.pprofServer)
+				assert.NotNil(t, app.grpcServer)
+				assert.NotNil(t, app.scheduler)
+				assert.NotNil(t, app.tracing)
 			}
 		})
 	}
@@ -113,13 +122,13 @@ func Test_Application_Run(t *testing.T) {
 		ClientURL: "http://localhost:8080",
 	}
 
-	ctx := context.Background()
 	mockPersistenceManager := persistence.NewMockManager(ctrl)
 	mockServer := server.NewMockServer(ctrl)
 	appLogger := logger.NewLogger()
 	repo := repository.NewInMemoryRepository()
-	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, appLogger)
 	mockPprofServer := server.NewMockPprofServer(ctrl)
+	mockGRPCServer := server.NewMockGRPCServer(ctrl)
+	mockTracing := tracing.NewMockProvider(ctrl)
 
 	tests := []struct {
 		name     string
@@ -138,9 +147,18 @@ func Test_Application_Run(t *testing.T) {
 					time.Sleep(100 * time.Millisecond)
 					return nil
 				})
-				mockPersistenceManager.EXPECT().Save().Return(nil)
-				mockServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
-				mockPprofServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
+				mockGRPCServer.EXPECT().Run().DoAndReturn(func() error {
+					time.Sleep(100 * time.Millisecond)
+					return nil
+				})
+				// NOTE: the servers stop before the storage is saved, so no request writes after the save
+				gomock.InOrder(
+					mockServer.EXPECT().Shutdown(gomock.Any()).Return(nil),
+					mockPprofServer.EXPECT().Shutdown(gomock.Any()).Return(nil),
+					mockGRPCServer.EXPECT().Shutdown(gomock.Any()).Return(nil),
+					mockPersistenceManager.EXPECT().Save().Return(nil),
+					mockTracing.EXPECT().Shutdown(gomock.Any()).Return(nil),
+				)
 			},
 			expected: nil,
 		},
@@ -157,18 +175,24 @@ func Test_Application_Run(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.before()
 
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			appScheduler := worker.NewScheduler(ctx, cfg, appLogger)
+			worker.NewDeleteWorker(ctx, cfg, repo, appScheduler, appLogger)
+			worker.NewClickRecorder(ctx, cfg, repo, appScheduler, appLogger)
+
 			app := &Application{
 				cfg:                &config.Config{},
 				logger:             appLogger,
 				persistenceManager: mockPersistenceManager,
-				deleteWorker:       appWorker,
+				scheduler:          appScheduler,
 				server:             mockServer,
 				pprofServer:        mockPprofServer,
+				grpcServer:         mockGRPCServer,
+				tracing:            mockTracing,
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
-			defer cancel()
-
 			err := app.Run(ctx)
 
 			if tt.expected != nil {
@@ -180,6 +204,71 @@ func Test_Application_Run(t *testing.T) {
 	}
 }
 
+func Test_Application_Run_Signal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGUSR1)
+	defer stop()
+
+	cfg := &config.Config{
+		GRPCAddr: "localhost:3282",
+	}
+	repo := repository.NewInMemoryRepository()
+	appLogger := logger.NewLogger()
+	appScheduler := worker.NewScheduler(ctx, cfg, appLogger)
+	worker.NewDeleteWorker(ctx, cfg, repo, appScheduler, appLogger)
+	worker.NewClickRecorder(ctx, cfg, repo, appScheduler, appLogger)
+
+	mockPersistenceManager := persistence.NewMockManager(ctrl)
+	mockServer := server.NewMockServer(ctrl)
+	mockPprofServer := server.NewMockPprofServer(ctrl)
+	mockTracing := tracing.NewMockProvider(ctrl)
+
+	// NOTE: the shutdown must get a live context, a cancelled one hard-stops the servers and fails the run
+	alive := func(ctx context.Context) error {
+		assert.NoError(t, ctx.Err())
+		return nil
+	}
+
+	mockPersistenceManager.EXPECT().Load().Return(nil)
+	mockServer.EXPECT().Run().Return(nil)
+	mockPprofServer.EXPECT().Run().Return(nil)
+	mockPersistenceManager.EXPECT().Save().Return(nil)
+	mockServer.EXPECT().Shutdown(gomock.Any()).DoAndReturn(alive)
+	mockPprofServer.EXPECT().Shutdown(gomock.Any()).DoAndReturn(alive)
+	mockTracing.EXPECT().Shutdown(gomock.Any()).DoAndReturn(alive)
+
+	application := &Application{
+		cfg:                cfg,
+		logger:             appLogger,
+		persistenceManager: mockPersistenceManager,
+		scheduler:          appScheduler,
+		server:             mockServer,
+		pprofServer:        mockPprofServer,
+		grpcServer:         server.NewGRPCServer(cfg, grpc.NewServer()),
+		tracing:            mockTracing,
+	}
+
+	runErrCh := make(chan error, 1)
+	go func() {
+		runErrCh <- application.Run(ctx)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(syscall.SIGUSR1))
+
+	select {
+	case err := <-runErrCh:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timeout: Run did not return after signal")
+	}
+}
+
 func Test_Application_Run_ShutdownErrors(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -192,11 +281,15 @@ func Test_Application_Run_ShutdownErrors(t *testing.T) {
 	}
 	repo := repository.NewInMemoryRepository()
 	appLogger := logger.NewLogger()
-	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, appLogger)
+	appScheduler := worker.NewScheduler(ctx, cfg, appLogger)
+	worker.NewDeleteWorker(ctx, cfg, repo, appScheduler, appLogger)
+	worker.NewClickRecorder(ctx, cfg, repo, appScheduler, appLogger)
 
 	mockPersistenceManager := persistence.NewMockManager(ctrl)
 	mockServer := server.NewMockServer(ctrl)
 	mockPprofServer := server.NewMockPprofServer(ctrl)
+	mockGRPCServer := server.NewMockGRPCServer(ctrl)
+	mockTracing := tracing.NewMockProvider(ctrl)
 
 	mockPersistenceManager.EXPECT().Load().Return(nil).AnyTimes()
 	mockServer.EXPECT().Run().DoAndReturn(func() error {
@@ -207,14 +300,20 @@ func Test_Application_Run_ShutdownErrors(t *testing.T) {
 		time.Sleep(50 * time.Millisecond)
 		return nil
 	}).AnyTimes()
+	mockGRPCServer.EXPECT().Run().DoAndReturn(func() error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}).AnyTimes()
 
 	application := &Application{
 		cfg:                cfg,
 		logger:             appLogger,
 		persistenceManager: mockPersistenceManager,
-		deleteWorker:       appWorker,
+		scheduler:          appScheduler,
 		server:             mockServer,
 		pprofServer:        mockPprofServer,
+		grpcServer:         mockGRPCServer,
+		tracing:            mockTracing,
 	}
 
 	runErrCh := make(chan error, 1)
@@ -227,24 +326,45 @@ func Test_Application_Run_ShutdownErrors(t *testing.T) {
 		{
 			name: "SaveError",
 			before: func() {
+				mockServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
+				mockPprofServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
+				mockGRPCServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
 				mockPersistenceManager.EXPECT().Save().Return(assert.AnError)
+				mockTracing.EXPECT().Shutdown(gomock.Any()).Return(nil)
 			},
 			expected: assert.AnError,
 		},
 		{
 			name: "ServerShutdownError",
 			before: func() {
-				mockPersistenceManager.EXPECT().Save().Return(nil)
+				// NOTE: a failed server shutdown still stops the other servers and saves the storage
 				mockServer.EXPECT().Shutdown(gomock.Any()).Return(assert.AnError)
+				mockPprofServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
+				mockGRPCServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
+				mockPersistenceManager.EXPECT().Save().Return(nil)
+				mockTracing.EXPECT().Shutdown(gomock.Any()).Return(nil)
 			},
 			expected: assert.AnError,
 		},
 		{
 			name: "PprofShutdownError",
 			before: func() {
-				mockPersistenceManager.EXPECT().Save().Return(nil)
 				mockServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
 				mockPprofServer.EXPECT().Shutdown(gomock.Any()).Return(assert.AnError)
+				mockGRPCServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
+				mockPersistenceManager.EXPECT().Save().Return(nil)
+				mockTracing.EXPECT().Shutdown(gomock.Any()).Return(nil)
+			},
+			expected: assert.AnError,
+		},
+		{
+			name: "GRPCShutdownError",
+			before: func() {
+				mockServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
+				mockPprofServer.EXPECT().Shutdown(gomock.Any()).Return(nil)
+				mockGRPCServer.EXPECT().Shutdown(gomock.Any()).Return(assert.AnError)
+				mockPersistenceManager.EXPECT().Save().Return(nil)
+				mockTracing.EXPECT().Shutdown(gomock.Any()).Return(nil)
 			},
 			expected: assert.AnError,
 		},
@@ -265,7 +385,7 @@ func Test_Application_Run_ShutdownErrors(t *testing.T) {
 			err := <-runErrCh
 
 			if tt.expected != nil {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -2,9 +2,12 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 
@@ -20,6 +23,84 @@ const ServerAddress = "localhost:8080"
 // ProfilerAddress is the address and port to run the profiler
 const ProfilerAddress = "localhost:2080"
 
+// GRPCAddress is the address and port to run the gRPC server
+const GRPCAddress = "localhost:3200"
+
+// ExpirySweepInterval is the interval between expired links sweeps
+const ExpirySweepInterval = time.Minute
+
+// RestoreGracePeriod is the period after deletion during which links can be restored by their owners
+const RestoreGracePeriod = 7 * 24 * time.Hour
+
+// PurgeRetention is the period after deletion after which links are permanently removed
+const PurgeRetention = 30 * 24 * time.Hour
+
+// PurgeInterval is the interval between purges of deleted links past the retention
+const PurgeInterval = time.Hour
+
+// DeleteWorkers is the default number of delete queue workers
+const DeleteWorkers = 4
+
+// DeleteMaxAttempts is the default number of attempts before a delete task is moved to the dead letters
+const DeleteMaxAttempts = 5
+
+// DeleteRetryBackoff is the default delay before the first retry of a failed delete task, doubled on every retry
+const DeleteRetryBackoff = time.Second
+
+// DeleteDrainTimeout is the default time the delete queue is drained for on shutdown
+const DeleteDrainTimeout = 10 * time.Second
+
+// DeleteBatchSize is the default maximum number of delete tasks coalesced into a single repository call
+const DeleteBatchSize = 100
+
+// DeleteBatchWindow is the default time a delete worker waits for more tasks before flushing a batch
+const DeleteBatchWindow = 50 * time.Millisecond
+
+// FileSyncPolicy is the default fsync policy of the file storage write-ahead log
+const FileSyncPolicy = "always"
+
+// FileSyncInterval is the interval between write-ahead log fsyncs with the "interval" policy
+const FileSyncInterval = time.Second
+
+// FileCompactionInterval is the interval between write-ahead log compactions into a snapshot
+const FileCompactionInterval = 10 * time.Minute
+
+// RateLimitCreate is the default rate limit of short link creation per client
+const RateLimitCreate = "60/m"
+
+// RateLimitBatch is the default rate limit of batch short link creation per client
+const RateLimitBatch = "10/m"
+
+// RateLimitRedirect is the default rate limit of short link resolution per client
+const RateLimitRedirect = "1200/m"
+
+// ShortCodeStrategy is the default short code generation strategy
+const ShortCodeStrategy = "hex"
+
+// ShortCodeLength is the default length of generated short codes
+const ShortCodeLength = 8
+
+// TraceSampleRatio is the default ratio of sampled traces
+const TraceSampleRatio = 0.1
+
+// LogFormat is the default log format, either json or console
+const LogFormat = "console"
+
+// LogLevel is the default log level
+const LogLevel = "info"
+
+// LogOutput is the default log output, stdout, stderr or a file path
+const LogOutput = "stdout"
+
+// HealthCheckTimeout is the default timeout of a single health check
+const HealthCheckTimeout = 2 * time.Second
+
+// HealthCacheTTL is the default time a health check result is reused for
+const HealthCacheTTL = 5 * time.Second
+
+// DeleteQueueSaturation is the default number of pending delete tasks reported as a saturated queue
+const DeleteQueueSaturation = 10000
+
 // Config is the application configuration
 type Config struct {
 	AppEnv          string `json:"env"`
@@ -27,6 +108,7 @@ type Config struct {
 	BaseURL         string `json:"base_url"`
 	ClientURL       string `json:"client_url"`
 	ProfilerAddr    string `json:"profiler_address"`
+	GRPCAddr        string `json:"grpc_address"`
 	FileStoragePath string `json:"file_storage_path"`
 	DatabaseDSN     string `json:"database_dsn"`
 	SecretKey       string `json:"secret_key"`
@@ -34,6 +116,50 @@ type Config struct {
 	Certificate     string `json:"certificate_path"`
 	PrivateKey      string `json:"certificate_key_path"`
 	ConfigFilePath  string
+
+	ExpirySweepInterval time.Duration `json:"expiry_sweep_interval"`
+
+	RestoreGracePeriod time.Duration `json:"restore_grace_period"`
+	PurgeRetention     time.Duration `json:"purge_retention"`
+	PurgeInterval      time.Duration `json:"purge_interval"`
+	PurgeSchedule      string        `json:"purge_schedule"`
+
+	DeleteWorkers      int           `json:"delete_workers"`
+	DeleteMaxAttempts  int           `json:"delete_max_attempts"`
+	DeleteRetryBackoff time.Duration `json:"delete_retry_backoff"`
+	DeleteDrainTimeout time.Duration `json:"delete_drain_timeout"`
+	DeleteBatchSize    int           `json:"delete_batch_size"`
+	DeleteBatchWindow  time.Duration `json:"delete_batch_window"`
+
+	FileSyncPolicy         string        `json:"file_sync_policy"`
+	FileSyncInterval       time.Duration `json:"file_sync_interval"`
+	FileCompactionInterval time.Duration `json:"file_compaction_interval"`
+
+	RateLimitCreate   string `json:"rate_limit_create"`
+	RateLimitBatch    string `json:"rate_limit_batch"`
+	RateLimitRedirect string `json:"rate_limit_redirect"`
+
+	ShortCodeStrategy string `json:"short_code_strategy"`
+	ShortCodeLength   int    `json:"short_code_length"`
+	ShortCodeSalt     string `json:"short_code_salt"`
+
+	OTLPEndpoint     string  `json:"otlp_endpoint"`
+	TraceFile        string  `json:"trace_file"`
+	TraceSampleRatio float64 `json:"trace_sample_ratio"`
+	// TracingDisabled turns the span export off, spans are still created for the trace IDs of the logs
+	TracingDisabled bool `json:"tracing_disabled"`
+
+	LogFormat string `json:"log_format"`
+	LogLevel  string `json:"log_level"`
+	LogOutput string `json:"log_output"`
+
+	HealthCheckTimeout    time.Duration `json:"health_check_timeout"`
+	HealthCacheTTL        time.Duration `json:"health_cache_ttl"`
+	DeleteQueueSaturation int64         `json:"delete_queue_saturation"`
+
+	TrustedSubnet string `json:"trusted_subnet"`
+	// TrustedProxies is a comma separated list of CIDRs of the proxies reporting the client IP in headers
+	TrustedProxies string `json:"trusted_proxies"`
 }
 
 // Flags is the flags for the configuration
@@ -48,9 +174,47 @@ type Flags struct {
 	ConfigFilePath  string
 }
 
+// UnmarshalJSON decodes the configuration file, durations are read as duration strings like "10m"
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type plain Config
+
+	// NOTE: the duration fields shadow the embedded ones and decode straight into the config
+	fields := struct {
+		*plain
+		ExpirySweepInterval    *Duration `json:"expiry_sweep_interval"`
+		RestoreGracePeriod     *Duration `json:"restore_grace_period"`
+		PurgeRetention         *Duration `json:"purge_retention"`
+		PurgeInterval          *Duration `json:"purge_interval"`
+		DeleteRetryBackoff     *Duration `json:"delete_retry_backoff"`
+		DeleteDrainTimeout     *Duration `json:"delete_drain_timeout"`
+		DeleteBatchWindow      *Duration `json:"delete_batch_window"`
+		FileSyncInterval       *Duration `json:"file_sync_interval"`
+		FileCompactionInterval *Duration `json:"file_compaction_interval"`
+		HealthCheckTimeout     *Duration `json:"health_check_timeout"`
+		HealthCacheTTL         *Duration `json:"health_cache_ttl"`
+	}{
+		plain:                  (*plain)(c),
+		ExpirySweepInterval:    (*Duration)(&c.ExpirySweepInterval),
+		RestoreGracePeriod:     (*Duration)(&c.RestoreGracePeriod),
+		PurgeRetention:         (*Duration)(&c.PurgeRetention),
+		PurgeInterval:          (*Duration)(&c.PurgeInterval),
+		DeleteRetryBackoff:     (*Duration)(&c.DeleteRetryBackoff),
+		DeleteDrainTimeout:     (*Duration)(&c.DeleteDrainTimeout),
+		DeleteBatchWindow:      (*Duration)(&c.DeleteBatchWindow),
+		FileSyncInterval:       (*Duration)(&c.FileSyncInterval),
+		FileCompactionInterval: (*Duration)(&c.FileCompactionInterval),
+		HealthCheckTimeout:     (*Duration)(&c.HealthCheckTimeout),
+		HealthCacheTTL:         (*Duration)(&c.HealthCacheTTL),
+	}
+
+	return json.Unmarshal(data, &fields)
+}
+
 // Builder is a builder for the Config
 type Builder struct {
 	cfg *Config
+	// errs are the invalid values found by the With methods, reported by Build
+	errs []error
 }
 
 // NewConfigBuilder creates a new Builder instance
@@ -62,7 +226,33 @@ func NewConfigBuilder() *Builder {
 
 	return &Builder{
 		cfg: &Config{
-			AppEnv: env,
+			AppEnv:                 env,
+			GRPCAddr:               GRPCAddress,
+			ExpirySweepInterval:    ExpirySweepInterval,
+			RestoreGracePeriod:     RestoreGracePeriod,
+			PurgeRetention:         PurgeRetention,
+			PurgeInterval:          PurgeInterval,
+			DeleteWorkers:          DeleteWorkers,
+			DeleteMaxAttempts:      DeleteMaxAttempts,
+			DeleteRetryBackoff:     DeleteRetryBackoff,
+			DeleteDrainTimeout:     DeleteDrainTimeout,
+			DeleteBatchSize:        DeleteBatchSize,
+			DeleteBatchWindow:      DeleteBatchWindow,
+			FileSyncPolicy:         FileSyncPolicy,
+			FileSyncInterval:       FileSyncInterval,
+			FileCompactionInterval: FileCompactionInterval,
+			RateLimitCreate:        RateLimitCreate,
+			RateLimitBatch:         RateLimitBatch,
+			RateLimitRedirect:      RateLimitRedirect,
+			ShortCodeStrategy:      ShortCodeStrategy,
+			ShortCodeLength:        ShortCodeLength,
+			TraceSampleRatio:       TraceSampleRatio,
+			LogFormat:              LogFormat,
+			LogLevel:               LogLevel,
+			LogOutput:              LogOutput,
+			HealthCheckTimeout:     HealthCheckTimeout,
+			HealthCacheTTL:         HealthCacheTTL,
+			DeleteQueueSaturation:  DeleteQueueSaturation,
 		},
 	}
 }
@@ -95,7 +285,7 @@ func ParseFlags() Flags {
 }
 
 // LoadConfig loads the application configuration, priority: env > flags > file
-func LoadConfig() *Config {
+func LoadConfig() (*Config, error) {
 	appLogger := logger.NewLogger()
 
 	env := os.Getenv("GO_ENV")
@@ -120,27 +310,29 @@ func LoadConfig() *Config {
 	builder := NewConfigBuilder()
 	builder.cfg.ConfigFilePath = flags.ConfigFilePath
 
-	cfg := builder.
+	return builder.
 		WithFile().
 		WithFlags(flags).
 		WithEnv().
 		Build()
-
-	return cfg
 }
 
-// WithFile loads the configuration from the JSON file
+// WithFile loads the configuration from the JSON file, it is skipped when no file is set
 func (b *Builder) WithFile() *Builder {
+	if b.cfg.ConfigFilePath == "" {
+		return b
+	}
+
 	file, err := os.Open(b.cfg.ConfigFilePath)
 	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("config file: %w", err))
 		return b
 	}
 	defer file.Close()
 
 	decoder := json.NewDecoder(file)
-	err = decoder.Decode(b.cfg)
-	if err != nil {
-		return b
+	if err = decoder.Decode(b.cfg); err != nil {
+		b.errs = append(b.errs, fmt.Errorf("config file %s: %w", b.cfg.ConfigFilePath, err))
 	}
 
 	return b
@@ -174,46 +366,133 @@ func (b *Builder) WithFlags(f Flags) *Builder {
 	return b
 }
 
-// WithEnv loads the configuration from the environment variables
+// WithEnv loads the configuration from the environment variables, unset and empty variables are skipped
 func (b *Builder) WithEnv() *Builder {
-	if v, ok := os.LookupEnv("SERVER_ADDRESS"); ok && v != "" {
-		b.cfg.Addr = v
-	}
-	if v, ok := os.LookupEnv("BASE_URL"); ok && v != "" {
-		b.cfg.BaseURL = v
-	}
-	if v, ok := os.LookupEnv("CLIENT_URL"); ok && v != "" {
-		b.cfg.ClientURL = v
-	}
-	if v, ok := os.LookupEnv("PROFILER_ADDRESS"); ok && v != "" {
-		b.cfg.ProfilerAddr = v
-	}
-	if v, ok := os.LookupEnv("FILE_STORAGE_PATH"); ok && v != "" {
-		b.cfg.FileStoragePath = v
-	}
-	if v, ok := os.LookupEnv("DATABASE_DSN"); ok && v != "" {
-		b.cfg.DatabaseDSN = v
+	b.env("SERVER_ADDRESS", text(&b.cfg.Addr))
+	b.env("BASE_URL", text(&b.cfg.BaseURL))
+	b.env("CLIENT_URL", text(&b.cfg.ClientURL))
+	b.env("PROFILER_ADDRESS", text(&b.cfg.ProfilerAddr))
+	b.env("GRPC_ADDRESS", text(&b.cfg.GRPCAddr))
+	b.env("FILE_STORAGE_PATH", text(&b.cfg.FileStoragePath))
+	b.env("DATABASE_DSN", text(&b.cfg.DatabaseDSN))
+	b.env("SECRET_KEY", text(&b.cfg.SecretKey))
+	b.env("ENABLE_HTTPS", boolean(&b.cfg.EnableHTTPS))
+	b.env("CERTIFICATE_PATH", text(&b.cfg.Certificate))
+	b.env("CERTIFICATE_KEY_PATH", text(&b.cfg.PrivateKey))
+	b.env("CONFIG", text(&b.cfg.ConfigFilePath))
+	b.env("EXPIRY_SWEEP_INTERVAL", duration(&b.cfg.ExpirySweepInterval))
+	b.env("RESTORE_GRACE_PERIOD", duration(&b.cfg.RestoreGracePeriod))
+	b.env("PURGE_RETENTION", duration(&b.cfg.PurgeRetention))
+	b.env("PURGE_INTERVAL", duration(&b.cfg.PurgeInterval))
+	b.env("PURGE_SCHEDULE", text(&b.cfg.PurgeSchedule))
+	b.env("DELETE_WORKERS", positive(&b.cfg.DeleteWorkers))
+	b.env("DELETE_MAX_ATTEMPTS", positive(&b.cfg.DeleteMaxAttempts))
+	b.env("DELETE_RETRY_BACKOFF", duration(&b.cfg.DeleteRetryBackoff))
+	b.env("DELETE_DRAIN_TIMEOUT", duration(&b.cfg.DeleteDrainTimeout))
+	b.env("DELETE_BATCH_SIZE", positive(&b.cfg.DeleteBatchSize))
+	b.env("DELETE_BATCH_WINDOW", duration(&b.cfg.DeleteBatchWindow))
+	b.env("FILE_SYNC_POLICY", text(&b.cfg.FileSyncPolicy))
+	b.env("FILE_SYNC_INTERVAL", duration(&b.cfg.FileSyncInterval))
+	b.env("FILE_COMPACTION_INTERVAL", duration(&b.cfg.FileCompactionInterval))
+	b.env("RATE_LIMIT_CREATE", text(&b.cfg.RateLimitCreate))
+	b.env("RATE_LIMIT_BATCH", text(&b.cfg.RateLimitBatch))
+	b.env("RATE_LIMIT_REDIRECT", text(&b.cfg.RateLimitRedirect))
+	b.env("SHORT_CODE_STRATEGY", text(&b.cfg.ShortCodeStrategy))
+	b.env("SHORT_CODE_LENGTH", positive(&b.cfg.ShortCodeLength))
+	b.env("SHORT_CODE_SALT", text(&b.cfg.ShortCodeSalt))
+	b.env("OTLP_ENDPOINT", text(&b.cfg.OTLPEndpoint))
+	b.env("TRACE_FILE", text(&b.cfg.TraceFile))
+	b.env("TRACE_SAMPLE_RATIO", ratio(&b.cfg.TraceSampleRatio))
+	b.env("TRACING_DISABLED", boolean(&b.cfg.TracingDisabled))
+	b.env("LOG_FORMAT", text(&b.cfg.LogFormat))
+	b.env("LOG_LEVEL", text(&b.cfg.LogLevel))
+	b.env("LOG_OUTPUT", text(&b.cfg.LogOutput))
+	b.env("HEALTH_CHECK_TIMEOUT", duration(&b.cfg.HealthCheckTimeout))
+	b.env("HEALTH_CACHE_TTL", duration(&b.cfg.HealthCacheTTL))
+	b.env("DELETE_QUEUE_SATURATION", positive(&b.cfg.DeleteQueueSaturation))
+	b.env("TRUSTED_SUBNET", text(&b.cfg.TrustedSubnet))
+	b.env("TRUSTED_PROXIES", text(&b.cfg.TrustedProxies))
+
+	return b
+}
+
+// env sets a field from the env var with the parser, an invalid value is reported by Build
+func (b *Builder) env(key string, parse func(v string) error) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
 	}
-	if v, ok := os.LookupEnv("SECRET_KEY"); ok && v != "" {
-		b.cfg.SecretKey = v
+
+	if err := parse(v); err != nil {
+		b.errs = append(b.errs, fmt.Errorf("%s: %w", key, err))
 	}
-	if v, ok := os.LookupEnv("ENABLE_HTTPS"); ok && v != "" {
-		b.cfg.EnableHTTPS = (v == "true")
+}
+
+// text parses a string value
+func text(field *string) func(v string) error {
+	return func(v string) error {
+		*field = v
+		return nil
 	}
-	if v, ok := os.LookupEnv("CERTIFICATE_PATH"); ok && v != "" {
-		b.cfg.Certificate = v
+}
+
+// boolean parses a boolean value like "true" or "false"
+func boolean(field *bool) func(v string) error {
+	return func(v string) error {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", v)
+		}
+
+		*field = parsed
+		return nil
 	}
-	if v, ok := os.LookupEnv("CERTIFICATE_KEY_PATH"); ok && v != "" {
-		b.cfg.PrivateKey = v
+}
+
+// duration parses a duration value like "10m"
+func duration(field *time.Duration) func(v string) error {
+	return func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+
+		*field = d
+		return nil
 	}
-	if v, ok := os.LookupEnv("CONFIG"); ok && v != "" {
-		b.cfg.ConfigFilePath = v
+}
+
+// positive parses a positive integer value
+func positive[T int | int64](field *T) func(v string) error {
+	return func(v string) error {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 || int64(T(n)) != n {
+			return fmt.Errorf("%q is not a positive integer", v)
+		}
+
+		*field = T(n)
+		return nil
 	}
+}
 
-	return b
+// ratio parses a ratio between 0 and 1
+func ratio(field *float64) func(v string) error {
+	return func(v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > 1 {
+			return fmt.Errorf("%q is not a ratio between 0 and 1", v)
+		}
+
+		*field = f
+		return nil
+	}
 }
 
-// Build builds the Config
-func (b *Builder) Build() *Config {
-	return b.cfg
+// Build builds the Config, it reports every invalid value of the file and the env vars
+func (b *Builder) Build() (*Config, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+
+	return b.cfg, nil
 }
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Duration is a time.Duration read from a duration string like "10m" or "1h30m", integer nanoseconds are accepted too
+type Duration time.Duration
+
+// UnmarshalJSON decodes a duration string or a number of nanoseconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		*d = Duration(v)
+		return nil
+	}
+
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+
+	*d = Duration(n)
+	return nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Duration_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected Duration
+		wantErr  bool
+	}{
+		{
+			name:     "Duration string",
+			data:     `"1h30m"`,
+			expected: Duration(90 * time.Minute),
+		},
+		{
+			name:     "Nanoseconds",
+			data:     `1000000000`,
+			expected: Duration(time.Second),
+		},
+		{
+			name:    "Invalid string",
+			data:    `"every hour"`,
+			wantErr: true,
+		},
+		{
+			name:    "String without unit",
+			data:    `"10"`,
+			wantErr: true,
+		},
+		{
+			name:    "Other type",
+			data:    `true`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Duration
+			err := json.Unmarshal([]byte(tt.data), &d)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
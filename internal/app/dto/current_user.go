@@ -4,3 +4,9 @@ type currentUserKey string
 
 // CurrentUser is the key for the current user in the context
 const CurrentUser = currentUserKey("current_user")
+
+// Authenticated is the key for the flag of the current user verified by the authentication cookie or an API token
+const Authenticated = currentUserKey("authenticated")
+
+// TokenScopes is the key for the scopes of the API token the current user is authenticated with
+const TokenScopes = currentUserKey("token_scopes")
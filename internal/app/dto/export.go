@@ -0,0 +1,162 @@
+package dto
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"shortly/internal/app/errors"
+)
+
+// ExportFormatCSV is the format of CSV exports with a header row
+const ExportFormatCSV = "csv"
+
+// ExportFormatJSON is the format of exports as a single JSON array
+const ExportFormatJSON = "json"
+
+// ExportFormatNDJSON is the format of exports with a JSON object per line
+const ExportFormatNDJSON = "ndjson"
+
+// exportHeader is the header row of CSV exports
+var exportHeader = []string{"short_url", "short_code", "original_url", "clicks", "created_at", "expires_at", "deleted_at", "expired"}
+
+// ExportWriter is an interface for writing exported links one by one
+type ExportWriter interface {
+	// ContentType returns the media type of the written body
+	ContentType() string
+	Write(url UserURLResponse) error
+	// Close writes the rest of the body, it must be called after the last link
+	Close() error
+}
+
+// NewExportWriter creates an export writer of the format, an empty format defaults to JSON
+func NewExportWriter(format string, w io.Writer) (ExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvExportWriter{writer: csv.NewWriter(w)}, nil
+	case ExportFormatJSON, "":
+		return &jsonExportWriter{writer: w, encoder: json.NewEncoder(w)}, nil
+	case ExportFormatNDJSON:
+		return &ndjsonExportWriter{encoder: json.NewEncoder(w)}, nil
+	default:
+		return nil, errors.ErrUnsupportedExportFormat
+	}
+}
+
+// csvExportWriter writes links as CSV rows after a header row
+type csvExportWriter struct {
+	writer *csv.Writer
+	header bool
+}
+
+// ContentType returns the CSV media type
+func (e *csvExportWriter) ContentType() string {
+	return "text/csv"
+}
+
+// Write writes the link as a CSV row
+func (e *csvExportWriter) Write(url UserURLResponse) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	return e.writer.Write([]string{
+		url.ShortURL,
+		url.ShortCode,
+		url.OriginalURL,
+		strconv.FormatInt(url.Clicks, 10),
+		url.CreatedAt.Format(time.RFC3339),
+		formatOptionalTime(url.ExpiresAt),
+		formatOptionalTime(url.DeletedAt),
+		strconv.FormatBool(url.Expired),
+	})
+}
+
+// Close writes the header of an empty export and flushes buffered rows
+func (e *csvExportWriter) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// writeHeader writes the header row once
+func (e *csvExportWriter) writeHeader() error {
+	if e.header {
+		return nil
+	}
+
+	e.header = true
+	return e.writer.Write(exportHeader)
+}
+
+// jsonExportWriter writes links as elements of a JSON array
+type jsonExportWriter struct {
+	writer  io.Writer
+	encoder *json.Encoder
+	count   int
+}
+
+// ContentType returns the JSON media type
+func (e *jsonExportWriter) ContentType() string {
+	return "application/json"
+}
+
+// Write writes the link as the next array element
+func (e *jsonExportWriter) Write(url UserURLResponse) error {
+	separator := ","
+	if e.count == 0 {
+		separator = "["
+	}
+	e.count++
+
+	if _, err := io.WriteString(e.writer, separator); err != nil {
+		return err
+	}
+
+	return e.encoder.Encode(url)
+}
+
+// Close closes the array, an empty export is an empty array
+func (e *jsonExportWriter) Close() error {
+	closing := "]\n"
+	if e.count == 0 {
+		closing = "[]\n"
+	}
+
+	_, err := io.WriteString(e.writer, closing)
+	return err
+}
+
+// ndjsonExportWriter writes links as JSON objects line by line
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+// ContentType returns the NDJSON media type
+func (e *ndjsonExportWriter) ContentType() string {
+	return ImportFormatNDJSON
+}
+
+// Write writes the link as a JSON line
+func (e *ndjsonExportWriter) Write(url UserURLResponse) error {
+	return e.encoder.Encode(url)
+}
+
+// Close does nothing, every line is complete
+func (e *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+// formatOptionalTime formats the time as RFC 3339, nil is an empty string
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
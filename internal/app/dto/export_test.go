@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/errors"
+)
+
+func Test_ExportWriter(t *testing.T) {
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	deletedAt := createdAt.Add(time.Hour)
+
+	urls := []UserURLResponse{
+		{
+			ShortURL:    "http://localhost:8080/abcd0001",
+			ShortCode:   "abcd0001",
+			OriginalURL: "https://example.com/?a=1,2",
+			Clicks:      3,
+			CreatedAt:   createdAt,
+		},
+		{
+			ShortURL:    "http://localhost:8080/abcd0002",
+			ShortCode:   "abcd0002",
+			OriginalURL: "https://github.com",
+			CreatedAt:   createdAt,
+			DeletedAt:   &deletedAt,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		format      string
+		urls        []UserURLResponse
+		contentType string
+		expected    string
+	}{
+		{
+			name:        "CSV",
+			format:      ExportFormatCSV,
+			urls:        urls,
+			contentType: "text/csv",
+			expected: "short_url,short_code,original_url,clicks,created_at,expires_at,deleted_at,expired\n" +
+				"http://localhost:8080/abcd0001,abcd0001,\"https://example.com/?a=1,2\",3,2026-10-17T12:00:00Z,,,false\n" +
+				"http://localhost:8080/abcd0002,abcd0002,https://github.com,0,2026-10-17T12:00:00Z,,2026-10-17T13:00:00Z,false\n",
+		},
+		{
+			name:        "Empty CSV",
+			format:      ExportFormatCSV,
+			contentType: "text/csv",
+			expected:    "short_url,short_code,original_url,clicks,created_at,expires_at,deleted_at,expired\n",
+		},
+		{
+			name:        "NDJSON",
+			format:      ExportFormatNDJSON,
+			urls:        urls,
+			contentType: ImportFormatNDJSON,
+			expected: `{"short_url":"http://localhost:8080/abcd0001","short_code":"abcd0001","original_url":"https://example.com/?a=1,2","clicks":3,"created_at":"2026-10-17T12:00:00Z"}` + "\n" +
+				`{"short_url":"http://localhost:8080/abcd0002","short_code":"abcd0002","original_url":"https://github.com","clicks":0,"created_at":"2026-10-17T12:00:00Z","deleted_at":"2026-10-17T13:00:00Z"}` + "\n",
+		},
+		{
+			name:        "Empty JSON",
+			format:      ExportFormatJSON,
+			contentType: "application/json",
+			expected:    "[]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+
+			export, err := NewExportWriter(tt.format, &body)
+			require.NoError(t, err)
+
+			for _, url := range tt.urls {
+				require.NoError(t, export.Write(url))
+			}
+			require.NoError(t, export.Close())
+
+			assert.Equal(t, tt.contentType, export.ContentType())
+			assert.Equal(t, tt.expected, body.String())
+		})
+	}
+}
+
+func Test_ExportWriter_JSON(t *testing.T) {
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	urls := []UserURLResponse{
+		{ShortURL: "http://localhost:8080/abcd0001", ShortCode: "abcd0001", OriginalURL: "https://example.com", CreatedAt: createdAt},
+		{ShortURL: "http://localhost:8080/abcd0002", ShortCode: "abcd0002", OriginalURL: "https://github.com", CreatedAt: createdAt},
+	}
+
+	for _, format := range []string{ExportFormatJSON, ""} {
+		var body bytes.Buffer
+
+		export, err := NewExportWriter(format, &body)
+		require.NoError(t, err)
+
+		for _, url := range urls {
+			require.NoError(t, export.Write(url))
+		}
+		require.NoError(t, export.Close())
+
+		var decoded []UserURLResponse
+		require.NoError(t, json.Unmarshal(body.Bytes(), &decoded))
+		assert.Equal(t, urls, decoded)
+	}
+}
+
+func Test_NewExportWriter_Unsupported(t *testing.T) {
+	_, err := NewExportWriter("xml", &bytes.Buffer{})
+	assert.Equal(t, errors.ErrUnsupportedExportFormat, err)
+}
@@ -1,6 +1,29 @@
 package dto
 
+import "time"
+
 // HealthResponse is a response for ping request
 type HealthResponse struct {
 	Result string `json:"result"`
 }
+
+// HealthReport is the health of the application and of its components
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// ComponentHealth is the result of the health check of a component
+type ComponentHealth struct {
+	Status     string    `json:"status"`
+	Critical   bool      `json:"critical"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// ReadinessResponse is a response for readiness check, the details of the components are left out
+type ReadinessResponse struct {
+	Status     string            `json:"status"`
+	Components map[string]string `json:"components"`
+}
@@ -0,0 +1,171 @@
+package dto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	"shortly/internal/app/errors"
+)
+
+// ImportFormatCSV is the media type of CSV imports with original_url,alias,correlation_id rows
+const ImportFormatCSV = "text/csv"
+
+// ImportFormatNDJSON is the media type of NDJSON imports with batch item objects, also used for the report
+const ImportFormatNDJSON = "application/x-ndjson"
+
+// MaxImportLineLength is the maximum length of an NDJSON import line
+const MaxImportLineLength = 64 * 1024
+
+// ImportStatusCreated is the status of an imported row which created a short link
+const ImportStatusCreated = "created"
+
+// ImportStatusExists is the status of an imported row whose original URL is already shortened
+const ImportStatusExists = "exists"
+
+// ImportStatusInvalid is the status of an imported row which failed to parse or validate
+const ImportStatusInvalid = "invalid"
+
+// ImportStatusFailed is the status of an imported row which could not be stored
+const ImportStatusFailed = "failed"
+
+// ImportRow is a parsed import row, Err is set when the row failed to parse or validate
+type ImportRow struct {
+	// Line is the line of the row in the imported file
+	Line int
+	BatchCreateShortLinkParams
+	Err error
+}
+
+// ImportResult is a report line of an imported row
+type ImportResult struct {
+	Line          int    `json:"line"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	Status        string `json:"status"`
+	ShortURL      string `json:"short_url,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// ImportReader is an interface for reading import rows one by one
+type ImportReader interface {
+	// Next returns the next row or io.EOF after the last one, other errors abort the import at the returned line
+	Next() (ImportRow, error)
+}
+
+// NewImportReader creates an import reader of the body media type
+func NewImportReader(contentType string, body io.Reader) (ImportReader, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, errors.ErrUnsupportedImportFormat
+	}
+
+	switch mediaType {
+	case ImportFormatCSV:
+		reader := csv.NewReader(body)
+		reader.FieldsPerRecord = -1
+		reader.ReuseRecord = true
+
+		return &csvImportReader{reader: reader}, nil
+	case ImportFormatNDJSON, "application/ndjson", "application/jsonl":
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), MaxImportLineLength)
+
+		return &ndjsonImportReader{scanner: scanner}, nil
+	default:
+		return nil, errors.ErrUnsupportedImportFormat
+	}
+}
+
+// csvImportReader reads original_url,alias,correlation_id rows, an optional header row is skipped
+type csvImportReader struct {
+	reader *csv.Reader
+	line   int
+	header bool
+}
+
+// Next returns the next CSV row
+func (r *csvImportReader) Next() (ImportRow, error) {
+	for {
+		record, err := r.reader.Read()
+		if err == io.EOF {
+			return ImportRow{}, io.EOF
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			r.line = parseErr.Line
+			return ImportRow{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+		}
+
+		if err != nil {
+			return ImportRow{Line: r.line + 1}, err
+		}
+
+		r.line, _ = r.reader.FieldPos(0)
+
+		if !r.header {
+			r.header = true
+			if strings.EqualFold(strings.TrimSpace(record[0]), "original_url") {
+				continue
+			}
+		}
+
+		row := ImportRow{Line: r.line}
+		if len(record) > 3 {
+			row.Err = errors.ErrImportFieldCount
+			return row, nil
+		}
+
+		row.OriginalURL = record[0]
+		if len(record) > 1 {
+			row.Alias = record[1]
+		}
+		if len(record) > 2 {
+			row.CorrelationID = strings.TrimSpace(record[2])
+		}
+
+		row.Err = row.validate(time.Now())
+
+		return row, nil
+	}
+}
+
+// ndjsonImportReader reads batch item objects line by line, blank lines are skipped
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// Next returns the next NDJSON row
+func (r *ndjsonImportReader) Next() (ImportRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		raw := bytes.TrimSpace(r.scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		row := ImportRow{Line: r.line}
+		if err := json.Unmarshal(raw, &row.BatchCreateShortLinkParams); err != nil {
+			row.Err = err
+			return row, nil
+		}
+
+		row.CorrelationID = strings.TrimSpace(row.CorrelationID)
+		row.Err = row.validate(time.Now())
+
+		return row, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return ImportRow{Line: r.line + 1}, err
+	}
+
+	return ImportRow{}, io.EOF
+}
@@ -0,0 +1,192 @@
+package dto
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/errors"
+)
+
+type importLine struct {
+	line          int
+	originalURL   string
+	alias         string
+	correlationID string
+	err           string
+}
+
+func readImport(reader ImportReader) ([]importLine, error) {
+	var lines []importLine
+
+	for {
+		row, err := reader.Next()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+
+		line := importLine{
+			line:          row.Line,
+			originalURL:   row.OriginalURL,
+			alias:         row.Alias,
+			correlationID: row.CorrelationID,
+		}
+		if row.Err != nil {
+			line.err = row.Err.Error()
+		}
+		lines = append(lines, line)
+	}
+}
+
+func Test_NewImportReader(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		expected    error
+	}{
+		{
+			name:        "CSV",
+			contentType: "text/csv; charset=utf-8",
+			expected:    nil,
+		},
+		{
+			name:        "NDJSON",
+			contentType: "application/x-ndjson",
+			expected:    nil,
+		},
+		{
+			name:        "JSON Lines",
+			contentType: "application/jsonl",
+			expected:    nil,
+		},
+		{
+			name:        "JSON",
+			contentType: "application/json",
+			expected:    errors.ErrUnsupportedImportFormat,
+		},
+		{
+			name:        "No content type",
+			contentType: "",
+			expected:    errors.ErrUnsupportedImportFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewImportReader(tt.contentType, strings.NewReader(""))
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func Test_CSVImportReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []importLine
+	}{
+		{
+			name: "Header is skipped",
+			body: "original_url,alias,correlation_id\n" +
+				"https://github.com,,1\n" +
+				" https://google.com , google , 2\n",
+			expected: []importLine{
+				{line: 2, originalURL: "https://github.com", correlationID: "1"},
+				{line: 3, originalURL: "https://google.com", alias: "google", correlationID: "2"},
+			},
+		},
+		{
+			name: "Without header and optional fields",
+			body: "https://github.com\n" +
+				"\"https://example.com/?a=1,2\",docs\n",
+			expected: []importLine{
+				{line: 1, originalURL: "https://github.com"},
+				{line: 2, originalURL: "https://example.com/?a=1,2", alias: "docs"},
+			},
+		},
+		{
+			name: "Invalid rows are reported and skipped",
+			body: "not a url,,1\n" +
+				"https://github.com,,2,extra\n" +
+				"https://github.com,a/b,3\n" +
+				"https://git\"hub.com,,4\n" +
+				"https://google.com,,5\n",
+			expected: []importLine{
+				{line: 1, originalURL: "not a url", correlationID: "1", err: errors.ErrInvalidURL.Error()},
+				{line: 2, err: errors.ErrImportFieldCount.Error()},
+				{line: 3, originalURL: "https://github.com", alias: "a/b", correlationID: "3", err: errors.ErrInvalidAlias.Error()},
+				{line: 4, err: `bare " in non-quoted-field`},
+				{line: 5, originalURL: "https://google.com", correlationID: "5"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewImportReader(ImportFormatCSV, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			lines, err := readImport(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, lines)
+		})
+	}
+}
+
+func Test_NDJSONImportReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []importLine
+		error    error
+	}{
+		{
+			name: "Rows",
+			body: `{"original_url": "https://github.com", "correlation_id": "1"}` + "\n" +
+				"\n" +
+				`{"original_url": "https://google.com", "alias": "google", "ttl": 60}` + "\n",
+			expected: []importLine{
+				{line: 1, originalURL: "https://github.com", correlationID: "1"},
+				{line: 3, originalURL: "https://google.com", alias: "google"},
+			},
+		},
+		{
+			name: "Invalid rows are reported and skipped",
+			body: `{"original_url": "https://github.com"` + "\n" +
+				`{"original_url": "https://github.com", "ttl": -1}` + "\n" +
+				`{"original_url": "https://google.com"}`,
+			expected: []importLine{
+				{line: 1, err: "unexpected end of JSON input"},
+				{line: 2, originalURL: "https://github.com", err: errors.ErrInvalidExpiration.Error()},
+				{line: 3, originalURL: "https://google.com"},
+			},
+		},
+		{
+			name: "Too long line aborts",
+			body: `{"original_url": "https://github.com"}` + "\n" +
+				`{"original_url": "https://google.com/` + strings.Repeat("a", MaxImportLineLength) + `"}`,
+			expected: []importLine{
+				{line: 1, originalURL: "https://github.com"},
+			},
+			error: bufio.ErrTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewImportReader(ImportFormatNDJSON, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			lines, err := readImport(reader)
+			assert.Equal(t, tt.error, err)
+			assert.Equal(t, tt.expected, lines)
+		})
+	}
+}
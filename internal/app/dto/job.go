@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatusQueued is the status of a job waiting in the queue
+const JobStatusQueued = "queued"
+
+// JobStatusRunning is the status of a job being performed
+const JobStatusRunning = "running"
+
+// JobStatusDone is the status of a successfully finished job
+const JobStatusDone = "done"
+
+// JobStatusFailed is the status of a job finished with an error
+const JobStatusFailed = "failed"
+
+// SkipReasonNotFound is reported for short codes which do not exist
+const SkipReasonNotFound = "not_found"
+
+// SkipReasonNotOwned is reported for short codes owned by another user
+const SkipReasonNotOwned = "not_owned"
+
+// SkipReasonAlreadyDeleted is reported for short codes deleted before the job
+const SkipReasonAlreadyDeleted = "already_deleted"
+
+// SkippedShortCode is a short code left untouched by a delete job
+type SkippedShortCode struct {
+	ShortCode string `json:"short_code"`
+	Reason    string `json:"reason"`
+}
+
+// DeleteJobResponse is a response describing the state of an asynchronous batch deletion
+type DeleteJobResponse struct {
+	ID             uuid.UUID          `json:"id"`
+	Status         string             `json:"status"`
+	Requested      int                `json:"requested"`
+	Deleted        int                `json:"deleted"`
+	NotOwned       int                `json:"not_owned"`
+	NotFound       int                `json:"not_found"`
+	AlreadyDeleted int                `json:"already_deleted"`
+	Skipped        []SkippedShortCode `json:"skipped,omitempty"`
+	Error          string             `json:"error,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	FinishedAt     *time.Time         `json:"finished_at,omitempty"`
+}
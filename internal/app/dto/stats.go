@@ -0,0 +1,37 @@
+package dto
+
+// ClickEvent is a short link click event
+type ClickEvent struct {
+	Referrer   string
+	UserAgent  string
+	RemoteAddr string
+}
+
+// URLStatsResponse is a response for short link statistics retrieval
+type URLStatsResponse struct {
+	ShortURL       string                `json:"short_url"`
+	OriginalURL    string                `json:"original_url"`
+	TotalClicks    int64                 `json:"total_clicks"`
+	UniqueVisitors int64                 `json:"unique_visitors"`
+	Daily          []DailyClicksResponse `json:"daily"`
+}
+
+// DailyClicksResponse is a short link statistics for a single day
+type DailyClicksResponse struct {
+	Date           string `json:"date"`
+	Clicks         int64  `json:"clicks"`
+	UniqueVisitors int64  `json:"unique_visitors"`
+}
+
+// InternalStatsResponse is a response for the internal statistics of the service
+type InternalStatsResponse struct {
+	URLs     int64                   `json:"urls"`
+	Users    int64                   `json:"users"`
+	Backends map[string]BackendStats `json:"backends"`
+}
+
+// BackendStats is the number of active links and of their distinct owners stored in a storage backend
+type BackendStats struct {
+	URLs  int64 `json:"urls"`
+	Users int64 `json:"users"`
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"shortly/internal/app/errors"
+)
+
+// ScopeLinksRead grants listing of the user short links and their statistics
+const ScopeLinksRead = "links:read"
+
+// ScopeLinksWrite grants creation, update and deletion of short links
+const ScopeLinksWrite = "links:write"
+
+// MaxAPITokenNameLength is the maximum length of an API token name
+const MaxAPITokenNameLength = 255
+
+// Scopes is the list of scopes an API token may be issued with
+var Scopes = []string{ScopeLinksRead, ScopeLinksWrite}
+
+// CreateAPITokenRequest is a request for API token creation
+type CreateAPITokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// APITokenResponse is an API token description, the secret itself is never returned after creation
+type APITokenResponse struct {
+	UUID      string    `json:"uuid"`
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateAPITokenResponse is a response for API token creation
+type CreateAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
+
+// Validate validates a create API token request, a token without scopes is granted all of them
+func (params *CreateAPITokenRequest) Validate(body io.Reader) error {
+	if err := json.NewDecoder(body).Decode(params); err != nil {
+		return err
+	}
+
+	params.Name = strings.TrimSpace(params.Name)
+	if params.Name == "" {
+		return errors.ErrAPITokenNameEmpty
+	}
+
+	if len(params.Name) > MaxAPITokenNameLength {
+		return errors.ErrAPITokenNameTooLong
+	}
+
+	if len(params.Scopes) == 0 {
+		params.Scopes = slices.Clone(Scopes)
+		return nil
+	}
+
+	for _, scope := range params.Scopes {
+		if !slices.Contains(Scopes, scope) {
+			return errors.ErrInvalidScope
+		}
+	}
+
+	slices.Sort(params.Scopes)
+	params.Scopes = slices.Compact(params.Scopes)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"shortly/internal/app/errors"
+)
+
+func Test_ValidateOnCreateAPIToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     io.Reader
+		scopes   []string
+		expected error
+	}{
+		{
+			name:     "Success",
+			body:     strings.NewReader(`{"name": "ci", "scopes": ["links:write"]}`),
+			scopes:   []string{ScopeLinksWrite},
+			expected: nil,
+		},
+		{
+			name:     "Success (all scopes by default)",
+			body:     strings.NewReader(`{"name": "ci"}`),
+			scopes:   []string{ScopeLinksRead, ScopeLinksWrite},
+			expected: nil,
+		},
+		{
+			name:     "Success (duplicated scopes)",
+			body:     strings.NewReader(`{"name": "ci", "scopes": ["links:write", "links:read", "links:write"]}`),
+			scopes:   []string{ScopeLinksRead, ScopeLinksWrite},
+			expected: nil,
+		},
+		{
+			name:     "Empty name",
+			body:     strings.NewReader(`{"name": "  "}`),
+			expected: errors.ErrAPITokenNameEmpty,
+		},
+		{
+			name:     "Too long name",
+			body:     strings.NewReader(`{"name": "` + strings.Repeat("a", MaxAPITokenNameLength+1) + `"}`),
+			expected: errors.ErrAPITokenNameTooLong,
+		},
+		{
+			name:     "Unknown scope",
+			body:     strings.NewReader(`{"name": "ci", "scopes": ["admin"]}`),
+			expected: errors.ErrInvalidScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params CreateAPITokenRequest
+			err := params.Validate(tt.body)
+
+			assert.ErrorIs(t, err, tt.expected)
+			if tt.expected == nil {
+				assert.Equal(t, tt.scopes, params.Scopes)
+			}
+		})
+	}
+}
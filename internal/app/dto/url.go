@@ -3,7 +3,10 @@ package dto
 import (
 	"encoding/json"
 	"io"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -11,9 +14,19 @@ import (
 	"shortly/internal/app/validator"
 )
 
+// Expiration is a set of optional link expiration attributes
+type Expiration struct {
+	// ExpiresAt is an absolute expiration time
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// TTL is a link lifetime in seconds
+	TTL int64 `json:"ttl,omitempty"`
+}
+
 // CreateShortLinkRequest is a request for short link creation
 type CreateShortLinkRequest struct {
-	URL string `json:"url"`
+	URL   string `json:"url"`
+	Alias string `json:"alias,omitempty"`
+	Expiration
 }
 
 // CreateShortLinkResponse is a response for short link creation
@@ -25,6 +38,8 @@ type CreateShortLinkResponse struct {
 type BatchCreateShortLinkParams struct {
 	CorrelationID string `json:"correlation_id"`
 	OriginalURL   string `json:"original_url"`
+	Alias         string `json:"alias,omitempty"`
+	Expiration
 }
 
 // BatchCreateShortLinkRequest is a request for batch short link creation
@@ -33,7 +48,11 @@ type BatchCreateShortLinkRequest []BatchCreateShortLinkParams
 // BatchCreateShortLinkResponse is a response for batch short link creation
 type BatchCreateShortLinkResponse struct {
 	CorrelationID string `json:"correlation_id"`
-	ShortURL      string `json:"short_url"`
+	ShortURL      string `json:"short_url,omitempty"`
+	// Conflict is set when the original URL is already shortened, ShortURL is the existing short URL then
+	Conflict bool `json:"conflict,omitempty"`
+	// Error is set when the item was not created
+	Error string `json:"error,omitempty"`
 }
 
 // BatchCreateShortLinkResponses is a response for batch short link creation
@@ -50,15 +69,68 @@ type GetUserURLsResponse struct {
 	OriginalURL string `json:"original_url"`
 }
 
+// SortCreatedAt orders listed links from the oldest
+const SortCreatedAt = "created_at"
+
+// SortCreatedAtDesc orders listed links from the newest
+const SortCreatedAtDesc = "-created_at"
+
+// SortClicks orders listed links from the least clicked
+const SortClicks = "clicks"
+
+// SortClicksDesc orders listed links from the most clicked
+const SortClicksDesc = "-clicks"
+
+// ListUserURLsRequest is a request for filtered user URLs retrieval
+type ListUserURLsRequest struct {
+	// Query is a case-insensitive substring of the original URL or the short code
+	Query string
+	Sort  string
+	// Deleted lists deleted links instead of active ones
+	Deleted bool
+	// Expired lists expired links instead of active ones
+	Expired       bool
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// UserURLResponse is a listed user short link
+type UserURLResponse struct {
+	ShortURL    string     `json:"short_url"`
+	ShortCode   string     `json:"short_code"`
+	OriginalURL string     `json:"original_url"`
+	Clicks      int64      `json:"clicks"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Expired     bool       `json:"expired,omitempty"`
+}
+
+// UpdateShortLinkRequest is a request for short link update
+type UpdateShortLinkRequest struct {
+	URL string `json:"url"`
+}
+
 // BatchDeleteShortLinkRequest is a request for batch short link deletion
 type BatchDeleteShortLinkRequest []string
 
 // BatchDeleteParams is a request for batch short link deletion
 type BatchDeleteParams struct {
+	// JobID identifies the delete job, a new one is assigned when empty
+	JobID      uuid.UUID
 	UserID     uuid.UUID
 	ShortCodes []string
 }
 
+// BatchRestoreShortLinkRequest is a request for batch restoration of deleted short links
+type BatchRestoreShortLinkRequest []string
+
+// BatchRestoreShortLinkResponse is a response for batch restoration of deleted short links
+type BatchRestoreShortLinkResponse struct {
+	// Restored lists the restored short codes, codes not deleted within the grace period are left out
+	Restored []string `json:"restored"`
+}
+
 // ErrorResponse is a response for batch short link deletion
 type ErrorResponse struct {
 	Error string `json:"error"`
@@ -70,7 +142,20 @@ func (params *CreateShortLinkRequest) Validate(body io.Reader) error {
 		return err
 	}
 
-	return params.validateURL()
+	return params.ValidateFields()
+}
+
+// ValidateFields validates and normalizes the fields of a decoded create short link request
+func (params *CreateShortLinkRequest) ValidateFields() error {
+	if err := params.validateURL(); err != nil {
+		return err
+	}
+
+	if err := params.validateAlias(); err != nil {
+		return err
+	}
+
+	return params.Expiration.validate(time.Now())
 }
 
 // Validate validates a batch create short link request
@@ -80,13 +165,20 @@ func (params *BatchCreateShortLinkRequest) Validate(body io.Reader) error {
 		return err
 	}
 
-	for _, p := range *params {
+	return params.ValidateFields()
+}
+
+// ValidateFields validates and normalizes the items of a decoded batch create short link request
+func (params *BatchCreateShortLinkRequest) ValidateFields() error {
+	for i := range *params {
+		p := &(*params)[i]
+
 		p.CorrelationID = strings.TrimSpace(p.CorrelationID)
 		if p.CorrelationID == "" {
 			return errors.ErrCorrelationIDEmpty
 		}
 
-		if err := validator.Validate(p.OriginalURL); err != nil {
+		if err := p.validate(time.Now()); err != nil {
 			return err
 		}
 	}
@@ -94,6 +186,63 @@ func (params *BatchCreateShortLinkRequest) Validate(body io.Reader) error {
 	return nil
 }
 
+// Validate parses and validates user URLs listing query parameters
+func (params *ListUserURLsRequest) Validate(query url.Values) error {
+	var err error
+
+	params.Query = query.Get("q")
+	params.Sort = query.Get("sort")
+
+	if params.Deleted, err = parseBool(query.Get("deleted")); err != nil {
+		return err
+	}
+
+	if params.Expired, err = parseBool(query.Get("expired")); err != nil {
+		return err
+	}
+
+	if params.CreatedAfter, err = parseTime(query.Get("created_after")); err != nil {
+		return err
+	}
+
+	if params.CreatedBefore, err = parseTime(query.Get("created_before")); err != nil {
+		return err
+	}
+
+	return params.ValidateFields()
+}
+
+// ValidateFields validates and normalizes the query and the sort of a user URLs listing request,
+// an empty sort lists the newest links first
+func (params *ListUserURLsRequest) ValidateFields() error {
+	params.Query = strings.TrimSpace(params.Query)
+
+	switch params.Sort {
+	case "":
+		params.Sort = SortCreatedAtDesc
+	case SortCreatedAt, SortCreatedAtDesc, SortClicks, SortClicksDesc:
+	default:
+		return errors.ErrInvalidSort
+	}
+
+	return nil
+}
+
+// Validate validates an update short link request
+func (params *UpdateShortLinkRequest) Validate(body io.Reader) error {
+	if err := json.NewDecoder(body).Decode(params); err != nil {
+		return err
+	}
+
+	params.URL = strings.TrimSpace(params.URL)
+
+	if params.URL == "" {
+		return errors.ErrOriginalURLEmpty
+	}
+
+	return validator.Validate(params.URL)
+}
+
 // Validate validates a batch delete short link request
 func (params *BatchDeleteShortLinkRequest) Validate(body io.Reader) error {
 	decoder := json.NewDecoder(body)
@@ -101,6 +250,11 @@ func (params *BatchDeleteShortLinkRequest) Validate(body io.Reader) error {
 		return err
 	}
 
+	return params.ValidateFields()
+}
+
+// ValidateFields validates the short codes of a decoded batch delete short link request
+func (params *BatchDeleteShortLinkRequest) ValidateFields() error {
 	if len(*params) == 0 {
 		return errors.ErrShortCodeEmpty
 	}
@@ -114,6 +268,11 @@ func (params *BatchDeleteShortLinkRequest) Validate(body io.Reader) error {
 	return nil
 }
 
+// Validate validates a batch restore short link request
+func (params *BatchRestoreShortLinkRequest) Validate(body io.Reader) error {
+	return (*BatchDeleteShortLinkRequest)(params).Validate(body)
+}
+
 // DeprecatedValidate validates a create short link request (text/plain endpoint)
 func (params *CreateShortLinkRequest) DeprecatedValidate(body io.Reader) error {
 	raw, err := io.ReadAll(body)
@@ -140,3 +299,91 @@ func (params *CreateShortLinkRequest) validateURL() error {
 
 	return nil
 }
+
+// validateAlias validates an optional custom alias
+func (params *CreateShortLinkRequest) validateAlias() error {
+	params.Alias = strings.TrimSpace(params.Alias)
+
+	if params.Alias == "" {
+		return nil
+	}
+
+	return validator.ValidateAlias(params.Alias)
+}
+
+// validate validates the link attributes of a batch item, the correlation ID is left to the caller
+func (p *BatchCreateShortLinkParams) validate(now time.Time) error {
+	p.OriginalURL = strings.TrimSpace(p.OriginalURL)
+
+	if err := validator.Validate(p.OriginalURL); err != nil {
+		return err
+	}
+
+	p.Alias = strings.TrimSpace(p.Alias)
+	if p.Alias != "" {
+		if err := validator.ValidateAlias(p.Alias); err != nil {
+			return err
+		}
+	}
+
+	return p.Expiration.validate(now)
+}
+
+// Deadline returns the link expiration time, zero time means the link never expires
+func (e Expiration) Deadline(now time.Time) time.Time {
+	if e.ExpiresAt != nil {
+		return e.ExpiresAt.UTC()
+	}
+
+	if e.TTL > 0 {
+		return now.Add(time.Duration(e.TTL) * time.Second).UTC()
+	}
+
+	return time.Time{}
+}
+
+// parseBool parses an optional boolean query parameter
+func parseBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.ErrInvalidFilter
+	}
+
+	return result, nil
+}
+
+// parseTime parses an optional RFC 3339 time or date query parameter, dates are taken as UTC midnight
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if result, err := time.Parse(layout, value); err == nil {
+			return result.UTC(), nil
+		}
+	}
+
+	return time.Time{}, errors.ErrInvalidFilter
+}
+
+// validate validates optional expiration attributes
+func (e Expiration) validate(now time.Time) error {
+	if e.ExpiresAt != nil && e.TTL != 0 {
+		return errors.ErrInvalidExpiration
+	}
+
+	if e.TTL < 0 {
+		return errors.ErrInvalidExpiration
+	}
+
+	if e.ExpiresAt != nil && !e.ExpiresAt.After(now) {
+		return errors.ErrInvalidExpiration
+	}
+
+	return nil
+}
@@ -2,8 +2,10 @@ package dto
 
 import (
 	"io"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -41,6 +43,46 @@ func Test_ValidateOnCreate(t *testing.T) {
 			body:     strings.NewReader(`{"url": "not-a-url"}`),
 			expected: errors.ErrInvalidURL,
 		},
+		{
+			name:     "Success with TTL",
+			body:     strings.NewReader(`{"url": "https://www.google.com", "ttl": 3600}`),
+			expected: nil,
+		},
+		{
+			name:     "Success with expiration time",
+			body:     strings.NewReader(`{"url": "https://www.google.com", "expires_at": "2999-01-01T00:00:00Z"}`),
+			expected: nil,
+		},
+		{
+			name:     "Negative TTL",
+			body:     strings.NewReader(`{"url": "https://www.google.com", "ttl": -1}`),
+			expected: errors.ErrInvalidExpiration,
+		},
+		{
+			name:     "Expiration time in the past",
+			body:     strings.NewReader(`{"url": "https://www.google.com", "expires_at": "2000-01-01T00:00:00Z"}`),
+			expected: errors.ErrInvalidExpiration,
+		},
+		{
+			name:     "Both TTL and expiration time",
+			body:     strings.NewReader(`{"url": "https://www.google.com", "ttl": 60, "expires_at": "2999-01-01T00:00:00Z"}`),
+			expected: errors.ErrInvalidExpiration,
+		},
+		{
+			name:     "Success with alias",
+			body:     strings.NewReader(`{"url": "https://www.google.com", "alias": "spring-sale"}`),
+			expected: nil,
+		},
+		{
+			name:     "Invalid alias",
+			body:     strings.NewReader(`{"url": "https://www.google.com", "alias": "spring sale"}`),
+			expected: errors.ErrInvalidAlias,
+		},
+		{
+			name:     "Reserved alias",
+			body:     strings.NewReader(`{"url": "https://www.google.com", "alias": "api"}`),
+			expected: errors.ErrAliasReserved,
+		},
 	}
 
 	for _, tt := range tests {
@@ -84,6 +126,21 @@ func Test_ValidateOnBatchCreate(t *testing.T) {
 			body:     strings.NewReader(`[{"correlation_id": "1234", "original_url": "not-a-url"}]`),
 			expected: errors.ErrInvalidURL,
 		},
+		{
+			name:     "Success with alias",
+			body:     strings.NewReader(`[{"correlation_id": "1234", "original_url": "https://www.google.com", "alias": "spring-sale"}]`),
+			expected: nil,
+		},
+		{
+			name:     "Invalid alias",
+			body:     strings.NewReader(`[{"correlation_id": "1234", "original_url": "https://www.google.com", "alias": "a"}]`),
+			expected: errors.ErrInvalidAlias,
+		},
+		{
+			name:     "Expiration time in the past",
+			body:     strings.NewReader(`[{"correlation_id": "1234", "original_url": "https://www.google.com", "expires_at": "2000-01-01T00:00:00Z"}]`),
+			expected: errors.ErrInvalidExpiration,
+		},
 	}
 
 	for _, tt := range tests {
@@ -129,6 +186,181 @@ func Test_ValidateOnBatchDelete(t *testing.T) {
 	}
 }
 
+func Test_ValidateOnBatchRestore(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     io.Reader
+		expected []string
+		error    error
+	}{
+		{
+			name:     "Success",
+			body:     strings.NewReader(`["1234abcd", "5678abcd"]`),
+			expected: []string{"1234abcd", "5678abcd"},
+		},
+		{
+			name:  "Empty",
+			body:  strings.NewReader(`[]`),
+			error: errors.ErrShortCodeEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params BatchRestoreShortLinkRequest
+			err := params.Validate(tt.body)
+
+			assert.Equal(t, tt.error, err)
+			if tt.error == nil {
+				assert.Equal(t, tt.expected, []string(params))
+			}
+		})
+	}
+}
+
+func Test_ValidateOnListUserURLs(t *testing.T) {
+	type result struct {
+		params ListUserURLsRequest
+		error  error
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected result
+	}{
+		{
+			name:  "Defaults",
+			query: "",
+			expected: result{
+				params: ListUserURLsRequest{Sort: SortCreatedAtDesc},
+			},
+		},
+		{
+			name:  "All parameters",
+			query: "q=+google+&sort=-clicks&deleted=true&expired=0&created_after=2026-10-01&created_before=2026-10-17T12:00:00%2B03:00",
+			expected: result{
+				params: ListUserURLsRequest{
+					Query:         "google",
+					Sort:          SortClicksDesc,
+					Deleted:       true,
+					CreatedAfter:  time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+					CreatedBefore: time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "Invalid sort",
+			query: "sort=original_url",
+			expected: result{
+				error: errors.ErrInvalidSort,
+			},
+		},
+		{
+			name:  "Invalid flag",
+			query: "deleted=maybe",
+			expected: result{
+				error: errors.ErrInvalidFilter,
+			},
+		},
+		{
+			name:  "Invalid time",
+			query: "created_after=yesterday",
+			expected: result{
+				error: errors.ErrInvalidFilter,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+
+			var params ListUserURLsRequest
+			err = params.Validate(query)
+
+			assert.Equal(t, tt.expected.error, err)
+			if tt.expected.error == nil {
+				assert.Equal(t, tt.expected.params, params)
+			}
+		})
+	}
+}
+
+func Test_ValidateFieldsOnCreate(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		params   CreateShortLinkRequest
+		expected error
+	}{
+		{
+			name:   "Trimmed URL and alias",
+			params: CreateShortLinkRequest{URL: " https://google.com ", Alias: " google "},
+		},
+		{
+			name:     "Empty URL",
+			params:   CreateShortLinkRequest{URL: " "},
+			expected: errors.ErrOriginalURLEmpty,
+		},
+		{
+			name:     "Both expiration attributes",
+			params:   CreateShortLinkRequest{URL: "https://google.com", Expiration: Expiration{ExpiresAt: &expiresAt, TTL: 60}},
+			expected: errors.ErrInvalidExpiration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.ValidateFields()
+
+			assert.Equal(t, tt.expected, err)
+			if tt.expected == nil {
+				assert.Equal(t, "https://google.com", tt.params.URL)
+				assert.Equal(t, "google", tt.params.Alias)
+			}
+		})
+	}
+}
+
+func Test_ValidateOnUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     io.Reader
+		expected error
+	}{
+		{
+			name:     "Success",
+			body:     strings.NewReader(`{"url":" https://example.com "}`),
+			expected: nil,
+		},
+		{
+			name:     "Empty URL",
+			body:     strings.NewReader(`{"url":""}`),
+			expected: errors.ErrOriginalURLEmpty,
+		},
+		{
+			name:     "Invalid URL",
+			body:     strings.NewReader(`{"url":"example"}`),
+			expected: errors.ErrInvalidURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params UpdateShortLinkRequest
+			err := params.Validate(tt.body)
+
+			assert.Equal(t, tt.expected, err)
+			if err == nil {
+				assert.Equal(t, "https://example.com", params.URL)
+			}
+		})
+	}
+}
+
 func Test_DeprecatedValidate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -171,3 +403,36 @@ func Test_DeprecatedValidate(t *testing.T) {
 		})
 	}
 }
+
+func Test_Expiration_Deadline(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expiration Expiration
+		expected   time.Time
+	}{
+		{
+			name:       "Never expires",
+			expiration: Expiration{},
+			expected:   time.Time{},
+		},
+		{
+			name:       "Absolute expiration",
+			expiration: Expiration{ExpiresAt: &expiresAt},
+			expected:   expiresAt,
+		},
+		{
+			name:       "TTL",
+			expiration: Expiration{TTL: 3600},
+			expected:   now.Add(time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.expiration.Deadline(now))
+		})
+	}
+}
@@ -14,12 +14,30 @@ var ErrCorrelationIDEmpty = errors.New("correlation id is required")
 // ErrShortCodeEmpty is returned when the short code is empty
 var ErrShortCodeEmpty = errors.New("short code is required")
 
+// ErrInvalidAlias is returned when the alias contains forbidden characters or has invalid length
+var ErrInvalidAlias = errors.New("invalid alias")
+
+// ErrAliasReserved is returned when the alias clashes with a reserved route
+var ErrAliasReserved = errors.New("alias is reserved")
+
+// ErrAliasAlreadyExists is returned when the alias is already taken
+var ErrAliasAlreadyExists = errors.New("alias already exists")
+
+// ErrShortCodeAlreadyExists is returned when the short code is already taken
+var ErrShortCodeAlreadyExists = errors.New("short code already exists")
+
 // ErrShortLinkNotFound is returned when the short link is not found
 var ErrShortLinkNotFound = errors.New("short link not found")
 
 // ErrShortLinkDeleted is returned when the short link is deleted
 var ErrShortLinkDeleted = errors.New("short link deleted")
 
+// ErrShortLinkExpired is returned when the short link is expired
+var ErrShortLinkExpired = errors.New("short link expired")
+
+// ErrInvalidExpiration is returned when the link expiration is invalid
+var ErrInvalidExpiration = errors.New("invalid expiration")
+
 // ErrFailedToReadRandomBytes is returned when the random bytes cannot be read
 var ErrFailedToReadRandomBytes = errors.New("failed to read secure random bytes")
 
@@ -44,6 +62,9 @@ var ErrFailedToSaveURL = errors.New("failed to save URL")
 // ErrURLAlreadyExists is returned when the URL already exists
 var ErrURLAlreadyExists = errors.New("URL already exists")
 
+// ErrFailedToUpdateURL is returned when the URL cannot be updated
+var ErrFailedToUpdateURL = errors.New("failed to update URL")
+
 // ErrInvalidToken is returned when JWT token is invalid
 var ErrInvalidToken = errors.New("invalid token")
 
@@ -56,5 +77,113 @@ var ErrInvalidUserID = errors.New("invalid user id")
 // ErrFailedToLoadUserUrls is returned when the user URLs cannot be loaded
 var ErrFailedToLoadUserUrls = errors.New("failed to load user URLs")
 
+// ErrFailedToLoadStats is returned when the short link statistics cannot be loaded
+var ErrFailedToLoadStats = errors.New("failed to load statistics")
+
+// ErrInvalidSort is returned when the listing order is unknown
+var ErrInvalidSort = errors.New("invalid sort")
+
+// ErrInvalidFilter is returned when a listing filter cannot be parsed
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// ErrUnsupportedImportFormat is returned when the import body is neither CSV nor NDJSON
+var ErrUnsupportedImportFormat = errors.New("unsupported import format, use text/csv or application/x-ndjson")
+
+// ErrImportFieldCount is returned when an import CSV row has more fields than expected
+var ErrImportFieldCount = errors.New("expected original_url,alias,correlation_id fields")
+
+// ErrInvalidAtomic is returned when the atomic batch flag is not a boolean
+var ErrInvalidAtomic = errors.New("atomic must be true or false")
+
+// ErrUnsupportedExportFormat is returned when the requested export format is not supported
+var ErrUnsupportedExportFormat = errors.New("unsupported export format, use csv, json or ndjson")
+
+// ErrFailedToRestoreURLs is returned when deleted short links cannot be restored
+var ErrFailedToRestoreURLs = errors.New("failed to restore URLs")
+
+// ErrFailedToDeleteURLs is returned when a delete job cannot delete short links
+var ErrFailedToDeleteURLs = errors.New("failed to delete URLs")
+
+// ErrDeleteJobNotFound is returned when the delete job is not found or belongs to another user
+var ErrDeleteJobNotFound = errors.New("delete job not found")
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrRateLimitExceeded is returned when the client exceeded the request rate limit
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
+// ErrInvalidRateLimit is returned when the rate limit cannot be parsed
+var ErrInvalidRateLimit = errors.New("invalid rate limit")
+
+// ErrAPITokenNotFound is returned when the API token is not found or already revoked
+var ErrAPITokenNotFound = errors.New("API token not found")
+
+// ErrAPITokenNameEmpty is returned when the API token name is empty
+var ErrAPITokenNameEmpty = errors.New("API token name is required")
+
+// ErrAPITokenNameTooLong is returned when the API token name exceeds the maximum length
+var ErrAPITokenNameTooLong = errors.New("API token name is too long")
+
+// ErrInvalidScope is returned when the API token scope is unknown
+var ErrInvalidScope = errors.New("invalid scope")
+
+// ErrInsufficientScope is returned when the API token lacks the scope required by the route
+var ErrInsufficientScope = errors.New("insufficient scope")
+
+// ErrSessionRequired is returned when the route cannot be accessed with an API token
+var ErrSessionRequired = errors.New("route requires a session cookie")
+
+// ErrInvalidSchedule is returned when the job schedule cannot be parsed
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ErrQueueEmpty is returned by a queue job when there is no work to process
+var ErrQueueEmpty = errors.New("queue is empty")
+
+// ErrInvalidLogLevel is returned when the log level is unknown
+var ErrInvalidLogLevel = errors.New("invalid log level")
+
+// ErrInvalidLogFormat is returned when the log format is neither json nor console
+var ErrInvalidLogFormat = errors.New("invalid log format")
+
+// ErrHealthCheckTimeout is returned when a health check does not finish within its timeout
+var ErrHealthCheckTimeout = errors.New("health check timed out")
+
+// ErrDeleteWorkerStalled is returned when no delete worker has polled the queue recently
+var ErrDeleteWorkerStalled = errors.New("delete worker stalled")
+
+// ErrDeleteQueueSaturated is returned when the delete queue holds more pending tasks than the threshold
+var ErrDeleteQueueSaturated = errors.New("delete queue saturated")
+
+// ErrCertificateExpired is returned when the TLS certificate has expired
+var ErrCertificateExpired = errors.New("certificate expired")
+
+// ErrCertificateExpiring is returned when the TLS certificate expires soon
+var ErrCertificateExpiring = errors.New("certificate expires soon")
+
+// ErrInvalidCertificate is returned when the TLS certificate cannot be parsed
+var ErrInvalidCertificate = errors.New("invalid certificate")
+
+// ErrUntrustedNetwork is returned when the client is outside of the trusted subnet
+var ErrUntrustedNetwork = errors.New("untrusted network")
+
+// ErrInvalidTrustedSubnet is returned when the trusted subnet is not a CIDR
+var ErrInvalidTrustedSubnet = errors.New("invalid trusted subnet")
+
+// ErrInvalidTrustedProxy is returned when a trusted proxy is not a CIDR
+var ErrInvalidTrustedProxy = errors.New("invalid trusted proxy")
+
+// ErrMissingToken is returned when a call lacks the authorization token
+var ErrMissingToken = errors.New("authorization token is required")
+
+// ErrInternalServer is returned when a call failed unexpectedly, the cause is logged only
+var ErrInternalServer = errors.New("internal server error")
+
 // Is a shortcut for errors.Is
 var Is = errors.Is
+
+// As a shortcut for errors.As
+var As = errors.As
+
+// Join a shortcut for errors.Join
+var Join = errors.Join
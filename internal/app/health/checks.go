@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"time"
+
+	"shortly/internal/app/errors"
+)
+
+// CertificateExpiryWarning is how long before its expiry a certificate is reported degraded
+const CertificateExpiryWarning = 14 * 24 * time.Hour
+
+// CertificateCheck reports the PEM certificate at the path down once expired and degraded when it expires soon,
+// the file is read on every check, so a renewed certificate is picked up
+func CertificateCheck(path string) Check {
+	return func(_ context.Context) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return errors.ErrInvalidCertificate
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return errors.ErrInvalidCertificate
+		}
+
+		left := time.Until(cert.NotAfter)
+		switch {
+		case left <= 0:
+			return errors.ErrCertificateExpired
+		case left < CertificateExpiryWarning:
+			return Degraded(errors.ErrCertificateExpiring)
+		}
+
+		return nil
+	}
+}
+
+// WritableCheck reports the file storage at the path down when a file cannot be created next to it
+func WritableCheck(path string) Check {
+	return func(_ context.Context) error {
+		file, err := os.CreateTemp(filepath.Dir(path), ".health-*")
+		if err != nil {
+			return err
+		}
+
+		file.Close()
+
+		return os.Remove(file.Name())
+	}
+}
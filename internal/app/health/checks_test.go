@@ -0,0 +1,140 @@
+package health
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/errors"
+)
+
+func Test_CertificateCheck(t *testing.T) {
+	dir := t.TempDir()
+
+	invalid := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name     string
+		path     string
+		status   string
+		expected error
+	}{
+		{
+			name:   "Valid",
+			path:   generateCertificate(t, dir, 90*24*time.Hour),
+			status: StatusUp,
+		},
+		{
+			name:     "Expiring",
+			path:     generateCertificate(t, dir, 24*time.Hour),
+			status:   StatusDegraded,
+			expected: errors.ErrCertificateExpiring,
+		},
+		{
+			name:     "Expired",
+			path:     generateCertificate(t, dir, -time.Hour),
+			status:   StatusDown,
+			expected: errors.ErrCertificateExpired,
+		},
+		{
+			name:     "Invalid",
+			path:     invalid,
+			status:   StatusDown,
+			expected: errors.ErrInvalidCertificate,
+		},
+		{
+			name:     "Missing",
+			path:     filepath.Join(dir, "missing.pem"),
+			status:   StatusDown,
+			expected: os.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CertificateCheck(tt.path)(context.Background())
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+			assert.Equal(t, tt.status, status(err))
+		})
+	}
+}
+
+func Test_WritableCheck(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		path    string
+		isError bool
+	}{
+		{
+			name: "Writable",
+			path: filepath.Join(dir, "storage.json"),
+		},
+		{
+			name:    "Missing directory",
+			path:    filepath.Join(dir, "missing", "storage.json"),
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WritableCheck(tt.path)(context.Background())
+
+			if tt.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			for _, entry := range entries {
+				assert.True(t, entry.IsDir(), entry.Name())
+			}
+		})
+	}
+}
+
+// generateCertificate writes a self-signed certificate expiring after the duration and returns its path
+func generateCertificate(t *testing.T, dir string, expiresIn time.Duration) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-24 * time.Hour),
+		NotAfter:     time.Now().Add(expiresIn),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	file, err := os.CreateTemp(dir, "cert-*.pem")
+	require.NoError(t, err)
+	defer file.Close()
+
+	require.NoError(t, pem.Encode(file, &pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	return file.Name()
+}
@@ -0,0 +1,223 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+)
+
+// StatusUp is the status of a healthy component
+const StatusUp = "up"
+
+// StatusDegraded is the status of a component serving with reduced capacity
+const StatusDegraded = "degraded"
+
+// StatusDown is the status of a failing component
+const StatusDown = "down"
+
+// Check is the health check of a component, a nil error reports the component up,
+// an error wrapped with Degraded reports it degraded and any other error reports it down
+type Check func(ctx context.Context) error
+
+// CheckOptions configures the health check of a component
+type CheckOptions struct {
+	// Critical components report the application down when they are down, other components degrade it
+	Critical bool
+	// Timeout overrides the default timeout of the check
+	Timeout time.Duration
+	// TTL overrides the default time the result of the check is reused for
+	TTL time.Duration
+}
+
+// Registry is an interface for the registry of the component health checks
+type Registry interface {
+	// Register adds the health check of the component, the check of a registered component is replaced
+	Register(name string, opts CheckOptions, check Check)
+	// Report checks all components in parallel, reusing the cached results, and reports the overall status
+	Report(ctx context.Context) dto.HealthReport
+}
+
+type registry struct {
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu         sync.RWMutex
+	components map[string]*component
+}
+
+type component struct {
+	name  string
+	opts  CheckOptions
+	check Check
+
+	mu      sync.Mutex
+	result  dto.ComponentHealth
+	expires time.Time
+}
+
+type degradedError struct {
+	err error
+}
+
+// NewRegistry creates a new registry with the check timeout and the cache TTL of the config
+func NewRegistry(cfg *config.Config) Registry {
+	return &registry{
+		timeout:    cfg.HealthCheckTimeout,
+		ttl:        cfg.HealthCacheTTL,
+		components: make(map[string]*component),
+	}
+}
+
+// Degraded marks the error of a check as a degraded component, a nil error stays nil
+func Degraded(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &degradedError{err: err}
+}
+
+// IsDegraded reports whether the error of a check marks a degraded component
+func IsDegraded(err error) bool {
+	var degraded *degradedError
+	return errors.As(err, &degraded)
+}
+
+// Error returns the message of the wrapped error
+func (e *degradedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *degradedError) Unwrap() error {
+	return e.err
+}
+
+// Register adds the health check of the component, the check of a registered component is replaced
+func (r *registry) Register(name string, opts CheckOptions, check Check) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = r.timeout
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = r.ttl
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.components[name] = &component{name: name, opts: opts, check: check}
+}
+
+// Report checks all components in parallel, reusing the cached results, and reports the overall status
+func (r *registry) Report(ctx context.Context) dto.HealthReport {
+	r.mu.RLock()
+	components := make([]*component, 0, len(r.components))
+	for _, c := range r.components {
+		components = append(components, c)
+	}
+	r.mu.RUnlock()
+
+	results := make([]dto.ComponentHealth, len(components))
+
+	var wg sync.WaitGroup
+	for i, c := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	report := dto.HealthReport{
+		Status:     StatusUp,
+		Components: make(map[string]dto.ComponentHealth, len(components)),
+	}
+	for i, c := range components {
+		report.Components[c.name] = results[i]
+		report.Status = worse(report.Status, impact(results[i]))
+	}
+
+	return report
+}
+
+// run returns the cached result of the check, or runs the check once it expired
+func (c *component) run(ctx context.Context) dto.ComponentHealth {
+	// NOTE: the lock is held while checking, so concurrent probes wait for a single check instead of piling up
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Now().Before(c.expires) {
+		return c.result
+	}
+
+	start := time.Now()
+	err := c.call(ctx)
+
+	c.result = dto.ComponentHealth{
+		Status:     status(err),
+		Critical:   c.opts.Critical,
+		DurationMs: time.Since(start).Milliseconds(),
+		CheckedAt:  start,
+	}
+	if err != nil {
+		c.result.Error = err.Error()
+	}
+	c.expires = start.Add(c.opts.TTL)
+
+	return c.result
+}
+
+// call runs the check within its timeout, a check ignoring the context is abandoned after the timeout
+func (c *component) call(ctx context.Context) error {
+	// NOTE: the result is shared between requests, so a cancelled request must not fail the check
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.opts.Timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.ErrHealthCheckTimeout
+	}
+}
+
+// status returns the status of a component from the error of its check
+func status(err error) string {
+	switch {
+	case err == nil:
+		return StatusUp
+	case IsDegraded(err):
+		return StatusDegraded
+	default:
+		return StatusDown
+	}
+}
+
+// impact returns the status of the application caused by the component, only critical components take it down
+func impact(result dto.ComponentHealth) string {
+	if result.Status == StatusDown && !result.Critical {
+		return StatusDegraded
+	}
+
+	return result.Status
+}
+
+// worse returns the worse of the statuses
+func worse(a, b string) string {
+	rank := map[string]int{StatusUp: 0, StatusDegraded: 1, StatusDown: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+
+	return a
+}
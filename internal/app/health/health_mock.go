@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/health/health.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/health/health.go -destination=internal/app/health/health_mock.go -package=health
+//
+
+// Package health is a generated GoMock package.
+package health
+
+import (
+	context "context"
+	reflect "reflect"
+	dto "shortly/internal/app/dto"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRegistry is a mock of Registry interface.
+type MockRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistryMockRecorder
+	isgomock struct{}
+}
+
+// MockRegistryMockRecorder is the mock recorder for MockRegistry.
+type MockRegistryMockRecorder struct {
+	mock *MockRegistry
+}
+
+// NewMockRegistry creates a new mock instance.
+func NewMockRegistry(ctrl *gomock.Controller) *MockRegistry {
+	mock := &MockRegistry{ctrl: ctrl}
+	mock.recorder = &MockRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistry) EXPECT() *MockRegistryMockRecorder {
+	return m.recorder
+}
+
+// Register mocks base method.
+func (m *MockRegistry) Register(name string, opts CheckOptions, check Check) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Register", name, opts, check)
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockRegistryMockRecorder) Register(name, opts, check any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockRegistry)(nil).Register), name, opts, check)
+}
+
+// Report mocks base method.
+func (m *MockRegistry) Report(ctx context.Context) dto.HealthReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx)
+	ret0, _ := ret[0].(dto.HealthReport)
+	return ret0
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockRegistryMockRecorder) Report(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockRegistry)(nil).Report), ctx)
+}
@@ -0,0 +1,187 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/errors"
+)
+
+func Test_Registry_Report(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return assert.AnError }
+	degraded := func(context.Context) error { return Degraded(assert.AnError) }
+
+	type check struct {
+		critical bool
+		check    Check
+	}
+
+	tests := []struct {
+		name       string
+		checks     map[string]check
+		status     string
+		components map[string]string
+	}{
+		{
+			name:       "No components",
+			checks:     map[string]check{},
+			status:     StatusUp,
+			components: map[string]string{},
+		},
+		{
+			name: "All up",
+			checks: map[string]check{
+				"database": {critical: true, check: up},
+				"worker":   {check: up},
+			},
+			status:     StatusUp,
+			components: map[string]string{"database": StatusUp, "worker": StatusUp},
+		},
+		{
+			name: "Degraded component",
+			checks: map[string]check{
+				"database": {critical: true, check: up},
+				"worker":   {check: degraded},
+			},
+			status:     StatusDegraded,
+			components: map[string]string{"database": StatusUp, "worker": StatusDegraded},
+		},
+		{
+			name: "Non-critical component down",
+			checks: map[string]check{
+				"database": {critical: true, check: up},
+				"worker":   {check: down},
+			},
+			status:     StatusDegraded,
+			components: map[string]string{"database": StatusUp, "worker": StatusDown},
+		},
+		{
+			name: "Critical component down",
+			checks: map[string]check{
+				"database": {critical: true, check: down},
+				"worker":   {check: degraded},
+			},
+			status:     StatusDown,
+			components: map[string]string{"database": StatusDown, "worker": StatusDegraded},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(&config.Config{HealthCheckTimeout: time.Second, HealthCacheTTL: time.Second})
+			for name, c := range tt.checks {
+				registry.Register(name, CheckOptions{Critical: c.critical}, c.check)
+			}
+
+			report := registry.Report(context.Background())
+
+			assert.Equal(t, tt.status, report.Status)
+			assert.Len(t, report.Components, len(tt.components))
+			for name, status := range tt.components {
+				assert.Equal(t, status, report.Components[name].Status, name)
+				assert.Equal(t, tt.checks[name].critical, report.Components[name].Critical, name)
+				assert.False(t, report.Components[name].CheckedAt.IsZero(), name)
+			}
+		})
+	}
+}
+
+func Test_Registry_ReportError(t *testing.T) {
+	registry := NewRegistry(&config.Config{HealthCheckTimeout: time.Second, HealthCacheTTL: time.Second})
+	registry.Register("up", CheckOptions{}, func(context.Context) error { return nil })
+	registry.Register("down", CheckOptions{}, func(context.Context) error { return assert.AnError })
+
+	report := registry.Report(context.Background())
+
+	assert.Empty(t, report.Components["up"].Error)
+	assert.Equal(t, assert.AnError.Error(), report.Components["down"].Error)
+}
+
+func Test_Registry_Timeout(t *testing.T) {
+	registry := NewRegistry(&config.Config{HealthCheckTimeout: time.Second, HealthCacheTTL: time.Second})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	registry.Register("stuck", CheckOptions{Timeout: 10 * time.Millisecond}, func(context.Context) error {
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	report := registry.Report(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, StatusDown, report.Components["stuck"].Status)
+	assert.Equal(t, errors.ErrHealthCheckTimeout.Error(), report.Components["stuck"].Error)
+}
+
+func Test_Registry_Cache(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		wait     time.Duration
+		expected int32
+	}{
+		{
+			name:     "Cached",
+			ttl:      time.Minute,
+			expected: 1,
+		},
+		{
+			name:     "Expired",
+			ttl:      10 * time.Millisecond,
+			wait:     20 * time.Millisecond,
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(&config.Config{HealthCheckTimeout: time.Second, HealthCacheTTL: time.Second})
+
+			var calls atomic.Int32
+			registry.Register("database", CheckOptions{TTL: tt.ttl}, func(context.Context) error {
+				calls.Add(1)
+				return nil
+			})
+
+			registry.Report(context.Background())
+			time.Sleep(tt.wait)
+			registry.Report(context.Background())
+
+			assert.Equal(t, tt.expected, calls.Load())
+		})
+	}
+}
+
+func Test_Registry_CancelledRequest(t *testing.T) {
+	registry := NewRegistry(&config.Config{HealthCheckTimeout: time.Second, HealthCacheTTL: time.Second})
+	registry.Register("database", CheckOptions{}, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report := registry.Report(ctx)
+
+	assert.Equal(t, StatusUp, report.Components["database"].Status)
+}
+
+func Test_Degraded(t *testing.T) {
+	assert.NoError(t, Degraded(nil))
+
+	err := Degraded(errors.ErrDeleteQueueSaturated)
+	assert.ErrorIs(t, err, errors.ErrDeleteQueueSaturated)
+	assert.Equal(t, errors.ErrDeleteQueueSaturated.Error(), err.Error())
+	assert.True(t, IsDegraded(err))
+	assert.False(t, IsDegraded(errors.ErrDeleteQueueSaturated))
+	assert.Equal(t, StatusDegraded, status(err))
+}
@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace is the prefix of all application metrics
+const Namespace = "shortly"
+
+// Registry is the application metrics registry
+var Registry = prometheus.NewRegistry()
+
+// HTTPRequestsTotal counts HTTP requests per route pattern and status code class
+var HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "http",
+	Name:      "requests_total",
+	Help:      "Total number of HTTP requests.",
+}, []string{"method", "route", "code"})
+
+// HTTPRequestDuration observes HTTP request latencies per route pattern and status code class
+var HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: Namespace,
+	Subsystem: "http",
+	Name:      "request_duration_seconds",
+	Help:      "HTTP request latencies in seconds.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "code"})
+
+// RepositoryOperationDuration observes repository operation latencies per backend
+var RepositoryOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: Namespace,
+	Subsystem: "repository",
+	Name:      "operation_duration_seconds",
+	Help:      "Repository operation latencies in seconds.",
+	Buckets:   []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1},
+}, []string{"backend", "operation"})
+
+// DeleteQueueDepth is the number of delete requests waiting in the durable queue
+var DeleteQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: Namespace,
+	Subsystem: "delete_worker",
+	Name:      "queue_depth",
+	Help:      "Number of delete requests waiting in the queue.",
+})
+
+// DeleteDeadLetters is the number of delete requests moved to the dead letters after the last attempt
+var DeleteDeadLetters = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: Namespace,
+	Subsystem: "delete_worker",
+	Name:      "dead_letters",
+	Help:      "Number of delete requests that failed permanently.",
+})
+
+// DeleteRequestsRetried counts failed attempts of delete requests scheduled for a retry
+var DeleteRequestsRetried = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "delete_worker",
+	Name:      "retried_total",
+	Help:      "Total number of delete request retries.",
+})
+
+// DeleteRequestsProcessed counts successfully processed delete requests
+var DeleteRequestsProcessed = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "delete_worker",
+	Name:      "processed_total",
+	Help:      "Total number of processed delete requests.",
+})
+
+// DeleteRequestsFailed counts failed delete requests
+var DeleteRequestsFailed = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "delete_worker",
+	Name:      "failed_total",
+	Help:      "Total number of failed delete requests.",
+})
+
+// DeleteBatchSize observes the number of delete requests coalesced into a single repository call
+var DeleteBatchSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Namespace: Namespace,
+	Subsystem: "delete_worker",
+	Name:      "batch_size",
+	Help:      "Number of delete requests flushed in a single batch.",
+	Buckets:   []float64{1, 2, 5, 10, 25, 50, 100, 250},
+})
+
+// JobRuns counts background job runs per job and result
+var JobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "jobs",
+	Name:      "runs_total",
+	Help:      "Total number of background job runs.",
+}, []string{"job", "result"})
+
+// JobDuration observes background job run latencies per job
+var JobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: Namespace,
+	Subsystem: "jobs",
+	Name:      "duration_seconds",
+	Help:      "Background job run latencies in seconds.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"job"})
+
+// ShortCodeRetries counts short code generation attempts rejected because the code was taken
+var ShortCodeRetries = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "short_code",
+	Name:      "generation_retries_total",
+	Help:      "Total number of short code generation retries caused by collisions.",
+})
+
+// RateLimitedRequests counts requests rejected by the rate limiter per scope
+var RateLimitedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "http",
+	Name:      "rate_limited_total",
+	Help:      "Total number of requests rejected by the rate limiter.",
+}, []string{"scope"})
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		RepositoryOperationDuration,
+		DeleteQueueDepth,
+		DeleteDeadLetters,
+		DeleteRequestsRetried,
+		DeleteRequestsProcessed,
+		DeleteRequestsFailed,
+		DeleteBatchSize,
+		JobRuns,
+		JobDuration,
+		ShortCodeRetries,
+		RateLimitedRequests,
+		pool,
+	)
+}
+
+// Handler returns an HTTP handler exposing the application metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveRequest records an HTTP request
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := StatusClass(status)
+
+	HTTPRequestsTotal.WithLabelValues(method, route, code).Inc()
+	HTTPRequestDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveRepository records a repository operation latency, intended to be deferred with the start time
+func ObserveRepository(backend, operation string, start time.Time) {
+	RepositoryOperationDuration.WithLabelValues(backend, operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveJob records a background job run with its result, intended to be deferred with the start time
+func ObserveJob(job, result string, start time.Time) {
+	JobRuns.WithLabelValues(job, result).Inc()
+	JobDuration.WithLabelValues(job).Observe(time.Since(start).Seconds())
+}
+
+// StatusClass returns the status code class, e.g. "2xx"
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+
+	return strconv.Itoa(status/100) + "xx"
+}
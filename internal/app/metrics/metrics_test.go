@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StatusClass(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		expected string
+	}{
+		{name: "Informational", status: http.StatusContinue, expected: "1xx"},
+		{name: "Success", status: http.StatusOK, expected: "2xx"},
+		{name: "Redirect", status: http.StatusTemporaryRedirect, expected: "3xx"},
+		{name: "Client error", status: http.StatusNotFound, expected: "4xx"},
+		{name: "Server error", status: http.StatusInternalServerError, expected: "5xx"},
+		{name: "Unknown", status: 0, expected: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StatusClass(tt.status))
+		})
+	}
+}
+
+func Test_ObserveRequest(t *testing.T) {
+	before := testutil.ToFloat64(HTTPRequestsTotal.WithLabelValues(http.MethodGet, "/test/{id}", "4xx"))
+
+	ObserveRequest(http.MethodGet, "/test/{id}", http.StatusNotFound, 10*time.Millisecond)
+
+	after := testutil.ToFloat64(HTTPRequestsTotal.WithLabelValues(http.MethodGet, "/test/{id}", "4xx"))
+	assert.Equal(t, before+1, after)
+}
+
+func Test_ObserveRepository(t *testing.T) {
+	before := testutil.CollectAndCount(RepositoryOperationDuration)
+
+	ObserveRepository("test", "observe", time.Now())
+
+	assert.Equal(t, before+1, testutil.CollectAndCount(RepositoryOperationDuration))
+}
+
+func Test_ObserveJob(t *testing.T) {
+	before := testutil.ToFloat64(JobRuns.WithLabelValues("test", "success"))
+
+	ObserveJob("test", "success", time.Now())
+
+	assert.Equal(t, before+1, testutil.ToFloat64(JobRuns.WithLabelValues("test", "success")))
+}
+
+func Test_Handler(t *testing.T) {
+	ShortCodeRetries.Inc()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+
+	Handler().ServeHTTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "shortly_short_code_generation_retries_total")
+	assert.Contains(t, string(body), "shortly_delete_worker_queue_depth")
+	assert.Contains(t, string(body), "go_goroutines")
+}
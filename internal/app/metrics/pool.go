@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pool is the collector of the database connection pool statistics
+var pool = newPoolCollector()
+
+// poolCollector exposes pgxpool statistics of the registered pool
+type poolCollector struct {
+	pool atomic.Pointer[pgxpool.Pool]
+
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	totalConns      *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+}
+
+func newPoolCollector() *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(Namespace, "pgxpool", name), help, nil, nil)
+	}
+
+	return &poolCollector{
+		acquiredConns:   desc("acquired_conns", "Number of currently acquired connections."),
+		idleConns:       desc("idle_conns", "Number of currently idle connections."),
+		totalConns:      desc("total_conns", "Total number of connections in the pool."),
+		maxConns:        desc("max_conns", "Maximum size of the pool."),
+		acquireCount:    desc("acquire_total", "Total number of successful connection acquisitions."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Total time spent acquiring connections in seconds."),
+		emptyAcquire:    desc("empty_acquire_total", "Total number of acquisitions that waited for a connection."),
+	}
+}
+
+// RegisterPool sets the database connection pool to collect statistics from
+func RegisterPool(p *pgxpool.Pool) {
+	pool.pool.Store(p)
+}
+
+// Describe sends the descriptors of the pool metrics
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquire
+}
+
+// Collect sends the current pool statistics, nothing is sent until a pool is registered
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	p := c.pool.Load()
+	if p == nil {
+		return
+	}
+
+	stat := p.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+}
@@ -2,22 +2,48 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 
 	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
 	"shortly/internal/app/service"
+	"shortly/internal/logger"
 )
 
 // CookieName is the name of the authentication cookie
 const CookieName = "auth"
 
-// Middleware is a middleware for authentication
-func Middleware(authenticator service.Authenticator) func(next http.Handler) http.Handler {
+// BearerScheme is the authorization scheme of API tokens
+const BearerScheme = "Bearer "
+
+// Middleware is a middleware for authentication by an API token or the authentication cookie
+func Middleware(authenticator service.Authenticator, tokens service.TokenResolver) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if raw, ok := bearerToken(r); ok {
+				// NOTE: an invalid API token is rejected instead of minting a new user, scripts must notice it
+				token, err := tokens.Resolve(r.Context(), raw)
+				if err != nil {
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+					writeError(w, http.StatusUnauthorized, errors.ErrInvalidToken)
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), dto.CurrentUser, token.UserUUID)
+				ctx = context.WithValue(ctx, dto.Authenticated, true)
+				ctx = context.WithValue(ctx, dto.TokenScopes, token.Scopes)
+				ctx = logger.WithUser(ctx, token.UserUUID.String())
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			var currentUserID uuid.UUID
+			var authenticated bool
 			cookie, err := r.Cookie(CookieName)
 
 			if err != nil || cookie == nil {
@@ -28,6 +54,7 @@ func Middleware(authenticator service.Authenticator) func(next http.Handler) htt
 				}
 			} else {
 				currentUserID, err = authenticator.Verify(cookie.Value)
+				authenticated = err == nil
 				if err != nil {
 					currentUserID, err = currentUser(w, authenticator)
 					if err != nil {
@@ -38,11 +65,58 @@ func Middleware(authenticator service.Authenticator) func(next http.Handler) htt
 			}
 
 			ctx := context.WithValue(r.Context(), dto.CurrentUser, currentUserID)
+			ctx = context.WithValue(ctx, dto.Authenticated, authenticated)
+			ctx = logger.WithUser(ctx, currentUserID.String())
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// RequireScope is a middleware rejecting API token requests lacking the scope, cookie sessions are not restricted
+func RequireScope(scope string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, ok := r.Context().Value(dto.TokenScopes).([]string)
+			if ok && !slices.Contains(scopes, scope) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+				writeError(w, http.StatusForbidden, errors.ErrInsufficientScope)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession is a middleware rejecting requests authenticated by an API token
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(dto.TokenScopes).([]string); ok {
+			writeError(w, http.StatusForbidden, errors.ErrSessionRequired)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bearerToken returns the API token of the Authorization header, other schemes are left to the cookie
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if len(header) < len(BearerScheme) || !strings.EqualFold(header[:len(BearerScheme)], BearerScheme) {
+		return "", false
+	}
+
+	return strings.TrimSpace(header[len(BearerScheme):]), true
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(dto.ErrorResponse{Error: err.Error()})
+}
+
 func currentUser(w http.ResponseWriter, authenticator service.Authenticator) (uuid.UUID, error) {
 	currentUserID, err := uuid.NewRandom()
 	if err != nil {
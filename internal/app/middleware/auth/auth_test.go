@@ -1,27 +1,38 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 
 	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
 	"shortly/internal/app/service"
 )
 
 func Test_Middleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
 	client := &http.Client{}
 	cfg := &config.Config{
 		SecretKey: "jwt-secret-key",
 	}
 	authenticator := service.NewAuthService(cfg)
+	tokens := service.NewMockTokenResolver(ctrl)
+	tokens.EXPECT().Resolve(gomock.Any(), gomock.Any()).Times(0)
 
 	type result struct {
 		code            int
 		cookieGenerated bool
+		authenticated   bool
 	}
 
 	tests := []struct {
@@ -49,6 +60,7 @@ func Test_Middleware(t *testing.T) {
 			expected: result{
 				code:            http.StatusOK,
 				cookieGenerated: false,
+				authenticated:   true,
 			},
 		},
 		{
@@ -79,6 +91,7 @@ func Test_Middleware(t *testing.T) {
 			expected: result{
 				code:            http.StatusOK,
 				cookieGenerated: false,
+				authenticated:   true,
 			},
 		},
 		{
@@ -94,7 +107,10 @@ func Test_Middleware(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			var authenticated bool
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authenticated, _ = r.Context().Value(dto.Authenticated).(bool)
+
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 				_, err := w.Write([]byte(`{"response": "ok"}`))
@@ -102,7 +118,7 @@ func Test_Middleware(t *testing.T) {
 			})
 
 			var wrappedHandler http.Handler = handler
-			wrappedHandler = Middleware(authenticator)(wrappedHandler)
+			wrappedHandler = Middleware(authenticator, tokens)(wrappedHandler)
 
 			ts := httptest.NewServer(wrappedHandler)
 			defer ts.Close()
@@ -128,6 +144,193 @@ func Test_Middleware(t *testing.T) {
 				}
 			}
 			assert.Equal(t, tt.expected.cookieGenerated, cookieFound)
+			assert.Equal(t, tt.expected.authenticated, authenticated)
+		})
+	}
+}
+
+func Test_Middleware_APIToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		SecretKey: "jwt-secret-key",
+	}
+	authenticator := service.NewAuthService(cfg)
+	tokens := service.NewMockTokenResolver(ctrl)
+
+	userID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	token := &repository.APIToken{UserUUID: userID, Scopes: []string{dto.ScopeLinksRead}}
+
+	type result struct {
+		code            int
+		cookieGenerated bool
+		userID          uuid.UUID
+		scopes          []string
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		prepare  func()
+		expected result
+	}{
+		{
+			name:   "Valid token",
+			header: "Bearer shk_valid",
+			prepare: func() {
+				tokens.EXPECT().Resolve(gomock.Any(), "shk_valid").Return(token, nil)
+			},
+			expected: result{
+				code:   http.StatusOK,
+				userID: userID,
+				scopes: []string{dto.ScopeLinksRead},
+			},
+		},
+		{
+			name:   "Case insensitive scheme",
+			header: "bearer shk_valid",
+			prepare: func() {
+				tokens.EXPECT().Resolve(gomock.Any(), "shk_valid").Return(token, nil)
+			},
+			expected: result{
+				code:   http.StatusOK,
+				userID: userID,
+				scopes: []string{dto.ScopeLinksRead},
+			},
+		},
+		{
+			name:   "Invalid token",
+			header: "Bearer shk_revoked",
+			prepare: func() {
+				tokens.EXPECT().Resolve(gomock.Any(), "shk_revoked").Return(nil, errors.ErrInvalidToken)
+			},
+			expected: result{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name:    "Other scheme falls back to the cookie",
+			header:  "Basic dXNlcjpwYXNz",
+			prepare: func() {},
+			expected: result{
+				code:            http.StatusOK,
+				cookieGenerated: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			var currentUserID uuid.UUID
+			var scopes []string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				currentUserID, _ = r.Context().Value(dto.CurrentUser).(uuid.UUID)
+				scopes, _ = r.Context().Value(dto.TokenScopes).([]string)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tt.header)
+			w := httptest.NewRecorder()
+
+			Middleware(authenticator, tokens)(handler).ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expected.code, resp.StatusCode)
+			assert.Equal(t, tt.expected.scopes, scopes)
+			assert.Equal(t, tt.expected.cookieGenerated, len(resp.Cookies()) > 0)
+
+			if tt.expected.userID != uuid.Nil {
+				assert.Equal(t, tt.expected.userID, currentUserID)
+			}
+
+			if tt.expected.code == http.StatusUnauthorized {
+				assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "invalid_token")
+			}
+		})
+	}
+}
+
+func Test_RequireScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		expected int
+	}{
+		{
+			name:     "Cookie session",
+			scopes:   nil,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "Token with the scope",
+			scopes:   []string{dto.ScopeLinksRead, dto.ScopeLinksWrite},
+			expected: http.StatusOK,
+		},
+		{
+			name:     "Token without the scope",
+			scopes:   []string{dto.ScopeLinksRead},
+			expected: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/shorten", nil)
+			if tt.scopes != nil {
+				req = req.WithContext(context.WithValue(req.Context(), dto.TokenScopes, tt.scopes))
+			}
+			w := httptest.NewRecorder()
+
+			RequireScope(dto.ScopeLinksWrite)(handler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func Test_RequireSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		expected int
+	}{
+		{
+			name:     "Cookie session",
+			scopes:   nil,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "API token",
+			scopes:   []string{dto.ScopeLinksRead, dto.ScopeLinksWrite},
+			expected: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/tokens", nil)
+			if tt.scopes != nil {
+				req = req.WithContext(context.WithValue(req.Context(), dto.TokenScopes, tt.scopes))
+			}
+			w := httptest.NewRecorder()
+
+			RequireSession(handler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
 		})
 	}
 }
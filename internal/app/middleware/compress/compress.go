@@ -89,6 +89,20 @@ func (c *compressWriter) WriteHeader(statusCode int) {
 	c.writer.WriteHeader(statusCode)
 }
 
+// Flush flushes the compressed data buffered so far to the client
+func (c *compressWriter) Flush() {
+	if err := c.gzipWriter.Flush(); err != nil {
+		return
+	}
+
+	http.NewResponseController(c.writer).Flush()
+}
+
+// Unwrap returns the original writer, so http.ResponseController reaches deadlines
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.writer
+}
+
 // Close closes the writer
 func (c *compressWriter) Close() error {
 	return c.gzipWriter.Close()
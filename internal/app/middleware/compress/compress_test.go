@@ -172,3 +172,30 @@ func Test_CompressMiddleware_RequestDecompression(t *testing.T) {
 		})
 	}
 }
+
+func Test_CompressMiddleware_Flush(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"line": 1}`))
+		assert.NoError(t, err)
+
+		assert.NoError(t, http.NewResponseController(w).Flush())
+		assert.True(t, recorder.Flushed)
+
+		reader, err := gzip.NewReader(bytes.NewReader(recorder.Body.Bytes()))
+		assert.NoError(t, err)
+
+		// NOTE: the stream is not closed yet, so the flushed data is followed by an unexpected EOF
+		flushed, _ := io.ReadAll(reader)
+		assert.Equal(t, `{"line": 1}`, string(flushed))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	Middleware(handler).ServeHTTP(recorder, request)
+
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"shortly/internal/app/metrics"
+)
+
+func Test_Middleware(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/api/shorten/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.Post("/api/shorten", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		route  string
+		code   string
+	}{
+		{
+			name:   "Route pattern",
+			method: http.MethodGet,
+			path:   "/api/shorten/abcd1234",
+			route:  "/api/shorten/{id}",
+			code:   "4xx",
+		},
+		{
+			name:   "Implicit status",
+			method: http.MethodPost,
+			path:   "/api/shorten",
+			route:  "/api/shorten",
+			code:   "2xx",
+		},
+		{
+			name:   "Unmatched route",
+			method: http.MethodGet,
+			path:   "/unknown/path",
+			route:  UnmatchedRoute,
+			code:   "4xx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.HTTPRequestsTotal.WithLabelValues(tt.method, tt.route, tt.code)
+			before := testutil.ToFloat64(counter)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
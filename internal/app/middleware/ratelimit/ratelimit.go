@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/metrics"
+	"shortly/internal/app/middleware/trusted"
+)
+
+// CreateScope is the rate limit scope of short link creation routes
+const CreateScope = "create"
+
+// BatchScope is the rate limit scope of batch creation routes
+const BatchScope = "batch"
+
+// RedirectScope is the rate limit scope of short link resolution routes
+const RedirectScope = "redirect"
+
+// Middleware is a middleware limiting the request rate per user, or per client IP for unauthenticated requests,
+// the client IP is taken from the proxy headers only when the peer is a trusted proxy
+func Middleware(store Store, scope string, limit Limit, proxies []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !limit.Enabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := store.Take(r.Context(), scope+":"+clientKey(r, proxies), limit)
+
+			// NOTE: the store being unavailable must not take the service down, requests are let through
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset.Seconds())))
+
+			if !result.Allowed {
+				metrics.RateLimitedRequests.WithLabelValues(scope).Inc()
+
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter.Seconds())))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(dto.ErrorResponse{Error: errors.ErrRateLimitExceeded.Error()})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey returns the user UUID verified by the authentication cookie or an API token, or the client IP otherwise
+func clientKey(r *http.Request, proxies []*net.IPNet) string {
+	// NOTE: users issued on the fly are not trusted, dropping the cookie would reset the limit
+	if authenticated, _ := r.Context().Value(dto.Authenticated).(bool); authenticated {
+		if id, ok := r.Context().Value(dto.CurrentUser).(uuid.UUID); ok {
+			return "user:" + id.String()
+		}
+	}
+
+	// NOTE: clients behind a proxy share its address, so they are told apart by the headers the proxy sets
+	if ip := trusted.ClientIP(r, proxies); ip != nil {
+		return "ip:" + ip.String()
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// ceilSeconds rounds seconds up, so clients never retry too early
+func ceilSeconds(s float64) int {
+	return int(math.Ceil(s))
+}
@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/middleware/trusted"
+)
+
+func Test_Middleware(t *testing.T) {
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	limit := Limit{Requests: 1, Period: time.Minute}
+	proxies, err := trusted.ParseProxies("192.168.0.0/16")
+	require.NoError(t, err)
+
+	type result struct {
+		codes      []int
+		retryAfter string
+	}
+
+	tests := []struct {
+		name     string
+		limit    Limit
+		requests []func(r *http.Request) *http.Request
+		expected result
+	}{
+		{
+			name:  "Disabled",
+			limit: Limit{},
+			requests: []func(r *http.Request) *http.Request{
+				fromIP("10.0.0.1:1000"),
+				fromIP("10.0.0.1:1000"),
+			},
+			expected: result{codes: []int{http.StatusOK, http.StatusOK}},
+		},
+		{
+			name:  "Same IP",
+			limit: limit,
+			requests: []func(r *http.Request) *http.Request{
+				fromIP("10.0.0.1:1000"),
+				fromIP("10.0.0.1:2000"),
+			},
+			expected: result{codes: []int{http.StatusOK, http.StatusTooManyRequests}, retryAfter: "60"},
+		},
+		{
+			name:  "Different IPs",
+			limit: limit,
+			requests: []func(r *http.Request) *http.Request{
+				fromIP("10.0.0.1:1000"),
+				fromIP("10.0.0.2:1000"),
+			},
+			expected: result{codes: []int{http.StatusOK, http.StatusOK}},
+		},
+		{
+			name:  "Authenticated user from different IPs",
+			limit: limit,
+			requests: []func(r *http.Request) *http.Request{
+				asUser(UserUUID, true, "10.0.0.1:1000"),
+				asUser(UserUUID, true, "10.0.0.2:1000"),
+			},
+			expected: result{codes: []int{http.StatusOK, http.StatusTooManyRequests}, retryAfter: "60"},
+		},
+		{
+			name:  "Clients behind a trusted proxy",
+			limit: limit,
+			requests: []func(r *http.Request) *http.Request{
+				withHeader(trusted.RealIPHeader, "203.0.113.1", fromIP("192.168.0.1:1000")),
+				withHeader(trusted.ForwardedForHeader, "203.0.113.2", fromIP("192.168.0.1:1000")),
+			},
+			expected: result{codes: []int{http.StatusOK, http.StatusOK}},
+		},
+		{
+			name:  "Same client behind a trusted proxy",
+			limit: limit,
+			requests: []func(r *http.Request) *http.Request{
+				withHeader(trusted.RealIPHeader, "203.0.113.1", fromIP("192.168.0.1:1000")),
+				withHeader(trusted.ForwardedForHeader, "203.0.113.1", fromIP("192.168.0.2:1000")),
+			},
+			expected: result{codes: []int{http.StatusOK, http.StatusTooManyRequests}, retryAfter: "60"},
+		},
+		{
+			name:  "Proxy headers of untrusted peers are ignored",
+			limit: limit,
+			requests: []func(r *http.Request) *http.Request{
+				withHeader(trusted.RealIPHeader, "203.0.113.1", fromIP("10.0.0.1:1000")),
+				withHeader(trusted.ForwardedForHeader, "203.0.113.2", fromIP("10.0.0.1:1000")),
+			},
+			expected: result{codes: []int{http.StatusOK, http.StatusTooManyRequests}, retryAfter: "60"},
+		},
+		{
+			name:  "Users issued on the fly are limited by IP",
+			limit: limit,
+			requests: []func(r *http.Request) *http.Request{
+				asUser(uuid.New(), false, "10.0.0.1:1000"),
+				asUser(uuid.New(), false, "10.0.0.1:1000"),
+			},
+			expected: result{codes: []int{http.StatusOK, http.StatusTooManyRequests}, retryAfter: "60"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Middleware(NewMemoryStore(), CreateScope, tt.limit, proxies)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			var codes []int
+			var last *http.Response
+
+			for _, request := range tt.requests {
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, request(httptest.NewRequest(http.MethodPost, "/api/shorten", nil)))
+
+				last = w.Result()
+				last.Body.Close()
+				codes = append(codes, last.StatusCode)
+			}
+
+			assert.Equal(t, tt.expected.codes, codes)
+			assert.Equal(t, tt.expected.retryAfter, last.Header.Get("Retry-After"))
+
+			if tt.limit.Enabled() {
+				assert.Equal(t, "1", last.Header.Get("RateLimit-Limit"))
+				assert.NotEmpty(t, last.Header.Get("RateLimit-Remaining"))
+				assert.NotEmpty(t, last.Header.Get("RateLimit-Reset"))
+			} else {
+				assert.Empty(t, last.Header.Get("RateLimit-Limit"))
+			}
+		})
+	}
+}
+
+func Test_Middleware_StoreFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewMockStore(ctrl)
+	store.EXPECT().Take(gomock.Any(), "redirect:ip:10.0.0.1", gomock.Any()).Return(Result{}, assert.AnError)
+
+	handler := Middleware(store, RedirectScope, Limit{Requests: 1, Period: time.Second}, nil)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, fromIP("10.0.0.1:1000")(httptest.NewRequest(http.MethodGet, "/abcd1234", nil)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func fromIP(addr string) func(r *http.Request) *http.Request {
+	return func(r *http.Request) *http.Request {
+		r.RemoteAddr = addr
+		return r
+	}
+}
+
+func asUser(id uuid.UUID, authenticated bool, addr string) func(r *http.Request) *http.Request {
+	return func(r *http.Request) *http.Request {
+		r.RemoteAddr = addr
+		ctx := context.WithValue(r.Context(), dto.CurrentUser, id)
+		ctx = context.WithValue(ctx, dto.Authenticated, authenticated)
+		return r.WithContext(ctx)
+	}
+}
+
+func withHeader(key, value string, request func(r *http.Request) *http.Request) func(r *http.Request) *http.Request {
+	return func(r *http.Request) *http.Request {
+		r.Header.Set(key, value)
+		return request(r)
+	}
+}
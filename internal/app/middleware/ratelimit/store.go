@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"shortly/internal/app/errors"
+)
+
+// SweepEvery is the number of takes between evictions of idle buckets from the memory store
+const SweepEvery = 1024
+
+// Limit is a token bucket limit, the bucket holds Requests tokens and is fully refilled over Period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled reports whether the limit restricts requests
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// rate returns the number of tokens refilled per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// ParseLimit parses limits like "60/m", "10/s" or "1000/h", an empty string or "0" disables the limit
+func ParseLimit(s string) (Limit, error) {
+	if s == "" || s == "0" {
+		return Limit{}, nil
+	}
+
+	requests, unit, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, errors.ErrInvalidRateLimit
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 0 {
+		return Limit{}, errors.ErrInvalidRateLimit
+	}
+
+	var period time.Duration
+	switch unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		if period, err = time.ParseDuration(unit); err != nil || period <= 0 {
+			return Limit{}, errors.ErrInvalidRateLimit
+		}
+	}
+
+	return Limit{Requests: n, Period: period}, nil
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+// Store is an interface for rate limit state storage, implementations may be shared between instances
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// bucket is a token bucket state
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+// memoryStore keeps token buckets in the process memory
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+	now     func() time.Time
+}
+
+// NewMemoryStore creates a new in-process rate limit store
+func NewMemoryStore() Store {
+	return &memoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take takes a token from the bucket of the key
+func (s *memoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	rate := limit.rate()
+	capacity := float64(limit.Requests)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+	b.period = limit.Period
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((capacity - b.tokens) / rate)
+
+	s.takes++
+	if s.takes%SweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	return result, nil
+}
+
+// sweep evicts buckets which are refilled by now, a new bucket starts full anyway
+func (s *memoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) >= b.period {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// seconds converts fractional seconds into a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
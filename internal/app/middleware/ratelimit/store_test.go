@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/errors"
+)
+
+func Test_ParseLimit(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Limit
+		err      error
+	}{
+		{value: "60/m", expected: Limit{Requests: 60, Period: time.Minute}},
+		{value: "10/s", expected: Limit{Requests: 10, Period: time.Second}},
+		{value: "1000/h", expected: Limit{Requests: 1000, Period: time.Hour}},
+		{value: "5/30s", expected: Limit{Requests: 5, Period: 30 * time.Second}},
+		{value: "", expected: Limit{}},
+		{value: "0", expected: Limit{}},
+		{value: "60", err: errors.ErrInvalidRateLimit},
+		{value: "x/m", err: errors.ErrInvalidRateLimit},
+		{value: "-1/m", err: errors.ErrInvalidRateLimit},
+		{value: "60/day", err: errors.ErrInvalidRateLimit},
+		{value: "60/-1s", err: errors.ErrInvalidRateLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			limit, err := ParseLimit(tt.value)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
+
+func Test_Limit_Enabled(t *testing.T) {
+	assert.True(t, Limit{Requests: 1, Period: time.Second}.Enabled())
+	assert.False(t, Limit{Requests: 0, Period: time.Second}.Enabled())
+	assert.False(t, Limit{Requests: 1}.Enabled())
+}
+
+func Test_MemoryStore_Take(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Requests: 2, Period: 2 * time.Second}
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		elapsed  time.Duration
+		key      string
+		expected Result
+	}{
+		{
+			name:     "First token",
+			key:      "a",
+			expected: Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second},
+		},
+		{
+			name:     "Last token",
+			key:      "a",
+			expected: Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second},
+		},
+		{
+			name:     "Exhausted",
+			key:      "a",
+			expected: Result{Allowed: false, Limit: 2, Remaining: 0, RetryAfter: time.Second, Reset: 2 * time.Second},
+		},
+		{
+			name:     "Other key",
+			key:      "b",
+			expected: Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second},
+		},
+		{
+			name:     "Refilled",
+			elapsed:  1500 * time.Millisecond,
+			key:      "a",
+			expected: Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 1500 * time.Millisecond},
+		},
+	}
+
+	now := start
+	store := NewMemoryStore().(*memoryStore)
+	store.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.elapsed)
+
+			result, err := store.Take(ctx, tt.key, limit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_MemoryStore_Sweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	store := NewMemoryStore().(*memoryStore)
+	store.now = func() time.Time { return now }
+
+	_, err := store.Take(ctx, "idle", Limit{Requests: 1, Period: time.Minute})
+	require.NoError(t, err)
+	_, err = store.Take(ctx, "slow", Limit{Requests: 1, Period: time.Hour})
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < SweepEvery; i++ {
+		_, err = store.Take(ctx, "active", Limit{Requests: 1, Period: time.Second})
+		require.NoError(t, err)
+	}
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "slow")
+	assert.Contains(t, store.buckets, "active")
+}
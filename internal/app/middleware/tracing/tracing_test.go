@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"shortly/internal/app/middleware/metrics"
+)
+
+func Test_Middleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/api/shorten/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.Post("/api/shorten", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	r.Get("/api/fail", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		traceParent string
+		span        string
+		route       string
+		code        int
+		status      codes.Code
+	}{
+		{
+			name:   "Route pattern",
+			method: http.MethodGet,
+			path:   "/api/shorten/abcd1234",
+			span:   "GET /api/shorten/{id}",
+			route:  "/api/shorten/{id}",
+			code:   http.StatusNotFound,
+			status: codes.Unset,
+		},
+		{
+			name:        "Trace parent",
+			method:      http.MethodPost,
+			path:        "/api/shorten",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			span:        "POST /api/shorten",
+			route:       "/api/shorten",
+			code:        http.StatusOK,
+			status:      codes.Unset,
+		},
+		{
+			name:   "Server error",
+			method: http.MethodGet,
+			path:   "/api/fail",
+			span:   "GET /api/fail",
+			route:  "/api/fail",
+			code:   http.StatusInternalServerError,
+			status: codes.Error,
+		},
+		{
+			name:   "Unmatched route",
+			method: http.MethodGet,
+			path:   "/unknown/path",
+			span:   "GET " + metrics.UnmatchedRoute,
+			route:  metrics.UnmatchedRoute,
+			code:   http.StatusNotFound,
+			status: codes.Unset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(recorder.Ended())
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.traceParent != "" {
+				req.Header.Set("traceparent", tt.traceParent)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, before+1)
+			span := spans[before]
+
+			assert.Equal(t, tt.span, span.Name())
+			assert.Equal(t, tt.status, span.Status().Code)
+			assert.Contains(t, span.Attributes(), semconv.HTTPRoute(tt.route))
+			assert.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCode(tt.code))
+
+			if tt.traceParent != "" {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+				assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+			} else {
+				assert.False(t, span.Parent().IsValid())
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package trusted
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+)
+
+// RealIPHeader is the header of the client IP set by a proxy
+const RealIPHeader = "X-Real-IP"
+
+// ForwardedForHeader is the header of the client IP followed by the IPs of the proxies a request passed
+const ForwardedForHeader = "X-Forwarded-For"
+
+// ParseSubnet parses the trusted subnet in the CIDR notation, an empty subnet trusts no client
+func ParseSubnet(subnet string) (*net.IPNet, error) {
+	if subnet == "" {
+		return nil, nil
+	}
+
+	_, network, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, errors.ErrInvalidTrustedSubnet
+	}
+
+	return network, nil
+}
+
+// ParseProxies parses the comma separated CIDRs of the trusted proxies, an empty list trusts no proxy
+func ParseProxies(proxies string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, proxy := range strings.Split(proxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, errors.ErrInvalidTrustedProxy
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// Middleware is a middleware allowing only clients of the trusted subnet, every client is denied without a subnet,
+// the client IP is taken from the proxy headers only when the peer is a trusted proxy
+func Middleware(subnet *net.IPNet, proxies []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subnet == nil || !subnet.Contains(ClientIP(r, proxies)) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(dto.ErrorResponse{Error: errors.ErrUntrustedNetwork.Error()})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the IP of the client, nil when it cannot be told,
+// X-Real-IP and then X-Forwarded-For are honoured only when the peer is a trusted proxy
+func ClientIP(r *http.Request, proxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !contains(proxies, ip) {
+		return ip
+	}
+
+	if value := strings.TrimSpace(r.Header.Get(RealIPHeader)); value != "" {
+		return net.ParseIP(value)
+	}
+
+	// NOTE: every proxy appends its peer, so the rightmost address not of a trusted proxy is the client,
+	// the addresses left of it are set by the client and cannot be trusted
+	hops := strings.Split(r.Header.Get(ForwardedForHeader), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		value := strings.TrimSpace(hops[i])
+		if value == "" {
+			continue
+		}
+
+		hop := net.ParseIP(value)
+		if hop == nil {
+			return nil
+		}
+
+		ip = hop
+		if !contains(proxies, hop) {
+			break
+		}
+	}
+
+	return ip
+}
+
+// contains reports whether the IP belongs to any of the networks
+func contains(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,253 @@
+package trusted
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/errors"
+)
+
+func Test_ParseSubnet(t *testing.T) {
+	tests := []struct {
+		name     string
+		subnet   string
+		expected string
+		error    error
+	}{
+		{
+			name:     "IPv4",
+			subnet:   "192.168.1.0/24",
+			expected: "192.168.1.0/24",
+		},
+		{
+			name:     "IPv6",
+			subnet:   "fd00::/8",
+			expected: "fd00::/8",
+		},
+		{
+			name:   "Empty",
+			subnet: "",
+		},
+		{
+			name:   "Invalid",
+			subnet: "192.168.1.1",
+			error:  errors.ErrInvalidTrustedSubnet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := ParseSubnet(tt.subnet)
+
+			assert.Equal(t, tt.error, err)
+			if tt.expected == "" {
+				assert.Nil(t, network)
+			} else {
+				require.NotNil(t, network)
+				assert.Equal(t, tt.expected, network.String())
+			}
+		})
+	}
+}
+
+func Test_ParseProxies(t *testing.T) {
+	tests := []struct {
+		name     string
+		proxies  string
+		expected []string
+		error    error
+	}{
+		{
+			name:     "Single",
+			proxies:  "127.0.0.1/32",
+			expected: []string{"127.0.0.1/32"},
+		},
+		{
+			name:     "List",
+			proxies:  "172.16.0.0/12, fd00::/8,",
+			expected: []string{"172.16.0.0/12", "fd00::/8"},
+		},
+		{
+			name:    "Empty",
+			proxies: "",
+		},
+		{
+			name:    "Invalid",
+			proxies: "172.16.0.0/12,proxy",
+			error:   errors.ErrInvalidTrustedProxy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networks, err := ParseProxies(tt.proxies)
+
+			assert.Equal(t, tt.error, err)
+			require.Len(t, networks, len(tt.expected))
+			for i, network := range networks {
+				assert.Equal(t, tt.expected[i], network.String())
+			}
+		})
+	}
+}
+
+func Test_ClientIP(t *testing.T) {
+	proxies, err := ParseProxies("172.16.0.0/12")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		realIP       string
+		forwardedFor string
+		expected     string
+	}{
+		{
+			name:       "Direct",
+			remoteAddr: "10.1.2.3:1000",
+			expected:   "10.1.2.3",
+		},
+		{
+			name:         "Headers from an untrusted peer",
+			remoteAddr:   "192.168.1.1:1000",
+			realIP:       "10.1.2.3",
+			forwardedFor: "10.1.2.3",
+			expected:     "192.168.1.1",
+		},
+		{
+			name:       "Real IP from a trusted proxy",
+			remoteAddr: "172.16.0.1:1000",
+			realIP:     "10.1.2.3",
+			expected:   "10.1.2.3",
+		},
+		{
+			name:         "Forwarded for from a trusted proxy",
+			remoteAddr:   "172.16.0.1:1000",
+			forwardedFor: "10.1.2.3",
+			expected:     "10.1.2.3",
+		},
+		{
+			name:         "Forwarded for through trusted proxies",
+			remoteAddr:   "172.16.0.1:1000",
+			forwardedFor: "10.1.2.3, 172.16.0.2",
+			expected:     "10.1.2.3",
+		},
+		{
+			name:         "Spoofed forwarded for",
+			remoteAddr:   "172.16.0.1:1000",
+			forwardedFor: "10.1.2.3, 192.168.1.1",
+			expected:     "192.168.1.1",
+		},
+		{
+			name:       "Proxy without headers",
+			remoteAddr: "172.16.0.1:1000",
+			expected:   "172.16.0.1",
+		},
+		{
+			name:         "Invalid forwarded for",
+			remoteAddr:   "172.16.0.1:1000",
+			forwardedFor: "unknown",
+			expected:     "",
+		},
+		{
+			name:       "Invalid remote address",
+			remoteAddr: "unknown",
+			expected:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/internal/stats", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.realIP != "" {
+				req.Header.Set(RealIPHeader, tt.realIP)
+			}
+			if tt.forwardedFor != "" {
+				req.Header.Set(ForwardedForHeader, tt.forwardedFor)
+			}
+
+			ip := ClientIP(req, proxies)
+
+			if tt.expected == "" {
+				assert.Nil(t, ip)
+			} else {
+				assert.Equal(t, tt.expected, ip.String())
+			}
+		})
+	}
+}
+
+func Test_Middleware(t *testing.T) {
+	network, err := ParseSubnet("10.0.0.0/8")
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		subnet     bool
+		remoteAddr string
+		realIP     string
+		expected   int
+	}{
+		{
+			name:       "Trusted",
+			subnet:     true,
+			remoteAddr: "10.1.2.3:1000",
+			expected:   http.StatusOK,
+		},
+		{
+			name:       "Untrusted",
+			subnet:     true,
+			remoteAddr: "192.168.1.1:1000",
+			expected:   http.StatusForbidden,
+		},
+		{
+			name:       "Invalid remote address",
+			subnet:     true,
+			remoteAddr: "unknown",
+			expected:   http.StatusForbidden,
+		},
+		{
+			name:       "Untrusted proxy headers",
+			subnet:     true,
+			remoteAddr: "192.168.1.1:1000",
+			realIP:     "10.1.2.3",
+			expected:   http.StatusForbidden,
+		},
+		{
+			name:       "No subnet",
+			subnet:     false,
+			remoteAddr: "10.1.2.3:1000",
+			expected:   http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trusted := network
+			if !tt.subnet {
+				trusted = nil
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/health/details", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set(RealIPHeader, tt.realIP)
+			w := httptest.NewRecorder()
+
+			Middleware(trusted, nil)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusForbidden {
+				assert.JSONEq(t, `{"error":"`+errors.ErrUntrustedNetwork.Error()+`"}`, w.Body.String())
+			}
+		})
+	}
+}
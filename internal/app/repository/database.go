@@ -2,16 +2,36 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"shortly/internal/app/errors"
+	"shortly/internal/app/metrics"
 	"shortly/internal/app/repository/db"
+	"shortly/internal/logger"
 )
 
 // MaxConnections is the maximum number of connections
 const MaxConnections = 100
 
+// UniqueViolation is the PostgreSQL error code for unique constraint violation
+const UniqueViolation = "23505"
+
+// ShortCodeConstraint is the name of the short code unique constraint
+const ShortCodeConstraint = "urls_short_code_key"
+
+// LongURLConstraint is the name of the long URL unique constraint
+const LongURLConstraint = "urls_long_url_key"
+
+// DatabaseBackend is the metrics label of the database repository
+const DatabaseBackend = "postgres"
+
 // Database is an interface for database operations
 type Database interface {
 	Repository
@@ -33,12 +53,14 @@ func NewDatabaseRepository(ctx context.Context, dsn string) (Database, error) {
 	}
 
 	poolConfig.MaxConns = MaxConnections
+	poolConfig.ConnConfig.Tracer = queryTracer{}
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	queries := db.New(pool)
+	metrics.RegisterPool(pool)
 
 	return &DatabaseRepo{
 		db:      pool,
@@ -48,15 +70,18 @@ func NewDatabaseRepository(ctx context.Context, dsn string) (Database, error) {
 
 // CreateURL creates a new URL record
 func (d *DatabaseRepo) CreateURL(ctx context.Context, url URL) (*URL, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "create_url", time.Now())
+
 	row, err := d.queries.CreateURL(ctx, db.CreateURLParams{
 		UUID:      url.UUID,
 		LongURL:   url.LongURL,
 		ShortCode: url.ShortCode,
 		UserUUID:  url.UserUUID,
+		ExpiresAt: toTimestamp(url.ExpiresAt),
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return &URL{
@@ -66,34 +91,55 @@ func (d *DatabaseRepo) CreateURL(ctx context.Context, url URL) (*URL, error) {
 	}, nil
 }
 
-// CreateURLs creates new URL records
-func (d *DatabaseRepo) CreateURLs(ctx context.Context, urls []URL) error {
+// CreateURLs creates new URL records in a single transaction and returns the stored record of each,
+// records of already shortened long URLs are returned instead of created
+func (d *DatabaseRepo) CreateURLs(ctx context.Context, urls []URL) ([]URL, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "create_urls", time.Now())
+
 	tx, err := d.db.Begin(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Rollback(ctx)
 
 	q := d.queries.WithTx(tx)
+	records := make([]URL, 0, len(urls))
 
 	for _, url := range urls {
-		_, err := q.CreateURL(ctx, db.CreateURLParams{
+		row, err := q.CreateURL(ctx, db.CreateURLParams{
 			UUID:      url.UUID,
 			LongURL:   url.LongURL,
 			ShortCode: url.ShortCode,
+			UserUUID:  url.UserUUID,
+			ExpiresAt: toTimestamp(url.ExpiresAt),
 		})
 		if err != nil {
-			return err
+			return nil, mapError(err)
 		}
+
+		records = append(records, URL{
+			UUID:      row.UUID,
+			LongURL:   row.LongURL,
+			ShortCode: row.ShortCode,
+		})
 	}
 
-	return tx.Commit(ctx)
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return records, nil
 }
 
 // GetURLByShortCode returns a URL record by short code
 func (d *DatabaseRepo) GetURLByShortCode(ctx context.Context, shortCode string) (*URL, bool) {
+	defer metrics.ObserveRepository(DatabaseBackend, "get_url_by_short_code", time.Now())
+
 	row, err := d.queries.GetURLByShortCode(ctx, shortCode)
 	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Error().Err(err).Msgf("Error loading URL %s", shortCode)
+		}
 		return nil, false
 	}
 
@@ -101,12 +147,39 @@ func (d *DatabaseRepo) GetURLByShortCode(ctx context.Context, shortCode string)
 		UUID:      row.UUID,
 		LongURL:   row.LongURL,
 		ShortCode: row.ShortCode,
+		UserUUID:  row.UserUUID,
 		DeletedAt: row.DeletedAt.Time,
+		ExpiresAt: row.ExpiresAt.Time,
+		Expired:   row.Expired,
 	}, true
 }
 
-// GetURLByUUID returns a URL record by UUID
+// GetURLsByLongURLs returns URL records with any of the long URLs
+func (d *DatabaseRepo) GetURLsByLongURLs(ctx context.Context, longURLs []string) ([]URL, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "get_urls_by_long_urls", time.Now())
+
+	rows, err := d.queries.GetURLsByLongURLs(ctx, longURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]URL, 0, len(rows))
+	for _, row := range rows {
+		urls = append(urls, URL{
+			UUID:      row.UUID,
+			LongURL:   row.LongURL,
+			ShortCode: row.ShortCode,
+			UserUUID:  row.UserUUID,
+		})
+	}
+
+	return urls, nil
+}
+
+// GetURLsByUserID returns URL records by user ID
 func (d *DatabaseRepo) GetURLsByUserID(ctx context.Context, id uuid.UUID, limit, offset int64) ([]URL, int, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "get_urls_by_user_id", time.Now())
+
 	params := db.GetURLsByUserIDParams{
 		UserUUID: id,
 		Limit:    limit,
@@ -130,22 +203,540 @@ func (d *DatabaseRepo) GetURLsByUserID(ctx context.Context, id uuid.UUID, limit,
 			UUID:      row.UUID,
 			LongURL:   row.LongURL,
 			ShortCode: row.ShortCode,
+			UserUUID:  id,
+			CreatedAt: row.CreatedAt.Time,
 		})
 	}
 
 	return urls, total, nil
 }
 
-// DeleteURLsByUserID deletes URL records by user ID
-func (d *DatabaseRepo) DeleteURLsByUserID(ctx context.Context, id uuid.UUID, shortCodes []string) error {
+// GetURLsByUserIDAfter returns URL records by user ID following the cursor, newest first
+func (d *DatabaseRepo) GetURLsByUserIDAfter(ctx context.Context, id uuid.UUID, after URLCursor, limit int64) ([]URL, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "get_urls_by_user_id_after", time.Now())
+
+	rows, err := d.queries.GetURLsByUserIDAfter(ctx, db.GetURLsByUserIDAfterParams{
+		UserUUID:  id,
+		CreatedAt: toTimestamp(after.CreatedAt),
+		UUID:      after.UUID,
+		Lim:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]URL, 0, len(rows))
+	for _, row := range rows {
+		urls = append(urls, URL{
+			UUID:      row.UUID,
+			LongURL:   row.LongURL,
+			ShortCode: row.ShortCode,
+			UserUUID:  id,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+
+	return urls, nil
+}
+
+// FindURLsByUserID returns URL records of the user matching the filter
+func (d *DatabaseRepo) FindURLsByUserID(ctx context.Context, filter URLFilter) ([]URLListItem, int, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "find_urls_by_user_id", time.Now())
+
+	total, err := d.queries.CountURLsByUserID(ctx, db.CountURLsByUserIDParams{
+		UserUUID:      filter.UserUUID,
+		Deleted:       filter.Deleted,
+		Expired:       filter.Expired,
+		Pattern:       likePattern(filter.Query),
+		CreatedAfter:  toTimestamp(filter.CreatedAfter),
+		CreatedBefore: toTimestamp(filter.CreatedBefore),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := d.queries.FindURLsByUserID(ctx, db.FindURLsByUserIDParams{
+		UserUUID:      filter.UserUUID,
+		Deleted:       filter.Deleted,
+		Expired:       filter.Expired,
+		Pattern:       likePattern(filter.Query),
+		CreatedAfter:  toTimestamp(filter.CreatedAfter),
+		CreatedBefore: toTimestamp(filter.CreatedBefore),
+		Sort:          filter.Sort,
+		Lim:           filter.Limit,
+		Off:           filter.Offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]URLListItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, URLListItem{
+			URL: URL{
+				UUID:      row.UUID,
+				LongURL:   row.LongURL,
+				ShortCode: row.ShortCode,
+				UserUUID:  filter.UserUUID,
+				CreatedAt: row.CreatedAt.Time,
+				DeletedAt: row.DeletedAt.Time,
+				ExpiresAt: row.ExpiresAt.Time,
+				Expired:   row.Expired,
+			},
+			Clicks: row.Clicks,
+		})
+	}
+
+	return items, int(total), nil
+}
+
+// ExportURLsByUserID returns URL records of the user after the cursor including deleted and expired ones, newest first,
+// a zero cursor starts from the newest record
+func (d *DatabaseRepo) ExportURLsByUserID(ctx context.Context, id uuid.UUID, after URLCursor, limit int64) ([]URLListItem, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "export_urls_by_user_id", time.Now())
+
+	rows, err := d.queries.ExportURLsByUserID(ctx, db.ExportURLsByUserIDParams{
+		UserUUID:  id,
+		CreatedAt: toTimestamp(after.CreatedAt),
+		UUID:      after.UUID,
+		Lim:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]URLListItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, URLListItem{
+			URL: URL{
+				UUID:      row.UUID,
+				LongURL:   row.LongURL,
+				ShortCode: row.ShortCode,
+				UserUUID:  id,
+				CreatedAt: row.CreatedAt.Time,
+				DeletedAt: row.DeletedAt.Time,
+				ExpiresAt: row.ExpiresAt.Time,
+				Expired:   row.Expired,
+			},
+			Clicks: row.Clicks,
+		})
+	}
+
+	return items, nil
+}
+
+// UpdateURL updates the long URL of a URL record owned by the user
+func (d *DatabaseRepo) UpdateURL(ctx context.Context, url URL) (*URL, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "update_url", time.Now())
+
+	row, err := d.queries.UpdateURL(ctx, db.UpdateURLParams{
+		ShortCode: url.ShortCode,
+		UserUUID:  url.UserUUID,
+		LongURL:   url.LongURL,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.ErrShortLinkNotFound
+		}
+		return nil, mapError(err)
+	}
+
+	return &URL{
+		UUID:      row.UUID,
+		LongURL:   row.LongURL,
+		ShortCode: row.ShortCode,
+		UserUUID:  row.UserUUID,
+		UpdatedAt: row.UpdatedAt.Time,
+	}, nil
+}
+
+// DeleteURLsByUserID deletes URL records by user ID and returns the deleted short codes, the deletion time is stored
+// in UTC like the restore and purge cutoffs
+func (d *DatabaseRepo) DeleteURLsByUserID(ctx context.Context, id uuid.UUID, shortCodes []string) ([]string, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "delete_urls_by_user_id", time.Now())
+
 	return d.queries.DeleteURLsByUserIDAndShortCodes(ctx, db.DeleteURLsByUserIDAndShortCodesParams{
 		UserUUID:   id,
 		ShortCodes: shortCodes,
+		DeletedAt:  toTimestamp(time.Now()),
+	})
+}
+
+// DeleteURLsByUserIDs marks URL records of many users as deleted with a single statement
+func (d *DatabaseRepo) DeleteURLsByUserIDs(ctx context.Context, shortCodes map[uuid.UUID][]string) (map[uuid.UUID][]string, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "delete_urls_by_user_ids", time.Now())
+
+	params := db.DeleteURLsByUserIDsParams{DeletedAt: toTimestamp(time.Now())}
+	for id, codes := range shortCodes {
+		for _, shortCode := range codes {
+			params.UserUUIDs = append(params.UserUUIDs, id)
+			params.ShortCodes = append(params.ShortCodes, shortCode)
+		}
+	}
+
+	rows, err := d.queries.DeleteURLsByUserIDs(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make(map[uuid.UUID][]string)
+	for _, row := range rows {
+		deleted[row.UserUUID] = append(deleted[row.UserUUID], row.ShortCode)
+	}
+
+	return deleted, nil
+}
+
+// RestoreURLsByUserID restores URL records of the user deleted since the given time
+func (d *DatabaseRepo) RestoreURLsByUserID(ctx context.Context, id uuid.UUID, shortCodes []string, deletedSince time.Time) ([]string, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "restore_urls_by_user_id", time.Now())
+
+	return d.queries.RestoreURLsByUserIDAndShortCodes(ctx, db.RestoreURLsByUserIDAndShortCodesParams{
+		UserUUID:     id,
+		ShortCodes:   shortCodes,
+		DeletedSince: toTimestamp(deletedSince),
+	})
+}
+
+// PurgeURLs permanently removes URL records deleted before the given time, clicks are removed by the cascade
+func (d *DatabaseRepo) PurgeURLs(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "purge_urls", time.Now())
+
+	return d.queries.PurgeURLs(ctx, toTimestamp(deletedBefore))
+}
+
+// ExpireURLs marks URL records with passed expiration time as expired, the current time is passed in UTC like
+// the stored expiration times instead of NOW() in the session time zone
+func (d *DatabaseRepo) ExpireURLs(ctx context.Context) (int64, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "expire_urls", time.Now())
+
+	return d.queries.ExpireURLs(ctx, toTimestamp(time.Now()))
+}
+
+// CreateClicks stores click events
+func (d *DatabaseRepo) CreateClicks(ctx context.Context, clicks []Click) error {
+	defer metrics.ObserveRepository(DatabaseBackend, "create_clicks", time.Now())
+
+	tx, err := d.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := d.queries.WithTx(tx)
+
+	for _, click := range clicks {
+		err = q.CreateClick(ctx, db.CreateClickParams{
+			UrlUuid:   click.URLUUID,
+			Referrer:  click.Referrer,
+			UserAgent: click.UserAgent,
+			IpHash:    click.IPHash,
+			CreatedAt: toTimestamp(click.CreatedAt),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetClickStats returns aggregated clicks statistics of a URL record, daily series starts from since
+func (d *DatabaseRepo) GetClickStats(ctx context.Context, urlID uuid.UUID, since time.Time) (*ClickStats, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "get_click_stats", time.Now())
+
+	summary, err := d.queries.GetClicksSummary(ctx, urlID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := d.queries.GetDailyClicks(ctx, db.GetDailyClicksParams{
+		UrlUuid:   urlID,
+		CreatedAt: toTimestamp(since),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ClickStats{
+		Total:  summary.Total,
+		Unique: summary.UniqueVisitors,
+		Daily:  make([]DailyClicks, 0, len(rows)),
+	}
+
+	for _, row := range rows {
+		stats.Daily = append(stats.Daily, DailyClicks{
+			Date:   row.Day.Time,
+			Total:  row.Total,
+			Unique: row.UniqueVisitors,
+		})
+	}
+
+	return stats, nil
+}
+
+// NextSequence returns the next value of the short code sequence
+func (d *DatabaseRepo) NextSequence(ctx context.Context) (int64, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "next_sequence", time.Now())
+
+	return d.queries.NextShortCodeSequence(ctx)
+}
+
+// CreateAPIToken stores a new API token
+func (d *DatabaseRepo) CreateAPIToken(ctx context.Context, token APIToken) (*APIToken, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "create_api_token", time.Now())
+
+	createdAt, err := d.queries.CreateAPIToken(ctx, db.CreateAPITokenParams{
+		UUID:      token.UUID,
+		UserUUID:  token.UserUUID,
+		Name:      token.Name,
+		Prefix:    token.Prefix,
+		TokenHash: token.Hash,
+		Scopes:    token.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token.CreatedAt = createdAt.Time
+	return &token, nil
+}
+
+// GetAPITokenByHash returns an active API token by the hash of its secret
+func (d *DatabaseRepo) GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, bool) {
+	defer metrics.ObserveRepository(DatabaseBackend, "get_api_token_by_hash", time.Now())
+
+	row, err := d.queries.GetAPITokenByHash(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Error().Err(err).Msg("Error loading API token")
+		}
+		return nil, false
+	}
+
+	return &APIToken{
+		UUID:      row.UUID,
+		UserUUID:  row.UserUUID,
+		Name:      row.Name,
+		Prefix:    row.Prefix,
+		Hash:      hash,
+		Scopes:    row.Scopes,
+		CreatedAt: row.CreatedAt.Time,
+	}, true
+}
+
+// GetAPITokensByUserID returns active API tokens of the user
+func (d *DatabaseRepo) GetAPITokensByUserID(ctx context.Context, id uuid.UUID) ([]APIToken, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "get_api_tokens_by_user_id", time.Now())
+
+	rows, err := d.queries.GetAPITokensByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]APIToken, 0, len(rows))
+	for _, row := range rows {
+		tokens = append(tokens, APIToken{
+			UUID:      row.UUID,
+			UserUUID:  row.UserUUID,
+			Name:      row.Name,
+			Prefix:    row.Prefix,
+			Scopes:    row.Scopes,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+
+	return tokens, nil
+}
+
+// RevokeAPIToken revokes an active API token owned by the user
+func (d *DatabaseRepo) RevokeAPIToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	defer metrics.ObserveRepository(DatabaseBackend, "revoke_api_token", time.Now())
+
+	count, err := d.queries.RevokeAPIToken(ctx, db.RevokeAPITokenParams{
+		UUID:     tokenID,
+		UserUUID: userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return errors.ErrAPITokenNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDeleteTask stores a new delete task
+func (d *DatabaseRepo) EnqueueDeleteTask(ctx context.Context, task DeleteTask) error {
+	defer metrics.ObserveRepository(DatabaseBackend, "enqueue_delete_task", time.Now())
+
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+	if task.RunAt.IsZero() {
+		task.RunAt = task.CreatedAt
+	}
+
+	return d.queries.EnqueueDeleteTask(ctx, db.EnqueueDeleteTaskParams{
+		UUID:        task.UUID,
+		UserUUID:    task.UserUUID,
+		ShortCodes:  task.ShortCodes,
+		RunAt:       toTimestamp(task.RunAt),
+		CreatedAt:   toTimestamp(task.CreatedAt),
+		TraceParent: task.TraceParent,
 	})
 }
 
+// ClaimDeleteTasks leases due delete tasks until the given time, tasks claimed by other instances are skipped
+func (d *DatabaseRepo) ClaimDeleteTasks(ctx context.Context, now, leaseUntil time.Time, limit int64) ([]DeleteTask, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "claim_delete_tasks", time.Now())
+
+	rows, err := d.queries.ClaimDeleteTasks(ctx, db.ClaimDeleteTasksParams{
+		LeaseUntil: toTimestamp(leaseUntil),
+		Now:        toTimestamp(now),
+		Lim:        limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]DeleteTask, 0, len(rows))
+	for _, row := range rows {
+		tasks = append(tasks, DeleteTask{
+			UUID:        row.UUID,
+			UserUUID:    row.UserUUID,
+			ShortCodes:  row.ShortCodes,
+			Attempts:    int(row.Attempts),
+			LastError:   row.LastError,
+			RunAt:       row.RunAt.Time,
+			CreatedAt:   row.CreatedAt.Time,
+			TraceParent: row.TraceParent,
+		})
+	}
+
+	return tasks, nil
+}
+
+// CompleteDeleteTasks removes processed delete tasks
+func (d *DatabaseRepo) CompleteDeleteTasks(ctx context.Context, ids []uuid.UUID) error {
+	defer metrics.ObserveRepository(DatabaseBackend, "complete_delete_tasks", time.Now())
+
+	return d.queries.CompleteDeleteTasks(ctx, ids)
+}
+
+// RetryDeleteTask schedules the next attempt of a delete task
+func (d *DatabaseRepo) RetryDeleteTask(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error {
+	defer metrics.ObserveRepository(DatabaseBackend, "retry_delete_task", time.Now())
+
+	return d.queries.RetryDeleteTask(ctx, db.RetryDeleteTaskParams{
+		UUID:      id,
+		RunAt:     toTimestamp(runAt),
+		LastError: lastError,
+	})
+}
+
+// FailDeleteTask moves a delete task to the dead letters
+func (d *DatabaseRepo) FailDeleteTask(ctx context.Context, id uuid.UUID, lastError string) error {
+	defer metrics.ObserveRepository(DatabaseBackend, "fail_delete_task", time.Now())
+
+	return d.queries.FailDeleteTask(ctx, db.FailDeleteTaskParams{
+		UUID:      id,
+		LastError: lastError,
+	})
+}
+
+// CountDeleteTasks counts pending and dead-lettered delete tasks
+func (d *DatabaseRepo) CountDeleteTasks(ctx context.Context) (DeleteTaskStats, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "count_delete_tasks", time.Now())
+
+	row, err := d.queries.CountDeleteTasks(ctx)
+	if err != nil {
+		return DeleteTaskStats{}, err
+	}
+
+	return DeleteTaskStats{Pending: row.Pending, Failed: row.Failed}, nil
+}
+
+// SaveDeleteJob stores the state of a delete job, skipped short codes are stored as JSON
+func (d *DatabaseRepo) SaveDeleteJob(ctx context.Context, job DeleteJob) error {
+	defer metrics.ObserveRepository(DatabaseBackend, "save_delete_job", time.Now())
+
+	skipped, err := json.Marshal(orEmpty(job.Skipped))
+	if err != nil {
+		return err
+	}
+
+	return d.queries.SaveDeleteJob(ctx, db.SaveDeleteJobParams{
+		UUID:       job.UUID,
+		UserUUID:   job.UserUUID,
+		Status:     job.Status,
+		ShortCodes: orEmpty(job.ShortCodes),
+		Deleted:    orEmpty(job.Deleted),
+		Skipped:    skipped,
+		Error:      job.Error,
+		CreatedAt:  toTimestamp(job.CreatedAt),
+		FinishedAt: toTimestamp(job.FinishedAt),
+	})
+}
+
+// GetDeleteJob returns the state of a delete job
+func (d *DatabaseRepo) GetDeleteJob(ctx context.Context, id uuid.UUID) (*DeleteJob, bool) {
+	defer metrics.ObserveRepository(DatabaseBackend, "get_delete_job", time.Now())
+
+	row, err := d.queries.GetDeleteJob(ctx, id)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Error().Err(err).Msg("Error loading delete job")
+		}
+		return nil, false
+	}
+
+	job := &DeleteJob{
+		UUID:       row.Uuid,
+		UserUUID:   row.UserUuid,
+		Status:     row.Status,
+		ShortCodes: row.ShortCodes,
+		Deleted:    row.Deleted,
+		Error:      row.Error,
+		CreatedAt:  row.CreatedAt.Time,
+		FinishedAt: row.FinishedAt.Time,
+	}
+
+	if err = json.Unmarshal(row.Skipped, &job.Skipped); err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msg("Error decoding skipped short codes of delete job")
+		return nil, false
+	}
+
+	return job, true
+}
+
+// PurgeDeleteJobs removes delete jobs finished before the given time
+func (d *DatabaseRepo) PurgeDeleteJobs(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "purge_delete_jobs", time.Now())
+
+	return d.queries.PurgeDeleteJobs(ctx, toTimestamp(finishedBefore))
+}
+
+// CountURLs counts active URL records, neither deleted nor expired
+func (d *DatabaseRepo) CountURLs(ctx context.Context) (int64, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "count_urls", time.Now())
+
+	return d.queries.CountURLs(ctx)
+}
+
+// CountUsers counts distinct users owning active URL records
+func (d *DatabaseRepo) CountUsers(ctx context.Context) (int64, error) {
+	defer metrics.ObserveRepository(DatabaseBackend, "count_users", time.Now())
+
+	return d.queries.CountUsers(ctx)
+}
+
 // Ping checks the database connection
 func (d *DatabaseRepo) Ping(ctx context.Context) error {
+	defer metrics.ObserveRepository(DatabaseBackend, "ping", time.Now())
+
 	_, err := d.queries.HealthCheck(ctx)
 	return err
 }
@@ -154,3 +745,38 @@ func (d *DatabaseRepo) Ping(ctx context.Context) error {
 func (d *DatabaseRepo) Close() {
 	d.db.Close()
 }
+
+// mapError converts database specific errors into application errors
+func mapError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != UniqueViolation {
+		return err
+	}
+
+	switch pgErr.ConstraintName {
+	case ShortCodeConstraint:
+		return errors.ErrShortCodeAlreadyExists
+	case LongURLConstraint:
+		return errors.ErrURLAlreadyExists
+	}
+
+	return err
+}
+
+// orEmpty replaces a nil slice with an empty one, so it is not stored as NULL
+func orEmpty[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+
+	return values
+}
+
+// toTimestamp converts time into a nullable database timestamp
+func toTimestamp(t time.Time) pgtype.Timestamp {
+	if t.IsZero() {
+		return pgtype.Timestamp{}
+	}
+
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}
+}
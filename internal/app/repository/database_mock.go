@@ -12,6 +12,7 @@ package repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,21 @@ func (m *MockDatabase) EXPECT() *MockDatabaseMockRecorder {
 	return m.recorder
 }
 
+// ClaimDeleteTasks mocks base method.
+func (m *MockDatabase) ClaimDeleteTasks(ctx context.Context, now, leaseUntil time.Time, limit int64) ([]DeleteTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeleteTasks", ctx, now, leaseUntil, limit)
+	ret0, _ := ret[0].([]DeleteTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeleteTasks indicates an expected call of ClaimDeleteTasks.
+func (mr *MockDatabaseMockRecorder) ClaimDeleteTasks(ctx, now, leaseUntil, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeleteTasks", reflect.TypeOf((*MockDatabase)(nil).ClaimDeleteTasks), ctx, now, leaseUntil, limit)
+}
+
 // Close mocks base method.
 func (m *MockDatabase) Close() {
 	m.ctrl.T.Helper()
@@ -53,6 +69,94 @@ func (mr *MockDatabaseMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDatabase)(nil).Close))
 }
 
+// CompleteDeleteTasks mocks base method.
+func (m *MockDatabase) CompleteDeleteTasks(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteDeleteTasks", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteDeleteTasks indicates an expected call of CompleteDeleteTasks.
+func (mr *MockDatabaseMockRecorder) CompleteDeleteTasks(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteDeleteTasks", reflect.TypeOf((*MockDatabase)(nil).CompleteDeleteTasks), ctx, ids)
+}
+
+// CountDeleteTasks mocks base method.
+func (m *MockDatabase) CountDeleteTasks(ctx context.Context) (DeleteTaskStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDeleteTasks", ctx)
+	ret0, _ := ret[0].(DeleteTaskStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDeleteTasks indicates an expected call of CountDeleteTasks.
+func (mr *MockDatabaseMockRecorder) CountDeleteTasks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDeleteTasks", reflect.TypeOf((*MockDatabase)(nil).CountDeleteTasks), ctx)
+}
+
+// CountURLs mocks base method.
+func (m *MockDatabase) CountURLs(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountURLs", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountURLs indicates an expected call of CountURLs.
+func (mr *MockDatabaseMockRecorder) CountURLs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountURLs", reflect.TypeOf((*MockDatabase)(nil).CountURLs), ctx)
+}
+
+// CountUsers mocks base method.
+func (m *MockDatabase) CountUsers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers.
+func (mr *MockDatabaseMockRecorder) CountUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockDatabase)(nil).CountUsers), ctx)
+}
+
+// CreateAPIToken mocks base method.
+func (m *MockDatabase) CreateAPIToken(ctx context.Context, token APIToken) (*APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIToken", ctx, token)
+	ret0, _ := ret[0].(*APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIToken indicates an expected call of CreateAPIToken.
+func (mr *MockDatabaseMockRecorder) CreateAPIToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIToken", reflect.TypeOf((*MockDatabase)(nil).CreateAPIToken), ctx, token)
+}
+
+// CreateClicks mocks base method.
+func (m *MockDatabase) CreateClicks(ctx context.Context, clicks []Click) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClicks", ctx, clicks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClicks indicates an expected call of CreateClicks.
+func (mr *MockDatabaseMockRecorder) CreateClicks(ctx, clicks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClicks", reflect.TypeOf((*MockDatabase)(nil).CreateClicks), ctx, clicks)
+}
+
 // CreateURL mocks base method.
 func (m *MockDatabase) CreateURL(ctx context.Context, url URL) (*URL, error) {
 	m.ctrl.T.Helper()
@@ -69,11 +173,12 @@ func (mr *MockDatabaseMockRecorder) CreateURL(ctx, url any) *gomock.Call {
 }
 
 // CreateURLs mocks base method.
-func (m *MockDatabase) CreateURLs(ctx context.Context, urls []URL) error {
+func (m *MockDatabase) CreateURLs(ctx context.Context, urls []URL) ([]URL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateURLs", ctx, urls)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateURLs indicates an expected call of CreateURLs.
@@ -83,11 +188,12 @@ func (mr *MockDatabaseMockRecorder) CreateURLs(ctx, urls any) *gomock.Call {
 }
 
 // DeleteURLsByUserID mocks base method.
-func (m *MockDatabase) DeleteURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string) error {
+func (m *MockDatabase) DeleteURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string) ([]string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteURLsByUserID", ctx, uuid, shortCodes)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteURLsByUserID indicates an expected call of DeleteURLsByUserID.
@@ -96,6 +202,155 @@ func (mr *MockDatabaseMockRecorder) DeleteURLsByUserID(ctx, uuid, shortCodes any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteURLsByUserID", reflect.TypeOf((*MockDatabase)(nil).DeleteURLsByUserID), ctx, uuid, shortCodes)
 }
 
+// DeleteURLsByUserIDs mocks base method.
+func (m *MockDatabase) DeleteURLsByUserIDs(ctx context.Context, shortCodes map[uuid.UUID][]string) (map[uuid.UUID][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteURLsByUserIDs", ctx, shortCodes)
+	ret0, _ := ret[0].(map[uuid.UUID][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteURLsByUserIDs indicates an expected call of DeleteURLsByUserIDs.
+func (mr *MockDatabaseMockRecorder) DeleteURLsByUserIDs(ctx, shortCodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteURLsByUserIDs", reflect.TypeOf((*MockDatabase)(nil).DeleteURLsByUserIDs), ctx, shortCodes)
+}
+
+// EnqueueDeleteTask mocks base method.
+func (m *MockDatabase) EnqueueDeleteTask(ctx context.Context, task DeleteTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeleteTask", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueDeleteTask indicates an expected call of EnqueueDeleteTask.
+func (mr *MockDatabaseMockRecorder) EnqueueDeleteTask(ctx, task any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeleteTask", reflect.TypeOf((*MockDatabase)(nil).EnqueueDeleteTask), ctx, task)
+}
+
+// ExpireURLs mocks base method.
+func (m *MockDatabase) ExpireURLs(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireURLs", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireURLs indicates an expected call of ExpireURLs.
+func (mr *MockDatabaseMockRecorder) ExpireURLs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireURLs", reflect.TypeOf((*MockDatabase)(nil).ExpireURLs), ctx)
+}
+
+// ExportURLsByUserID mocks base method.
+func (m *MockDatabase) ExportURLsByUserID(ctx context.Context, uuid uuid.UUID, after URLCursor, limit int64) ([]URLListItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportURLsByUserID", ctx, uuid, after, limit)
+	ret0, _ := ret[0].([]URLListItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportURLsByUserID indicates an expected call of ExportURLsByUserID.
+func (mr *MockDatabaseMockRecorder) ExportURLsByUserID(ctx, uuid, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportURLsByUserID", reflect.TypeOf((*MockDatabase)(nil).ExportURLsByUserID), ctx, uuid, after, limit)
+}
+
+// FailDeleteTask mocks base method.
+func (m *MockDatabase) FailDeleteTask(ctx context.Context, id uuid.UUID, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailDeleteTask", ctx, id, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailDeleteTask indicates an expected call of FailDeleteTask.
+func (mr *MockDatabaseMockRecorder) FailDeleteTask(ctx, id, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailDeleteTask", reflect.TypeOf((*MockDatabase)(nil).FailDeleteTask), ctx, id, lastError)
+}
+
+// FindURLsByUserID mocks base method.
+func (m *MockDatabase) FindURLsByUserID(ctx context.Context, filter URLFilter) ([]URLListItem, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindURLsByUserID", ctx, filter)
+	ret0, _ := ret[0].([]URLListItem)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindURLsByUserID indicates an expected call of FindURLsByUserID.
+func (mr *MockDatabaseMockRecorder) FindURLsByUserID(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindURLsByUserID", reflect.TypeOf((*MockDatabase)(nil).FindURLsByUserID), ctx, filter)
+}
+
+// GetAPITokenByHash mocks base method.
+func (m *MockDatabase) GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPITokenByHash", ctx, hash)
+	ret0, _ := ret[0].(*APIToken)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetAPITokenByHash indicates an expected call of GetAPITokenByHash.
+func (mr *MockDatabaseMockRecorder) GetAPITokenByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPITokenByHash", reflect.TypeOf((*MockDatabase)(nil).GetAPITokenByHash), ctx, hash)
+}
+
+// GetAPITokensByUserID mocks base method.
+func (m *MockDatabase) GetAPITokensByUserID(ctx context.Context, id uuid.UUID) ([]APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPITokensByUserID", ctx, id)
+	ret0, _ := ret[0].([]APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPITokensByUserID indicates an expected call of GetAPITokensByUserID.
+func (mr *MockDatabaseMockRecorder) GetAPITokensByUserID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPITokensByUserID", reflect.TypeOf((*MockDatabase)(nil).GetAPITokensByUserID), ctx, id)
+}
+
+// GetClickStats mocks base method.
+func (m *MockDatabase) GetClickStats(ctx context.Context, urlID uuid.UUID, since time.Time) (*ClickStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStats", ctx, urlID, since)
+	ret0, _ := ret[0].(*ClickStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStats indicates an expected call of GetClickStats.
+func (mr *MockDatabaseMockRecorder) GetClickStats(ctx, urlID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStats", reflect.TypeOf((*MockDatabase)(nil).GetClickStats), ctx, urlID, since)
+}
+
+// GetDeleteJob mocks base method.
+func (m *MockDatabase) GetDeleteJob(ctx context.Context, id uuid.UUID) (*DeleteJob, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleteJob", ctx, id)
+	ret0, _ := ret[0].(*DeleteJob)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetDeleteJob indicates an expected call of GetDeleteJob.
+func (mr *MockDatabaseMockRecorder) GetDeleteJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleteJob", reflect.TypeOf((*MockDatabase)(nil).GetDeleteJob), ctx, id)
+}
+
 // GetURLByShortCode mocks base method.
 func (m *MockDatabase) GetURLByShortCode(ctx context.Context, shortCode string) (*URL, bool) {
 	m.ctrl.T.Helper()
@@ -111,6 +366,21 @@ func (mr *MockDatabaseMockRecorder) GetURLByShortCode(ctx, shortCode any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLByShortCode", reflect.TypeOf((*MockDatabase)(nil).GetURLByShortCode), ctx, shortCode)
 }
 
+// GetURLsByLongURLs mocks base method.
+func (m *MockDatabase) GetURLsByLongURLs(ctx context.Context, longURLs []string) ([]URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLsByLongURLs", ctx, longURLs)
+	ret0, _ := ret[0].([]URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetURLsByLongURLs indicates an expected call of GetURLsByLongURLs.
+func (mr *MockDatabaseMockRecorder) GetURLsByLongURLs(ctx, longURLs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLsByLongURLs", reflect.TypeOf((*MockDatabase)(nil).GetURLsByLongURLs), ctx, longURLs)
+}
+
 // GetURLsByUserID mocks base method.
 func (m *MockDatabase) GetURLsByUserID(ctx context.Context, uuid uuid.UUID, limit, offset int64) ([]URL, int, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +397,36 @@ func (mr *MockDatabaseMockRecorder) GetURLsByUserID(ctx, uuid, limit, offset any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLsByUserID", reflect.TypeOf((*MockDatabase)(nil).GetURLsByUserID), ctx, uuid, limit, offset)
 }
 
+// GetURLsByUserIDAfter mocks base method.
+func (m *MockDatabase) GetURLsByUserIDAfter(ctx context.Context, uuid uuid.UUID, after URLCursor, limit int64) ([]URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLsByUserIDAfter", ctx, uuid, after, limit)
+	ret0, _ := ret[0].([]URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetURLsByUserIDAfter indicates an expected call of GetURLsByUserIDAfter.
+func (mr *MockDatabaseMockRecorder) GetURLsByUserIDAfter(ctx, uuid, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLsByUserIDAfter", reflect.TypeOf((*MockDatabase)(nil).GetURLsByUserIDAfter), ctx, uuid, after, limit)
+}
+
+// NextSequence mocks base method.
+func (m *MockDatabase) NextSequence(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextSequence", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextSequence indicates an expected call of NextSequence.
+func (mr *MockDatabaseMockRecorder) NextSequence(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextSequence", reflect.TypeOf((*MockDatabase)(nil).NextSequence), ctx)
+}
+
 // Ping mocks base method.
 func (m *MockDatabase) Ping(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -140,3 +440,105 @@ func (mr *MockDatabaseMockRecorder) Ping(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockDatabase)(nil).Ping), ctx)
 }
+
+// PurgeDeleteJobs mocks base method.
+func (m *MockDatabase) PurgeDeleteJobs(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleteJobs", ctx, finishedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleteJobs indicates an expected call of PurgeDeleteJobs.
+func (mr *MockDatabaseMockRecorder) PurgeDeleteJobs(ctx, finishedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleteJobs", reflect.TypeOf((*MockDatabase)(nil).PurgeDeleteJobs), ctx, finishedBefore)
+}
+
+// PurgeURLs mocks base method.
+func (m *MockDatabase) PurgeURLs(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeURLs", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeURLs indicates an expected call of PurgeURLs.
+func (mr *MockDatabaseMockRecorder) PurgeURLs(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeURLs", reflect.TypeOf((*MockDatabase)(nil).PurgeURLs), ctx, deletedBefore)
+}
+
+// RestoreURLsByUserID mocks base method.
+func (m *MockDatabase) RestoreURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string, deletedSince time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreURLsByUserID", ctx, uuid, shortCodes, deletedSince)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreURLsByUserID indicates an expected call of RestoreURLsByUserID.
+func (mr *MockDatabaseMockRecorder) RestoreURLsByUserID(ctx, uuid, shortCodes, deletedSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreURLsByUserID", reflect.TypeOf((*MockDatabase)(nil).RestoreURLsByUserID), ctx, uuid, shortCodes, deletedSince)
+}
+
+// RetryDeleteTask mocks base method.
+func (m *MockDatabase) RetryDeleteTask(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDeleteTask", ctx, id, runAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryDeleteTask indicates an expected call of RetryDeleteTask.
+func (mr *MockDatabaseMockRecorder) RetryDeleteTask(ctx, id, runAt, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDeleteTask", reflect.TypeOf((*MockDatabase)(nil).RetryDeleteTask), ctx, id, runAt, lastError)
+}
+
+// RevokeAPIToken mocks base method.
+func (m *MockDatabase) RevokeAPIToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIToken", ctx, userID, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIToken indicates an expected call of RevokeAPIToken.
+func (mr *MockDatabaseMockRecorder) RevokeAPIToken(ctx, userID, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIToken", reflect.TypeOf((*MockDatabase)(nil).RevokeAPIToken), ctx, userID, tokenID)
+}
+
+// SaveDeleteJob mocks base method.
+func (m *MockDatabase) SaveDeleteJob(ctx context.Context, job DeleteJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeleteJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeleteJob indicates an expected call of SaveDeleteJob.
+func (mr *MockDatabaseMockRecorder) SaveDeleteJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeleteJob", reflect.TypeOf((*MockDatabase)(nil).SaveDeleteJob), ctx, job)
+}
+
+// UpdateURL mocks base method.
+func (m *MockDatabase) UpdateURL(ctx context.Context, url URL) (*URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateURL", ctx, url)
+	ret0, _ := ret[0].(*URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateURL indicates an expected call of UpdateURL.
+func (mr *MockDatabaseMockRecorder) UpdateURL(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateURL", reflect.TypeOf((*MockDatabase)(nil).UpdateURL), ctx, url)
+}
@@ -6,11 +6,13 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"shortly/internal/app/errors"
 	"shortly/internal/app/repository/db"
 	"shortly/internal/spec"
 )
@@ -170,6 +172,35 @@ func Benchmark_DatabaseRepository_CreateURL(b *testing.B) {
 	}
 }
 
+func Test_DatabaseRepository_CreateURL_ShortCodeTaken(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURL(ctx, URL{
+		UUID:      UUID1,
+		LongURL:   "https://example.com",
+		ShortCode: "spring-sale",
+	})
+	assert.NoError(t, err)
+
+	_, err = store.CreateURL(ctx, URL{
+		UUID:      UUID2,
+		LongURL:   "https://github.com",
+		ShortCode: "spring-sale",
+	})
+	assert.ErrorIs(t, err, errors.ErrShortCodeAlreadyExists)
+}
+
 func Test_DatabaseRepository_CreateURLs(t *testing.T) {
 	ctx := context.Background()
 	dsn := os.Getenv("DATABASE_DSN")
@@ -204,7 +235,7 @@ func Test_DatabaseRepository_CreateURLs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err = store.CreateURLs(ctx, tt.urls)
+			_, err = store.CreateURLs(ctx, tt.urls)
 			assert.NoError(t, err)
 
 			for _, url := range tt.urls {
@@ -225,6 +256,43 @@ func Test_DatabaseRepository_CreateURLs(t *testing.T) {
 	}
 }
 
+func Test_DatabaseRepository_CreateURLs_Existing(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://example.com", ShortCode: "abcd0001"})
+	require.NoError(t, err)
+
+	records, err := store.CreateURLs(ctx, []URL{
+		{UUID: uuid.New(), LongURL: "https://example.com", ShortCode: "abcd0002"},
+		{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0003"},
+		{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0004"},
+	})
+	require.NoError(t, err)
+
+	shortCodes := make([]string, 0, len(records))
+	for _, record := range records {
+		shortCodes = append(shortCodes, record.ShortCode)
+	}
+	assert.Equal(t, []string{"abcd0001", "abcd0003", "abcd0003"}, shortCodes)
+
+	record, err := store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0005"})
+	require.NoError(t, err)
+	assert.Equal(t, "abcd0003", record.ShortCode)
+
+	for _, shortCode := range []string{"abcd0002", "abcd0004", "abcd0005"} {
+		_, found := store.GetURLByShortCode(ctx, shortCode)
+		assert.False(t, found)
+	}
+}
+
 func Test_DatabaseRepository_GetURLByShortCode(t *testing.T) {
 	ctx := context.Background()
 	dsn := os.Getenv("DATABASE_DSN")
@@ -313,6 +381,37 @@ func Benchmark_DatabaseRepository_GetURLByShortCode(b *testing.B) {
 	}
 }
 
+func Test_DatabaseRepository_GetURLsByLongURLs(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURLs(ctx, []URL{
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720001"), LongURL: "https://a.com", ShortCode: "abcd0001", UserUUID: UserUUID},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720002"), LongURL: "https://b.com", ShortCode: "abcd0002", UserUUID: UserUUID},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720003"), LongURL: "https://c.com", ShortCode: "abcd0003", UserUUID: UserUUID},
+	})
+	require.NoError(t, err)
+
+	urls, err := store.GetURLsByLongURLs(ctx, []string{"https://a.com", "https://c.com", "https://d.com"})
+	require.NoError(t, err)
+
+	shortCodes := make([]string, 0, len(urls))
+	for _, url := range urls {
+		assert.Equal(t, UserUUID, url.UserUUID)
+		shortCodes = append(shortCodes, url.ShortCode)
+	}
+	assert.ElementsMatch(t, []string{"abcd0001", "abcd0003"}, shortCodes)
+}
+
 func Test_DatabaseRepository_GetURLsByUserID(t *testing.T) {
 	ctx := context.Background()
 	dsn := os.Getenv("DATABASE_DSN")
@@ -438,6 +537,254 @@ func Test_DatabaseRepository_GetURLsByUserID(t *testing.T) {
 	}
 }
 
+func Test_DatabaseRepository_GetURLsByUserIDAfter(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURLs(ctx, []URL{
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720001"), LongURL: "https://a.com", ShortCode: "abcd0001", UserUUID: UserUUID},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720002"), LongURL: "https://b.com", ShortCode: "abcd0002", UserUUID: UserUUID},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720003"), LongURL: "https://c.com", ShortCode: "abcd0003", UserUUID: UserUUID},
+	})
+	require.NoError(t, err)
+
+	err = spec.RunQuery(ctx, dsn, "UPDATE urls SET created_at = '2026-10-01 12:00:00'")
+	require.NoError(t, err)
+
+	first, _, err := store.GetURLsByUserID(ctx, UserUUID, 1, 0)
+	require.NoError(t, err)
+
+	_, err = store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://new.com", ShortCode: "abcd0006", UserUUID: UserUUID})
+	require.NoError(t, err)
+
+	shortCodes := []string{first[0].ShortCode}
+	last := first[0]
+
+	for {
+		page, err := store.GetURLsByUserIDAfter(ctx, UserUUID, URLCursor{CreatedAt: last.CreatedAt, UUID: last.UUID}, 1)
+		require.NoError(t, err)
+
+		if len(page) == 0 {
+			break
+		}
+
+		shortCodes = append(shortCodes, page[0].ShortCode)
+		last = page[0]
+	}
+
+	assert.Equal(t, []string{"abcd0003", "abcd0002", "abcd0001"}, shortCodes)
+}
+
+func Test_DatabaseRepository_ExportURLsByUserID(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURLs(ctx, []URL{
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720001"), LongURL: "https://a.com", ShortCode: "abcd0001", UserUUID: UserUUID},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720002"), LongURL: "https://b.com", ShortCode: "abcd0002", UserUUID: UserUUID},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720003"), LongURL: "https://c.com", ShortCode: "abcd0003", UserUUID: UserUUID},
+	})
+	require.NoError(t, err)
+
+	err = spec.RunQuery(ctx, dsn, "UPDATE urls SET created_at = '2026-10-01 12:00:00'")
+	require.NoError(t, err)
+
+	_, err = store.DeleteURLsByUserID(ctx, UserUUID, []string{"abcd0002"})
+	require.NoError(t, err)
+
+	var shortCodes []string
+	var cursor URLCursor
+
+	for {
+		page, err := store.ExportURLsByUserID(ctx, UserUUID, cursor, 2)
+		require.NoError(t, err)
+
+		if len(page) == 0 {
+			break
+		}
+
+		for _, item := range page {
+			shortCodes = append(shortCodes, item.ShortCode)
+		}
+		last := page[len(page)-1]
+		cursor = URLCursor{CreatedAt: last.CreatedAt, UUID: last.UUID}
+	}
+
+	assert.Equal(t, []string{"abcd0003", "abcd0002", "abcd0001"}, shortCodes)
+}
+
+func Test_DatabaseRepository_FindURLsByUserID(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID3, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720003")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURLs(ctx, []URL{
+		{UUID: UUID1, LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID},
+		{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID},
+		{UUID: UUID3, LongURL: "https://example.com/100%_off", ShortCode: "abcd0003", UserUUID: UserUUID},
+		{UUID: uuid.New(), LongURL: "https://deleted.com", ShortCode: "abcd0004", UserUUID: UserUUID},
+	})
+	require.NoError(t, err)
+
+	_, err = store.DeleteURLsByUserID(ctx, UserUUID, []string{"abcd0004"})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateClicks(ctx, []Click{
+		{URLUUID: UUID1, IPHash: "a", CreatedAt: time.Now()},
+		{URLUUID: UUID1, IPHash: "b", CreatedAt: time.Now()},
+		{URLUUID: UUID3, IPHash: "a", CreatedAt: time.Now()},
+	}))
+
+	type result struct {
+		shortCodes []string
+		total      int
+	}
+
+	tests := []struct {
+		name     string
+		filter   URLFilter
+		expected result
+	}{
+		{
+			name:     "Most clicked first",
+			filter:   URLFilter{Sort: SortClicksDesc, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0001", "abcd0003", "abcd0002"}, total: 3},
+		},
+		{
+			name:     "Least clicked first",
+			filter:   URLFilter{Sort: SortClicks, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0002", "abcd0003", "abcd0001"}, total: 3},
+		},
+		{
+			name:     "Case-insensitive search",
+			filter:   URLFilter{Query: "GITHUB", Limit: 25},
+			expected: result{shortCodes: []string{"abcd0002"}, total: 1},
+		},
+		{
+			name:     "Wildcards match literally",
+			filter:   URLFilter{Query: "%_", Limit: 25},
+			expected: result{shortCodes: []string{"abcd0003"}, total: 1},
+		},
+		{
+			name:     "Deleted",
+			filter:   URLFilter{Deleted: true, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0004"}, total: 1},
+		},
+		{
+			name:     "Page beyond the last",
+			filter:   URLFilter{Sort: SortClicks, Limit: 2, Offset: 4},
+			expected: result{shortCodes: []string{}, total: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.UserUUID = UserUUID
+
+			items, total, err := store.FindURLsByUserID(ctx, tt.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.total, total)
+
+			shortCodes := make([]string, 0, len(items))
+			for _, item := range items {
+				shortCodes = append(shortCodes, item.ShortCode)
+			}
+			assert.Equal(t, tt.expected.shortCodes, shortCodes)
+		})
+	}
+}
+
+func Test_DatabaseRepository_UpdateURL(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	for _, url := range []URL{
+		{UUID: UUID1, LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID1},
+		{UUID: UUID2, LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID1},
+	} {
+		_, err = store.CreateURL(ctx, url)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		url      URL
+		expected error
+	}{
+		{
+			name:     "Success",
+			url:      URL{ShortCode: "abcd0001", UserUUID: UserUUID1, LongURL: "https://example.com"},
+			expected: nil,
+		},
+		{
+			name:     "Conflict",
+			url:      URL{ShortCode: "abcd0002", UserUUID: UserUUID1, LongURL: "https://example.com"},
+			expected: errors.ErrURLAlreadyExists,
+		},
+		{
+			name:     "Not owned",
+			url:      URL{ShortCode: "abcd0001", UserUUID: UserUUID2, LongURL: "https://example.org"},
+			expected: errors.ErrShortLinkNotFound,
+		},
+		{
+			name:     "Not found",
+			url:      URL{ShortCode: "abcd0003", UserUUID: UserUUID1, LongURL: "https://example.org"},
+			expected: errors.ErrShortLinkNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, err := store.UpdateURL(ctx, tt.url)
+
+			assert.Equal(t, tt.expected, err)
+			if tt.expected == nil {
+				assert.Equal(t, tt.url.LongURL, url.LongURL)
+				assert.False(t, url.UpdatedAt.IsZero())
+			}
+		})
+	}
+}
+
 func Test_DatabaseRepository_DeleteURLsByUserID(t *testing.T) {
 	ctx := context.Background()
 	dsn := os.Getenv("DATABASE_DSN")
@@ -547,7 +894,7 @@ func Test_DatabaseRepository_DeleteURLsByUserID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.before()
 
-			err = store.DeleteURLsByUserID(ctx, tt.params.UserUUID, tt.params.ShortCodes)
+			_, err = store.DeleteURLsByUserID(ctx, tt.params.UserUUID, tt.params.ShortCodes)
 			assert.NoError(t, err)
 
 			_, total, err := store.GetURLsByUserID(ctx, tt.ownerID, 25, 0)
@@ -562,6 +909,509 @@ func Test_DatabaseRepository_DeleteURLsByUserID(t *testing.T) {
 	}
 }
 
+func Test_DatabaseRepository_DeleteURLsByUserIDs(t *testing.T) {
+	ctx := context.Background()
+
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+
+	tests := []struct {
+		name       string
+		shortCodes map[uuid.UUID][]string
+		expected   map[uuid.UUID][]string
+	}{
+		{
+			name:       "Many users",
+			shortCodes: map[uuid.UUID][]string{UserUUID1: {"abcd0001", "abcd0003"}, UserUUID2: {"abcd0002"}},
+			expected:   map[uuid.UUID][]string{UserUUID1: {"abcd0001"}, UserUUID2: {"abcd0002"}},
+		},
+		{
+			name:       "Not owned",
+			shortCodes: map[uuid.UUID][]string{UserUUID2: {"abcd0001"}},
+			expected:   map[uuid.UUID][]string{},
+		},
+		{
+			name:       "Not found",
+			shortCodes: map[uuid.UUID][]string{UserUUID1: {"1234abcd"}},
+			expected:   map[uuid.UUID][]string{},
+		},
+		{
+			name:       "Empty",
+			shortCodes: map[uuid.UUID][]string{},
+			expected:   map[uuid.UUID][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn := os.Getenv("DATABASE_DSN")
+			store, err := NewDatabaseRepository(ctx, dsn)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, spec.TruncateTables(ctx, dsn))
+			})
+
+			_, err = store.CreateURLs(ctx, []URL{
+				{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID1},
+				{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID2},
+				{UUID: uuid.New(), LongURL: "https://example.com", ShortCode: "abcd0003", UserUUID: UserUUID1},
+			})
+			require.NoError(t, err)
+			_, err = store.DeleteURLsByUserID(ctx, UserUUID1, []string{"abcd0003"})
+			require.NoError(t, err)
+
+			deleted, err := store.DeleteURLsByUserIDs(ctx, tt.shortCodes)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, deleted)
+
+			for _, codes := range tt.expected {
+				for _, code := range codes {
+					url, found := store.GetURLByShortCode(ctx, code)
+					require.True(t, found)
+					assert.False(t, url.DeletedAt.IsZero())
+				}
+			}
+		})
+	}
+}
+
+func Test_DatabaseRepository_ExpireURLs(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURLs(ctx, []URL{
+		{
+			UUID:      UUID1,
+			LongURL:   "https://google.com",
+			ShortCode: "abcd0001",
+			ExpiresAt: time.Now().Add(-time.Hour),
+		},
+		{
+			UUID:      UUID2,
+			LongURL:   "https://github.com",
+			ShortCode: "abcd0002",
+			ExpiresAt: time.Now().Add(time.Hour),
+		},
+	})
+	assert.NoError(t, err)
+
+	count, err := store.ExpireURLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	expired, found := store.GetURLByShortCode(ctx, "abcd0001")
+	assert.True(t, found)
+	assert.True(t, expired.Expired)
+
+	active, found := store.GetURLByShortCode(ctx, "abcd0002")
+	assert.True(t, found)
+	assert.False(t, active.Expired)
+}
+
+func Test_DatabaseRepository_ExpireURLs_SessionTimeZone(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+
+	// NOTE: the session clock of Asia/Tokyo is 9 hours ahead of the UTC expiration times
+	store, err := NewDatabaseRepository(ctx, dsn+"&timezone=Asia/Tokyo")
+	assert.NoError(t, err)
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURL(ctx, URL{
+		UUID:      UUID,
+		LongURL:   "https://google.com",
+		ShortCode: "abcd0001",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	count, err := store.ExpireURLs(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func Test_DatabaseRepository_CountURLs(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+	UserUUID3, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174003")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURLs(ctx, []URL{
+		{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID1},
+		{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID1},
+		{UUID: uuid.New(), LongURL: "https://golang.org", ShortCode: "abcd0003", UserUUID: UserUUID2},
+		{UUID: uuid.New(), LongURL: "https://example.com", ShortCode: "abcd0004", UserUUID: UserUUID2},
+		{UUID: uuid.New(), LongURL: "https://example.org", ShortCode: "abcd0005", UserUUID: UserUUID3, ExpiresAt: time.Now().Add(-time.Hour)},
+		// NOTE: links created without a user are not counted as an owner
+		{UUID: uuid.New(), LongURL: "https://example.net", ShortCode: "abcd0006", UserUUID: uuid.Nil},
+	})
+	require.NoError(t, err)
+
+	_, err = store.DeleteURLsByUserID(ctx, UserUUID2, []string{"abcd0004"})
+	require.NoError(t, err)
+	_, err = store.ExpireURLs(ctx)
+	require.NoError(t, err)
+
+	urls, err := store.CountURLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), urls)
+
+	users, err := store.CountUsers(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), users)
+}
+
+func Test_DatabaseRepository_RestoreURLsByUserID(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURLs(ctx, []URL{
+		{UUID: UUID1, LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID1},
+		{UUID: UUID2, LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID1},
+	})
+	require.NoError(t, err)
+	_, err = store.DeleteURLsByUserID(ctx, UserUUID1, []string{"abcd0001"})
+	require.NoError(t, err)
+
+	restored, err := store.RestoreURLsByUserID(ctx, UserUUID2, []string{"abcd0001"}, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, restored)
+
+	restored, err = store.RestoreURLsByUserID(ctx, UserUUID1, []string{"abcd0001"}, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, restored)
+
+	restored, err = store.RestoreURLsByUserID(ctx, UserUUID1, []string{"abcd0001", "abcd0002"}, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abcd0001"}, restored)
+
+	url, found := store.GetURLByShortCode(ctx, "abcd0001")
+	assert.True(t, found)
+	assert.True(t, url.DeletedAt.IsZero())
+}
+
+func Test_DatabaseRepository_RestoreURLsByUserID_SessionTimeZone(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+
+	// NOTE: the session clock of Pacific/Honolulu is 10 hours behind the UTC restore cutoff
+	store, err := NewDatabaseRepository(ctx, dsn+"&timezone=Pacific/Honolulu")
+	assert.NoError(t, err)
+
+	UserUUID := uuid.New()
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID})
+	assert.NoError(t, err)
+
+	_, err = store.DeleteURLsByUserID(ctx, UserUUID, []string{"abcd0001"})
+	assert.NoError(t, err)
+
+	purged, err := store.PurgeURLs(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+
+	restored, err := store.RestoreURLsByUserID(ctx, UserUUID, []string{"abcd0001"}, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abcd0001"}, restored)
+}
+
+func Test_DatabaseRepository_PurgeURLs(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURLs(ctx, []URL{
+		{UUID: UUID1, LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID},
+		{UUID: UUID2, LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID},
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateClicks(ctx, []Click{{URLUUID: UUID1, IPHash: "hash", CreatedAt: time.Now()}}))
+	_, err = store.DeleteURLsByUserID(ctx, UserUUID, []string{"abcd0001"})
+	require.NoError(t, err)
+
+	count, err := store.PurgeURLs(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, found := store.GetURLByShortCode(ctx, "abcd0001")
+	assert.False(t, found)
+
+	_, found = store.GetURLByShortCode(ctx, "abcd0002")
+	assert.True(t, found)
+
+	stats, err := store.GetClickStats(ctx, UUID1, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), stats.Total)
+}
+
+func Test_DatabaseRepository_DeleteTasks(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UserUUID := uuid.New()
+	Task1, Task2 := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	require.NoError(t, store.EnqueueDeleteTask(ctx, DeleteTask{
+		UUID: Task1, UserUUID: UserUUID, ShortCodes: []string{"abcd0001", "abcd0002"}, RunAt: now.Add(-time.Minute), CreatedAt: now,
+		TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}))
+	require.NoError(t, store.EnqueueDeleteTask(ctx, DeleteTask{
+		UUID: Task2, UserUUID: UserUUID, ShortCodes: []string{"abcd0003"}, RunAt: now.Add(time.Hour), CreatedAt: now,
+	}))
+
+	stats, err := store.CountDeleteTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DeleteTaskStats{Pending: 2}, stats)
+
+	tasks, err := store.ClaimDeleteTasks(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, Task1, tasks[0].UUID)
+	assert.Equal(t, UserUUID, tasks[0].UserUUID)
+	assert.Equal(t, []string{"abcd0001", "abcd0002"}, tasks[0].ShortCodes)
+	assert.Equal(t, 1, tasks[0].Attempts)
+	assert.True(t, now.Add(time.Minute).Equal(tasks[0].RunAt))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tasks[0].TraceParent)
+
+	// NOTE: a claimed task is leased and not claimed again until the lease passes
+	tasks, err = store.ClaimDeleteTasks(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	require.NoError(t, store.RetryDeleteTask(ctx, Task1, now, "timeout"))
+
+	tasks, err = store.ClaimDeleteTasks(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, 2, tasks[0].Attempts)
+	assert.Equal(t, "timeout", tasks[0].LastError)
+
+	require.NoError(t, store.FailDeleteTask(ctx, Task1, "timeout"))
+	require.NoError(t, store.CompleteDeleteTasks(ctx, []uuid.UUID{Task2}))
+
+	stats, err = store.CountDeleteTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DeleteTaskStats{Failed: 1}, stats)
+
+	// NOTE: dead letters are never claimed
+	tasks, err = store.ClaimDeleteTasks(ctx, now.Add(24*time.Hour), now.Add(25*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func Test_DatabaseRepository_DeleteJobs(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	UserUUID := uuid.New()
+	Job1, Job2 := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	require.NoError(t, store.SaveDeleteJob(ctx, DeleteJob{
+		UUID: Job1, UserUUID: UserUUID, Status: "queued", ShortCodes: []string{"abcd0001", "abcd0002"}, CreatedAt: now,
+	}))
+	require.NoError(t, store.SaveDeleteJob(ctx, DeleteJob{
+		UUID: Job2, UserUUID: UserUUID, Status: "running", ShortCodes: []string{"abcd0003"}, CreatedAt: now,
+	}))
+
+	// NOTE: saving a job again replaces its state
+	require.NoError(t, store.SaveDeleteJob(ctx, DeleteJob{
+		UUID: Job1, UserUUID: UserUUID, Status: "done", ShortCodes: []string{"abcd0001", "abcd0002"},
+		Deleted: []string{"abcd0001"}, Skipped: []SkippedShortCode{{ShortCode: "abcd0002", Reason: "not_found"}},
+		CreatedAt: now, FinishedAt: now.Add(time.Minute),
+	}))
+
+	job, ok := store.GetDeleteJob(ctx, Job1)
+	require.True(t, ok)
+	assert.Equal(t, UserUUID, job.UserUUID)
+	assert.Equal(t, "done", job.Status)
+	assert.Equal(t, []string{"abcd0001", "abcd0002"}, job.ShortCodes)
+	assert.Equal(t, []string{"abcd0001"}, job.Deleted)
+	assert.Equal(t, []SkippedShortCode{{ShortCode: "abcd0002", Reason: "not_found"}}, job.Skipped)
+	assert.True(t, now.Equal(job.CreatedAt))
+	assert.True(t, now.Add(time.Minute).Equal(job.FinishedAt))
+
+	_, ok = store.GetDeleteJob(ctx, uuid.New())
+	assert.False(t, ok)
+
+	// NOTE: unfinished jobs are never purged
+	count, err := store.PurgeDeleteJobs(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, ok = store.GetDeleteJob(ctx, Job1)
+	assert.False(t, ok)
+
+	_, ok = store.GetDeleteJob(ctx, Job2)
+	assert.True(t, ok)
+}
+
+func Test_DatabaseRepository_GetClickStats(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	day1 := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	_, err = store.CreateURL(ctx, URL{
+		UUID:      UUID,
+		LongURL:   "https://google.com",
+		ShortCode: "abcd0001",
+	})
+	assert.NoError(t, err)
+
+	err = store.CreateClicks(ctx, []Click{
+		{URLUUID: UUID, IPHash: "hash1", CreatedAt: day1.Add(-48 * time.Hour)},
+		{URLUUID: UUID, IPHash: "hash1", CreatedAt: day2},
+		{URLUUID: UUID, IPHash: "hash2", CreatedAt: day1},
+		{URLUUID: UUID, IPHash: "hash1", CreatedAt: day1.Add(time.Hour)},
+	})
+	assert.NoError(t, err)
+
+	stats, err := store.GetClickStats(ctx, UUID, day1.Truncate(24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), stats.Total)
+	assert.Equal(t, int64(2), stats.Unique)
+	assert.Len(t, stats.Daily, 2)
+	assert.Equal(t, int64(2), stats.Daily[0].Total)
+	assert.Equal(t, int64(2), stats.Daily[0].Unique)
+	assert.Equal(t, int64(1), stats.Daily[1].Total)
+}
+
+func Test_DatabaseRepository_NextSequence(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	first, err := store.NextSequence(ctx)
+	assert.NoError(t, err)
+
+	second, err := store.NextSequence(ctx)
+	assert.NoError(t, err)
+
+	assert.Greater(t, second, first)
+}
+
+func Test_DatabaseRepository_APITokens(t *testing.T) {
+	ctx := context.Background()
+	dsn := os.Getenv("DATABASE_DSN")
+	store, err := NewDatabaseRepository(ctx, dsn)
+	assert.NoError(t, err)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	OtherUserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+	TokenUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+
+	t.Cleanup(func() {
+		err = spec.TruncateTables(ctx, dsn)
+		require.NoError(t, err)
+	})
+
+	created, err := store.CreateAPIToken(ctx, APIToken{
+		UUID:     TokenUUID,
+		UserUUID: UserUUID,
+		Name:     "ci",
+		Prefix:   "shk_abcdefgh",
+		Hash:     "hash",
+		Scopes:   []string{"links:read"},
+	})
+	require.NoError(t, err)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	token, ok := store.GetAPITokenByHash(ctx, "hash")
+	assert.True(t, ok)
+	assert.Equal(t, created, token)
+
+	tokens, err := store.GetAPITokensByUserID(ctx, UserUUID)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	err = store.RevokeAPIToken(ctx, OtherUserUUID, TokenUUID)
+	assert.ErrorIs(t, err, errors.ErrAPITokenNotFound)
+
+	err = store.RevokeAPIToken(ctx, UserUUID, TokenUUID)
+	assert.NoError(t, err)
+
+	_, ok = store.GetAPITokenByHash(ctx, "hash")
+	assert.False(t, ok)
+}
+
 func Test_DatabaseRepository_Ping(t *testing.T) {
 	ctx := context.Background()
 	dsn := os.Getenv("DATABASE_DSN")
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"shortly/internal/app/tracing"
+	"shortly/internal/logger"
+)
+
+// DefaultQuerySpan is the span name of queries without a sqlc name comment
+const DefaultQuerySpan = "db.query"
+
+// queryTracer is a pgx tracer starting a client span and logging the duration of every database query
+type queryTracer struct{}
+
+type queryKey struct{}
+
+// query is the name and the start time of a running query
+type query struct {
+	name  string
+	start time.Time
+}
+
+// TraceQueryStart starts the query span named after the sqlc query
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name := querySpanName(data.SQL)
+	ctx = context.WithValue(ctx, queryKey{}, query{name: name, start: time.Now()})
+
+	ctx, _ = tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+
+	return ctx
+}
+
+// TraceQueryEnd records the query error, ends the query span and logs the query at the debug level
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil && data.Err != pgx.ErrNoRows {
+		tracing.RecordError(span, data.Err)
+	}
+
+	span.End()
+
+	if q, ok := ctx.Value(queryKey{}).(query); ok {
+		logger.FromContext(ctx).Debug().
+			Str("query", q.name).
+			Dur("duration", time.Since(q.start)).
+			Int64("rows", data.CommandTag.RowsAffected()).
+			Err(data.Err).
+			Msg("Database query")
+	}
+}
+
+// querySpanName returns the name of the sqlc query from its "-- name: CreateURL :one" comment
+func querySpanName(sql string) string {
+	name, ok := strings.CutPrefix(strings.TrimSpace(sql), "-- name: ")
+	if !ok {
+		return DefaultQuerySpan
+	}
+
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return DefaultQuerySpan
+	}
+
+	return "db." + fields[0]
+}
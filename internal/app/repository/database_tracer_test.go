@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"shortly/internal/logger"
+)
+
+func Test_QueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	tests := []struct {
+		name   string
+		sql    string
+		err    error
+		span   string
+		status codes.Code
+	}{
+		{
+			name:   "Named query",
+			sql:    "-- name: CreateURL :one\nINSERT INTO urls (uuid) VALUES ($1)",
+			span:   "db.CreateURL",
+			status: codes.Unset,
+		},
+		{
+			name:   "Unnamed query",
+			sql:    "SELECT 1",
+			span:   DefaultQuerySpan,
+			status: codes.Unset,
+		},
+		{
+			name:   "No rows",
+			sql:    "-- name: GetURLByShortCode :one\nSELECT uuid FROM urls",
+			err:    pgx.ErrNoRows,
+			span:   "db.GetURLByShortCode",
+			status: codes.Unset,
+		},
+		{
+			name:   "Error",
+			sql:    "-- name: DeleteURLs :exec\nUPDATE urls SET is_deleted = true",
+			err:    assert.AnError,
+			span:   "db.DeleteURLs",
+			status: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(recorder.Ended())
+			tracer := queryTracer{}
+
+			ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: tt.sql})
+			tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: tt.err})
+
+			spans := recorder.Ended()
+			require.Len(t, spans, before+1)
+			span := spans[before]
+
+			assert.Equal(t, tt.span, span.Name())
+			assert.Equal(t, tt.status, span.Status().Code)
+			assert.Contains(t, span.Attributes(), semconv.DBQueryText(tt.sql))
+		})
+	}
+}
+
+func Test_QueryTracer_Log(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queries.log")
+	appLogger, err := logger.New(logger.Options{Format: logger.FormatJSON, Level: "debug", Output: path})
+	require.NoError(t, err)
+
+	ctx := logger.WithContext(context.Background(), appLogger)
+	tracer := queryTracer{}
+
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "-- name: CreateURL :one\nINSERT INTO urls (uuid) VALUES ($1)"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("INSERT 0 1")})
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, "debug", entry["level"])
+	assert.Equal(t, "db.CreateURL", entry["query"])
+	assert.Equal(t, float64(1), entry["rows"])
+	assert.Contains(t, entry, "duration")
+}
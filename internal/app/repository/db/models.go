@@ -9,6 +9,50 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiToken struct {
+	Uuid      uuid.UUID
+	UserUuid  uuid.UUID
+	Name      string
+	Prefix    string
+	TokenHash string
+	Scopes    []string
+	CreatedAt pgtype.Timestamp
+	RevokedAt pgtype.Timestamp
+}
+
+type Click struct {
+	Uuid      uuid.UUID
+	UrlUuid   uuid.UUID
+	Referrer  string
+	UserAgent string
+	IpHash    string
+	CreatedAt pgtype.Timestamp
+}
+
+type DeleteJob struct {
+	Uuid       uuid.UUID
+	UserUuid   uuid.UUID
+	Status     string
+	ShortCodes []string
+	Deleted    []string
+	Skipped    []byte
+	Error      string
+	CreatedAt  pgtype.Timestamp
+	FinishedAt pgtype.Timestamp
+}
+
+type DeleteTask struct {
+	Uuid        uuid.UUID
+	UserUuid    uuid.UUID
+	ShortCodes  []string
+	Attempts    int32
+	LastError   string
+	RunAt       pgtype.Timestamp
+	CreatedAt   pgtype.Timestamp
+	FailedAt    pgtype.Timestamp
+	TraceParent string
+}
+
 type Url struct {
 	Uuid      uuid.UUID
 	LongUrl   string
@@ -17,4 +61,6 @@ type Url struct {
 	UpdatedAt pgtype.Timestamp
 	UserUuid  uuid.UUID
 	DeletedAt pgtype.Timestamp
+	ExpiresAt pgtype.Timestamp
+	Expired   bool
 }
@@ -12,9 +12,213 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimDeleteTasks = `-- name: ClaimDeleteTasks :many
+UPDATE delete_tasks
+SET run_at = $1, attempts = attempts + 1
+WHERE uuid IN (
+  SELECT uuid FROM delete_tasks
+  WHERE failed_at IS NULL AND run_at <= $2
+  ORDER BY run_at, created_at
+  LIMIT $3
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING uuid, user_uuid, short_codes, attempts, last_error, run_at, created_at, trace_parent
+`
+
+type ClaimDeleteTasksParams struct {
+	LeaseUntil pgtype.Timestamp
+	Now        pgtype.Timestamp
+	Lim        int64
+}
+
+type ClaimDeleteTasksRow struct {
+	UUID        uuid.UUID
+	UserUUID    uuid.UUID
+	ShortCodes  []string
+	Attempts    int32
+	LastError   string
+	RunAt       pgtype.Timestamp
+	CreatedAt   pgtype.Timestamp
+	TraceParent string
+}
+
+func (q *Queries) ClaimDeleteTasks(ctx context.Context, arg ClaimDeleteTasksParams) ([]ClaimDeleteTasksRow, error) {
+	rows, err := q.db.Query(ctx, claimDeleteTasks, arg.LeaseUntil, arg.Now, arg.Lim)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimDeleteTasksRow
+	for rows.Next() {
+		var i ClaimDeleteTasksRow
+		if err := rows.Scan(
+			&i.UUID,
+			&i.UserUUID,
+			&i.ShortCodes,
+			&i.Attempts,
+			&i.LastError,
+			&i.RunAt,
+			&i.CreatedAt,
+			&i.TraceParent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const completeDeleteTasks = `-- name: CompleteDeleteTasks :exec
+DELETE FROM delete_tasks
+WHERE uuid = ANY($1::uuid[])
+`
+
+func (q *Queries) CompleteDeleteTasks(ctx context.Context, dollar_1 []uuid.UUID) error {
+	_, err := q.db.Exec(ctx, completeDeleteTasks, dollar_1)
+	return err
+}
+
+const countDeleteTasks = `-- name: CountDeleteTasks :one
+SELECT
+  COUNT(*) FILTER (WHERE failed_at IS NULL) AS pending,
+  COUNT(*) FILTER (WHERE failed_at IS NOT NULL) AS failed
+FROM delete_tasks
+`
+
+type CountDeleteTasksRow struct {
+	Pending int64
+	Failed  int64
+}
+
+func (q *Queries) CountDeleteTasks(ctx context.Context) (CountDeleteTasksRow, error) {
+	row := q.db.QueryRow(ctx, countDeleteTasks)
+	var i CountDeleteTasksRow
+	err := row.Scan(&i.Pending, &i.Failed)
+	return i, err
+}
+
+const countURLs = `-- name: CountURLs :one
+SELECT COUNT(*)
+FROM urls
+WHERE deleted_at IS NULL AND expired = FALSE
+`
+
+func (q *Queries) CountURLs(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countURLs)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countURLsByUserID = `-- name: CountURLsByUserID :one
+SELECT COUNT(*)
+FROM urls AS u
+WHERE u.user_uuid = $1
+  AND CASE
+    WHEN $2::boolean THEN u.deleted_at IS NOT NULL
+    WHEN $3::boolean THEN u.deleted_at IS NULL AND u.expired
+    ELSE u.deleted_at IS NULL AND NOT u.expired
+  END
+  AND ($4::text = '' OR u.long_url ILIKE $4 OR u.short_code ILIKE $4)
+  AND ($5::timestamp IS NULL OR u.created_at >= $5)
+  AND ($6::timestamp IS NULL OR u.created_at < $6)
+`
+
+type CountURLsByUserIDParams struct {
+	UserUUID      uuid.UUID
+	Deleted       bool
+	Expired       bool
+	Pattern       string
+	CreatedAfter  pgtype.Timestamp
+	CreatedBefore pgtype.Timestamp
+}
+
+func (q *Queries) CountURLsByUserID(ctx context.Context, arg CountURLsByUserIDParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countURLsByUserID,
+		arg.UserUUID,
+		arg.Deleted,
+		arg.Expired,
+		arg.Pattern,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(DISTINCT user_uuid)
+FROM urls
+WHERE deleted_at IS NULL AND expired = FALSE AND user_uuid <> '00000000-0000-0000-0000-000000000000'
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAPIToken = `-- name: CreateAPIToken :one
+INSERT INTO api_tokens (uuid, user_uuid, name, prefix, token_hash, scopes)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING created_at
+`
+
+type CreateAPITokenParams struct {
+	UUID      uuid.UUID
+	UserUUID  uuid.UUID
+	Name      string
+	Prefix    string
+	TokenHash string
+	Scopes    []string
+}
+
+func (q *Queries) CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (pgtype.Timestamp, error) {
+	row := q.db.QueryRow(ctx, createAPIToken,
+		arg.UUID,
+		arg.UserUUID,
+		arg.Name,
+		arg.Prefix,
+		arg.TokenHash,
+		arg.Scopes,
+	)
+	var created_at pgtype.Timestamp
+	err := row.Scan(&created_at)
+	return created_at, err
+}
+
+const createClick = `-- name: CreateClick :exec
+INSERT INTO clicks (url_uuid, referrer, user_agent, ip_hash, created_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateClickParams struct {
+	UrlUuid   uuid.UUID
+	Referrer  string
+	UserAgent string
+	IpHash    string
+	CreatedAt pgtype.Timestamp
+}
+
+func (q *Queries) CreateClick(ctx context.Context, arg CreateClickParams) error {
+	_, err := q.db.Exec(ctx, createClick,
+		arg.UrlUuid,
+		arg.Referrer,
+		arg.UserAgent,
+		arg.IpHash,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const createURL = `-- name: CreateURL :one
-INSERT INTO urls (uuid, long_url, short_code, user_uuid)
-VALUES ($1, $2, $3, $4)
+INSERT INTO urls (uuid, long_url, short_code, user_uuid, expires_at)
+VALUES ($1, $2, $3, $4, $5)
 ON CONFLICT (long_url) DO UPDATE SET short_code = urls.short_code
 RETURNING uuid, long_url, short_code
 `
@@ -24,6 +228,7 @@ type CreateURLParams struct {
 	LongURL   string
 	ShortCode string
 	UserUUID  uuid.UUID
+	ExpiresAt pgtype.Timestamp
 }
 
 type CreateURLRow struct {
@@ -38,37 +243,476 @@ func (q *Queries) CreateURL(ctx context.Context, arg CreateURLParams) (CreateURL
 		arg.LongURL,
 		arg.ShortCode,
 		arg.UserUUID,
+		arg.ExpiresAt,
 	)
 	var i CreateURLRow
 	err := row.Scan(&i.UUID, &i.LongURL, &i.ShortCode)
 	return i, err
 }
 
-const deleteURLsByUserIDAndShortCodes = `-- name: DeleteURLsByUserIDAndShortCodes :exec
+const deleteURLsByUserIDAndShortCodes = `-- name: DeleteURLsByUserIDAndShortCodes :many
 UPDATE urls
-SET deleted_at = NOW()
+SET deleted_at = $3
 WHERE user_uuid = $1 AND short_code = ANY($2::varchar[]) AND deleted_at IS NULL
+RETURNING short_code
 `
 
 type DeleteURLsByUserIDAndShortCodesParams struct {
 	UserUUID   uuid.UUID
 	ShortCodes []string
+	DeletedAt  pgtype.Timestamp
+}
+
+func (q *Queries) DeleteURLsByUserIDAndShortCodes(ctx context.Context, arg DeleteURLsByUserIDAndShortCodesParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, deleteURLsByUserIDAndShortCodes, arg.UserUUID, arg.ShortCodes, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var short_code string
+		if err := rows.Scan(&short_code); err != nil {
+			return nil, err
+		}
+		items = append(items, short_code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteURLsByUserIDs = `-- name: DeleteURLsByUserIDs :many
+UPDATE urls AS u
+SET deleted_at = $1
+FROM unnest($2::uuid[], $3::varchar[]) AS d(user_uuid, short_code)
+WHERE u.user_uuid = d.user_uuid AND u.short_code = d.short_code AND u.deleted_at IS NULL
+RETURNING u.user_uuid, u.short_code
+`
+
+type DeleteURLsByUserIDsParams struct {
+	DeletedAt  pgtype.Timestamp
+	UserUUIDs  []uuid.UUID
+	ShortCodes []string
 }
 
-func (q *Queries) DeleteURLsByUserIDAndShortCodes(ctx context.Context, arg DeleteURLsByUserIDAndShortCodesParams) error {
-	_, err := q.db.Exec(ctx, deleteURLsByUserIDAndShortCodes, arg.UserUUID, arg.ShortCodes)
+type DeleteURLsByUserIDsRow struct {
+	UserUUID  uuid.UUID
+	ShortCode string
+}
+
+func (q *Queries) DeleteURLsByUserIDs(ctx context.Context, arg DeleteURLsByUserIDsParams) ([]DeleteURLsByUserIDsRow, error) {
+	rows, err := q.db.Query(ctx, deleteURLsByUserIDs, arg.DeletedAt, arg.UserUUIDs, arg.ShortCodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeleteURLsByUserIDsRow
+	for rows.Next() {
+		var i DeleteURLsByUserIDsRow
+		if err := rows.Scan(&i.UserUUID, &i.ShortCode); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const enqueueDeleteTask = `-- name: EnqueueDeleteTask :exec
+INSERT INTO delete_tasks (uuid, user_uuid, short_codes, run_at, created_at, trace_parent)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type EnqueueDeleteTaskParams struct {
+	UUID        uuid.UUID
+	UserUUID    uuid.UUID
+	ShortCodes  []string
+	RunAt       pgtype.Timestamp
+	CreatedAt   pgtype.Timestamp
+	TraceParent string
+}
+
+func (q *Queries) EnqueueDeleteTask(ctx context.Context, arg EnqueueDeleteTaskParams) error {
+	_, err := q.db.Exec(ctx, enqueueDeleteTask,
+		arg.UUID,
+		arg.UserUUID,
+		arg.ShortCodes,
+		arg.RunAt,
+		arg.CreatedAt,
+		arg.TraceParent,
+	)
+	return err
+}
+
+const expireURLs = `-- name: ExpireURLs :execrows
+UPDATE urls
+SET expired = TRUE
+WHERE expires_at <= $1 AND expired = FALSE
+`
+
+func (q *Queries) ExpireURLs(ctx context.Context, now pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, expireURLs, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const exportURLsByUserID = `-- name: ExportURLsByUserID :many
+SELECT
+  u.uuid,
+  u.long_url,
+  u.short_code,
+  u.created_at,
+  u.deleted_at,
+  u.expires_at,
+  u.expired,
+  c.clicks
+FROM urls AS u
+CROSS JOIN LATERAL (
+  SELECT COUNT(*) AS clicks FROM clicks WHERE url_uuid = u.uuid
+) AS c
+WHERE u.user_uuid = $1
+  AND ($2::timestamp IS NULL OR (u.created_at, u.uuid) < ($2, $3::uuid))
+ORDER BY u.created_at DESC, u.uuid DESC
+LIMIT $4
+`
+
+type ExportURLsByUserIDParams struct {
+	UserUUID  uuid.UUID
+	CreatedAt pgtype.Timestamp
+	UUID      uuid.UUID
+	Lim       int64
+}
+
+type ExportURLsByUserIDRow struct {
+	UUID      uuid.UUID
+	LongURL   string
+	ShortCode string
+	CreatedAt pgtype.Timestamp
+	DeletedAt pgtype.Timestamp
+	ExpiresAt pgtype.Timestamp
+	Expired   bool
+	Clicks    int64
+}
+
+func (q *Queries) ExportURLsByUserID(ctx context.Context, arg ExportURLsByUserIDParams) ([]ExportURLsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, exportURLsByUserID,
+		arg.UserUUID,
+		arg.CreatedAt,
+		arg.UUID,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExportURLsByUserIDRow
+	for rows.Next() {
+		var i ExportURLsByUserIDRow
+		if err := rows.Scan(
+			&i.UUID,
+			&i.LongURL,
+			&i.ShortCode,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.Expired,
+			&i.Clicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const failDeleteTask = `-- name: FailDeleteTask :exec
+UPDATE delete_tasks
+SET failed_at = NOW(), last_error = $2
+WHERE uuid = $1
+`
+
+type FailDeleteTaskParams struct {
+	UUID      uuid.UUID
+	LastError string
+}
+
+func (q *Queries) FailDeleteTask(ctx context.Context, arg FailDeleteTaskParams) error {
+	_, err := q.db.Exec(ctx, failDeleteTask, arg.UUID, arg.LastError)
 	return err
 }
 
+const findURLsByUserID = `-- name: FindURLsByUserID :many
+SELECT
+  u.uuid,
+  u.long_url,
+  u.short_code,
+  u.created_at,
+  u.deleted_at,
+  u.expires_at,
+  u.expired,
+  c.clicks
+FROM urls AS u
+CROSS JOIN LATERAL (
+  SELECT COUNT(*) AS clicks FROM clicks WHERE url_uuid = u.uuid
+) AS c
+WHERE u.user_uuid = $1
+  AND CASE
+    WHEN $2::boolean THEN u.deleted_at IS NOT NULL
+    WHEN $3::boolean THEN u.deleted_at IS NULL AND u.expired
+    ELSE u.deleted_at IS NULL AND NOT u.expired
+  END
+  AND ($4::text = '' OR u.long_url ILIKE $4 OR u.short_code ILIKE $4)
+  AND ($5::timestamp IS NULL OR u.created_at >= $5)
+  AND ($6::timestamp IS NULL OR u.created_at < $6)
+ORDER BY
+  CASE WHEN $7::text = 'clicks' THEN c.clicks END ASC,
+  CASE WHEN $7::text = '-clicks' THEN c.clicks END DESC,
+  CASE WHEN $7::text = 'created_at' THEN u.created_at END ASC,
+  u.created_at DESC,
+  u.uuid DESC
+LIMIT $8 OFFSET $9
+`
+
+type FindURLsByUserIDParams struct {
+	UserUUID      uuid.UUID
+	Deleted       bool
+	Expired       bool
+	Pattern       string
+	CreatedAfter  pgtype.Timestamp
+	CreatedBefore pgtype.Timestamp
+	Sort          string
+	Lim           int64
+	Off           int64
+}
+
+type FindURLsByUserIDRow struct {
+	UUID      uuid.UUID
+	LongURL   string
+	ShortCode string
+	CreatedAt pgtype.Timestamp
+	DeletedAt pgtype.Timestamp
+	ExpiresAt pgtype.Timestamp
+	Expired   bool
+	Clicks    int64
+}
+
+func (q *Queries) FindURLsByUserID(ctx context.Context, arg FindURLsByUserIDParams) ([]FindURLsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, findURLsByUserID,
+		arg.UserUUID,
+		arg.Deleted,
+		arg.Expired,
+		arg.Pattern,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Sort,
+		arg.Lim,
+		arg.Off,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindURLsByUserIDRow
+	for rows.Next() {
+		var i FindURLsByUserIDRow
+		if err := rows.Scan(
+			&i.UUID,
+			&i.LongURL,
+			&i.ShortCode,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.Expired,
+			&i.Clicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAPITokenByHash = `-- name: GetAPITokenByHash :one
+SELECT uuid, user_uuid, name, prefix, scopes, created_at
+FROM api_tokens
+WHERE token_hash = $1 AND revoked_at IS NULL
+`
+
+type GetAPITokenByHashRow struct {
+	UUID      uuid.UUID
+	UserUUID  uuid.UUID
+	Name      string
+	Prefix    string
+	Scopes    []string
+	CreatedAt pgtype.Timestamp
+}
+
+func (q *Queries) GetAPITokenByHash(ctx context.Context, tokenHash string) (GetAPITokenByHashRow, error) {
+	row := q.db.QueryRow(ctx, getAPITokenByHash, tokenHash)
+	var i GetAPITokenByHashRow
+	err := row.Scan(
+		&i.UUID,
+		&i.UserUUID,
+		&i.Name,
+		&i.Prefix,
+		&i.Scopes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAPITokensByUserID = `-- name: GetAPITokensByUserID :many
+SELECT uuid, user_uuid, name, prefix, scopes, created_at
+FROM api_tokens
+WHERE user_uuid = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC
+`
+
+type GetAPITokensByUserIDRow struct {
+	UUID      uuid.UUID
+	UserUUID  uuid.UUID
+	Name      string
+	Prefix    string
+	Scopes    []string
+	CreatedAt pgtype.Timestamp
+}
+
+func (q *Queries) GetAPITokensByUserID(ctx context.Context, userUuid uuid.UUID) ([]GetAPITokensByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getAPITokensByUserID, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAPITokensByUserIDRow
+	for rows.Next() {
+		var i GetAPITokensByUserIDRow
+		if err := rows.Scan(
+			&i.UUID,
+			&i.UserUUID,
+			&i.Name,
+			&i.Prefix,
+			&i.Scopes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getClicksSummary = `-- name: GetClicksSummary :one
+SELECT
+  COUNT(*) AS total,
+  COUNT(DISTINCT ip_hash) AS unique_visitors
+FROM clicks
+WHERE url_uuid = $1
+`
+
+type GetClicksSummaryRow struct {
+	Total          int64
+	UniqueVisitors int64
+}
+
+func (q *Queries) GetClicksSummary(ctx context.Context, urlUuid uuid.UUID) (GetClicksSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getClicksSummary, urlUuid)
+	var i GetClicksSummaryRow
+	err := row.Scan(&i.Total, &i.UniqueVisitors)
+	return i, err
+}
+
+const getDailyClicks = `-- name: GetDailyClicks :many
+SELECT
+  date_trunc('day', created_at)::timestamp AS day,
+  COUNT(*) AS total,
+  COUNT(DISTINCT ip_hash) AS unique_visitors
+FROM clicks
+WHERE url_uuid = $1 AND created_at >= $2
+GROUP BY day
+ORDER BY day
+`
+
+type GetDailyClicksParams struct {
+	UrlUuid   uuid.UUID
+	CreatedAt pgtype.Timestamp
+}
+
+type GetDailyClicksRow struct {
+	Day            pgtype.Timestamp
+	Total          int64
+	UniqueVisitors int64
+}
+
+func (q *Queries) GetDailyClicks(ctx context.Context, arg GetDailyClicksParams) ([]GetDailyClicksRow, error) {
+	rows, err := q.db.Query(ctx, getDailyClicks, arg.UrlUuid, arg.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDailyClicksRow
+	for rows.Next() {
+		var i GetDailyClicksRow
+		if err := rows.Scan(&i.Day, &i.Total, &i.UniqueVisitors); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeleteJob = `-- name: GetDeleteJob :one
+SELECT uuid, user_uuid, status, short_codes, deleted, skipped, error, created_at, finished_at
+FROM delete_jobs
+WHERE uuid = $1
+`
+
+func (q *Queries) GetDeleteJob(ctx context.Context, id uuid.UUID) (DeleteJob, error) {
+	row := q.db.QueryRow(ctx, getDeleteJob, id)
+	var i DeleteJob
+	err := row.Scan(
+		&i.Uuid,
+		&i.UserUuid,
+		&i.Status,
+		&i.ShortCodes,
+		&i.Deleted,
+		&i.Skipped,
+		&i.Error,
+		&i.CreatedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
 const getURLByShortCode = `-- name: GetURLByShortCode :one
-SELECT uuid, long_url, short_code, deleted_at FROM urls WHERE short_code = $1
+SELECT uuid, long_url, short_code, user_uuid, deleted_at, expires_at, expired FROM urls WHERE short_code = $1
 `
 
 type GetURLByShortCodeRow struct {
 	UUID      uuid.UUID
 	LongURL   string
 	ShortCode string
+	UserUUID  uuid.UUID
 	DeletedAt pgtype.Timestamp
+	ExpiresAt pgtype.Timestamp
+	Expired   bool
 }
 
 func (q *Queries) GetURLByShortCode(ctx context.Context, shortCode string) (GetURLByShortCodeRow, error) {
@@ -78,26 +722,66 @@ func (q *Queries) GetURLByShortCode(ctx context.Context, shortCode string) (GetU
 		&i.UUID,
 		&i.LongURL,
 		&i.ShortCode,
+		&i.UserUUID,
 		&i.DeletedAt,
+		&i.ExpiresAt,
+		&i.Expired,
 	)
 	return i, err
 }
 
+const getURLsByLongURLs = `-- name: GetURLsByLongURLs :many
+SELECT uuid, long_url, short_code, user_uuid FROM urls WHERE long_url = ANY($1::text[])
+`
+
+type GetURLsByLongURLsRow struct {
+	UUID      uuid.UUID
+	LongURL   string
+	ShortCode string
+	UserUUID  uuid.UUID
+}
+
+func (q *Queries) GetURLsByLongURLs(ctx context.Context, longUrls []string) ([]GetURLsByLongURLsRow, error) {
+	rows, err := q.db.Query(ctx, getURLsByLongURLs, longUrls)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByLongURLsRow
+	for rows.Next() {
+		var i GetURLsByLongURLsRow
+		if err := rows.Scan(
+			&i.UUID,
+			&i.LongURL,
+			&i.ShortCode,
+			&i.UserUUID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getURLsByUserID = `-- name: GetURLsByUserID :many
 WITH counter AS (
   SELECT COUNT(*) AS total
   FROM urls
-  WHERE user_uuid = $1 AND deleted_at IS NULL
+  WHERE user_uuid = $1 AND deleted_at IS NULL AND expired = FALSE
 )
 SELECT
   u.uuid,
   u.long_url,
   u.short_code,
+  u.created_at,
   counter.total
 FROM urls AS u
 RIGHT JOIN counter ON TRUE
-WHERE u.user_uuid = $1 AND deleted_at IS NULL
-ORDER BY u.created_at DESC LIMIT $2 OFFSET $3
+WHERE u.user_uuid = $1 AND deleted_at IS NULL AND expired = FALSE
+ORDER BY u.created_at DESC, u.uuid DESC LIMIT $2 OFFSET $3
 `
 
 type GetURLsByUserIDParams struct {
@@ -110,6 +794,7 @@ type GetURLsByUserIDRow struct {
 	UUID      uuid.UUID
 	LongURL   string
 	ShortCode string
+	CreatedAt pgtype.Timestamp
 	Total     int64
 }
 
@@ -126,6 +811,7 @@ func (q *Queries) GetURLsByUserID(ctx context.Context, arg GetURLsByUserIDParams
 			&i.UUID,
 			&i.LongURL,
 			&i.ShortCode,
+			&i.CreatedAt,
 			&i.Total,
 		); err != nil {
 			return nil, err
@@ -138,6 +824,59 @@ func (q *Queries) GetURLsByUserID(ctx context.Context, arg GetURLsByUserIDParams
 	return items, nil
 }
 
+const getURLsByUserIDAfter = `-- name: GetURLsByUserIDAfter :many
+SELECT uuid, long_url, short_code, created_at
+FROM urls
+WHERE user_uuid = $1 AND deleted_at IS NULL AND expired = FALSE
+  AND (created_at, uuid) < ($2::timestamp, $3::uuid)
+ORDER BY created_at DESC, uuid DESC
+LIMIT $4
+`
+
+type GetURLsByUserIDAfterParams struct {
+	UserUUID  uuid.UUID
+	CreatedAt pgtype.Timestamp
+	UUID      uuid.UUID
+	Lim       int64
+}
+
+type GetURLsByUserIDAfterRow struct {
+	UUID      uuid.UUID
+	LongURL   string
+	ShortCode string
+	CreatedAt pgtype.Timestamp
+}
+
+func (q *Queries) GetURLsByUserIDAfter(ctx context.Context, arg GetURLsByUserIDAfterParams) ([]GetURLsByUserIDAfterRow, error) {
+	rows, err := q.db.Query(ctx, getURLsByUserIDAfter,
+		arg.UserUUID,
+		arg.CreatedAt,
+		arg.UUID,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByUserIDAfterRow
+	for rows.Next() {
+		var i GetURLsByUserIDAfterRow
+		if err := rows.Scan(
+			&i.UUID,
+			&i.LongURL,
+			&i.ShortCode,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const healthCheck = `-- name: HealthCheck :one
 SELECT 1
 `
@@ -148,3 +887,178 @@ func (q *Queries) HealthCheck(ctx context.Context) (int64, error) {
 	err := row.Scan(&result)
 	return result, err
 }
+
+const nextShortCodeSequence = `-- name: NextShortCodeSequence :one
+SELECT nextval('short_code_seq')::bigint
+`
+
+func (q *Queries) NextShortCodeSequence(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, nextShortCodeSequence)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const purgeDeleteJobs = `-- name: PurgeDeleteJobs :execrows
+DELETE FROM delete_jobs
+WHERE finished_at < $1
+`
+
+func (q *Queries) PurgeDeleteJobs(ctx context.Context, finishedAt pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeleteJobs, finishedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeURLs = `-- name: PurgeURLs :execrows
+DELETE FROM urls
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeURLs(ctx context.Context, deletedAt pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeURLs, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreURLsByUserIDAndShortCodes = `-- name: RestoreURLsByUserIDAndShortCodes :many
+UPDATE urls
+SET deleted_at = NULL
+WHERE user_uuid = $1 AND short_code = ANY($2::varchar[]) AND deleted_at >= $3
+RETURNING short_code
+`
+
+type RestoreURLsByUserIDAndShortCodesParams struct {
+	UserUUID     uuid.UUID
+	ShortCodes   []string
+	DeletedSince pgtype.Timestamp
+}
+
+func (q *Queries) RestoreURLsByUserIDAndShortCodes(ctx context.Context, arg RestoreURLsByUserIDAndShortCodesParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, restoreURLsByUserIDAndShortCodes, arg.UserUUID, arg.ShortCodes, arg.DeletedSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var short_code string
+		if err := rows.Scan(&short_code); err != nil {
+			return nil, err
+		}
+		items = append(items, short_code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const retryDeleteTask = `-- name: RetryDeleteTask :exec
+UPDATE delete_tasks
+SET run_at = $2, last_error = $3
+WHERE uuid = $1
+`
+
+type RetryDeleteTaskParams struct {
+	UUID      uuid.UUID
+	RunAt     pgtype.Timestamp
+	LastError string
+}
+
+func (q *Queries) RetryDeleteTask(ctx context.Context, arg RetryDeleteTaskParams) error {
+	_, err := q.db.Exec(ctx, retryDeleteTask, arg.UUID, arg.RunAt, arg.LastError)
+	return err
+}
+
+const revokeAPIToken = `-- name: RevokeAPIToken :execrows
+UPDATE api_tokens
+SET revoked_at = NOW()
+WHERE uuid = $1 AND user_uuid = $2 AND revoked_at IS NULL
+`
+
+type RevokeAPITokenParams struct {
+	UUID     uuid.UUID
+	UserUUID uuid.UUID
+}
+
+func (q *Queries) RevokeAPIToken(ctx context.Context, arg RevokeAPITokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeAPIToken, arg.UUID, arg.UserUUID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const saveDeleteJob = `-- name: SaveDeleteJob :exec
+INSERT INTO delete_jobs (uuid, user_uuid, status, short_codes, deleted, skipped, error, created_at, finished_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (uuid) DO UPDATE
+SET status = EXCLUDED.status, deleted = EXCLUDED.deleted, skipped = EXCLUDED.skipped,
+  error = EXCLUDED.error, finished_at = EXCLUDED.finished_at
+`
+
+type SaveDeleteJobParams struct {
+	UUID       uuid.UUID
+	UserUUID   uuid.UUID
+	Status     string
+	ShortCodes []string
+	Deleted    []string
+	Skipped    []byte
+	Error      string
+	CreatedAt  pgtype.Timestamp
+	FinishedAt pgtype.Timestamp
+}
+
+func (q *Queries) SaveDeleteJob(ctx context.Context, arg SaveDeleteJobParams) error {
+	_, err := q.db.Exec(ctx, saveDeleteJob,
+		arg.UUID,
+		arg.UserUUID,
+		arg.Status,
+		arg.ShortCodes,
+		arg.Deleted,
+		arg.Skipped,
+		arg.Error,
+		arg.CreatedAt,
+		arg.FinishedAt,
+	)
+	return err
+}
+
+const updateURL = `-- name: UpdateURL :one
+UPDATE urls
+SET long_url = $3, updated_at = NOW()
+WHERE short_code = $1 AND user_uuid = $2 AND deleted_at IS NULL
+RETURNING uuid, long_url, short_code, user_uuid, updated_at
+`
+
+type UpdateURLParams struct {
+	ShortCode string
+	UserUUID  uuid.UUID
+	LongURL   string
+}
+
+type UpdateURLRow struct {
+	UUID      uuid.UUID
+	LongURL   string
+	ShortCode string
+	UserUUID  uuid.UUID
+	UpdatedAt pgtype.Timestamp
+}
+
+func (q *Queries) UpdateURL(ctx context.Context, arg UpdateURLParams) (UpdateURLRow, error) {
+	row := q.db.QueryRow(ctx, updateURL, arg.ShortCode, arg.UserUUID, arg.LongURL)
+	var i UpdateURLRow
+	err := row.Scan(
+		&i.UUID,
+		&i.LongURL,
+		&i.ShortCode,
+		&i.UserUUID,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -1,17 +1,42 @@
 package repository
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
 
 	"shortly/internal/app/errors"
+	"shortly/internal/app/metrics"
 )
 
+// FileBackend is the name of the file storage backend in metrics
+const FileBackend = "file"
+
+// TmpSuffix is appended to the file storage path while a snapshot is being written
+const TmpSuffix = ".tmp"
+
 // File is an interface for file repository
 type File interface {
 	Load() (*Memento, error)
 	Save(m *Memento) error
+	// Counter counts the URL records of the last snapshot
+	Counter
+}
+
+// snapshotRecord is a line of the snapshot file, either a URL record, an API token wrapped into api_token,
+// a delete task wrapped into delete_task, a delete job wrapped into delete_job or the short code sequence
+type snapshotRecord struct {
+	URL
+	APIToken   *APIToken   `json:"api_token,omitempty"`
+	DeleteTask *DeleteTask `json:"delete_task,omitempty"`
+	DeleteJob  *DeleteJob  `json:"delete_job,omitempty"`
+	Sequence   *int64      `json:"sequence,omitempty"`
 }
 
 type fileRepo struct {
@@ -35,33 +60,189 @@ func (f *fileRepo) Load() (*Memento, error) {
 	memento := &Memento{State: []URL{}}
 
 	for {
-		var url URL
-		if err = decoder.Decode(&url); err != nil {
+		var record snapshotRecord
+		if err = decoder.Decode(&record); err != nil {
 			if errors.Is(err, io.EOF) {
 				break
 			}
 			return nil, errors.ErrorFailedToReadFromFile
 		}
-		memento.State = append(memento.State, url)
+
+		if record.APIToken != nil {
+			memento.Tokens = append(memento.Tokens, *record.APIToken)
+			continue
+		}
+
+		if record.DeleteTask != nil {
+			memento.Tasks = append(memento.Tasks, *record.DeleteTask)
+			continue
+		}
+
+		if record.DeleteJob != nil {
+			memento.Jobs = append(memento.Jobs, *record.DeleteJob)
+			continue
+		}
+
+		if record.Sequence != nil {
+			memento.Sequence = *record.Sequence
+			continue
+		}
+
+		memento.State = append(memento.State, record.URL)
 	}
 
 	return memento, nil
 }
 
-// Save writes data to the file
+// Save writes data to a temporary file and atomically replaces the file with it
 func (f *fileRepo) Save(memento *Memento) error {
-	file, err := os.OpenFile(f.filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	tmpPath := f.filePath + TmpSuffix
+
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.ErrFailedToOpenFile
+	}
+	defer os.Remove(tmpPath)
+
+	if err = writeSnapshot(file, memento); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	if err = os.Rename(tmpPath, f.filePath); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	// NOTE: the rename itself is durable only once the parent directory is synced
+	if dir, err := os.Open(filepath.Dir(f.filePath)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	return nil
+}
+
+// CountURLs counts active URL records of the last snapshot, a missing snapshot has none
+func (f *fileRepo) CountURLs(_ context.Context) (int64, error) {
+	defer metrics.ObserveRepository(FileBackend, "count_urls", time.Now())
+
+	var count int64
+	err := f.scan(func(url URL) {
+		if url.DeletedAt.IsZero() && !url.Expired {
+			count++
+		}
+	})
+
+	return count, err
+}
+
+// CountUsers counts distinct users owning active URL records of the last snapshot, a missing snapshot has none
+func (f *fileRepo) CountUsers(_ context.Context) (int64, error) {
+	defer metrics.ObserveRepository(FileBackend, "count_users", time.Now())
+
+	users := make(map[uuid.UUID]struct{})
+	err := f.scan(func(url URL) {
+		if url.DeletedAt.IsZero() && !url.Expired && url.UserUUID != uuid.Nil {
+			users[url.UserUUID] = struct{}{}
+		}
+	})
+
+	return int64(len(users)), err
+}
+
+// scan streams the URL records of the snapshot without loading it, unlike Load a missing file is not created
+func (f *fileRepo) scan(fn func(url URL)) error {
+	file, err := os.Open(f.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
 	if err != nil {
 		return errors.ErrFailedToOpenFile
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for {
+		var record snapshotRecord
+		if err = decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errors.ErrorFailedToReadFromFile
+		}
+
+		if record.APIToken == nil && record.DeleteTask == nil && record.DeleteJob == nil && record.Sequence == nil {
+			fn(record.URL)
+		}
+	}
+}
+
+// writeSnapshot encodes a line per URL record, wrapped API token, delete task and delete job and the short code sequence,
+// then flushes and syncs the file
+func writeSnapshot(file *os.File, memento *Memento) error {
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+
 	for _, row := range memento.State {
-		if err = encoder.Encode(row); err != nil {
+		if err := encoder.Encode(row); err != nil {
 			return errors.ErrFailedToWriteToFile
 		}
 	}
 
+	// NOTE: tokens are wrapped, so snapshots stay readable line by line as URL records
+	for _, token := range memento.Tokens {
+		record := struct {
+			APIToken APIToken `json:"api_token"`
+		}{APIToken: token}
+
+		if err := encoder.Encode(record); err != nil {
+			return errors.ErrFailedToWriteToFile
+		}
+	}
+
+	// NOTE: pending and dead-lettered deletions are queued in the memento only, the WAL is truncated on compaction
+	for _, task := range memento.Tasks {
+		record := struct {
+			DeleteTask DeleteTask `json:"delete_task"`
+		}{DeleteTask: task}
+
+		if err := encoder.Encode(record); err != nil {
+			return errors.ErrFailedToWriteToFile
+		}
+	}
+
+	// NOTE: job states outlive their tasks, so finished jobs are reported after a restart too
+	for _, job := range memento.Jobs {
+		record := struct {
+			DeleteJob DeleteJob `json:"delete_job"`
+		}{DeleteJob: job}
+
+		if err := encoder.Encode(record); err != nil {
+			return errors.ErrFailedToWriteToFile
+		}
+	}
+
+	if memento.Sequence > 0 {
+		record := struct {
+			Sequence int64 `json:"sequence"`
+		}{Sequence: memento.Sequence}
+
+		if err := encoder.Encode(record); err != nil {
+			return errors.ErrFailedToWriteToFile
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	if err := file.Sync(); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
 	return nil
 }
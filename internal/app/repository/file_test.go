@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -146,6 +148,91 @@ func Test_FileStorageRepository_Save(t *testing.T) {
 			},
 			expected: nil,
 		},
+		{
+			name:   "With API tokens",
+			before: func(_ string) {},
+			payload: &Memento{
+				State: []URL{
+					{
+						UUID:      UUID,
+						LongURL:   "http://example.com",
+						ShortCode: "abcd1234",
+					},
+				},
+				Tokens: []APIToken{
+					{
+						UUID:      UUID,
+						UserUUID:  UUID,
+						Name:      "ci",
+						Prefix:    "shk_abcdefgh",
+						Hash:      "hash",
+						Scopes:    []string{"links:read"},
+						CreatedAt: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name:   "With sequence",
+			before: func(_ string) {},
+			payload: &Memento{
+				State: []URL{
+					{
+						UUID:      UUID,
+						LongURL:   "http://example.com",
+						ShortCode: "abcd1234",
+					},
+				},
+				Sequence: 42,
+			},
+			expected: nil,
+		},
+		{
+			name:   "With delete tasks",
+			before: func(_ string) {},
+			payload: &Memento{
+				State: []URL{
+					{
+						UUID:      UUID,
+						LongURL:   "http://example.com",
+						ShortCode: "abcd1234",
+					},
+				},
+				Tasks: []DeleteTask{
+					{
+						UUID:       UUID,
+						UserUUID:   UUID,
+						ShortCodes: []string{"abcd1234"},
+						Attempts:   1,
+						LastError:  "timeout",
+						RunAt:      time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+						CreatedAt:  time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name:   "With delete jobs",
+			before: func(_ string) {},
+			payload: &Memento{
+				State: []URL{},
+				Jobs: []DeleteJob{
+					{
+						UUID:       UUID,
+						UserUUID:   UUID,
+						Status:     "done",
+						ShortCodes: []string{"abcd1234", "abcd0002"},
+						Deleted:    []string{"abcd1234"},
+						Skipped:    []SkippedShortCode{{ShortCode: "abcd0002", Reason: "not_found"}},
+						CreatedAt:  time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC),
+						FinishedAt: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			expected: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -156,6 +243,90 @@ func Test_FileStorageRepository_Save(t *testing.T) {
 			err := fileRepo.Save(tt.payload)
 
 			assert.Equal(t, tt.expected, err)
+
+			_, err = os.Stat(filePath + TmpSuffix)
+			assert.True(t, os.IsNotExist(err))
+
+			memento, err := fileRepo.Load()
+			require.NoError(t, err)
+			assert.Equal(t, tt.payload.State, memento.State)
+			assert.Equal(t, tt.payload.Tokens, memento.Tokens)
+			assert.Equal(t, tt.payload.Tasks, memento.Tasks)
+			assert.Equal(t, tt.payload.Jobs, memento.Jobs)
+			assert.Equal(t, tt.payload.Sequence, memento.Sequence)
+		})
+	}
+}
+
+func Test_FileStorageRepository_Count(t *testing.T) {
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+
+	type result struct {
+		urls  int64
+		users int64
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		before   func(filePath string)
+		expected result
+	}{
+		{
+			name: "Success",
+			before: func(filePath string) {
+				err := NewFileRepository(filePath).Save(&Memento{
+					State: []URL{
+						{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID1},
+						{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID1},
+						{UUID: uuid.New(), LongURL: "https://golang.org", ShortCode: "abcd0003", UserUUID: UserUUID2},
+						{UUID: uuid.New(), LongURL: "https://example.com", ShortCode: "abcd0004", UserUUID: UserUUID2, DeletedAt: time.Now()},
+						{UUID: uuid.New(), LongURL: "https://example.org", ShortCode: "abcd0005", UserUUID: uuid.New(), Expired: true},
+					},
+					Tokens: []APIToken{{UUID: uuid.New(), UserUUID: uuid.New(), Name: "ci"}},
+				})
+				require.NoError(t, err)
+			},
+			expected: result{urls: 3, users: 2},
+		},
+		{
+			name: "File not exists",
+			before: func(filePath string) {
+				os.Remove(filePath)
+			},
+			expected: result{},
+		},
+		{
+			name: "Invalid JSON",
+			before: func(filePath string) {
+				require.NoError(t, os.WriteFile(filePath, []byte(`{invalid json}`), 0644))
+			},
+			expected: result{err: errors.ErrorFailedToReadFromFile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			filePath := t.TempDir() + "/store-test.json"
+
+			tt.before(filePath)
+
+			fileRepo := NewFileRepository(filePath)
+
+			urls, err := fileRepo.CountURLs(ctx)
+			assert.Equal(t, tt.expected.err, err)
+			assert.Equal(t, tt.expected.urls, urls)
+
+			users, err := fileRepo.CountUsers(ctx)
+			assert.Equal(t, tt.expected.err, err)
+			assert.Equal(t, tt.expected.users, users)
+
+			// NOTE: counting never creates the snapshot
+			if tt.expected.urls == 0 && tt.expected.err == nil {
+				assert.NoFileExists(t, filePath)
+			}
 		})
 	}
 }
@@ -1,24 +1,54 @@
 package repository
 
 import (
+	"bytes"
 	"context"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+
+	"shortly/internal/app/errors"
+	"shortly/internal/app/metrics"
 )
 
+// InMemoryBackend is the metrics label of the in-memory repository
+const InMemoryBackend = "memory"
+
 // InMemory is an interface for in-memory storage
 type InMemory interface {
 	Repository
 	CreateMemento() *Memento
+	// Snapshot passes a memento to fn and holds writes until fn returns, so fn can compact the journal safely
+	Snapshot(fn func(memento *Memento) error) error
 	Restore(m *Memento)
 	Clear()
+	// SetJournal sets the journal recording every state mutation before it is applied
+	SetJournal(j Journal)
+	// Apply applies a journal entry to the state without recording it
+	Apply(entry JournalEntry)
 }
 
 // InMemoryRepo is a repository for in-memory storage
 type InMemoryRepo struct {
-	data sync.Map
+	data    sync.Map
+	tokens  sync.Map
+	mu      sync.RWMutex
+	clicks  map[uuid.UUID][]Click
+	seq     atomic.Int64
+	wmu     sync.Mutex
+	journal Journal
+	// tasks are the queued delete tasks, written under both wmu and tmu and read under either
+	tasks map[uuid.UUID]DeleteTask
+	// jobs are the delete job states, guarded like tasks
+	jobs map[uuid.UUID]DeleteJob
+	// longURLs indexes the short code of every record by its long URL, it is written together with data
+	longURLs sync.Map
+	// NOTE: tmu lets CreateMemento copy the tasks and jobs without wmu, wmu is always taken before the journal lock
+	tmu sync.Mutex
 }
 
 // NewInMemoryRepository creates a new in-memory repository instance
@@ -26,22 +56,145 @@ func NewInMemoryRepository() InMemory {
 	return &InMemoryRepo{}
 }
 
-// CreateURL creates a new URL record
+// CreateURL creates a new URL record, the existing record is returned when the long URL is already shortened
 func (m *InMemoryRepo) CreateURL(_ context.Context, url URL) (*URL, error) {
-	m.data.Store(url.ShortCode, url)
+	defer metrics.ObserveRepository(InMemoryBackend, "create_url", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	if existing, ok := m.findByLongURLs([]string{url.LongURL})[url.LongURL]; ok {
+		return &existing, nil
+	}
+
+	if _, ok := m.data.Load(url.ShortCode); ok {
+		return nil, errors.ErrShortCodeAlreadyExists
+	}
+
+	if url.CreatedAt.IsZero() {
+		url.CreatedAt = time.Now()
+	}
+
+	if err := m.record(JournalEntry{Op: OpCreate, URLs: []URL{url}}); err != nil {
+		return nil, err
+	}
+
+	m.store(url)
 	return &url, nil
 }
 
-// CreateURLs creates new URL records
-func (m *InMemoryRepo) CreateURLs(_ context.Context, urls []URL) error {
+// CreateURLs creates new URL records all at once and returns the stored record of each,
+// records of already shortened long URLs are returned instead of created
+func (m *InMemoryRepo) CreateURLs(_ context.Context, urls []URL) ([]URL, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "create_urls", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	now := time.Now()
+	longURLs := make([]string, 0, len(urls))
 	for _, url := range urls {
-		m.data.Store(url.ShortCode, url)
+		longURLs = append(longURLs, url.LongURL)
+	}
+
+	existing := m.findByLongURLs(longURLs)
+	shortCodes := make(map[string]struct{}, len(urls))
+	records := make([]URL, 0, len(urls))
+	created := make([]URL, 0, len(urls))
+
+	for _, url := range urls {
+		if record, ok := existing[url.LongURL]; ok {
+			records = append(records, record)
+			continue
+		}
+
+		if _, taken := shortCodes[url.ShortCode]; taken {
+			return nil, errors.ErrShortCodeAlreadyExists
+		}
+		if _, ok := m.data.Load(url.ShortCode); ok {
+			return nil, errors.ErrShortCodeAlreadyExists
+		}
+
+		if url.CreatedAt.IsZero() {
+			url.CreatedAt = now
+		}
+
+		shortCodes[url.ShortCode] = struct{}{}
+		existing[url.LongURL] = url
+		records = append(records, url)
+		created = append(created, url)
+	}
+
+	if len(created) == 0 {
+		return records, nil
+	}
+
+	if err := m.record(JournalEntry{Op: OpCreate, URLs: created}); err != nil {
+		return nil, err
+	}
+
+	for _, url := range created {
+		m.store(url)
+	}
+	return records, nil
+}
+
+// findByLongURLs returns stored records with any of the long URLs keyed by long URL
+func (m *InMemoryRepo) findByLongURLs(longURLs []string) map[string]URL {
+	found := make(map[string]URL, len(longURLs))
+
+	for _, longURL := range longURLs {
+		if url, ok := m.findByLongURL(longURL); ok {
+			found[longURL] = url
+		}
+	}
+
+	return found
+}
+
+// findByLongURL returns the stored record of the long URL through the long URL index
+func (m *InMemoryRepo) findByLongURL(longURL string) (URL, bool) {
+	shortCode, ok := m.longURLs.Load(longURL)
+	if !ok {
+		return URL{}, false
+	}
+
+	value, ok := m.data.Load(shortCode)
+	if !ok {
+		return URL{}, false
+	}
+
+	url, ok := value.(URL)
+	return url, ok
+}
+
+// store stores the URL record and indexes its long URL, the index entry of a replaced long URL is removed
+func (m *InMemoryRepo) store(url URL) {
+	if previous, loaded := m.data.Swap(url.ShortCode, url); loaded {
+		if old, ok := previous.(URL); ok && old.LongURL != url.LongURL {
+			m.longURLs.CompareAndDelete(old.LongURL, old.ShortCode)
+		}
+	}
+
+	m.longURLs.Store(url.LongURL, url.ShortCode)
+}
+
+// remove removes the URL record and its long URL index entry
+func (m *InMemoryRepo) remove(shortCode string) {
+	previous, loaded := m.data.LoadAndDelete(shortCode)
+	if !loaded {
+		return
+	}
+
+	if old, ok := previous.(URL); ok {
+		m.longURLs.CompareAndDelete(old.LongURL, shortCode)
 	}
-	return nil
 }
 
 // GetURLByShortCode returns a URL record by short code
 func (m *InMemoryRepo) GetURLByShortCode(_ context.Context, shortCode string) (*URL, bool) {
+	defer metrics.ObserveRepository(InMemoryBackend, "get_url_by_short_code", time.Now())
+
 	value, ok := m.data.Load(shortCode)
 	if !ok {
 		return nil, false
@@ -55,58 +208,704 @@ func (m *InMemoryRepo) GetURLByShortCode(_ context.Context, shortCode string) (*
 	return &url, true
 }
 
-// GetURLsByUserID returns URL records by user ID
+// GetURLsByLongURLs returns URL records with any of the long URLs
+func (m *InMemoryRepo) GetURLsByLongURLs(_ context.Context, longURLs []string) ([]URL, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "get_urls_by_long_urls", time.Now())
+
+	found := m.findByLongURLs(longURLs)
+
+	results := make([]URL, 0, len(found))
+	for _, url := range found {
+		results = append(results, url)
+	}
+
+	return results, nil
+}
+
+// GetURLsByUserID returns URL records by user ID, newest first
 func (m *InMemoryRepo) GetURLsByUserID(_ context.Context, id uuid.UUID, limit, offset int64) ([]URL, int, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "get_urls_by_user_id", time.Now())
+
 	var results []URL
 
 	m.data.Range(func(_, value interface{}) bool {
 		url, ok := value.(URL)
-		if ok && url.UserUUID == id && url.DeletedAt.IsZero() {
+		if ok && url.UserUUID == id && url.DeletedAt.IsZero() && !url.Expired {
 			results = append(results, url)
 		}
 		return true
 	})
 
-	total := len(results)
+	// NOTE: sync.Map ranges in random order, pages are stable only once sorted
+	sort.Slice(results, func(i, j int) bool {
+		return newer(results[i], results[j])
+	})
+
+	return paginate(results, limit, offset), len(results), nil
+}
+
+// GetURLsByUserIDAfter returns URL records by user ID following the cursor, newest first
+func (m *InMemoryRepo) GetURLsByUserIDAfter(_ context.Context, id uuid.UUID, after URLCursor, limit int64) ([]URL, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "get_urls_by_user_id_after", time.Now())
+
+	var results []URL
+
+	m.data.Range(func(_, value interface{}) bool {
+		url, ok := value.(URL)
+		if ok && url.UserUUID == id && url.DeletedAt.IsZero() && !url.Expired && after.Before(url) {
+			results = append(results, url)
+		}
+		return true
+	})
+
+	sort.Slice(results, func(i, j int) bool {
+		return newer(results[i], results[j])
+	})
+
+	return paginate(results, limit, 0), nil
+}
+
+// FindURLsByUserID returns URL records of the user matching the filter
+func (m *InMemoryRepo) FindURLsByUserID(_ context.Context, filter URLFilter) ([]URLListItem, int, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "find_urls_by_user_id", time.Now())
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []URLListItem
+	query := strings.ToLower(filter.Query)
+
+	m.data.Range(func(_, value interface{}) bool {
+		url, ok := value.(URL)
+		if ok && matches(url, filter, query) {
+			results = append(results, URLListItem{URL: url, Clicks: int64(len(m.clicks[url.UUID]))})
+		}
+		return true
+	})
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+
+		switch {
+		case filter.Sort == SortClicks && a.Clicks != b.Clicks:
+			return a.Clicks < b.Clicks
+		case filter.Sort == SortClicksDesc && a.Clicks != b.Clicks:
+			return a.Clicks > b.Clicks
+		case filter.Sort == SortCreatedAt && !a.CreatedAt.Equal(b.CreatedAt):
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+
+		return newer(a.URL, b.URL)
+	})
+
+	return paginate(results, filter.Limit, filter.Offset), len(results), nil
+}
+
+// ExportURLsByUserID returns URL records of the user after the cursor including deleted and expired ones, newest first,
+// a zero cursor starts from the newest record
+func (m *InMemoryRepo) ExportURLsByUserID(_ context.Context, id uuid.UUID, after URLCursor, limit int64) ([]URLListItem, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "export_urls_by_user_id", time.Now())
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []URLListItem
+
+	m.data.Range(func(_, value interface{}) bool {
+		url, ok := value.(URL)
+		if ok && url.UserUUID == id && (after.CreatedAt.IsZero() || after.Before(url)) {
+			results = append(results, URLListItem{URL: url, Clicks: int64(len(m.clicks[url.UUID]))})
+		}
+		return true
+	})
+
+	sort.Slice(results, func(i, j int) bool {
+		return newer(results[i].URL, results[j].URL)
+	})
+
+	return paginate(results, limit, 0), nil
+}
+
+// UpdateURL updates the long URL of a URL record owned by the user
+func (m *InMemoryRepo) UpdateURL(_ context.Context, url URL) (*URL, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "update_url", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
 
-	start := int(offset)
-	if start > total {
-		start = total
+	value, ok := m.data.Load(url.ShortCode)
+	if !ok {
+		return nil, errors.ErrShortLinkNotFound
+	}
+
+	record, ok := value.(URL)
+	if !ok || record.UserUUID != url.UserUUID || !record.DeletedAt.IsZero() {
+		return nil, errors.ErrShortLinkNotFound
+	}
+
+	if other, ok := m.findByLongURL(url.LongURL); ok && other.ShortCode != record.ShortCode {
+		return nil, errors.ErrURLAlreadyExists
 	}
 
-	end := int(offset + limit)
-	if end > total {
-		end = total
+	record.LongURL = url.LongURL
+	record.UpdatedAt = time.Now()
+
+	if err := m.record(JournalEntry{Op: OpUpdate, URLs: []URL{record}}); err != nil {
+		return nil, err
 	}
 
-	return results[start:end], total, nil
+	m.store(record)
+
+	return &record, nil
 }
 
-// DeleteURLsByUserID deletes URL records by user ID
-func (m *InMemoryRepo) DeleteURLsByUserID(_ context.Context, id uuid.UUID, shortCodes []string) error {
+// DeleteURLsByUserID deletes URL records by user ID and returns the deleted short codes
+func (m *InMemoryRepo) DeleteURLsByUserID(_ context.Context, id uuid.UUID, shortCodes []string) ([]string, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "delete_urls_by_user_id", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	var deleted []string
 	for _, shortCode := range shortCodes {
-		value, ok := m.data.Load(shortCode)
-		if !ok {
+		if url, ok := m.deletable(id, shortCode); ok {
+			deleted = append(deleted, url.ShortCode)
+		}
+	}
+
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+
+	entry := JournalEntry{Op: OpDelete, UserUUID: id, ShortCodes: deleted, DeletedAt: time.Now()}
+	if err := m.record(entry); err != nil {
+		return nil, err
+	}
+
+	m.apply(entry)
+	return deleted, nil
+}
+
+// DeleteURLsByUserIDs marks URL records of many users as deleted, every user is journaled separately
+func (m *InMemoryRepo) DeleteURLsByUserIDs(_ context.Context, shortCodes map[uuid.UUID][]string) (map[uuid.UUID][]string, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "delete_urls_by_user_ids", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	now := time.Now()
+	deleted := make(map[uuid.UUID][]string, len(shortCodes))
+
+	for id, codes := range shortCodes {
+		var userDeleted []string
+		for _, shortCode := range codes {
+			if url, ok := m.deletable(id, shortCode); ok {
+				userDeleted = append(userDeleted, url.ShortCode)
+			}
+		}
+
+		if len(userDeleted) == 0 {
 			continue
 		}
 
+		entry := JournalEntry{Op: OpDelete, UserUUID: id, ShortCodes: userDeleted, DeletedAt: now}
+		if err := m.record(entry); err != nil {
+			return nil, err
+		}
+
+		m.apply(entry)
+		deleted[id] = userDeleted
+	}
+
+	return deleted, nil
+}
+
+// RestoreURLsByUserID restores URL records of the user deleted since the given time
+func (m *InMemoryRepo) RestoreURLsByUserID(_ context.Context, id uuid.UUID, shortCodes []string, deletedSince time.Time) ([]string, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "restore_urls_by_user_id", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	var restored []string
+	for _, shortCode := range shortCodes {
+		if url, ok := m.restorable(id, shortCode); ok && !url.DeletedAt.Before(deletedSince) {
+			restored = append(restored, url.ShortCode)
+		}
+	}
+
+	if len(restored) == 0 {
+		return nil, nil
+	}
+
+	entry := JournalEntry{Op: OpRestore, UserUUID: id, ShortCodes: restored}
+	if err := m.record(entry); err != nil {
+		return nil, err
+	}
+
+	m.apply(entry)
+	return restored, nil
+}
+
+// PurgeURLs permanently removes URL records deleted before the given time together with their clicks
+func (m *InMemoryRepo) PurgeURLs(_ context.Context, deletedBefore time.Time) (int64, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "purge_urls", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	var purged []string
+	m.data.Range(func(_, value interface{}) bool {
+		url, ok := value.(URL)
+		if ok && !url.DeletedAt.IsZero() && url.DeletedAt.Before(deletedBefore) {
+			purged = append(purged, url.ShortCode)
+		}
+		return true
+	})
+
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	entry := JournalEntry{Op: OpPurge, ShortCodes: purged}
+	if err := m.record(entry); err != nil {
+		return 0, err
+	}
+
+	m.apply(entry)
+	return int64(len(purged)), nil
+}
+
+// ExpireURLs marks URL records with passed expiration time as expired
+func (m *InMemoryRepo) ExpireURLs(_ context.Context) (int64, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "expire_urls", time.Now())
+
+	// NOTE: expiration is derived from the stored expiry time and is not journaled
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	var count int64
+	now := time.Now()
+
+	m.data.Range(func(_, value interface{}) bool {
 		url, ok := value.(URL)
-		if !ok {
+		if ok && !url.Expired && url.IsExpired(now) {
+			url.Expired = true
+			m.store(url)
+			count++
+		}
+		return true
+	})
+
+	return count, nil
+}
+
+// CreateClicks stores click events
+func (m *InMemoryRepo) CreateClicks(_ context.Context, clicks []Click) error {
+	defer metrics.ObserveRepository(InMemoryBackend, "create_clicks", time.Now())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.clicks == nil {
+		m.clicks = make(map[uuid.UUID][]Click)
+	}
+
+	for _, click := range clicks {
+		m.clicks[click.URLUUID] = append(m.clicks[click.URLUUID], click)
+	}
+
+	return nil
+}
+
+// GetClickStats returns aggregated clicks statistics of a URL record, daily series starts from since
+func (m *InMemoryRepo) GetClickStats(_ context.Context, urlID uuid.UUID, since time.Time) (*ClickStats, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "get_click_stats", time.Now())
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := &ClickStats{Daily: []DailyClicks{}}
+	visitors := make(map[string]struct{})
+	days := make(map[time.Time]*DailyClicks)
+	dailyVisitors := make(map[time.Time]map[string]struct{})
+
+	for _, click := range m.clicks[urlID] {
+		stats.Total++
+		visitors[click.IPHash] = struct{}{}
+
+		if click.CreatedAt.Before(since) {
 			continue
 		}
 
-		if url.UserUUID == id && url.DeletedAt.IsZero() {
-			url.DeletedAt = time.Now()
-			m.data.Store(shortCode, url)
+		day := click.CreatedAt.UTC().Truncate(24 * time.Hour)
+		if _, ok := days[day]; !ok {
+			days[day] = &DailyClicks{Date: day}
+			dailyVisitors[day] = make(map[string]struct{})
 		}
+
+		days[day].Total++
+		dailyVisitors[day][click.IPHash] = struct{}{}
 	}
 
+	stats.Unique = int64(len(visitors))
+
+	for day, daily := range days {
+		daily.Unique = int64(len(dailyVisitors[day]))
+		stats.Daily = append(stats.Daily, *daily)
+	}
+
+	sort.Slice(stats.Daily, func(i, j int) bool {
+		return stats.Daily[i].Date.Before(stats.Daily[j].Date)
+	})
+
+	return stats, nil
+}
+
+// NextSequence returns the next value of the short code sequence
+func (m *InMemoryRepo) NextSequence(_ context.Context) (int64, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "next_sequence", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	// NOTE: every value is journaled before it is handed out, so a restart never issues a short code twice
+	entry := JournalEntry{Op: OpSequence, Sequence: m.seq.Load() + 1}
+	if err := m.record(entry); err != nil {
+		return 0, err
+	}
+
+	m.apply(entry)
+	return entry.Sequence, nil
+}
+
+// CreateAPIToken stores a new API token
+func (m *InMemoryRepo) CreateAPIToken(_ context.Context, token APIToken) (*APIToken, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "create_api_token", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	token.CreatedAt = time.Now()
+
+	if err := m.record(JournalEntry{Op: OpCreateToken, Tokens: []APIToken{token}}); err != nil {
+		return nil, err
+	}
+
+	m.tokens.Store(token.Hash, token)
+	return &token, nil
+}
+
+// GetAPITokenByHash returns an active API token by the hash of its secret
+func (m *InMemoryRepo) GetAPITokenByHash(_ context.Context, hash string) (*APIToken, bool) {
+	defer metrics.ObserveRepository(InMemoryBackend, "get_api_token_by_hash", time.Now())
+
+	value, ok := m.tokens.Load(hash)
+	if !ok {
+		return nil, false
+	}
+
+	token, ok := value.(APIToken)
+	if !ok || !token.RevokedAt.IsZero() {
+		return nil, false
+	}
+
+	return &token, true
+}
+
+// GetAPITokensByUserID returns active API tokens of the user
+func (m *InMemoryRepo) GetAPITokensByUserID(_ context.Context, id uuid.UUID) ([]APIToken, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "get_api_tokens_by_user_id", time.Now())
+
+	tokens := make([]APIToken, 0)
+
+	m.tokens.Range(func(_, value interface{}) bool {
+		token, ok := value.(APIToken)
+		if ok && token.UserUUID == id && token.RevokedAt.IsZero() {
+			tokens = append(tokens, token)
+		}
+		return true
+	})
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+
+	return tokens, nil
+}
+
+// RevokeAPIToken revokes an active API token owned by the user
+func (m *InMemoryRepo) RevokeAPIToken(_ context.Context, userID, tokenID uuid.UUID) error {
+	defer metrics.ObserveRepository(InMemoryBackend, "revoke_api_token", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	var revoked *APIToken
+	m.tokens.Range(func(_, value interface{}) bool {
+		token, ok := value.(APIToken)
+		if ok && token.UUID == tokenID && token.UserUUID == userID && token.RevokedAt.IsZero() {
+			revoked = &token
+		}
+		return revoked == nil
+	})
+
+	if revoked == nil {
+		return errors.ErrAPITokenNotFound
+	}
+
+	revoked.RevokedAt = time.Now()
+
+	if err := m.record(JournalEntry{Op: OpRevokeToken, Tokens: []APIToken{*revoked}}); err != nil {
+		return err
+	}
+
+	m.tokens.Store(revoked.Hash, *revoked)
+	return nil
+}
+
+// EnqueueDeleteTask stores a new delete task
+func (m *InMemoryRepo) EnqueueDeleteTask(_ context.Context, task DeleteTask) error {
+	defer metrics.ObserveRepository(InMemoryBackend, "enqueue_delete_task", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+	if task.RunAt.IsZero() {
+		task.RunAt = task.CreatedAt
+	}
+
+	entry := JournalEntry{Op: OpEnqueueTask, Tasks: []DeleteTask{task}}
+	if err := m.record(entry); err != nil {
+		return err
+	}
+
+	m.apply(entry)
+	return nil
+}
+
+// ClaimDeleteTasks leases due delete tasks until the given time, the longest waiting first
+func (m *InMemoryRepo) ClaimDeleteTasks(_ context.Context, now, leaseUntil time.Time, limit int64) ([]DeleteTask, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "claim_delete_tasks", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	due := make([]DeleteTask, 0)
+	for _, task := range m.tasks {
+		if task.FailedAt.IsZero() && !task.RunAt.After(now) {
+			due = append(due, task)
+		}
+	}
+
+	if len(due) == 0 {
+		return due, nil
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].RunAt.Equal(due[j].RunAt) {
+			return due[i].RunAt.Before(due[j].RunAt)
+		}
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+
+	claimed := paginate(due, limit, 0)
+	for i := range claimed {
+		claimed[i].RunAt = leaseUntil
+		claimed[i].Attempts++
+	}
+
+	entry := JournalEntry{Op: OpUpdateTask, Tasks: claimed}
+	if err := m.record(entry); err != nil {
+		return nil, err
+	}
+
+	m.apply(entry)
+	return claimed, nil
+}
+
+// CompleteDeleteTasks removes processed delete tasks
+func (m *InMemoryRepo) CompleteDeleteTasks(_ context.Context, ids []uuid.UUID) error {
+	defer metrics.ObserveRepository(InMemoryBackend, "complete_delete_tasks", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	tasks := make([]DeleteTask, 0, len(ids))
+	for _, id := range ids {
+		if task, ok := m.tasks[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	entry := JournalEntry{Op: OpCompleteTask, Tasks: tasks}
+	if err := m.record(entry); err != nil {
+		return err
+	}
+
+	m.apply(entry)
+	return nil
+}
+
+// RetryDeleteTask schedules the next attempt of a delete task
+func (m *InMemoryRepo) RetryDeleteTask(_ context.Context, id uuid.UUID, runAt time.Time, lastError string) error {
+	defer metrics.ObserveRepository(InMemoryBackend, "retry_delete_task", time.Now())
+
+	return m.updateTask(id, func(task *DeleteTask) {
+		task.RunAt = runAt
+		task.LastError = lastError
+	})
+}
+
+// FailDeleteTask moves a delete task to the dead letters
+func (m *InMemoryRepo) FailDeleteTask(_ context.Context, id uuid.UUID, lastError string) error {
+	defer metrics.ObserveRepository(InMemoryBackend, "fail_delete_task", time.Now())
+
+	return m.updateTask(id, func(task *DeleteTask) {
+		task.FailedAt = time.Now()
+		task.LastError = lastError
+	})
+}
+
+// CountURLs counts active URL records, neither deleted nor expired
+func (m *InMemoryRepo) CountURLs(_ context.Context) (int64, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "count_urls", time.Now())
+
+	var count int64
+	m.data.Range(func(_, value interface{}) bool {
+		if url, ok := value.(URL); ok && url.DeletedAt.IsZero() && !url.Expired {
+			count++
+		}
+		return true
+	})
+
+	return count, nil
+}
+
+// CountUsers counts distinct users owning active URL records
+func (m *InMemoryRepo) CountUsers(_ context.Context) (int64, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "count_users", time.Now())
+
+	users := make(map[uuid.UUID]struct{})
+	m.data.Range(func(_, value interface{}) bool {
+		if url, ok := value.(URL); ok && url.DeletedAt.IsZero() && !url.Expired && url.UserUUID != uuid.Nil {
+			users[url.UserUUID] = struct{}{}
+		}
+		return true
+	})
+
+	return int64(len(users)), nil
+}
+
+// CountDeleteTasks counts pending and dead-lettered delete tasks
+func (m *InMemoryRepo) CountDeleteTasks(_ context.Context) (DeleteTaskStats, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "count_delete_tasks", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	var stats DeleteTaskStats
+	for _, task := range m.tasks {
+		if task.FailedAt.IsZero() {
+			stats.Pending++
+		} else {
+			stats.Failed++
+		}
+	}
+
+	return stats, nil
+}
+
+// updateTask applies fn to the stored delete task and journals the change, unknown tasks are ignored
+func (m *InMemoryRepo) updateTask(id uuid.UUID, fn func(task *DeleteTask)) error {
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	task, ok := m.tasks[id]
+	if !ok {
+		return nil
+	}
+
+	fn(&task)
+
+	entry := JournalEntry{Op: OpUpdateTask, Tasks: []DeleteTask{task}}
+	if err := m.record(entry); err != nil {
+		return err
+	}
+
+	m.apply(entry)
+	return nil
+}
+
+// SaveDeleteJob stores the state of a delete job
+func (m *InMemoryRepo) SaveDeleteJob(_ context.Context, job DeleteJob) error {
+	defer metrics.ObserveRepository(InMemoryBackend, "save_delete_job", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	entry := JournalEntry{Op: OpSaveJob, Jobs: []DeleteJob{job}}
+	if err := m.record(entry); err != nil {
+		return err
+	}
+
+	m.apply(entry)
 	return nil
 }
 
+// GetDeleteJob returns the state of a delete job
+func (m *InMemoryRepo) GetDeleteJob(_ context.Context, id uuid.UUID) (*DeleteJob, bool) {
+	defer metrics.ObserveRepository(InMemoryBackend, "get_delete_job", time.Now())
+
+	m.tmu.Lock()
+	defer m.tmu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, false
+	}
+
+	return &job, true
+}
+
+// PurgeDeleteJobs removes delete jobs finished before the given time
+func (m *InMemoryRepo) PurgeDeleteJobs(_ context.Context, finishedBefore time.Time) (int64, error) {
+	defer metrics.ObserveRepository(InMemoryBackend, "purge_delete_jobs", time.Now())
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	var purged []DeleteJob
+	for _, job := range m.jobs {
+		if !job.FinishedAt.IsZero() && job.FinishedAt.Before(finishedBefore) {
+			purged = append(purged, job)
+		}
+	}
+
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	entry := JournalEntry{Op: OpPurgeJobs, Jobs: purged}
+	if err := m.record(entry); err != nil {
+		return 0, err
+	}
+
+	m.apply(entry)
+	return int64(len(purged)), nil
+}
+
 // CreateMemento creates a memento of the current state
 func (m *InMemoryRepo) CreateMemento() *Memento {
 	var results []URL
+	var tokens []APIToken
 
 	m.data.Range(func(_, value interface{}) bool {
 		url, ok := value.(URL)
@@ -116,19 +915,299 @@ func (m *InMemoryRepo) CreateMemento() *Memento {
 		return true
 	})
 
-	return &Memento{State: results}
+	m.tokens.Range(func(_, value interface{}) bool {
+		token, ok := value.(APIToken)
+		if ok {
+			tokens = append(tokens, token)
+		}
+		return true
+	})
+
+	m.tmu.Lock()
+	var tasks []DeleteTask
+	for _, task := range m.tasks {
+		tasks = append(tasks, task)
+	}
+
+	var jobs []DeleteJob
+	for _, job := range m.jobs {
+		jobs = append(jobs, job)
+	}
+	m.tmu.Unlock()
+
+	return &Memento{State: results, Tokens: tokens, Tasks: tasks, Jobs: jobs, Sequence: m.seq.Load()}
+}
+
+// Snapshot creates a memento and calls fn with it while writes wait, journal entries recorded after the memento
+// would otherwise be lost when fn truncates the journal
+func (m *InMemoryRepo) Snapshot(fn func(memento *Memento) error) error {
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	return fn(m.CreateMemento())
 }
 
 // Restore restores the state from a memento
 func (m *InMemoryRepo) Restore(memento *Memento) {
 	m.data = sync.Map{}
+	m.longURLs = sync.Map{}
 
 	for _, url := range memento.State {
-		m.data.Store(url.ShortCode, url)
+		m.store(url)
+	}
+
+	m.tokens = sync.Map{}
+
+	for _, token := range memento.Tokens {
+		m.tokens.Store(token.Hash, token)
+	}
+
+	m.wmu.Lock()
+	m.tmu.Lock()
+	m.tasks = make(map[uuid.UUID]DeleteTask, len(memento.Tasks))
+	for _, task := range memento.Tasks {
+		m.tasks[task.UUID] = task
+	}
+
+	m.jobs = make(map[uuid.UUID]DeleteJob, len(memento.Jobs))
+	for _, job := range memento.Jobs {
+		m.jobs[job.UUID] = job
 	}
+	m.tmu.Unlock()
+	m.wmu.Unlock()
+
+	// NOTE: snapshots without a stored sequence continue after the number of restored records
+	m.seq.Store(max(memento.Sequence, int64(len(memento.State))))
 }
 
 // Clear clears the repository
 func (m *InMemoryRepo) Clear() {
 	m.data = sync.Map{}
+	m.longURLs = sync.Map{}
+	m.tokens = sync.Map{}
+	m.seq.Store(0)
+
+	m.wmu.Lock()
+	m.tmu.Lock()
+	m.tasks = nil
+	m.jobs = nil
+	m.tmu.Unlock()
+	m.wmu.Unlock()
+
+	m.mu.Lock()
+	m.clicks = nil
+	m.mu.Unlock()
+}
+
+// SetJournal sets the journal recording every state mutation
+func (m *InMemoryRepo) SetJournal(j Journal) {
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	m.journal = j
+}
+
+// Apply applies a journal entry to the state
+func (m *InMemoryRepo) Apply(entry JournalEntry) {
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	m.apply(entry)
+}
+
+// apply applies a journal entry, replaying an already applied entry leaves the state unchanged
+func (m *InMemoryRepo) apply(entry JournalEntry) {
+	switch entry.Op {
+	case OpCreate:
+		for _, url := range entry.URLs {
+			if _, ok := m.data.Load(url.ShortCode); !ok {
+				m.store(url)
+			}
+		}
+	case OpUpdate:
+		for _, url := range entry.URLs {
+			m.store(url)
+		}
+	case OpDelete:
+		for _, shortCode := range entry.ShortCodes {
+			if url, ok := m.deletable(entry.UserUUID, shortCode); ok {
+				url.DeletedAt = entry.DeletedAt
+				m.store(*url)
+			}
+		}
+	case OpRestore:
+		for _, shortCode := range entry.ShortCodes {
+			if url, ok := m.restorable(entry.UserUUID, shortCode); ok {
+				url.DeletedAt = time.Time{}
+				m.store(*url)
+			}
+		}
+	case OpPurge:
+		for _, shortCode := range entry.ShortCodes {
+			m.purge(shortCode)
+		}
+	case OpCreateToken:
+		for _, token := range entry.Tokens {
+			m.tokens.LoadOrStore(token.Hash, token)
+		}
+	case OpRevokeToken:
+		for _, token := range entry.Tokens {
+			m.tokens.Store(token.Hash, token)
+		}
+	case OpEnqueueTask, OpUpdateTask, OpCompleteTask, OpSaveJob, OpPurgeJobs:
+		m.applyTasks(entry)
+	case OpSequence:
+		if entry.Sequence > m.seq.Load() {
+			m.seq.Store(entry.Sequence)
+		}
+	}
+}
+
+// applyTasks applies a delete task or job entry, tmu is taken so that a memento can copy them without wmu
+func (m *InMemoryRepo) applyTasks(entry JournalEntry) {
+	m.tmu.Lock()
+	defer m.tmu.Unlock()
+
+	switch entry.Op {
+	case OpEnqueueTask:
+		if m.tasks == nil {
+			m.tasks = make(map[uuid.UUID]DeleteTask)
+		}
+		for _, task := range entry.Tasks {
+			if _, ok := m.tasks[task.UUID]; !ok {
+				m.tasks[task.UUID] = task
+			}
+		}
+	case OpUpdateTask:
+		for _, task := range entry.Tasks {
+			if _, ok := m.tasks[task.UUID]; ok {
+				m.tasks[task.UUID] = task
+			}
+		}
+	case OpCompleteTask:
+		for _, task := range entry.Tasks {
+			delete(m.tasks, task.UUID)
+		}
+	case OpSaveJob:
+		if m.jobs == nil {
+			m.jobs = make(map[uuid.UUID]DeleteJob)
+		}
+		for _, job := range entry.Jobs {
+			m.jobs[job.UUID] = job
+		}
+	case OpPurgeJobs:
+		for _, job := range entry.Jobs {
+			delete(m.jobs, job.UUID)
+		}
+	}
+}
+
+// deletable returns the URL record if it is owned by the user and not deleted yet
+func (m *InMemoryRepo) deletable(id uuid.UUID, shortCode string) (*URL, bool) {
+	value, ok := m.data.Load(shortCode)
+	if !ok {
+		return nil, false
+	}
+
+	url, ok := value.(URL)
+	if !ok || url.UserUUID != id || !url.DeletedAt.IsZero() {
+		return nil, false
+	}
+
+	return &url, true
+}
+
+// restorable returns the URL record if it is owned by the user and deleted
+func (m *InMemoryRepo) restorable(id uuid.UUID, shortCode string) (*URL, bool) {
+	value, ok := m.data.Load(shortCode)
+	if !ok {
+		return nil, false
+	}
+
+	url, ok := value.(URL)
+	if !ok || url.UserUUID != id || url.DeletedAt.IsZero() {
+		return nil, false
+	}
+
+	return &url, true
+}
+
+// purge removes the deleted URL record and its clicks
+func (m *InMemoryRepo) purge(shortCode string) {
+	value, ok := m.data.Load(shortCode)
+	if !ok {
+		return
+	}
+
+	url, ok := value.(URL)
+	if !ok || url.DeletedAt.IsZero() {
+		return
+	}
+
+	m.remove(shortCode)
+
+	m.mu.Lock()
+	delete(m.clicks, url.UUID)
+	m.mu.Unlock()
+}
+
+// matches reports whether the URL record satisfies the filter, query is the lowercased filter query
+func matches(url URL, filter URLFilter, query string) bool {
+	if url.UserUUID != filter.UserUUID {
+		return false
+	}
+
+	switch {
+	case filter.Deleted:
+		if url.DeletedAt.IsZero() {
+			return false
+		}
+	case filter.Expired:
+		if !url.DeletedAt.IsZero() || !url.Expired {
+			return false
+		}
+	default:
+		if !url.DeletedAt.IsZero() || url.Expired {
+			return false
+		}
+	}
+
+	if !filter.CreatedAfter.IsZero() && url.CreatedAt.Before(filter.CreatedAfter) {
+		return false
+	}
+
+	if !filter.CreatedBefore.IsZero() && !url.CreatedAt.Before(filter.CreatedBefore) {
+		return false
+	}
+
+	return query == "" ||
+		strings.Contains(strings.ToLower(url.LongURL), query) ||
+		strings.Contains(strings.ToLower(url.ShortCode), query)
+}
+
+// newer reports whether the URL record a was created after b, records created at the same time are ordered by UUID descending
+func newer(a, b URL) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+
+	return bytes.Compare(a.UUID[:], b.UUID[:]) > 0
+}
+
+// paginate returns the page of items starting at offset
+func paginate[T any](items []T, limit, offset int64) []T {
+	total := int64(len(items))
+	start := min(offset, total)
+	end := min(offset+limit, total)
+
+	return items[start:end]
+}
+
+// record appends the entry to the journal if one is set
+func (m *InMemoryRepo) record(entry JournalEntry) error {
+	if m.journal == nil {
+		return nil
+	}
+
+	return m.journal.Append(entry)
 }
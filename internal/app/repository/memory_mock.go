@@ -12,6 +12,7 @@ package repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,33 @@ func (m *MockInMemory) EXPECT() *MockInMemoryMockRecorder {
 	return m.recorder
 }
 
+// Apply mocks base method.
+func (m *MockInMemory) Apply(entry JournalEntry) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Apply", entry)
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockInMemoryMockRecorder) Apply(entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockInMemory)(nil).Apply), entry)
+}
+
+// ClaimDeleteTasks mocks base method.
+func (m *MockInMemory) ClaimDeleteTasks(ctx context.Context, now, leaseUntil time.Time, limit int64) ([]DeleteTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeleteTasks", ctx, now, leaseUntil, limit)
+	ret0, _ := ret[0].([]DeleteTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeleteTasks indicates an expected call of ClaimDeleteTasks.
+func (mr *MockInMemoryMockRecorder) ClaimDeleteTasks(ctx, now, leaseUntil, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeleteTasks", reflect.TypeOf((*MockInMemory)(nil).ClaimDeleteTasks), ctx, now, leaseUntil, limit)
+}
+
 // Clear mocks base method.
 func (m *MockInMemory) Clear() {
 	m.ctrl.T.Helper()
@@ -53,6 +81,94 @@ func (mr *MockInMemoryMockRecorder) Clear() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockInMemory)(nil).Clear))
 }
 
+// CompleteDeleteTasks mocks base method.
+func (m *MockInMemory) CompleteDeleteTasks(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteDeleteTasks", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteDeleteTasks indicates an expected call of CompleteDeleteTasks.
+func (mr *MockInMemoryMockRecorder) CompleteDeleteTasks(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteDeleteTasks", reflect.TypeOf((*MockInMemory)(nil).CompleteDeleteTasks), ctx, ids)
+}
+
+// CountDeleteTasks mocks base method.
+func (m *MockInMemory) CountDeleteTasks(ctx context.Context) (DeleteTaskStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDeleteTasks", ctx)
+	ret0, _ := ret[0].(DeleteTaskStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDeleteTasks indicates an expected call of CountDeleteTasks.
+func (mr *MockInMemoryMockRecorder) CountDeleteTasks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDeleteTasks", reflect.TypeOf((*MockInMemory)(nil).CountDeleteTasks), ctx)
+}
+
+// CountURLs mocks base method.
+func (m *MockInMemory) CountURLs(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountURLs", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountURLs indicates an expected call of CountURLs.
+func (mr *MockInMemoryMockRecorder) CountURLs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountURLs", reflect.TypeOf((*MockInMemory)(nil).CountURLs), ctx)
+}
+
+// CountUsers mocks base method.
+func (m *MockInMemory) CountUsers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers.
+func (mr *MockInMemoryMockRecorder) CountUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockInMemory)(nil).CountUsers), ctx)
+}
+
+// CreateAPIToken mocks base method.
+func (m *MockInMemory) CreateAPIToken(ctx context.Context, token APIToken) (*APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIToken", ctx, token)
+	ret0, _ := ret[0].(*APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIToken indicates an expected call of CreateAPIToken.
+func (mr *MockInMemoryMockRecorder) CreateAPIToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIToken", reflect.TypeOf((*MockInMemory)(nil).CreateAPIToken), ctx, token)
+}
+
+// CreateClicks mocks base method.
+func (m *MockInMemory) CreateClicks(ctx context.Context, clicks []Click) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClicks", ctx, clicks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClicks indicates an expected call of CreateClicks.
+func (mr *MockInMemoryMockRecorder) CreateClicks(ctx, clicks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClicks", reflect.TypeOf((*MockInMemory)(nil).CreateClicks), ctx, clicks)
+}
+
 // CreateMemento mocks base method.
 func (m *MockInMemory) CreateMemento() *Memento {
 	m.ctrl.T.Helper()
@@ -83,11 +199,12 @@ func (mr *MockInMemoryMockRecorder) CreateURL(ctx, url any) *gomock.Call {
 }
 
 // CreateURLs mocks base method.
-func (m *MockInMemory) CreateURLs(ctx context.Context, urls []URL) error {
+func (m *MockInMemory) CreateURLs(ctx context.Context, urls []URL) ([]URL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateURLs", ctx, urls)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateURLs indicates an expected call of CreateURLs.
@@ -97,11 +214,12 @@ func (mr *MockInMemoryMockRecorder) CreateURLs(ctx, urls any) *gomock.Call {
 }
 
 // DeleteURLsByUserID mocks base method.
-func (m *MockInMemory) DeleteURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string) error {
+func (m *MockInMemory) DeleteURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string) ([]string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteURLsByUserID", ctx, uuid, shortCodes)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteURLsByUserID indicates an expected call of DeleteURLsByUserID.
@@ -110,6 +228,155 @@ func (mr *MockInMemoryMockRecorder) DeleteURLsByUserID(ctx, uuid, shortCodes any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteURLsByUserID", reflect.TypeOf((*MockInMemory)(nil).DeleteURLsByUserID), ctx, uuid, shortCodes)
 }
 
+// DeleteURLsByUserIDs mocks base method.
+func (m *MockInMemory) DeleteURLsByUserIDs(ctx context.Context, shortCodes map[uuid.UUID][]string) (map[uuid.UUID][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteURLsByUserIDs", ctx, shortCodes)
+	ret0, _ := ret[0].(map[uuid.UUID][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteURLsByUserIDs indicates an expected call of DeleteURLsByUserIDs.
+func (mr *MockInMemoryMockRecorder) DeleteURLsByUserIDs(ctx, shortCodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteURLsByUserIDs", reflect.TypeOf((*MockInMemory)(nil).DeleteURLsByUserIDs), ctx, shortCodes)
+}
+
+// EnqueueDeleteTask mocks base method.
+func (m *MockInMemory) EnqueueDeleteTask(ctx context.Context, task DeleteTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeleteTask", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueDeleteTask indicates an expected call of EnqueueDeleteTask.
+func (mr *MockInMemoryMockRecorder) EnqueueDeleteTask(ctx, task any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeleteTask", reflect.TypeOf((*MockInMemory)(nil).EnqueueDeleteTask), ctx, task)
+}
+
+// ExpireURLs mocks base method.
+func (m *MockInMemory) ExpireURLs(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireURLs", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireURLs indicates an expected call of ExpireURLs.
+func (mr *MockInMemoryMockRecorder) ExpireURLs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireURLs", reflect.TypeOf((*MockInMemory)(nil).ExpireURLs), ctx)
+}
+
+// ExportURLsByUserID mocks base method.
+func (m *MockInMemory) ExportURLsByUserID(ctx context.Context, uuid uuid.UUID, after URLCursor, limit int64) ([]URLListItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportURLsByUserID", ctx, uuid, after, limit)
+	ret0, _ := ret[0].([]URLListItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportURLsByUserID indicates an expected call of ExportURLsByUserID.
+func (mr *MockInMemoryMockRecorder) ExportURLsByUserID(ctx, uuid, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportURLsByUserID", reflect.TypeOf((*MockInMemory)(nil).ExportURLsByUserID), ctx, uuid, after, limit)
+}
+
+// FailDeleteTask mocks base method.
+func (m *MockInMemory) FailDeleteTask(ctx context.Context, id uuid.UUID, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailDeleteTask", ctx, id, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailDeleteTask indicates an expected call of FailDeleteTask.
+func (mr *MockInMemoryMockRecorder) FailDeleteTask(ctx, id, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailDeleteTask", reflect.TypeOf((*MockInMemory)(nil).FailDeleteTask), ctx, id, lastError)
+}
+
+// FindURLsByUserID mocks base method.
+func (m *MockInMemory) FindURLsByUserID(ctx context.Context, filter URLFilter) ([]URLListItem, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindURLsByUserID", ctx, filter)
+	ret0, _ := ret[0].([]URLListItem)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindURLsByUserID indicates an expected call of FindURLsByUserID.
+func (mr *MockInMemoryMockRecorder) FindURLsByUserID(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindURLsByUserID", reflect.TypeOf((*MockInMemory)(nil).FindURLsByUserID), ctx, filter)
+}
+
+// GetAPITokenByHash mocks base method.
+func (m *MockInMemory) GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPITokenByHash", ctx, hash)
+	ret0, _ := ret[0].(*APIToken)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetAPITokenByHash indicates an expected call of GetAPITokenByHash.
+func (mr *MockInMemoryMockRecorder) GetAPITokenByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPITokenByHash", reflect.TypeOf((*MockInMemory)(nil).GetAPITokenByHash), ctx, hash)
+}
+
+// GetAPITokensByUserID mocks base method.
+func (m *MockInMemory) GetAPITokensByUserID(ctx context.Context, id uuid.UUID) ([]APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPITokensByUserID", ctx, id)
+	ret0, _ := ret[0].([]APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPITokensByUserID indicates an expected call of GetAPITokensByUserID.
+func (mr *MockInMemoryMockRecorder) GetAPITokensByUserID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPITokensByUserID", reflect.TypeOf((*MockInMemory)(nil).GetAPITokensByUserID), ctx, id)
+}
+
+// GetClickStats mocks base method.
+func (m *MockInMemory) GetClickStats(ctx context.Context, urlID uuid.UUID, since time.Time) (*ClickStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStats", ctx, urlID, since)
+	ret0, _ := ret[0].(*ClickStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStats indicates an expected call of GetClickStats.
+func (mr *MockInMemoryMockRecorder) GetClickStats(ctx, urlID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStats", reflect.TypeOf((*MockInMemory)(nil).GetClickStats), ctx, urlID, since)
+}
+
+// GetDeleteJob mocks base method.
+func (m *MockInMemory) GetDeleteJob(ctx context.Context, id uuid.UUID) (*DeleteJob, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleteJob", ctx, id)
+	ret0, _ := ret[0].(*DeleteJob)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetDeleteJob indicates an expected call of GetDeleteJob.
+func (mr *MockInMemoryMockRecorder) GetDeleteJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleteJob", reflect.TypeOf((*MockInMemory)(nil).GetDeleteJob), ctx, id)
+}
+
 // GetURLByShortCode mocks base method.
 func (m *MockInMemory) GetURLByShortCode(ctx context.Context, shortCode string) (*URL, bool) {
 	m.ctrl.T.Helper()
@@ -125,6 +392,21 @@ func (mr *MockInMemoryMockRecorder) GetURLByShortCode(ctx, shortCode any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLByShortCode", reflect.TypeOf((*MockInMemory)(nil).GetURLByShortCode), ctx, shortCode)
 }
 
+// GetURLsByLongURLs mocks base method.
+func (m *MockInMemory) GetURLsByLongURLs(ctx context.Context, longURLs []string) ([]URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLsByLongURLs", ctx, longURLs)
+	ret0, _ := ret[0].([]URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetURLsByLongURLs indicates an expected call of GetURLsByLongURLs.
+func (mr *MockInMemoryMockRecorder) GetURLsByLongURLs(ctx, longURLs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLsByLongURLs", reflect.TypeOf((*MockInMemory)(nil).GetURLsByLongURLs), ctx, longURLs)
+}
+
 // GetURLsByUserID mocks base method.
 func (m *MockInMemory) GetURLsByUserID(ctx context.Context, uuid uuid.UUID, limit, offset int64) ([]URL, int, error) {
 	m.ctrl.T.Helper()
@@ -141,6 +423,66 @@ func (mr *MockInMemoryMockRecorder) GetURLsByUserID(ctx, uuid, limit, offset any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLsByUserID", reflect.TypeOf((*MockInMemory)(nil).GetURLsByUserID), ctx, uuid, limit, offset)
 }
 
+// GetURLsByUserIDAfter mocks base method.
+func (m *MockInMemory) GetURLsByUserIDAfter(ctx context.Context, uuid uuid.UUID, after URLCursor, limit int64) ([]URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLsByUserIDAfter", ctx, uuid, after, limit)
+	ret0, _ := ret[0].([]URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetURLsByUserIDAfter indicates an expected call of GetURLsByUserIDAfter.
+func (mr *MockInMemoryMockRecorder) GetURLsByUserIDAfter(ctx, uuid, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLsByUserIDAfter", reflect.TypeOf((*MockInMemory)(nil).GetURLsByUserIDAfter), ctx, uuid, after, limit)
+}
+
+// NextSequence mocks base method.
+func (m *MockInMemory) NextSequence(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextSequence", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextSequence indicates an expected call of NextSequence.
+func (mr *MockInMemoryMockRecorder) NextSequence(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextSequence", reflect.TypeOf((*MockInMemory)(nil).NextSequence), ctx)
+}
+
+// PurgeDeleteJobs mocks base method.
+func (m *MockInMemory) PurgeDeleteJobs(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleteJobs", ctx, finishedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleteJobs indicates an expected call of PurgeDeleteJobs.
+func (mr *MockInMemoryMockRecorder) PurgeDeleteJobs(ctx, finishedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleteJobs", reflect.TypeOf((*MockInMemory)(nil).PurgeDeleteJobs), ctx, finishedBefore)
+}
+
+// PurgeURLs mocks base method.
+func (m *MockInMemory) PurgeURLs(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeURLs", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeURLs indicates an expected call of PurgeURLs.
+func (mr *MockInMemoryMockRecorder) PurgeURLs(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeURLs", reflect.TypeOf((*MockInMemory)(nil).PurgeURLs), ctx, deletedBefore)
+}
+
 // Restore mocks base method.
 func (m_2 *MockInMemory) Restore(m *Memento) {
 	m_2.ctrl.T.Helper()
@@ -152,3 +494,101 @@ func (mr *MockInMemoryMockRecorder) Restore(m any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockInMemory)(nil).Restore), m)
 }
+
+// RestoreURLsByUserID mocks base method.
+func (m *MockInMemory) RestoreURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string, deletedSince time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreURLsByUserID", ctx, uuid, shortCodes, deletedSince)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreURLsByUserID indicates an expected call of RestoreURLsByUserID.
+func (mr *MockInMemoryMockRecorder) RestoreURLsByUserID(ctx, uuid, shortCodes, deletedSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreURLsByUserID", reflect.TypeOf((*MockInMemory)(nil).RestoreURLsByUserID), ctx, uuid, shortCodes, deletedSince)
+}
+
+// RetryDeleteTask mocks base method.
+func (m *MockInMemory) RetryDeleteTask(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDeleteTask", ctx, id, runAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryDeleteTask indicates an expected call of RetryDeleteTask.
+func (mr *MockInMemoryMockRecorder) RetryDeleteTask(ctx, id, runAt, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDeleteTask", reflect.TypeOf((*MockInMemory)(nil).RetryDeleteTask), ctx, id, runAt, lastError)
+}
+
+// RevokeAPIToken mocks base method.
+func (m *MockInMemory) RevokeAPIToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIToken", ctx, userID, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIToken indicates an expected call of RevokeAPIToken.
+func (mr *MockInMemoryMockRecorder) RevokeAPIToken(ctx, userID, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIToken", reflect.TypeOf((*MockInMemory)(nil).RevokeAPIToken), ctx, userID, tokenID)
+}
+
+// SaveDeleteJob mocks base method.
+func (m *MockInMemory) SaveDeleteJob(ctx context.Context, job DeleteJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeleteJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeleteJob indicates an expected call of SaveDeleteJob.
+func (mr *MockInMemoryMockRecorder) SaveDeleteJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeleteJob", reflect.TypeOf((*MockInMemory)(nil).SaveDeleteJob), ctx, job)
+}
+
+// SetJournal mocks base method.
+func (m *MockInMemory) SetJournal(j Journal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetJournal", j)
+}
+
+// SetJournal indicates an expected call of SetJournal.
+func (mr *MockInMemoryMockRecorder) SetJournal(j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJournal", reflect.TypeOf((*MockInMemory)(nil).SetJournal), j)
+}
+
+// Snapshot mocks base method.
+func (m *MockInMemory) Snapshot(fn func(*Memento) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockInMemoryMockRecorder) Snapshot(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockInMemory)(nil).Snapshot), fn)
+}
+
+// UpdateURL mocks base method.
+func (m *MockInMemory) UpdateURL(ctx context.Context, url URL) (*URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateURL", ctx, url)
+	ret0, _ := ret[0].(*URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateURL indicates an expected call of UpdateURL.
+func (mr *MockInMemoryMockRecorder) UpdateURL(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateURL", reflect.TypeOf((*MockInMemory)(nil).UpdateURL), ctx, url)
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 
+	"shortly/internal/app/errors"
 	"shortly/internal/app/repository/db"
 )
 
@@ -62,6 +66,36 @@ func Test_InMemoryRepository_CreateURL(t *testing.T) {
 	}
 }
 
+func Test_InMemoryRepository_CreateURL_ShortCodeTaken(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	_, err := store.CreateURL(ctx, URL{
+		LongURL:   "https://example.com",
+		ShortCode: "spring-sale",
+	})
+	assert.NoError(t, err)
+
+	_, err = store.CreateURL(ctx, URL{
+		LongURL:   "https://github.com",
+		ShortCode: "spring-sale",
+	})
+	assert.ErrorIs(t, err, errors.ErrShortCodeAlreadyExists)
+
+	_, err = store.CreateURLs(ctx, []URL{
+		{LongURL: "https://google.com", ShortCode: "abcd0001"},
+		{LongURL: "https://github.com", ShortCode: "spring-sale"},
+	})
+	assert.ErrorIs(t, err, errors.ErrShortCodeAlreadyExists)
+
+	storedURL, found := store.GetURLByShortCode(ctx, "spring-sale")
+	assert.True(t, found)
+	assert.Equal(t, "https://example.com", storedURL.LongURL)
+
+	_, found = store.GetURLByShortCode(ctx, "abcd0001")
+	assert.False(t, found)
+}
+
 func Benchmark_InMemoryRepository_CreateURL(b *testing.B) {
 	ctx := context.Background()
 	store := NewInMemoryRepository()
@@ -114,7 +148,7 @@ func Test_InMemoryRepository_CreateURLs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := store.CreateURLs(ctx, tt.urls)
+			_, err := store.CreateURLs(ctx, tt.urls)
 			assert.NoError(t, err)
 
 			snapshot := store.CreateMemento()
@@ -123,6 +157,36 @@ func Test_InMemoryRepository_CreateURLs(t *testing.T) {
 	}
 }
 
+func Test_InMemoryRepository_CreateURLs_Existing(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	_, err := store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://example.com", ShortCode: "abcd0001"})
+	require.NoError(t, err)
+
+	records, err := store.CreateURLs(ctx, []URL{
+		{UUID: uuid.New(), LongURL: "https://example.com", ShortCode: "abcd0002"},
+		{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0003"},
+		{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0004"},
+	})
+	require.NoError(t, err)
+
+	shortCodes := make([]string, 0, len(records))
+	for _, record := range records {
+		shortCodes = append(shortCodes, record.ShortCode)
+	}
+	assert.Equal(t, []string{"abcd0001", "abcd0003", "abcd0003"}, shortCodes)
+
+	record, err := store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0005"})
+	require.NoError(t, err)
+	assert.Equal(t, "abcd0003", record.ShortCode)
+
+	for _, shortCode := range []string{"abcd0002", "abcd0004", "abcd0005"} {
+		_, found := store.GetURLByShortCode(ctx, shortCode)
+		assert.False(t, found)
+	}
+}
+
 func Test_InMemoryRepository_GetURLByShortCode(t *testing.T) {
 	ctx := context.Background()
 	store := NewInMemoryRepository()
@@ -276,6 +340,26 @@ func Test_InMemoryRepository_GetURLsByUserID(t *testing.T) {
 				ShortCode: "",
 			},
 		},
+		{
+			name: "Expired",
+			before: func() {
+				_, err := store.CreateURL(ctx, URL{
+					UUID:      UUID1,
+					LongURL:   "https://google.com",
+					ShortCode: "abcd0001",
+					UserUUID:  UserUUID1,
+					Expired:   true,
+				})
+				assert.NoError(t, err)
+			},
+			UserID: UserUUID1,
+			expected: result{
+				count:     0,
+				UUID:      uuid.Nil,
+				LongURL:   "",
+				ShortCode: "",
+			},
+		},
 		{
 			name:   "Empty",
 			before: func() {},
@@ -310,6 +394,132 @@ func Test_InMemoryRepository_GetURLsByUserID(t *testing.T) {
 	}
 }
 
+func Test_InMemoryRepository_LongURLIndex(t *testing.T) {
+	ctx := context.Background()
+	UserUUID := uuid.New()
+
+	store := NewInMemoryRepository()
+	_, err := store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID})
+	require.NoError(t, err)
+
+	// NOTE: an updated record is found by its new long URL only
+	_, err = store.UpdateURL(ctx, URL{LongURL: "https://github.com", ShortCode: "abcd0001", UserUUID: UserUUID})
+	require.NoError(t, err)
+
+	existing, err := store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0002"})
+	require.NoError(t, err)
+	assert.Equal(t, "abcd0001", existing.ShortCode)
+
+	created, err := store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0003"})
+	require.NoError(t, err)
+	assert.Equal(t, "abcd0003", created.ShortCode)
+
+	_, err = store.UpdateURL(ctx, URL{LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID})
+	assert.ErrorIs(t, err, errors.ErrURLAlreadyExists)
+
+	// NOTE: a purged record frees its long URL
+	_, err = store.DeleteURLsByUserID(ctx, UserUUID, []string{"abcd0001"})
+	require.NoError(t, err)
+	_, err = store.PurgeURLs(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	urls, err := store.CreateURLs(ctx, []URL{{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0004"}})
+	require.NoError(t, err)
+	assert.Equal(t, "abcd0004", urls[0].ShortCode)
+
+	// NOTE: the index is rebuilt from a restored snapshot
+	restored := NewInMemoryRepository()
+	restored.Restore(store.CreateMemento())
+
+	found, err := restored.GetURLsByLongURLs(ctx, []string{"https://github.com", "https://google.com", "https://example.com"})
+	require.NoError(t, err)
+	shortCodes := make([]string, 0, len(found))
+	for _, url := range found {
+		shortCodes = append(shortCodes, url.ShortCode)
+	}
+	assert.ElementsMatch(t, []string{"abcd0003", "abcd0004"}, shortCodes)
+}
+
+func Test_InMemoryRepository_UpdateURL(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+
+	_, err := store.CreateURLs(ctx, []URL{
+		{
+			LongURL:   "https://google.com",
+			ShortCode: "abcd0001",
+			UserUUID:  UserUUID1,
+		},
+		{
+			LongURL:   "https://github.com",
+			ShortCode: "abcd0002",
+			UserUUID:  UserUUID1,
+		},
+		{
+			LongURL:   "https://golang.org",
+			ShortCode: "abcd0003",
+			UserUUID:  UserUUID1,
+			DeletedAt: time.Now(),
+		},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		url      URL
+		expected error
+	}{
+		{
+			name:     "Success",
+			url:      URL{ShortCode: "abcd0001", UserUUID: UserUUID1, LongURL: "https://example.com"},
+			expected: nil,
+		},
+		{
+			name:     "Same URL",
+			url:      URL{ShortCode: "abcd0002", UserUUID: UserUUID1, LongURL: "https://github.com"},
+			expected: nil,
+		},
+		{
+			name:     "Conflict",
+			url:      URL{ShortCode: "abcd0002", UserUUID: UserUUID1, LongURL: "https://example.com"},
+			expected: errors.ErrURLAlreadyExists,
+		},
+		{
+			name:     "Not owned",
+			url:      URL{ShortCode: "abcd0001", UserUUID: UserUUID2, LongURL: "https://example.org"},
+			expected: errors.ErrShortLinkNotFound,
+		},
+		{
+			name:     "Deleted",
+			url:      URL{ShortCode: "abcd0003", UserUUID: UserUUID1, LongURL: "https://example.org"},
+			expected: errors.ErrShortLinkNotFound,
+		},
+		{
+			name:     "Not found",
+			url:      URL{ShortCode: "abcd0004", UserUUID: UserUUID1, LongURL: "https://example.org"},
+			expected: errors.ErrShortLinkNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, err := store.UpdateURL(ctx, tt.url)
+
+			assert.Equal(t, tt.expected, err)
+			if tt.expected == nil {
+				assert.Equal(t, tt.url.LongURL, url.LongURL)
+				assert.False(t, url.UpdatedAt.IsZero())
+
+				stored, _ := store.GetURLByShortCode(ctx, tt.url.ShortCode)
+				assert.Equal(t, tt.url.LongURL, stored.LongURL)
+			}
+		})
+	}
+}
+
 func Test_InMemoryRepository_DeleteURLsByUserID(t *testing.T) {
 	ctx := context.Background()
 	store := NewInMemoryRepository()
@@ -422,8 +632,13 @@ func Test_InMemoryRepository_DeleteURLsByUserID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.before()
 
-			err := store.DeleteURLsByUserID(ctx, tt.params.UserUUID, tt.params.ShortCodes)
+			deleted, err := store.DeleteURLsByUserID(ctx, tt.params.UserUUID, tt.params.ShortCodes)
 			assert.NoError(t, err)
+			if tt.deleted {
+				assert.ElementsMatch(t, tt.params.ShortCodes, deleted)
+			} else {
+				assert.Empty(t, deleted)
+			}
 
 			snapshot := store.CreateMemento()
 			assert.Equal(t, tt.expected, len(snapshot.State))
@@ -443,10 +658,434 @@ func Test_InMemoryRepository_DeleteURLsByUserID(t *testing.T) {
 	}
 }
 
+func Test_InMemoryRepository_DeleteURLsByUserIDs(t *testing.T) {
+	ctx := context.Background()
+
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+
+	tests := []struct {
+		name       string
+		shortCodes map[uuid.UUID][]string
+		expected   map[uuid.UUID][]string
+	}{
+		{
+			name:       "Many users",
+			shortCodes: map[uuid.UUID][]string{UserUUID1: {"abcd0001", "abcd0003"}, UserUUID2: {"abcd0002"}},
+			expected:   map[uuid.UUID][]string{UserUUID1: {"abcd0001"}, UserUUID2: {"abcd0002"}},
+		},
+		{
+			name:       "Not owned",
+			shortCodes: map[uuid.UUID][]string{UserUUID2: {"abcd0001"}},
+			expected:   map[uuid.UUID][]string{},
+		},
+		{
+			name:       "Not found",
+			shortCodes: map[uuid.UUID][]string{UserUUID1: {"1234abcd"}},
+			expected:   map[uuid.UUID][]string{},
+		},
+		{
+			name:       "Empty",
+			shortCodes: map[uuid.UUID][]string{},
+			expected:   map[uuid.UUID][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewInMemoryRepository()
+
+			_, err := store.CreateURLs(ctx, []URL{
+				{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID1},
+				{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID2},
+				{UUID: uuid.New(), LongURL: "https://example.com", ShortCode: "abcd0003", UserUUID: UserUUID1},
+			})
+			require.NoError(t, err)
+			_, err = store.DeleteURLsByUserID(ctx, UserUUID1, []string{"abcd0003"})
+			require.NoError(t, err)
+
+			deleted, err := store.DeleteURLsByUserIDs(ctx, tt.shortCodes)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, deleted)
+
+			for _, codes := range tt.expected {
+				for _, code := range codes {
+					url, found := store.GetURLByShortCode(ctx, code)
+					require.True(t, found)
+					assert.False(t, url.DeletedAt.IsZero())
+				}
+			}
+		})
+	}
+}
+
+func Test_InMemoryRepository_ExpireURLs(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	now := time.Now()
+
+	_, err := store.CreateURLs(ctx, []URL{
+		{
+			LongURL:   "https://google.com",
+			ShortCode: "abcd0001",
+			ExpiresAt: now.Add(-time.Minute),
+		},
+		{
+			LongURL:   "https://github.com",
+			ShortCode: "abcd0002",
+			ExpiresAt: now.Add(time.Hour),
+		},
+		{
+			LongURL:   "https://example.com",
+			ShortCode: "abcd0003",
+		},
+	})
+	assert.NoError(t, err)
+
+	count, err := store.ExpireURLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	expired, _ := store.GetURLByShortCode(ctx, "abcd0001")
+	assert.True(t, expired.Expired)
+
+	active, _ := store.GetURLByShortCode(ctx, "abcd0002")
+	assert.False(t, active.Expired)
+
+	permanent, _ := store.GetURLByShortCode(ctx, "abcd0003")
+	assert.False(t, permanent.Expired)
+
+	count, err = store.ExpireURLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func Test_InMemoryRepository_CountURLs(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+	UserUUID3, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174003")
+
+	_, err := store.CreateURLs(ctx, []URL{
+		{LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID1},
+		{LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID1},
+		{LongURL: "https://golang.org", ShortCode: "abcd0003", UserUUID: UserUUID2},
+		{LongURL: "https://example.com", ShortCode: "abcd0004", UserUUID: UserUUID2},
+		{LongURL: "https://example.org", ShortCode: "abcd0005", UserUUID: UserUUID3, ExpiresAt: time.Now().Add(-time.Minute)},
+		{LongURL: "https://example.net", ShortCode: "abcd0006"},
+	})
+	require.NoError(t, err)
+
+	_, err = store.DeleteURLsByUserID(ctx, UserUUID2, []string{"abcd0004"})
+	require.NoError(t, err)
+	_, err = store.ExpireURLs(ctx)
+	require.NoError(t, err)
+
+	urls, err := store.CountURLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), urls)
+
+	users, err := store.CountUsers(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), users)
+}
+
+func Test_InMemoryRepository_RestoreURLsByUserID(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+	now := time.Now()
+
+	_, err := store.CreateURLs(ctx, []URL{
+		{LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID1, DeletedAt: now.Add(-time.Hour)},
+		{LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID1, DeletedAt: now.Add(-3 * time.Hour)},
+		{LongURL: "https://example.com", ShortCode: "abcd0003", UserUUID: UserUUID2, DeletedAt: now.Add(-time.Hour)},
+		{LongURL: "https://example.org", ShortCode: "abcd0004", UserUUID: UserUUID1},
+	})
+	require.NoError(t, err)
+
+	restored, err := store.RestoreURLsByUserID(ctx, UserUUID1, []string{"abcd0001", "abcd0002", "abcd0003", "abcd0004", "abcd0005"}, now.Add(-2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abcd0001"}, restored)
+
+	url, _ := store.GetURLByShortCode(ctx, "abcd0001")
+	assert.True(t, url.DeletedAt.IsZero())
+
+	url, _ = store.GetURLByShortCode(ctx, "abcd0002")
+	assert.False(t, url.DeletedAt.IsZero())
+
+	url, _ = store.GetURLByShortCode(ctx, "abcd0003")
+	assert.False(t, url.DeletedAt.IsZero())
+
+	restored, err = store.RestoreURLsByUserID(ctx, UserUUID1, []string{"abcd0001"}, now.Add(-2*time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, restored)
+}
+
+func Test_InMemoryRepository_PurgeURLs(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+	now := time.Now()
+
+	_, err := store.CreateURLs(ctx, []URL{
+		{UUID: UUID1, LongURL: "https://google.com", ShortCode: "abcd0001", DeletedAt: now.Add(-48 * time.Hour)},
+		{UUID: UUID2, LongURL: "https://github.com", ShortCode: "abcd0002", DeletedAt: now.Add(-time.Hour)},
+		{LongURL: "https://example.com", ShortCode: "abcd0003"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateClicks(ctx, []Click{
+		{URLUUID: UUID1, IPHash: "hash", CreatedAt: now},
+		{URLUUID: UUID2, IPHash: "hash", CreatedAt: now},
+	}))
+
+	count, err := store.PurgeURLs(ctx, now.Add(-24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, ok := store.GetURLByShortCode(ctx, "abcd0001")
+	assert.False(t, ok)
+
+	_, ok = store.GetURLByShortCode(ctx, "abcd0002")
+	assert.True(t, ok)
+
+	_, ok = store.GetURLByShortCode(ctx, "abcd0003")
+	assert.True(t, ok)
+
+	stats, err := store.GetClickStats(ctx, UUID1, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), stats.Total)
+
+	stats, err = store.GetClickStats(ctx, UUID2, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Total)
+
+	count, err = store.PurgeURLs(ctx, now.Add(-24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func Test_InMemoryRepository_DeleteTasks(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	UserUUID := uuid.New()
+	Task1, Task2 := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	require.NoError(t, store.EnqueueDeleteTask(ctx, DeleteTask{
+		UUID: Task1, UserUUID: UserUUID, ShortCodes: []string{"abcd0001", "abcd0002"}, RunAt: now.Add(-time.Minute), CreatedAt: now,
+		TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}))
+	require.NoError(t, store.EnqueueDeleteTask(ctx, DeleteTask{
+		UUID: Task2, UserUUID: UserUUID, ShortCodes: []string{"abcd0003"}, RunAt: now.Add(time.Hour), CreatedAt: now,
+	}))
+
+	stats, err := store.CountDeleteTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DeleteTaskStats{Pending: 2}, stats)
+
+	tasks, err := store.ClaimDeleteTasks(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, Task1, tasks[0].UUID)
+	assert.Equal(t, UserUUID, tasks[0].UserUUID)
+	assert.Equal(t, []string{"abcd0001", "abcd0002"}, tasks[0].ShortCodes)
+	assert.Equal(t, 1, tasks[0].Attempts)
+	assert.True(t, now.Add(time.Minute).Equal(tasks[0].RunAt))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tasks[0].TraceParent)
+
+	// NOTE: a claimed task is leased and not claimed again until the lease passes
+	tasks, err = store.ClaimDeleteTasks(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	require.NoError(t, store.RetryDeleteTask(ctx, Task1, now, "timeout"))
+
+	tasks, err = store.ClaimDeleteTasks(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, 2, tasks[0].Attempts)
+	assert.Equal(t, "timeout", tasks[0].LastError)
+
+	require.NoError(t, store.FailDeleteTask(ctx, Task1, "timeout"))
+	require.NoError(t, store.CompleteDeleteTasks(ctx, []uuid.UUID{Task2}))
+
+	stats, err = store.CountDeleteTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DeleteTaskStats{Failed: 1}, stats)
+
+	// NOTE: dead letters are never claimed
+	tasks, err = store.ClaimDeleteTasks(ctx, now.Add(24*time.Hour), now.Add(25*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func Test_InMemoryRepository_DeleteJobs(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	UserUUID := uuid.New()
+	Job1, Job2 := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	require.NoError(t, store.SaveDeleteJob(ctx, DeleteJob{
+		UUID: Job1, UserUUID: UserUUID, Status: "queued", ShortCodes: []string{"abcd0001", "abcd0002"}, CreatedAt: now,
+	}))
+	require.NoError(t, store.SaveDeleteJob(ctx, DeleteJob{
+		UUID: Job2, UserUUID: UserUUID, Status: "running", ShortCodes: []string{"abcd0003"}, CreatedAt: now,
+	}))
+
+	// NOTE: saving a job again replaces its state
+	require.NoError(t, store.SaveDeleteJob(ctx, DeleteJob{
+		UUID: Job1, UserUUID: UserUUID, Status: "done", ShortCodes: []string{"abcd0001", "abcd0002"},
+		Deleted: []string{"abcd0001"}, Skipped: []SkippedShortCode{{ShortCode: "abcd0002", Reason: "not_found"}},
+		CreatedAt: now, FinishedAt: now.Add(time.Minute),
+	}))
+
+	job, ok := store.GetDeleteJob(ctx, Job1)
+	require.True(t, ok)
+	assert.Equal(t, UserUUID, job.UserUUID)
+	assert.Equal(t, "done", job.Status)
+	assert.Equal(t, []string{"abcd0001", "abcd0002"}, job.ShortCodes)
+	assert.Equal(t, []string{"abcd0001"}, job.Deleted)
+	assert.Equal(t, []SkippedShortCode{{ShortCode: "abcd0002", Reason: "not_found"}}, job.Skipped)
+	assert.True(t, now.Equal(job.CreatedAt))
+	assert.True(t, now.Add(time.Minute).Equal(job.FinishedAt))
+
+	_, ok = store.GetDeleteJob(ctx, uuid.New())
+	assert.False(t, ok)
+
+	// NOTE: unfinished jobs are never purged
+	count, err := store.PurgeDeleteJobs(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, ok = store.GetDeleteJob(ctx, Job1)
+	assert.False(t, ok)
+
+	_, ok = store.GetDeleteJob(ctx, Job2)
+	assert.True(t, ok)
+}
+
+func Test_InMemoryRepository_GetClickStats(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+	day1 := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	err := store.CreateClicks(ctx, []Click{
+		{URLUUID: UUID1, IPHash: "hash1", CreatedAt: day1.Add(-48 * time.Hour)},
+		{URLUUID: UUID1, IPHash: "hash1", CreatedAt: day2},
+		{URLUUID: UUID1, IPHash: "hash2", CreatedAt: day1},
+		{URLUUID: UUID1, IPHash: "hash1", CreatedAt: day1.Add(time.Hour)},
+		{URLUUID: UUID2, IPHash: "hash3", CreatedAt: day1},
+	})
+	assert.NoError(t, err)
+
+	stats, err := store.GetClickStats(ctx, UUID1, day1.Truncate(24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, &ClickStats{
+		Total:  4,
+		Unique: 2,
+		Daily: []DailyClicks{
+			{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Total: 2, Unique: 2},
+			{Date: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), Total: 1, Unique: 1},
+		},
+	}, stats)
+
+	uuid3, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720003")
+	stats, err = store.GetClickStats(ctx, uuid3, day1)
+	assert.NoError(t, err)
+	assert.Equal(t, &ClickStats{Daily: []DailyClicks{}}, stats)
+}
+
+func Test_InMemoryRepository_NextSequence(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+
+	first, err := store.NextSequence(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), first)
+
+	second, err := store.NextSequence(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), second)
+
+	assert.Equal(t, int64(2), store.CreateMemento().Sequence)
+
+	store.Restore(&Memento{State: []URL{{ShortCode: "abcd0001"}}, Sequence: 10})
+	next, _ := store.NextSequence(ctx)
+	assert.Equal(t, int64(11), next)
+
+	store.Restore(&Memento{State: []URL{{ShortCode: "abcd0001"}, {ShortCode: "abcd0002"}}})
+	next, _ = store.NextSequence(ctx)
+	assert.Equal(t, int64(3), next)
+
+	store.Clear()
+	next, _ = store.NextSequence(ctx)
+	assert.Equal(t, int64(1), next)
+}
+
+func Test_InMemoryRepository_NextSequence_Journal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+	journal := NewMockJournal(ctrl)
+	store.SetJournal(journal)
+
+	var entries []JournalEntry
+	journal.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}).Times(2)
+	journal.EXPECT().Append(gomock.Any()).Return(errors.ErrFailedToWriteToFile)
+
+	for i := 0; i < 2; i++ {
+		_, err := store.NextSequence(ctx)
+		require.NoError(t, err)
+	}
+
+	// NOTE: a value which failed to be journaled is not handed out
+	_, err := store.NextSequence(ctx)
+	assert.ErrorIs(t, err, errors.ErrFailedToWriteToFile)
+	assert.Equal(t, int64(2), store.CreateMemento().Sequence)
+
+	// NOTE: replaying the log twice or over a newer snapshot never moves the sequence back
+	replayed := NewInMemoryRepository()
+	replayed.Restore(&Memento{Sequence: 1})
+	for i := 0; i < 2; i++ {
+		for _, entry := range entries {
+			replayed.Apply(entry)
+		}
+	}
+	assert.Equal(t, int64(2), replayed.CreateMemento().Sequence)
+
+	newer := NewInMemoryRepository()
+	newer.Restore(&Memento{Sequence: 10})
+	for _, entry := range entries {
+		newer.Apply(entry)
+	}
+	assert.Equal(t, int64(10), newer.CreateMemento().Sequence)
+}
+
 func Test_InMemoryRepository_CreateMemento(t *testing.T) {
 	ctx := context.Background()
 
 	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
 
 	type result struct {
 		memento *Memento
@@ -465,6 +1104,7 @@ func Test_InMemoryRepository_CreateMemento(t *testing.T) {
 					UUID:      UUID,
 					LongURL:   "http://example.com",
 					ShortCode: "abcd1234",
+					CreatedAt: createdAt,
 				})
 			},
 			expected: result{
@@ -474,6 +1114,7 @@ func Test_InMemoryRepository_CreateMemento(t *testing.T) {
 							UUID:      UUID,
 							LongURL:   "http://example.com",
 							ShortCode: "abcd1234",
+							CreatedAt: createdAt,
 						},
 					},
 				},
@@ -530,6 +1171,7 @@ func Test_InMemoryRepository_Restore(t *testing.T) {
 							ShortCode: "abcd1234",
 						},
 					},
+					Sequence: 1,
 				},
 				err: nil,
 			},
@@ -600,3 +1242,662 @@ func Test_InMemoryRepository_Clear(t *testing.T) {
 		})
 	}
 }
+
+func Test_InMemoryRepository_Journal(t *testing.T) {
+	ctx := context.Background()
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	url := URL{
+		UUID:      UUID,
+		LongURL:   "https://google.com",
+		ShortCode: "abcd0001",
+		UserUUID:  UserUUID,
+		CreatedAt: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		before   func(store InMemory, journal *MockJournal)
+		action   func(store InMemory) error
+		expected error
+		check    func(t *testing.T, store InMemory)
+	}{
+		{
+			name: "Create recorded",
+			before: func(_ InMemory, journal *MockJournal) {
+				journal.EXPECT().Append(JournalEntry{Op: OpCreate, URLs: []URL{url}}).Return(nil)
+			},
+			action: func(store InMemory) error {
+				_, err := store.CreateURL(ctx, url)
+				return err
+			},
+			expected: nil,
+			check: func(t *testing.T, store InMemory) {
+				_, ok := store.GetURLByShortCode(ctx, url.ShortCode)
+				assert.True(t, ok)
+			},
+		},
+		{
+			name: "Create not applied when journal fails",
+			before: func(_ InMemory, journal *MockJournal) {
+				journal.EXPECT().Append(gomock.Any()).Return(errors.ErrFailedToWriteToFile)
+			},
+			action: func(store InMemory) error {
+				_, err := store.CreateURLs(ctx, []URL{url})
+				return err
+			},
+			expected: errors.ErrFailedToWriteToFile,
+			check: func(t *testing.T, store InMemory) {
+				_, ok := store.GetURLByShortCode(ctx, url.ShortCode)
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "Update recorded",
+			before: func(store InMemory, journal *MockJournal) {
+				store.Apply(JournalEntry{Op: OpCreate, URLs: []URL{url}})
+				journal.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry JournalEntry) error {
+					assert.Equal(t, OpUpdate, entry.Op)
+					assert.Equal(t, "https://github.com", entry.URLs[0].LongURL)
+					return nil
+				})
+			},
+			action: func(store InMemory) error {
+				_, err := store.UpdateURL(ctx, URL{ShortCode: url.ShortCode, UserUUID: UserUUID, LongURL: "https://github.com"})
+				return err
+			},
+			expected: nil,
+			check: func(t *testing.T, store InMemory) {
+				record, ok := store.GetURLByShortCode(ctx, url.ShortCode)
+				assert.True(t, ok)
+				assert.Equal(t, "https://github.com", record.LongURL)
+			},
+		},
+		{
+			name: "Delete not applied when journal fails",
+			before: func(store InMemory, journal *MockJournal) {
+				store.Apply(JournalEntry{Op: OpCreate, URLs: []URL{url}})
+				journal.EXPECT().Append(gomock.Any()).Return(errors.ErrFailedToWriteToFile)
+			},
+			action: func(store InMemory) error {
+				_, err := store.DeleteURLsByUserID(ctx, UserUUID, []string{url.ShortCode})
+				return err
+			},
+			expected: errors.ErrFailedToWriteToFile,
+			check: func(t *testing.T, store InMemory) {
+				record, ok := store.GetURLByShortCode(ctx, url.ShortCode)
+				assert.True(t, ok)
+				assert.True(t, record.DeletedAt.IsZero())
+			},
+		},
+		{
+			name: "Restore recorded",
+			before: func(store InMemory, journal *MockJournal) {
+				store.Apply(JournalEntry{Op: OpCreate, URLs: []URL{url}})
+				store.Apply(JournalEntry{Op: OpDelete, UserUUID: UserUUID, ShortCodes: []string{url.ShortCode}, DeletedAt: time.Now()})
+				journal.EXPECT().Append(JournalEntry{Op: OpRestore, UserUUID: UserUUID, ShortCodes: []string{url.ShortCode}}).Return(nil)
+			},
+			action: func(store InMemory) error {
+				_, err := store.RestoreURLsByUserID(ctx, UserUUID, []string{url.ShortCode}, time.Now().Add(-time.Hour))
+				return err
+			},
+			expected: nil,
+			check: func(t *testing.T, store InMemory) {
+				record, ok := store.GetURLByShortCode(ctx, url.ShortCode)
+				assert.True(t, ok)
+				assert.True(t, record.DeletedAt.IsZero())
+			},
+		},
+		{
+			name: "Purge not applied when journal fails",
+			before: func(store InMemory, journal *MockJournal) {
+				store.Apply(JournalEntry{Op: OpCreate, URLs: []URL{url}})
+				store.Apply(JournalEntry{Op: OpDelete, UserUUID: UserUUID, ShortCodes: []string{url.ShortCode}, DeletedAt: url.CreatedAt})
+				journal.EXPECT().Append(JournalEntry{Op: OpPurge, ShortCodes: []string{url.ShortCode}}).Return(errors.ErrFailedToWriteToFile)
+			},
+			action: func(store InMemory) error {
+				_, err := store.PurgeURLs(ctx, time.Now())
+				return err
+			},
+			expected: errors.ErrFailedToWriteToFile,
+			check: func(t *testing.T, store InMemory) {
+				_, ok := store.GetURLByShortCode(ctx, url.ShortCode)
+				assert.True(t, ok)
+			},
+		},
+		{
+			name:   "Nothing to delete is not recorded",
+			before: func(_ InMemory, _ *MockJournal) {},
+			action: func(store InMemory) error {
+				_, err := store.DeleteURLsByUserID(ctx, UserUUID, []string{url.ShortCode})
+				return err
+			},
+			expected: nil,
+			check:    func(_ *testing.T, _ InMemory) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := NewInMemoryRepository()
+			journal := NewMockJournal(ctrl)
+			tt.before(store, journal)
+			store.SetJournal(journal)
+
+			err := tt.action(store)
+			assert.Equal(t, tt.expected, err)
+
+			tt.check(t, store)
+		})
+	}
+}
+
+func Test_InMemoryRepository_Apply(t *testing.T) {
+	ctx := context.Background()
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	deletedAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	entries := []JournalEntry{
+		{Op: OpCreate, URLs: []URL{{LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID}}},
+		{Op: OpUpdate, URLs: []URL{{LongURL: "https://github.com", ShortCode: "abcd0001", UserUUID: UserUUID}}},
+		{Op: OpCreate, URLs: []URL{{LongURL: "https://example.com", ShortCode: "abcd0002", UserUUID: UserUUID}}},
+		{Op: OpDelete, UserUUID: UserUUID, ShortCodes: []string{"abcd0002"}, DeletedAt: deletedAt},
+		{Op: OpCreate, URLs: []URL{{LongURL: "https://example.org", ShortCode: "abcd0003", UserUUID: UserUUID}}},
+		{Op: OpDelete, UserUUID: UserUUID, ShortCodes: []string{"abcd0001", "abcd0003"}, DeletedAt: deletedAt},
+		{Op: OpRestore, UserUUID: UserUUID, ShortCodes: []string{"abcd0001"}},
+		{Op: OpPurge, ShortCodes: []string{"abcd0003"}},
+	}
+
+	store := NewInMemoryRepository()
+
+	// NOTE: a log replayed over a snapshot which already contains its entries leaves the state unchanged
+	for i := 0; i < 2; i++ {
+		for _, entry := range entries {
+			store.Apply(entry)
+		}
+	}
+
+	first, ok := store.GetURLByShortCode(ctx, "abcd0001")
+	assert.True(t, ok)
+	assert.Equal(t, "https://github.com", first.LongURL)
+
+	assert.True(t, first.DeletedAt.IsZero())
+
+	second, ok := store.GetURLByShortCode(ctx, "abcd0002")
+	assert.True(t, ok)
+	assert.Equal(t, deletedAt, second.DeletedAt)
+
+	_, ok = store.GetURLByShortCode(ctx, "abcd0003")
+	assert.False(t, ok)
+}
+
+func Test_InMemoryRepository_APITokens(t *testing.T) {
+	ctx := context.Background()
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	OtherUserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+	TokenUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+
+	store := NewInMemoryRepository()
+
+	created, err := store.CreateAPIToken(ctx, APIToken{
+		UUID:     TokenUUID,
+		UserUUID: UserUUID,
+		Name:     "ci",
+		Prefix:   "shk_abcdefgh",
+		Hash:     "hash",
+		Scopes:   []string{"links:read"},
+	})
+	require.NoError(t, err)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	token, ok := store.GetAPITokenByHash(ctx, "hash")
+	assert.True(t, ok)
+	assert.Equal(t, created, token)
+
+	_, ok = store.GetAPITokenByHash(ctx, "unknown")
+	assert.False(t, ok)
+
+	tokens, err := store.GetAPITokensByUserID(ctx, UserUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, []APIToken{*created}, tokens)
+
+	tokens, err = store.GetAPITokensByUserID(ctx, OtherUserUUID)
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	// NOTE: tokens survive a snapshot round trip
+	restored := NewInMemoryRepository()
+	restored.Restore(store.CreateMemento())
+	_, ok = restored.GetAPITokenByHash(ctx, "hash")
+	assert.True(t, ok)
+
+	err = store.RevokeAPIToken(ctx, OtherUserUUID, TokenUUID)
+	assert.ErrorIs(t, err, errors.ErrAPITokenNotFound)
+
+	err = store.RevokeAPIToken(ctx, UserUUID, TokenUUID)
+	assert.NoError(t, err)
+
+	err = store.RevokeAPIToken(ctx, UserUUID, TokenUUID)
+	assert.ErrorIs(t, err, errors.ErrAPITokenNotFound)
+
+	_, ok = store.GetAPITokenByHash(ctx, "hash")
+	assert.False(t, ok)
+
+	tokens, err = store.GetAPITokensByUserID(ctx, UserUUID)
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+}
+
+func Test_InMemoryRepository_APITokens_Journal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	TokenUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	token := APIToken{UUID: TokenUUID, UserUUID: UserUUID, Name: "ci", Hash: "hash"}
+
+	store := NewInMemoryRepository()
+	journal := NewMockJournal(ctrl)
+	store.SetJournal(journal)
+
+	var entries []JournalEntry
+	journal.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}).Times(2)
+
+	_, err := store.CreateAPIToken(ctx, token)
+	require.NoError(t, err)
+	require.NoError(t, store.RevokeAPIToken(ctx, UserUUID, TokenUUID))
+
+	assert.Equal(t, OpCreateToken, entries[0].Op)
+	assert.Equal(t, OpRevokeToken, entries[1].Op)
+	assert.False(t, entries[1].Tokens[0].RevokedAt.IsZero())
+
+	journal.EXPECT().Append(gomock.Any()).Return(errors.ErrFailedToWriteToFile)
+	_, err = store.CreateAPIToken(ctx, APIToken{UUID: uuid.New(), UserUUID: UserUUID, Hash: "other"})
+	assert.ErrorIs(t, err, errors.ErrFailedToWriteToFile)
+
+	_, ok := store.GetAPITokenByHash(ctx, "other")
+	assert.False(t, ok)
+
+	// NOTE: replaying the log leaves the token revoked
+	replayed := NewInMemoryRepository()
+	for i := 0; i < 2; i++ {
+		for _, entry := range entries {
+			replayed.Apply(entry)
+		}
+	}
+
+	_, ok = replayed.GetAPITokenByHash(ctx, "hash")
+	assert.False(t, ok)
+}
+
+func Test_InMemoryRepository_DeleteTasks_Journal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	store := NewInMemoryRepository()
+	journal := NewMockJournal(ctrl)
+	store.SetJournal(journal)
+
+	var entries []JournalEntry
+	journal.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}).AnyTimes()
+
+	Task1, Task2 := uuid.New(), uuid.New()
+	require.NoError(t, store.EnqueueDeleteTask(ctx, DeleteTask{UUID: Task1, ShortCodes: []string{"abcd0001"}}))
+	require.NoError(t, store.EnqueueDeleteTask(ctx, DeleteTask{UUID: Task2, ShortCodes: []string{"abcd0002"}}))
+	_, err := store.ClaimDeleteTasks(ctx, now.Add(time.Second), now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.NoError(t, store.FailDeleteTask(ctx, Task1, "timeout"))
+	require.NoError(t, store.CompleteDeleteTasks(ctx, []uuid.UUID{Task2}))
+
+	assert.Equal(t, []string{OpEnqueueTask, OpEnqueueTask, OpUpdateTask, OpUpdateTask, OpCompleteTask}, []string{
+		entries[0].Op, entries[1].Op, entries[2].Op, entries[3].Op, entries[4].Op,
+	})
+
+	// NOTE: replaying the log twice keeps the completed task removed and the failed one dead-lettered
+	replayed := NewInMemoryRepository()
+	for i := 0; i < 2; i++ {
+		for _, entry := range entries {
+			replayed.Apply(entry)
+		}
+	}
+
+	stats, err := replayed.CountDeleteTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DeleteTaskStats{Failed: 1}, stats)
+
+	memento := replayed.CreateMemento()
+	require.Len(t, memento.Tasks, 1)
+	assert.Equal(t, Task1, memento.Tasks[0].UUID)
+	assert.Equal(t, 1, memento.Tasks[0].Attempts)
+	assert.Equal(t, "timeout", memento.Tasks[0].LastError)
+
+	restored := NewInMemoryRepository()
+	restored.Restore(memento)
+
+	stats, err = restored.CountDeleteTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DeleteTaskStats{Failed: 1}, stats)
+
+}
+
+func Test_InMemoryRepository_DeleteTasks_JournalFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	store := NewInMemoryRepository()
+	journal := NewMockJournal(ctrl)
+	store.SetJournal(journal)
+
+	journal.EXPECT().Append(gomock.Any()).Return(errors.ErrFailedToWriteToFile)
+
+	err := store.EnqueueDeleteTask(ctx, DeleteTask{UUID: uuid.New(), ShortCodes: []string{"abcd0001"}})
+	assert.ErrorIs(t, err, errors.ErrFailedToWriteToFile)
+
+	stats, err := store.CountDeleteTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DeleteTaskStats{}, stats)
+}
+
+func Test_InMemoryRepository_DeleteJobs_Journal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	store := NewInMemoryRepository()
+	journal := NewMockJournal(ctrl)
+	store.SetJournal(journal)
+
+	var entries []JournalEntry
+	journal.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}).AnyTimes()
+
+	Job1, Job2 := uuid.New(), uuid.New()
+	require.NoError(t, store.SaveDeleteJob(ctx, DeleteJob{UUID: Job1, Status: "done", CreatedAt: now, FinishedAt: now}))
+	require.NoError(t, store.SaveDeleteJob(ctx, DeleteJob{UUID: Job2, Status: "queued", CreatedAt: now}))
+	require.NoError(t, store.SaveDeleteJob(ctx, DeleteJob{UUID: Job2, Status: "done", CreatedAt: now, FinishedAt: now.Add(time.Hour)}))
+	_, err := store.PurgeDeleteJobs(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{OpSaveJob, OpSaveJob, OpSaveJob, OpPurgeJobs}, []string{
+		entries[0].Op, entries[1].Op, entries[2].Op, entries[3].Op,
+	})
+
+	// NOTE: replaying the log twice keeps the purged job removed and the last state of the other one
+	replayed := NewInMemoryRepository()
+	for i := 0; i < 2; i++ {
+		for _, entry := range entries {
+			replayed.Apply(entry)
+		}
+	}
+
+	_, ok := replayed.GetDeleteJob(ctx, Job1)
+	assert.False(t, ok)
+
+	memento := replayed.CreateMemento()
+	require.Len(t, memento.Jobs, 1)
+	assert.Equal(t, Job2, memento.Jobs[0].UUID)
+	assert.Equal(t, "done", memento.Jobs[0].Status)
+
+	restored := NewInMemoryRepository()
+	restored.Restore(memento)
+
+	job, ok := restored.GetDeleteJob(ctx, Job2)
+	require.True(t, ok)
+	assert.Equal(t, "done", job.Status)
+}
+
+func Test_InMemoryRepository_FindURLsByUserID(t *testing.T) {
+	ctx := context.Background()
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	OtherUserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+	day1 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	store := NewInMemoryRepository()
+	store.Apply(JournalEntry{Op: OpCreate, URLs: []URL{
+		{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID, CreatedAt: day1},
+		{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID, CreatedAt: day2},
+		{UUID: uuid.New(), LongURL: "https://example.com/100%_off", ShortCode: "abcd0003", UserUUID: UserUUID, CreatedAt: day3},
+		{UUID: uuid.New(), LongURL: "https://deleted.com", ShortCode: "abcd0004", UserUUID: UserUUID, CreatedAt: day2, DeletedAt: day3},
+		{UUID: uuid.New(), LongURL: "https://expired.com", ShortCode: "abcd0005", UserUUID: UserUUID, CreatedAt: day2, Expired: true},
+		{UUID: uuid.New(), LongURL: "https://other.com", ShortCode: "abcd0006", UserUUID: OtherUserUUID, CreatedAt: day2},
+	}})
+
+	first, _ := store.GetURLByShortCode(ctx, "abcd0001")
+	third, _ := store.GetURLByShortCode(ctx, "abcd0003")
+	require.NoError(t, store.CreateClicks(ctx, []Click{
+		{URLUUID: first.UUID, IPHash: "a"},
+		{URLUUID: first.UUID, IPHash: "b"},
+		{URLUUID: third.UUID, IPHash: "a"},
+	}))
+
+	type result struct {
+		shortCodes []string
+		total      int
+	}
+
+	tests := []struct {
+		name     string
+		filter   URLFilter
+		expected result
+	}{
+		{
+			name:     "Newest first by default",
+			filter:   URLFilter{Limit: 25},
+			expected: result{shortCodes: []string{"abcd0003", "abcd0002", "abcd0001"}, total: 3},
+		},
+		{
+			name:     "Oldest first",
+			filter:   URLFilter{Sort: SortCreatedAt, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0001", "abcd0002", "abcd0003"}, total: 3},
+		},
+		{
+			name:     "Most clicked first",
+			filter:   URLFilter{Sort: SortClicksDesc, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0001", "abcd0003", "abcd0002"}, total: 3},
+		},
+		{
+			name:     "Least clicked first",
+			filter:   URLFilter{Sort: SortClicks, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0002", "abcd0003", "abcd0001"}, total: 3},
+		},
+		{
+			name:     "Case-insensitive search",
+			filter:   URLFilter{Query: "GITHUB", Limit: 25},
+			expected: result{shortCodes: []string{"abcd0002"}, total: 1},
+		},
+		{
+			name:     "Search by short code",
+			filter:   URLFilter{Query: "0001", Limit: 25},
+			expected: result{shortCodes: []string{"abcd0001"}, total: 1},
+		},
+		{
+			name:     "Wildcards match literally",
+			filter:   URLFilter{Query: "%_", Limit: 25},
+			expected: result{shortCodes: []string{"abcd0003"}, total: 1},
+		},
+		{
+			name:     "Deleted",
+			filter:   URLFilter{Deleted: true, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0004"}, total: 1},
+		},
+		{
+			name:     "Expired",
+			filter:   URLFilter{Expired: true, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0005"}, total: 1},
+		},
+		{
+			name:     "Created after",
+			filter:   URLFilter{CreatedAfter: day2, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0003", "abcd0002"}, total: 2},
+		},
+		{
+			name:     "Created before",
+			filter:   URLFilter{CreatedBefore: day2, Limit: 25},
+			expected: result{shortCodes: []string{"abcd0001"}, total: 1},
+		},
+		{
+			name:     "Last page",
+			filter:   URLFilter{Limit: 2, Offset: 2},
+			expected: result{shortCodes: []string{"abcd0001"}, total: 3},
+		},
+		{
+			name:     "Page beyond the last",
+			filter:   URLFilter{Limit: 2, Offset: 4},
+			expected: result{shortCodes: []string{}, total: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.UserUUID = UserUUID
+
+			items, total, err := store.FindURLsByUserID(ctx, tt.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.total, total)
+
+			shortCodes := make([]string, 0, len(items))
+			for _, item := range items {
+				shortCodes = append(shortCodes, item.ShortCode)
+			}
+			assert.Equal(t, tt.expected.shortCodes, shortCodes)
+		})
+	}
+
+	items, _, err := store.FindURLsByUserID(ctx, URLFilter{UserUUID: UserUUID, Query: "google", Limit: 25})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), items[0].Clicks)
+}
+
+func Test_InMemoryRepository_GetURLsByUserIDAfter(t *testing.T) {
+	ctx := context.Background()
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewInMemoryRepository()
+	store.Apply(JournalEntry{Op: OpCreate, URLs: []URL{
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720001"), LongURL: "https://a.com", ShortCode: "abcd0001", UserUUID: UserUUID, CreatedAt: createdAt},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720002"), LongURL: "https://b.com", ShortCode: "abcd0002", UserUUID: UserUUID, CreatedAt: createdAt},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720003"), LongURL: "https://c.com", ShortCode: "abcd0003", UserUUID: UserUUID, CreatedAt: createdAt.Add(time.Hour)},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720004"), LongURL: "https://d.com", ShortCode: "abcd0004", UserUUID: UserUUID, CreatedAt: createdAt.Add(-time.Hour)},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720005"), LongURL: "https://e.com", ShortCode: "abcd0005", UserUUID: UserUUID, CreatedAt: createdAt, DeletedAt: createdAt},
+	}})
+
+	first, _, err := store.GetURLsByUserID(ctx, UserUUID, 2, 0)
+	require.NoError(t, err)
+
+	// NOTE: a link added in the middle of the walk is newer than the cursor and neither shifts nor repeats pages
+	_, err = store.CreateURL(ctx, URL{UUID: uuid.New(), LongURL: "https://new.com", ShortCode: "abcd0006", UserUUID: UserUUID})
+	require.NoError(t, err)
+
+	shortCodes := []string{first[0].ShortCode, first[1].ShortCode}
+	last := first[len(first)-1]
+
+	for {
+		page, err := store.GetURLsByUserIDAfter(ctx, UserUUID, URLCursor{CreatedAt: last.CreatedAt, UUID: last.UUID}, 2)
+		require.NoError(t, err)
+
+		if len(page) == 0 {
+			break
+		}
+
+		for _, url := range page {
+			shortCodes = append(shortCodes, url.ShortCode)
+		}
+		last = page[len(page)-1]
+	}
+
+	assert.Equal(t, []string{"abcd0003", "abcd0002", "abcd0001", "abcd0004"}, shortCodes)
+}
+
+func Test_InMemoryRepository_GetURLsByLongURLs(t *testing.T) {
+	ctx := context.Background()
+
+	store := NewInMemoryRepository()
+	_, err := store.CreateURLs(ctx, []URL{
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720001"), LongURL: "https://a.com", ShortCode: "abcd0001"},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720002"), LongURL: "https://b.com", ShortCode: "abcd0002"},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720003"), LongURL: "https://c.com", ShortCode: "abcd0003"},
+	})
+	require.NoError(t, err)
+
+	urls, err := store.GetURLsByLongURLs(ctx, []string{"https://a.com", "https://c.com", "https://d.com"})
+	require.NoError(t, err)
+
+	shortCodes := make([]string, 0, len(urls))
+	for _, url := range urls {
+		shortCodes = append(shortCodes, url.ShortCode)
+	}
+	assert.ElementsMatch(t, []string{"abcd0001", "abcd0003"}, shortCodes)
+
+	urls, err = store.GetURLsByLongURLs(ctx, []string{})
+	require.NoError(t, err)
+	assert.Empty(t, urls)
+}
+
+func Test_InMemoryRepository_ExportURLsByUserID(t *testing.T) {
+	ctx := context.Background()
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewInMemoryRepository()
+	store.Apply(JournalEntry{Op: OpCreate, URLs: []URL{
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720001"), LongURL: "https://a.com", ShortCode: "abcd0001", UserUUID: UserUUID, CreatedAt: createdAt},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720002"), LongURL: "https://b.com", ShortCode: "abcd0002", UserUUID: UserUUID, CreatedAt: createdAt, DeletedAt: createdAt},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720003"), LongURL: "https://c.com", ShortCode: "abcd0003", UserUUID: UserUUID, CreatedAt: createdAt.Add(time.Hour), Expired: true},
+		{UUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720004"), LongURL: "https://d.com", ShortCode: "abcd0004", UserUUID: uuid.New(), CreatedAt: createdAt},
+	}})
+	require.NoError(t, store.CreateClicks(ctx, []Click{
+		{URLUUID: uuid.MustParse("6455bd07-e431-4851-af3c-4f703f720001"), CreatedAt: createdAt},
+	}))
+
+	var shortCodes []string
+	var clicks []int64
+	var cursor URLCursor
+
+	for {
+		page, err := store.ExportURLsByUserID(ctx, UserUUID, cursor, 2)
+		require.NoError(t, err)
+
+		if len(page) == 0 {
+			break
+		}
+
+		for _, item := range page {
+			shortCodes = append(shortCodes, item.ShortCode)
+			clicks = append(clicks, item.Clicks)
+		}
+		last := page[len(page)-1]
+		cursor = URLCursor{CreatedAt: last.CreatedAt, UUID: last.UUID}
+	}
+
+	assert.Equal(t, []string{"abcd0003", "abcd0002", "abcd0001"}, shortCodes)
+	assert.Equal(t, []int64{0, 0, 1}, clicks)
+}
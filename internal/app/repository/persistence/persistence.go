@@ -1,11 +1,20 @@
 package persistence
 
 import (
+	"context"
+
 	"shortly/internal/app/config"
 	"shortly/internal/app/repository"
+	"shortly/internal/app/worker"
 	"shortly/internal/logger"
 )
 
+// CompactionJob is the name of the write-ahead log compaction job in the scheduler
+const CompactionJob = "file_compaction"
+
+// WALSyncJob is the name of the write-ahead log fsync job in the scheduler
+const WALSyncJob = "wal_sync"
+
 // Manager is an interface for the persistence manager
 type Manager interface {
 	Load() error
@@ -15,15 +24,17 @@ type Manager interface {
 // noOpManager is a no-op persistence manager
 type noOpManager struct{}
 
-// manager is a persistence manager
+// manager is a persistence manager, the repository state is kept in a snapshot file and a write-ahead log
 type manager struct {
 	repo      repository.InMemory
 	file      repository.File
+	wal       repository.WAL
 	appLogger *logger.Logger
 }
 
-// NewPersistenceManager creates a new persistence manager instance
-func NewPersistenceManager(cfg *config.Config, repo repository.Repository, logger *logger.Logger) Manager {
+// NewPersistenceManager creates a new persistence manager instance, the periodic compaction and the write-ahead log
+// fsyncs of the "interval" policy are registered as jobs of the scheduler
+func NewPersistenceManager(cfg *config.Config, repo repository.Repository, scheduler worker.Scheduler, logger *logger.Logger) Manager {
 	inMemoryRepo, ok := repo.(repository.InMemory)
 	if !ok {
 		logger.Warn().Msg("Persistence manager initialization skipped, not an in-memory repository")
@@ -36,13 +47,25 @@ func NewPersistenceManager(cfg *config.Config, repo repository.Repository, logge
 	}
 
 	fileRepo := repository.NewFileRepository(cfg.FileStoragePath)
+	wal := repository.NewWAL(cfg.FileStoragePath+repository.WALSuffix, cfg.FileSyncPolicy)
 	logger.Info().Msg("Persistence manager is initialized with " + cfg.FileStoragePath)
 
-	return &manager{
+	pm := &manager{
 		repo:      inMemoryRepo,
 		file:      fileRepo,
+		wal:       wal,
 		appLogger: logger,
 	}
+
+	if cfg.FileCompactionInterval > 0 {
+		scheduler.Schedule(worker.Every(cfg.FileCompactionInterval), worker.NewJob(CompactionJob, pm.compact))
+	}
+
+	if cfg.FileSyncPolicy == repository.SyncInterval && cfg.FileSyncInterval > 0 {
+		scheduler.Schedule(worker.Every(cfg.FileSyncInterval), worker.NewJob(WALSyncJob, pm.sync))
+	}
+
+	return pm
 }
 
 // Load stub implementation for no-op manager
@@ -50,7 +73,7 @@ func (n *noOpManager) Load() error {
 	return nil
 }
 
-// Load loads data from file and restores the repository state
+// Load restores the snapshot, replays the write-ahead log and starts journaling repository mutations
 func (pm *manager) Load() error {
 	snapshot, err := pm.file.Load()
 
@@ -60,6 +83,12 @@ func (pm *manager) Load() error {
 		pm.appLogger.Error().Err(err).Msg("Failed to load data from file")
 	}
 
+	if err = pm.wal.Replay(pm.repo.Apply); err != nil {
+		pm.appLogger.Error().Err(err).Msg("Failed to replay write-ahead log")
+	}
+
+	pm.repo.SetJournal(pm.wal)
+
 	return nil
 }
 
@@ -68,13 +97,30 @@ func (n *noOpManager) Save() error {
 	return nil
 }
 
-// Save saves the repository state to the file
+// Save compacts the write-ahead log into the snapshot and closes it, it is called once the scheduler is stopped
 func (pm *manager) Save() error {
-	snapshot := pm.repo.CreateMemento()
-
-	if err := pm.file.Save(snapshot); err != nil {
+	if err := pm.compact(context.Background()); err != nil {
 		pm.appLogger.Error().Err(err).Msg("Failed to save data to file")
 	}
 
+	if err := pm.wal.Close(); err != nil {
+		pm.appLogger.Error().Err(err).Msg("Failed to close write-ahead log")
+	}
+
 	return nil
 }
+
+// compact writes the repository state to the snapshot file and truncates the write-ahead log, repository writes
+// wait until both are done so that no acknowledged entry is truncated before it is in the snapshot
+func (pm *manager) compact(_ context.Context) error {
+	return pm.repo.Snapshot(func(memento *repository.Memento) error {
+		return pm.wal.Compact(func() error {
+			return pm.file.Save(memento)
+		})
+	})
+}
+
+// sync flushes the write-ahead log to the disk
+func (pm *manager) sync(_ context.Context) error {
+	return pm.wal.Sync()
+}
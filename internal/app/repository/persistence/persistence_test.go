@@ -2,17 +2,22 @@ package persistence
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"shortly/internal/app/config"
 	"shortly/internal/app/errors"
 	"shortly/internal/app/repository"
+	"shortly/internal/app/worker"
 	"shortly/internal/logger"
 	"shortly/internal/spec"
 )
@@ -89,13 +94,74 @@ func Test_NewPersistenceManager(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pm := NewPersistenceManager(tt.cfg, tt.repo, appLogger)
+			pm := NewPersistenceManager(tt.cfg, tt.repo, worker.NewScheduler(ctx, tt.cfg, appLogger), appLogger)
 			assert.NotNil(t, pm)
 			assert.IsType(t, tt.expectedType, pm)
 		})
 	}
 }
 
+func Test_NewPersistenceManager_Jobs(t *testing.T) {
+	filePath := t.TempDir() + "/store-test.json"
+	appLogger := logger.NewLogger()
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		expected int
+	}{
+		{
+			name: "Compaction and interval sync",
+			cfg: &config.Config{
+				FileStoragePath:        filePath,
+				FileSyncPolicy:         repository.SyncInterval,
+				FileSyncInterval:       time.Second,
+				FileCompactionInterval: time.Minute,
+			},
+			expected: 2,
+		},
+		{
+			name: "Compaction only",
+			cfg: &config.Config{
+				FileStoragePath:        filePath,
+				FileSyncPolicy:         repository.SyncAlways,
+				FileSyncInterval:       time.Second,
+				FileCompactionInterval: time.Minute,
+			},
+			expected: 1,
+		},
+		{
+			name: "Disabled",
+			cfg: &config.Config{
+				FileStoragePath: filePath,
+				FileSyncPolicy:  repository.SyncNever,
+			},
+			expected: 0,
+		},
+		{
+			name: "Without file path",
+			cfg: &config.Config{
+				FileSyncPolicy:         repository.SyncInterval,
+				FileSyncInterval:       time.Second,
+				FileCompactionInterval: time.Minute,
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			scheduler := worker.NewMockScheduler(ctrl)
+			scheduler.EXPECT().Schedule(gomock.Any(), gomock.Any()).Times(tt.expected)
+
+			NewPersistenceManager(tt.cfg, repository.NewInMemoryRepository(), scheduler, appLogger)
+		})
+	}
+}
+
 func Test_noOpManager_Load(t *testing.T) {
 	n := &noOpManager{}
 	err := n.Load()
@@ -118,6 +184,7 @@ func Test_PersistenceManager_Load(t *testing.T) {
 	}
 	mockRepo := repository.NewMockInMemory(ctrl)
 	mockFileRepo := repository.NewMockFile(ctrl)
+	mockWAL := repository.NewMockWAL(ctrl)
 	appLogger := logger.NewLogger()
 
 	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
@@ -141,13 +208,25 @@ func Test_PersistenceManager_Load(t *testing.T) {
 			before: func() {
 				mockFileRepo.EXPECT().Load().Return(snapshot, nil)
 				mockRepo.EXPECT().Restore(snapshot)
+				mockWAL.EXPECT().Replay(gomock.Any()).Return(nil)
+				mockRepo.EXPECT().SetJournal(mockWAL)
 			},
 		},
 		{
 			name: "Failure",
 			before: func() {
 				mockFileRepo.EXPECT().Load().Return(nil, errors.ErrFailedToOpenFile)
-				appLogger.Error().Err(errors.ErrFailedToOpenFile).Msg("Failed to load data from file")
+				mockWAL.EXPECT().Replay(gomock.Any()).Return(nil)
+				mockRepo.EXPECT().SetJournal(mockWAL)
+			},
+		},
+		{
+			name: "Replay failure",
+			before: func() {
+				mockFileRepo.EXPECT().Load().Return(snapshot, nil)
+				mockRepo.EXPECT().Restore(snapshot)
+				mockWAL.EXPECT().Replay(gomock.Any()).Return(errors.ErrorFailedToReadFromFile)
+				mockRepo.EXPECT().SetJournal(mockWAL)
 			},
 		},
 	}
@@ -159,6 +238,7 @@ func Test_PersistenceManager_Load(t *testing.T) {
 			pm := &manager{
 				repo:      mockRepo,
 				file:      mockFileRepo,
+				wal:       mockWAL,
 				appLogger: appLogger,
 			}
 			err := pm.Load()
@@ -182,6 +262,7 @@ func Test_PersistenceManager_Save(t *testing.T) {
 	}
 	mockRepo := repository.NewMockInMemory(ctrl)
 	mockFileRepo := repository.NewMockFile(ctrl)
+	mockWAL := repository.NewMockWAL(ctrl)
 	appLogger := logger.NewLogger()
 
 	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
@@ -203,16 +284,19 @@ func Test_PersistenceManager_Save(t *testing.T) {
 		{
 			name: "Success",
 			before: func() {
-				mockRepo.EXPECT().CreateMemento().Return(snapshot)
+				mockRepo.EXPECT().Snapshot(gomock.Any()).DoAndReturn(func(fn func(*repository.Memento) error) error { return fn(snapshot) })
+				mockWAL.EXPECT().Compact(gomock.Any()).DoAndReturn(func(fn func() error) error { return fn() })
 				mockFileRepo.EXPECT().Save(snapshot).Return(nil)
+				mockWAL.EXPECT().Close().Return(nil)
 			},
 		},
 		{
 			name: "Failure",
 			before: func() {
-				mockRepo.EXPECT().CreateMemento().Return(snapshot)
+				mockRepo.EXPECT().Snapshot(gomock.Any()).DoAndReturn(func(fn func(*repository.Memento) error) error { return fn(snapshot) })
+				mockWAL.EXPECT().Compact(gomock.Any()).DoAndReturn(func(fn func() error) error { return fn() })
 				mockFileRepo.EXPECT().Save(snapshot).Return(errors.ErrFailedToOpenFile)
-				appLogger.Error().Err(errors.ErrFailedToOpenFile).Msg("Failed to save data to file")
+				mockWAL.EXPECT().Close().Return(nil)
 			},
 		},
 	}
@@ -224,6 +308,7 @@ func Test_PersistenceManager_Save(t *testing.T) {
 			pm := &manager{
 				repo:      mockRepo,
 				file:      mockFileRepo,
+				wal:       mockWAL,
 				appLogger: appLogger,
 			}
 			err := pm.Save()
@@ -236,3 +321,271 @@ func Test_PersistenceManager_Save(t *testing.T) {
 		})
 	}
 }
+
+func Test_PersistenceManager_Recovery(t *testing.T) {
+	ctx := context.Background()
+	filePath := t.TempDir() + "/store-test.json"
+	appLogger := logger.NewLogger()
+
+	tests := []struct {
+		name       string
+		syncPolicy string
+	}{
+		{
+			name:       "Sync always",
+			syncPolicy: repository.SyncAlways,
+		},
+		{
+			name:       "Sync interval",
+			syncPolicy: repository.SyncInterval,
+		},
+		{
+			name:       "Sync never",
+			syncPolicy: repository.SyncNever,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				FileStoragePath:        filePath,
+				FileSyncPolicy:         tt.syncPolicy,
+				FileSyncInterval:       time.Millisecond,
+				FileCompactionInterval: time.Hour,
+			}
+			userUUID := uuid.New()
+
+			repo := repository.NewInMemoryRepository()
+			pm := NewPersistenceManager(cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+			require.NoError(t, pm.Load())
+
+			_, err := repo.CreateURL(ctx, repository.URL{UUID: uuid.New(), LongURL: "http://a.com", ShortCode: "aaaa", UserUUID: userUUID})
+			require.NoError(t, err)
+			_, err = repo.CreateURLs(ctx, []repository.URL{
+				{UUID: uuid.New(), LongURL: "http://b.com", ShortCode: "bbbb", UserUUID: userUUID},
+				{UUID: uuid.New(), LongURL: "http://c.com", ShortCode: "cccc", UserUUID: userUUID},
+			})
+			require.NoError(t, err)
+			_, err = repo.DeleteURLsByUserID(ctx, userUUID, []string{"bbbb"})
+			require.NoError(t, err)
+			taskUUID := uuid.New()
+			require.NoError(t, repo.EnqueueDeleteTask(ctx, repository.DeleteTask{UUID: taskUUID, UserUUID: userUUID, ShortCodes: []string{"cccc"}}))
+
+			// NOTE: the manager is abandoned without Save to simulate a crash
+			recovered := repository.NewInMemoryRepository()
+			require.NoError(t, NewPersistenceManager(cfg, recovered, worker.NewScheduler(ctx, cfg, appLogger), appLogger).Load())
+
+			a, ok := recovered.GetURLByShortCode(ctx, "aaaa")
+			require.True(t, ok)
+			assert.Equal(t, "http://a.com", a.LongURL)
+
+			b, ok := recovered.GetURLByShortCode(ctx, "bbbb")
+			require.True(t, ok)
+			assert.False(t, b.DeletedAt.IsZero())
+
+			_, ok = recovered.GetURLByShortCode(ctx, "cccc")
+			assert.True(t, ok)
+
+			tasks, err := recovered.ClaimDeleteTasks(ctx, time.Now(), time.Now().Add(time.Minute), 10)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+			assert.Equal(t, taskUUID, tasks[0].UUID)
+			assert.Equal(t, []string{"cccc"}, tasks[0].ShortCodes)
+
+			require.NoError(t, pm.Save())
+
+			t.Cleanup(func() {
+				os.Remove(filePath)
+				os.Remove(filePath + repository.WALSuffix)
+			})
+		})
+	}
+}
+
+func Test_PersistenceManager_Compaction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	filePath := t.TempDir() + "/store-test.json"
+	appLogger := logger.NewLogger()
+	cfg := &config.Config{
+		FileStoragePath:        filePath,
+		FileSyncPolicy:         repository.SyncAlways,
+		FileCompactionInterval: 10 * time.Millisecond,
+	}
+
+	repo := repository.NewInMemoryRepository()
+	scheduler := worker.NewScheduler(ctx, cfg, appLogger)
+	pm := NewPersistenceManager(cfg, repo, scheduler, appLogger)
+	require.NoError(t, pm.Load())
+	require.NoError(t, scheduler.Start())
+
+	_, err := repo.CreateURL(ctx, repository.URL{UUID: uuid.New(), LongURL: "http://a.com", ShortCode: "aaaa"})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		info, err := os.Stat(filePath + repository.WALSuffix)
+		return err == nil && info.Size() == 0
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, scheduler.Stop())
+	require.NoError(t, pm.Save())
+
+	memento, err := repository.NewFileRepository(filePath).Load()
+	require.NoError(t, err)
+	require.Len(t, memento.State, 1)
+	assert.Equal(t, "aaaa", memento.State[0].ShortCode)
+}
+
+func Test_PersistenceManager_CompactionDeleteTasks(t *testing.T) {
+	ctx := context.Background()
+	filePath := t.TempDir() + "/store-test.json"
+	appLogger := logger.NewLogger()
+	cfg := &config.Config{
+		AppEnv:                 "test",
+		FileStoragePath:        filePath,
+		FileSyncPolicy:         repository.SyncAlways,
+		FileCompactionInterval: time.Hour,
+		DeleteWorkers:          1,
+		DeleteMaxAttempts:      1,
+		DeleteDrainTimeout:     time.Second,
+	}
+	userUUID := uuid.New()
+
+	repo := repository.NewInMemoryRepository()
+	pm := NewPersistenceManager(cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	require.NoError(t, pm.Load())
+
+	_, err := repo.CreateURL(ctx, repository.URL{UUID: uuid.New(), LongURL: "http://a.com", ShortCode: "aaaa", UserUUID: userUUID})
+	require.NoError(t, err)
+	require.NoError(t, repo.EnqueueDeleteTask(ctx, repository.DeleteTask{UUID: uuid.New(), UserUUID: userUUID, ShortCodes: []string{"aaaa"}}))
+
+	// NOTE: the write-ahead log is truncated on compaction, so the task is restored from the snapshot only
+	require.NoError(t, pm.Save())
+	info, err := os.Stat(filePath + repository.WALSuffix)
+	require.NoError(t, err)
+	require.Zero(t, info.Size())
+
+	restarted := repository.NewInMemoryRepository()
+	require.NoError(t, NewPersistenceManager(cfg, restarted, worker.NewScheduler(ctx, cfg, appLogger), appLogger).Load())
+
+	// NOTE: the worker is stopped before it starts, so the restored task is processed by the drain
+	workerCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	scheduler := worker.NewScheduler(workerCtx, cfg, appLogger)
+	worker.NewDeleteWorker(workerCtx, cfg, restarted, scheduler, appLogger)
+	require.NoError(t, scheduler.Start())
+	require.NoError(t, scheduler.Stop())
+
+	url, ok := restarted.GetURLByShortCode(ctx, "aaaa")
+	require.True(t, ok)
+	assert.False(t, url.DeletedAt.IsZero())
+
+	stats, err := restarted.CountDeleteTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, repository.DeleteTaskStats{}, stats)
+}
+
+func Test_PersistenceManager_Sequence(t *testing.T) {
+	ctx := context.Background()
+	filePath := t.TempDir() + "/store-test.json"
+	appLogger := logger.NewLogger()
+	cfg := &config.Config{
+		FileStoragePath: filePath,
+		FileSyncPolicy:  repository.SyncAlways,
+	}
+
+	repo := repository.NewInMemoryRepository()
+	require.NoError(t, NewPersistenceManager(cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger).Load())
+	for i := 0; i < 3; i++ {
+		_, err := repo.NextSequence(ctx)
+		require.NoError(t, err)
+	}
+
+	// NOTE: the manager is abandoned without Save, the sequence is recovered from the write-ahead log
+	recovered := repository.NewInMemoryRepository()
+	pm := NewPersistenceManager(cfg, recovered, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	require.NoError(t, pm.Load())
+
+	next, err := recovered.NextSequence(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), next)
+
+	// NOTE: the write-ahead log is truncated on compaction, the sequence is restored from the snapshot
+	require.NoError(t, pm.Save())
+
+	restarted := repository.NewInMemoryRepository()
+	require.NoError(t, NewPersistenceManager(cfg, restarted, worker.NewScheduler(ctx, cfg, appLogger), appLogger).Load())
+
+	next, err = restarted.NextSequence(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), next)
+}
+
+func Test_PersistenceManager_ConcurrentCompaction(t *testing.T) {
+	ctx := context.Background()
+	filePath := t.TempDir() + "/store-test.json"
+	appLogger := logger.NewLogger()
+	cfg := &config.Config{
+		FileStoragePath: filePath,
+		FileSyncPolicy:  repository.SyncNever,
+	}
+	userUUID := uuid.New()
+
+	repo := repository.NewInMemoryRepository()
+	pm := NewPersistenceManager(cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger).(*manager)
+	require.NoError(t, pm.Load())
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				shortCode := fmt.Sprintf("w%dn%d", i, j)
+				_, err := repo.CreateURL(ctx, repository.URL{UUID: uuid.New(), LongURL: "http://" + shortCode + ".com", ShortCode: shortCode, UserUUID: userUUID})
+				assert.NoError(t, err)
+				assert.NoError(t, repo.EnqueueDeleteTask(ctx, repository.DeleteTask{UUID: uuid.New(), UserUUID: userUUID, ShortCodes: []string{shortCode}}))
+			}
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 50; j++ {
+			assert.NoError(t, pm.compact(ctx))
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("writers and compaction deadlocked")
+	}
+
+	count, err := repo.CountURLs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(400), count)
+
+	// NOTE: the manager is abandoned without Save, every acknowledged write is in the snapshot or the write-ahead log
+	recovered := repository.NewInMemoryRepository()
+	require.NoError(t, NewPersistenceManager(cfg, recovered, worker.NewScheduler(ctx, cfg, appLogger), appLogger).Load())
+
+	count, err = recovered.CountURLs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(400), count)
+
+	stats, err := recovered.CountDeleteTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(400), stats.Pending)
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,7 +17,105 @@ type URL struct {
 	LongURL   string    `json:"long_url"`
 	ShortCode string    `json:"short_code"`
 	UserUUID  uuid.UUID `json:"user_uuid"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Expired   bool      `json:"expired"`
+}
+
+// IsExpired checks if the URL is expired at the given time
+func (u *URL) IsExpired(now time.Time) bool {
+	if u.Expired {
+		return true
+	}
+
+	return !u.ExpiresAt.IsZero() && !u.ExpiresAt.After(now)
+}
+
+// SortCreatedAt orders listed URL records from the oldest
+const SortCreatedAt = "created_at"
+
+// SortCreatedAtDesc orders listed URL records from the newest
+const SortCreatedAtDesc = "-created_at"
+
+// SortClicks orders listed URL records from the least clicked
+const SortClicks = "clicks"
+
+// SortClicksDesc orders listed URL records from the most clicked
+const SortClicksDesc = "-clicks"
+
+// URLFilter is a set of criteria for listing URL records of a user
+type URLFilter struct {
+	UserUUID uuid.UUID
+	// Query is a case-insensitive substring of the long URL or the short code
+	Query string
+	// Deleted lists deleted records instead of active ones
+	Deleted bool
+	// Expired lists expired records instead of active ones
+	Expired       bool
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Sort is one of the Sort* orders, records created at the same time are ordered by UUID descending
+	Sort   string
+	Limit  int64
+	Offset int64
+}
+
+// URLCursor is a keyset position in URL records ordered from the newest, ties are broken by UUID descending
+type URLCursor struct {
+	CreatedAt time.Time
+	UUID      uuid.UUID
+}
+
+// Before reports whether the record comes after the cursor position in the newest first order
+func (c URLCursor) Before(url URL) bool {
+	if !url.CreatedAt.Equal(c.CreatedAt) {
+		return url.CreatedAt.Before(c.CreatedAt)
+	}
+
+	return bytes.Compare(url.UUID[:], c.UUID[:]) < 0
+}
+
+// likeEscaper escapes LIKE wildcards, so a query matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePattern converts a substring query into a LIKE pattern escaped with a backslash, an empty query stays empty
+func likePattern(query string) string {
+	if query == "" {
+		return ""
+	}
+
+	return "%" + likeEscaper.Replace(query) + "%"
+}
+
+// URLListItem is a listed URL record with its number of clicks
+type URLListItem struct {
+	URL
+	Clicks int64
+}
+
+// Click is a short link click event entity
+type Click struct {
+	URLUUID   uuid.UUID `json:"url_uuid"`
+	Referrer  string    `json:"referrer"`
+	UserAgent string    `json:"user_agent"`
+	IPHash    string    `json:"ip_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ClickStats is an aggregated clicks statistics of a short link
+type ClickStats struct {
+	Total  int64
+	Unique int64
+	Daily  []DailyClicks
+}
+
+// DailyClicks is a clicks statistics of a short link for a single day
+type DailyClicks struct {
+	Date   time.Time
+	Total  int64
+	Unique int64
 }
 
 // User is a user entity
@@ -23,18 +123,118 @@ type User struct {
 	UUID uuid.UUID `json:"uuid"`
 }
 
+// APIToken is a personal access token entity, only the hash of the secret is stored
+type APIToken struct {
+	UUID      uuid.UUID `json:"uuid"`
+	UserUUID  uuid.UUID `json:"user_uuid"`
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"`
+	Hash      string    `json:"hash"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// DeleteTask is a queued batch deletion of user URL records, failed tasks are kept as dead letters
+type DeleteTask struct {
+	UUID       uuid.UUID `json:"uuid"`
+	UserUUID   uuid.UUID `json:"user_uuid"`
+	ShortCodes []string  `json:"short_codes"`
+	Attempts   int       `json:"attempts"`
+	LastError  string    `json:"last_error,omitempty"`
+	// RunAt is the time of the next attempt, claimed tasks are leased until it
+	RunAt     time.Time `json:"run_at"`
+	CreatedAt time.Time `json:"created_at"`
+	FailedAt  time.Time `json:"failed_at"`
+	// TraceParent is the W3C trace context of the request which enqueued the task
+	TraceParent string `json:"trace_parent,omitempty"`
+}
+
+// DeleteJob is the reported state of a batch deletion, it is kept after its delete task is processed
+type DeleteJob struct {
+	UUID       uuid.UUID          `json:"uuid"`
+	UserUUID   uuid.UUID          `json:"user_uuid"`
+	Status     string             `json:"status"`
+	ShortCodes []string           `json:"short_codes"`
+	Deleted    []string           `json:"deleted,omitempty"`
+	Skipped    []SkippedShortCode `json:"skipped,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	FinishedAt time.Time          `json:"finished_at"`
+}
+
+// SkippedShortCode is a short code a delete job left untouched and the reason
+type SkippedShortCode struct {
+	ShortCode string `json:"short_code"`
+	Reason    string `json:"reason"`
+}
+
+// DeleteTaskStats is the number of pending and dead-lettered delete tasks
+type DeleteTaskStats struct {
+	Pending int64
+	Failed  int64
+}
+
 // Memento is a memento entity
 type Memento struct {
-	State []URL `json:"state"`
+	State    []URL        `json:"state"`
+	Tokens   []APIToken   `json:"tokens,omitempty"`
+	Tasks    []DeleteTask `json:"tasks,omitempty"`
+	Jobs     []DeleteJob  `json:"jobs,omitempty"`
+	Sequence int64        `json:"sequence,omitempty"`
 }
 
 // Repository is an interface for repository
 type Repository interface {
 	CreateURL(ctx context.Context, url URL) (*URL, error)
-	CreateURLs(ctx context.Context, urls []URL) error
+	CreateURLs(ctx context.Context, urls []URL) ([]URL, error)
 	GetURLByShortCode(ctx context.Context, shortCode string) (*URL, bool)
+	GetURLsByLongURLs(ctx context.Context, longURLs []string) ([]URL, error)
 	GetURLsByUserID(ctx context.Context, uuid uuid.UUID, limit, offset int64) ([]URL, int, error)
-	DeleteURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string) error
+	GetURLsByUserIDAfter(ctx context.Context, uuid uuid.UUID, after URLCursor, limit int64) ([]URL, error)
+	FindURLsByUserID(ctx context.Context, filter URLFilter) ([]URLListItem, int, error)
+	ExportURLsByUserID(ctx context.Context, uuid uuid.UUID, after URLCursor, limit int64) ([]URLListItem, error)
+	UpdateURL(ctx context.Context, url URL) (*URL, error)
+	// DeleteURLsByUserID marks URL records of the user as deleted and returns the short codes of the deleted ones
+	DeleteURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string) ([]string, error)
+	// DeleteURLsByUserIDs marks URL records of many users as deleted at once and returns the deleted short codes per user
+	DeleteURLsByUserIDs(ctx context.Context, shortCodes map[uuid.UUID][]string) (map[uuid.UUID][]string, error)
+	// RestoreURLsByUserID restores URL records of the user deleted since the given time and returns their short codes
+	RestoreURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string, deletedSince time.Time) ([]string, error)
+	// PurgeURLs permanently removes URL records deleted before the given time together with their clicks
+	PurgeURLs(ctx context.Context, deletedBefore time.Time) (int64, error)
+	ExpireURLs(ctx context.Context) (int64, error)
+	CreateClicks(ctx context.Context, clicks []Click) error
+	GetClickStats(ctx context.Context, urlID uuid.UUID, since time.Time) (*ClickStats, error)
+	NextSequence(ctx context.Context) (int64, error)
+	CreateAPIToken(ctx context.Context, token APIToken) (*APIToken, error)
+	GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, bool)
+	GetAPITokensByUserID(ctx context.Context, id uuid.UUID) ([]APIToken, error)
+	RevokeAPIToken(ctx context.Context, userID, tokenID uuid.UUID) error
+	EnqueueDeleteTask(ctx context.Context, task DeleteTask) error
+	// ClaimDeleteTasks leases due delete tasks until the given time and counts the attempt
+	ClaimDeleteTasks(ctx context.Context, now, leaseUntil time.Time, limit int64) ([]DeleteTask, error)
+	// CompleteDeleteTasks removes processed delete tasks
+	CompleteDeleteTasks(ctx context.Context, ids []uuid.UUID) error
+	// RetryDeleteTask schedules the next attempt of a delete task
+	RetryDeleteTask(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error
+	// FailDeleteTask moves a delete task to the dead letters
+	FailDeleteTask(ctx context.Context, id uuid.UUID, lastError string) error
+	CountDeleteTasks(ctx context.Context) (DeleteTaskStats, error)
+	// SaveDeleteJob stores the state of a delete job, the state of an existing job is replaced
+	SaveDeleteJob(ctx context.Context, job DeleteJob) error
+	GetDeleteJob(ctx context.Context, id uuid.UUID) (*DeleteJob, bool)
+	// PurgeDeleteJobs removes delete jobs finished before the given time
+	PurgeDeleteJobs(ctx context.Context, finishedBefore time.Time) (int64, error)
+	Counter
+}
+
+// Counter is an interface for counting the active URL records of a storage backend
+type Counter interface {
+	// CountURLs counts active URL records, neither deleted nor expired
+	CountURLs(ctx context.Context) (int64, error)
+	// CountUsers counts distinct users owning active URL records
+	CountUsers(ctx context.Context) (int64, error)
 }
 
 // HealthChecker is an interface for health checker
@@ -42,6 +242,18 @@ type HealthChecker interface {
 	Ping(ctx context.Context) error
 }
 
+// Backend returns the name of the storage backend of the repository
+func Backend(repo Repository) string {
+	switch repo.(type) {
+	case *DatabaseRepo:
+		return DatabaseBackend
+	case *SQLiteRepo:
+		return SQLiteBackend
+	default:
+		return InMemoryBackend
+	}
+}
+
 // Factory is a factory for repository
 type Factory struct {
 	DSN    string
@@ -50,7 +262,14 @@ type Factory struct {
 
 // NewRepository creates a new repository instance
 func NewRepository(ctx context.Context, f *Factory) (Repository, error) {
-	if f.DSN != "" {
+	if IsSQLiteDSN(f.DSN) {
+		db, err := NewSQLiteRepository(ctx, f.DSN)
+		if err == nil {
+			f.Logger.Info().Msg("Using SQLite database")
+			return db, nil
+		}
+		f.Logger.Error().Err(err).Msg("Failed to open SQLite database")
+	} else if f.DSN != "" {
 		db, err := NewDatabaseRepository(ctx, f.DSN)
 		if err == nil {
 			f.Logger.Info().Msg("Using PostgreSQL database")
@@ -12,6 +12,7 @@ package repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,109 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ClaimDeleteTasks mocks base method.
+func (m *MockRepository) ClaimDeleteTasks(ctx context.Context, now, leaseUntil time.Time, limit int64) ([]DeleteTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeleteTasks", ctx, now, leaseUntil, limit)
+	ret0, _ := ret[0].([]DeleteTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeleteTasks indicates an expected call of ClaimDeleteTasks.
+func (mr *MockRepositoryMockRecorder) ClaimDeleteTasks(ctx, now, leaseUntil, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeleteTasks", reflect.TypeOf((*MockRepository)(nil).ClaimDeleteTasks), ctx, now, leaseUntil, limit)
+}
+
+// CompleteDeleteTasks mocks base method.
+func (m *MockRepository) CompleteDeleteTasks(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteDeleteTasks", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteDeleteTasks indicates an expected call of CompleteDeleteTasks.
+func (mr *MockRepositoryMockRecorder) CompleteDeleteTasks(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteDeleteTasks", reflect.TypeOf((*MockRepository)(nil).CompleteDeleteTasks), ctx, ids)
+}
+
+// CountDeleteTasks mocks base method.
+func (m *MockRepository) CountDeleteTasks(ctx context.Context) (DeleteTaskStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDeleteTasks", ctx)
+	ret0, _ := ret[0].(DeleteTaskStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDeleteTasks indicates an expected call of CountDeleteTasks.
+func (mr *MockRepositoryMockRecorder) CountDeleteTasks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDeleteTasks", reflect.TypeOf((*MockRepository)(nil).CountDeleteTasks), ctx)
+}
+
+// CountURLs mocks base method.
+func (m *MockRepository) CountURLs(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountURLs", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountURLs indicates an expected call of CountURLs.
+func (mr *MockRepositoryMockRecorder) CountURLs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountURLs", reflect.TypeOf((*MockRepository)(nil).CountURLs), ctx)
+}
+
+// CountUsers mocks base method.
+func (m *MockRepository) CountUsers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers.
+func (mr *MockRepositoryMockRecorder) CountUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockRepository)(nil).CountUsers), ctx)
+}
+
+// CreateAPIToken mocks base method.
+func (m *MockRepository) CreateAPIToken(ctx context.Context, token APIToken) (*APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIToken", ctx, token)
+	ret0, _ := ret[0].(*APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIToken indicates an expected call of CreateAPIToken.
+func (mr *MockRepositoryMockRecorder) CreateAPIToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIToken", reflect.TypeOf((*MockRepository)(nil).CreateAPIToken), ctx, token)
+}
+
+// CreateClicks mocks base method.
+func (m *MockRepository) CreateClicks(ctx context.Context, clicks []Click) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClicks", ctx, clicks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClicks indicates an expected call of CreateClicks.
+func (mr *MockRepositoryMockRecorder) CreateClicks(ctx, clicks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClicks", reflect.TypeOf((*MockRepository)(nil).CreateClicks), ctx, clicks)
+}
+
 // CreateURL mocks base method.
 func (m *MockRepository) CreateURL(ctx context.Context, url URL) (*URL, error) {
 	m.ctrl.T.Helper()
@@ -57,11 +161,12 @@ func (mr *MockRepositoryMockRecorder) CreateURL(ctx, url any) *gomock.Call {
 }
 
 // CreateURLs mocks base method.
-func (m *MockRepository) CreateURLs(ctx context.Context, urls []URL) error {
+func (m *MockRepository) CreateURLs(ctx context.Context, urls []URL) ([]URL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateURLs", ctx, urls)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateURLs indicates an expected call of CreateURLs.
@@ -71,11 +176,12 @@ func (mr *MockRepositoryMockRecorder) CreateURLs(ctx, urls any) *gomock.Call {
 }
 
 // DeleteURLsByUserID mocks base method.
-func (m *MockRepository) DeleteURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string) error {
+func (m *MockRepository) DeleteURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string) ([]string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteURLsByUserID", ctx, uuid, shortCodes)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteURLsByUserID indicates an expected call of DeleteURLsByUserID.
@@ -84,6 +190,155 @@ func (mr *MockRepositoryMockRecorder) DeleteURLsByUserID(ctx, uuid, shortCodes a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteURLsByUserID", reflect.TypeOf((*MockRepository)(nil).DeleteURLsByUserID), ctx, uuid, shortCodes)
 }
 
+// DeleteURLsByUserIDs mocks base method.
+func (m *MockRepository) DeleteURLsByUserIDs(ctx context.Context, shortCodes map[uuid.UUID][]string) (map[uuid.UUID][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteURLsByUserIDs", ctx, shortCodes)
+	ret0, _ := ret[0].(map[uuid.UUID][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteURLsByUserIDs indicates an expected call of DeleteURLsByUserIDs.
+func (mr *MockRepositoryMockRecorder) DeleteURLsByUserIDs(ctx, shortCodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteURLsByUserIDs", reflect.TypeOf((*MockRepository)(nil).DeleteURLsByUserIDs), ctx, shortCodes)
+}
+
+// EnqueueDeleteTask mocks base method.
+func (m *MockRepository) EnqueueDeleteTask(ctx context.Context, task DeleteTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeleteTask", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueDeleteTask indicates an expected call of EnqueueDeleteTask.
+func (mr *MockRepositoryMockRecorder) EnqueueDeleteTask(ctx, task any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeleteTask", reflect.TypeOf((*MockRepository)(nil).EnqueueDeleteTask), ctx, task)
+}
+
+// ExpireURLs mocks base method.
+func (m *MockRepository) ExpireURLs(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireURLs", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireURLs indicates an expected call of ExpireURLs.
+func (mr *MockRepositoryMockRecorder) ExpireURLs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireURLs", reflect.TypeOf((*MockRepository)(nil).ExpireURLs), ctx)
+}
+
+// ExportURLsByUserID mocks base method.
+func (m *MockRepository) ExportURLsByUserID(ctx context.Context, uuid uuid.UUID, after URLCursor, limit int64) ([]URLListItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportURLsByUserID", ctx, uuid, after, limit)
+	ret0, _ := ret[0].([]URLListItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportURLsByUserID indicates an expected call of ExportURLsByUserID.
+func (mr *MockRepositoryMockRecorder) ExportURLsByUserID(ctx, uuid, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportURLsByUserID", reflect.TypeOf((*MockRepository)(nil).ExportURLsByUserID), ctx, uuid, after, limit)
+}
+
+// FailDeleteTask mocks base method.
+func (m *MockRepository) FailDeleteTask(ctx context.Context, id uuid.UUID, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailDeleteTask", ctx, id, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailDeleteTask indicates an expected call of FailDeleteTask.
+func (mr *MockRepositoryMockRecorder) FailDeleteTask(ctx, id, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailDeleteTask", reflect.TypeOf((*MockRepository)(nil).FailDeleteTask), ctx, id, lastError)
+}
+
+// FindURLsByUserID mocks base method.
+func (m *MockRepository) FindURLsByUserID(ctx context.Context, filter URLFilter) ([]URLListItem, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindURLsByUserID", ctx, filter)
+	ret0, _ := ret[0].([]URLListItem)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindURLsByUserID indicates an expected call of FindURLsByUserID.
+func (mr *MockRepositoryMockRecorder) FindURLsByUserID(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindURLsByUserID", reflect.TypeOf((*MockRepository)(nil).FindURLsByUserID), ctx, filter)
+}
+
+// GetAPITokenByHash mocks base method.
+func (m *MockRepository) GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPITokenByHash", ctx, hash)
+	ret0, _ := ret[0].(*APIToken)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetAPITokenByHash indicates an expected call of GetAPITokenByHash.
+func (mr *MockRepositoryMockRecorder) GetAPITokenByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPITokenByHash", reflect.TypeOf((*MockRepository)(nil).GetAPITokenByHash), ctx, hash)
+}
+
+// GetAPITokensByUserID mocks base method.
+func (m *MockRepository) GetAPITokensByUserID(ctx context.Context, id uuid.UUID) ([]APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPITokensByUserID", ctx, id)
+	ret0, _ := ret[0].([]APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPITokensByUserID indicates an expected call of GetAPITokensByUserID.
+func (mr *MockRepositoryMockRecorder) GetAPITokensByUserID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPITokensByUserID", reflect.TypeOf((*MockRepository)(nil).GetAPITokensByUserID), ctx, id)
+}
+
+// GetClickStats mocks base method.
+func (m *MockRepository) GetClickStats(ctx context.Context, urlID uuid.UUID, since time.Time) (*ClickStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStats", ctx, urlID, since)
+	ret0, _ := ret[0].(*ClickStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStats indicates an expected call of GetClickStats.
+func (mr *MockRepositoryMockRecorder) GetClickStats(ctx, urlID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStats", reflect.TypeOf((*MockRepository)(nil).GetClickStats), ctx, urlID, since)
+}
+
+// GetDeleteJob mocks base method.
+func (m *MockRepository) GetDeleteJob(ctx context.Context, id uuid.UUID) (*DeleteJob, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleteJob", ctx, id)
+	ret0, _ := ret[0].(*DeleteJob)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetDeleteJob indicates an expected call of GetDeleteJob.
+func (mr *MockRepositoryMockRecorder) GetDeleteJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleteJob", reflect.TypeOf((*MockRepository)(nil).GetDeleteJob), ctx, id)
+}
+
 // GetURLByShortCode mocks base method.
 func (m *MockRepository) GetURLByShortCode(ctx context.Context, shortCode string) (*URL, bool) {
 	m.ctrl.T.Helper()
@@ -99,6 +354,21 @@ func (mr *MockRepositoryMockRecorder) GetURLByShortCode(ctx, shortCode any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLByShortCode", reflect.TypeOf((*MockRepository)(nil).GetURLByShortCode), ctx, shortCode)
 }
 
+// GetURLsByLongURLs mocks base method.
+func (m *MockRepository) GetURLsByLongURLs(ctx context.Context, longURLs []string) ([]URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLsByLongURLs", ctx, longURLs)
+	ret0, _ := ret[0].([]URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetURLsByLongURLs indicates an expected call of GetURLsByLongURLs.
+func (mr *MockRepositoryMockRecorder) GetURLsByLongURLs(ctx, longURLs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLsByLongURLs", reflect.TypeOf((*MockRepository)(nil).GetURLsByLongURLs), ctx, longURLs)
+}
+
 // GetURLsByUserID mocks base method.
 func (m *MockRepository) GetURLsByUserID(ctx context.Context, uuid uuid.UUID, limit, offset int64) ([]URL, int, error) {
 	m.ctrl.T.Helper()
@@ -115,6 +385,192 @@ func (mr *MockRepositoryMockRecorder) GetURLsByUserID(ctx, uuid, limit, offset a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLsByUserID", reflect.TypeOf((*MockRepository)(nil).GetURLsByUserID), ctx, uuid, limit, offset)
 }
 
+// GetURLsByUserIDAfter mocks base method.
+func (m *MockRepository) GetURLsByUserIDAfter(ctx context.Context, uuid uuid.UUID, after URLCursor, limit int64) ([]URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLsByUserIDAfter", ctx, uuid, after, limit)
+	ret0, _ := ret[0].([]URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetURLsByUserIDAfter indicates an expected call of GetURLsByUserIDAfter.
+func (mr *MockRepositoryMockRecorder) GetURLsByUserIDAfter(ctx, uuid, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLsByUserIDAfter", reflect.TypeOf((*MockRepository)(nil).GetURLsByUserIDAfter), ctx, uuid, after, limit)
+}
+
+// NextSequence mocks base method.
+func (m *MockRepository) NextSequence(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextSequence", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextSequence indicates an expected call of NextSequence.
+func (mr *MockRepositoryMockRecorder) NextSequence(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextSequence", reflect.TypeOf((*MockRepository)(nil).NextSequence), ctx)
+}
+
+// PurgeDeleteJobs mocks base method.
+func (m *MockRepository) PurgeDeleteJobs(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleteJobs", ctx, finishedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleteJobs indicates an expected call of PurgeDeleteJobs.
+func (mr *MockRepositoryMockRecorder) PurgeDeleteJobs(ctx, finishedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleteJobs", reflect.TypeOf((*MockRepository)(nil).PurgeDeleteJobs), ctx, finishedBefore)
+}
+
+// PurgeURLs mocks base method.
+func (m *MockRepository) PurgeURLs(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeURLs", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeURLs indicates an expected call of PurgeURLs.
+func (mr *MockRepositoryMockRecorder) PurgeURLs(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeURLs", reflect.TypeOf((*MockRepository)(nil).PurgeURLs), ctx, deletedBefore)
+}
+
+// RestoreURLsByUserID mocks base method.
+func (m *MockRepository) RestoreURLsByUserID(ctx context.Context, uuid uuid.UUID, shortCodes []string, deletedSince time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreURLsByUserID", ctx, uuid, shortCodes, deletedSince)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreURLsByUserID indicates an expected call of RestoreURLsByUserID.
+func (mr *MockRepositoryMockRecorder) RestoreURLsByUserID(ctx, uuid, shortCodes, deletedSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreURLsByUserID", reflect.TypeOf((*MockRepository)(nil).RestoreURLsByUserID), ctx, uuid, shortCodes, deletedSince)
+}
+
+// RetryDeleteTask mocks base method.
+func (m *MockRepository) RetryDeleteTask(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDeleteTask", ctx, id, runAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryDeleteTask indicates an expected call of RetryDeleteTask.
+func (mr *MockRepositoryMockRecorder) RetryDeleteTask(ctx, id, runAt, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDeleteTask", reflect.TypeOf((*MockRepository)(nil).RetryDeleteTask), ctx, id, runAt, lastError)
+}
+
+// RevokeAPIToken mocks base method.
+func (m *MockRepository) RevokeAPIToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIToken", ctx, userID, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIToken indicates an expected call of RevokeAPIToken.
+func (mr *MockRepositoryMockRecorder) RevokeAPIToken(ctx, userID, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIToken", reflect.TypeOf((*MockRepository)(nil).RevokeAPIToken), ctx, userID, tokenID)
+}
+
+// SaveDeleteJob mocks base method.
+func (m *MockRepository) SaveDeleteJob(ctx context.Context, job DeleteJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeleteJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeleteJob indicates an expected call of SaveDeleteJob.
+func (mr *MockRepositoryMockRecorder) SaveDeleteJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeleteJob", reflect.TypeOf((*MockRepository)(nil).SaveDeleteJob), ctx, job)
+}
+
+// UpdateURL mocks base method.
+func (m *MockRepository) UpdateURL(ctx context.Context, url URL) (*URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateURL", ctx, url)
+	ret0, _ := ret[0].(*URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateURL indicates an expected call of UpdateURL.
+func (mr *MockRepositoryMockRecorder) UpdateURL(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateURL", reflect.TypeOf((*MockRepository)(nil).UpdateURL), ctx, url)
+}
+
+// MockCounter is a mock of Counter interface.
+type MockCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCounterMockRecorder
+	isgomock struct{}
+}
+
+// MockCounterMockRecorder is the mock recorder for MockCounter.
+type MockCounterMockRecorder struct {
+	mock *MockCounter
+}
+
+// NewMockCounter creates a new mock instance.
+func NewMockCounter(ctrl *gomock.Controller) *MockCounter {
+	mock := &MockCounter{ctrl: ctrl}
+	mock.recorder = &MockCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCounter) EXPECT() *MockCounterMockRecorder {
+	return m.recorder
+}
+
+// CountURLs mocks base method.
+func (m *MockCounter) CountURLs(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountURLs", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountURLs indicates an expected call of CountURLs.
+func (mr *MockCounterMockRecorder) CountURLs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountURLs", reflect.TypeOf((*MockCounter)(nil).CountURLs), ctx)
+}
+
+// CountUsers mocks base method.
+func (m *MockCounter) CountUsers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers.
+func (mr *MockCounterMockRecorder) CountUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockCounter)(nil).CountUsers), ctx)
+}
+
 // MockHealthChecker is a mock of HealthChecker interface.
 type MockHealthChecker struct {
 	ctrl     *gomock.Controller
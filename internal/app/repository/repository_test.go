@@ -3,8 +3,10 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"shortly/internal/app/config"
@@ -16,8 +18,10 @@ func Test_NewRepository(t *testing.T) {
 	defer ctrl.Finish()
 
 	ctx := context.Background()
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
 	appLogger := logger.NewLogger()
+	sqliteDSN := SQLiteScheme + t.TempDir() + "/shortly-test.db"
 
 	tests := []struct {
 		name         string
@@ -35,6 +39,11 @@ func Test_NewRepository(t *testing.T) {
 			dsn:          cfg.DatabaseDSN,
 			expectedType: &DatabaseRepo{},
 		},
+		{
+			name:         "SQLite database repository",
+			dsn:          sqliteDSN,
+			expectedType: &SQLiteRepo{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -50,3 +59,70 @@ func Test_NewRepository(t *testing.T) {
 		})
 	}
 }
+
+func Test_URL_IsExpired(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		url      URL
+		expected bool
+	}{
+		{
+			name:     "Without expiration",
+			url:      URL{},
+			expected: false,
+		},
+		{
+			name:     "Expires in future",
+			url:      URL{ExpiresAt: now.Add(time.Hour)},
+			expected: false,
+		},
+		{
+			name:     "Expiration time passed",
+			url:      URL{ExpiresAt: now.Add(-time.Second)},
+			expected: true,
+		},
+		{
+			name:     "Marked as expired",
+			url:      URL{Expired: true},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.url.IsExpired(now))
+		})
+	}
+}
+
+func Test_Backend(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     Repository
+		expected string
+	}{
+		{
+			name:     "In-Memory repository",
+			repo:     NewInMemoryRepository(),
+			expected: InMemoryBackend,
+		},
+		{
+			name:     "PostgreSQL database repository",
+			repo:     &DatabaseRepo{},
+			expected: DatabaseBackend,
+		},
+		{
+			name:     "SQLite database repository",
+			repo:     &SQLiteRepo{},
+			expected: SQLiteBackend,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Backend(tt.repo))
+		})
+	}
+}
@@ -0,0 +1,1046 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	migrations "shortly/db/migrate/sqlite"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/metrics"
+	"shortly/internal/logger"
+)
+
+// SQLiteBackend is the metrics label of the SQLite repository
+const SQLiteBackend = "sqlite"
+
+// SQLiteScheme is the DSN scheme selecting the SQLite repository, e.g. sqlite://data/shortly.db
+const SQLiteScheme = "sqlite://"
+
+// SQLiteFileScheme is the SQLite URI filename scheme, e.g. file:shortly.db?cache=shared
+const SQLiteFileScheme = "file:"
+
+// SQLiteTimeLayout is the layout of stored timestamps, it sorts lexicographically and is understood by SQLite date functions
+const SQLiteTimeLayout = "2006-01-02 15:04:05.000"
+
+// SQLiteBusyTimeout is the time a statement waits for a locked database
+const SQLiteBusyTimeout = 5 * time.Second
+
+// sqliteUniqueViolation is the prefix of SQLite unique constraint errors, followed by table.column and the result code
+const sqliteUniqueViolation = "UNIQUE constraint failed: "
+
+const (
+	sqliteCreateURL = `INSERT INTO urls (uuid, long_url, short_code, user_uuid, expires_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (long_url) DO UPDATE SET short_code = urls.short_code
+RETURNING uuid, long_url, short_code`
+
+	sqliteGetURLByShortCode = `SELECT uuid, long_url, short_code, user_uuid, deleted_at, expires_at, expired
+FROM urls WHERE short_code = ?`
+
+	sqliteGetURLsByLongURLs = `SELECT uuid, long_url, short_code, user_uuid
+FROM urls WHERE long_url IN (`
+
+	sqliteGetURLsByUserID = `SELECT uuid, long_url, short_code, created_at, COUNT(*) OVER () AS total
+FROM urls
+WHERE user_uuid = ? AND deleted_at IS NULL AND expired = FALSE
+ORDER BY created_at DESC, uuid DESC LIMIT ? OFFSET ?`
+
+	sqliteGetURLsByUserIDAfter = `SELECT uuid, long_url, short_code, created_at
+FROM urls
+WHERE user_uuid = ? AND deleted_at IS NULL AND expired = FALSE
+  AND (created_at, uuid) < (?, ?)
+ORDER BY created_at DESC, uuid DESC LIMIT ?`
+
+	sqliteURLFilter = `WHERE u.user_uuid = ?
+  AND CASE
+    WHEN ? THEN u.deleted_at IS NOT NULL
+    WHEN ? THEN u.deleted_at IS NULL AND u.expired
+    ELSE u.deleted_at IS NULL AND NOT u.expired
+  END
+  AND (? = '' OR u.long_url LIKE ? ESCAPE '\' OR u.short_code LIKE ? ESCAPE '\')
+  AND (? IS NULL OR u.created_at >= ?)
+  AND (? IS NULL OR u.created_at < ?)`
+
+	sqliteFindURLsByUserID = `SELECT u.uuid, u.long_url, u.short_code, u.created_at, u.deleted_at, u.expires_at, u.expired,
+  (SELECT COUNT(*) FROM clicks WHERE url_uuid = u.uuid) AS clicks
+FROM urls AS u
+` + sqliteURLFilter + `
+ORDER BY
+  CASE WHEN ? = 'clicks' THEN clicks END ASC,
+  CASE WHEN ? = '-clicks' THEN clicks END DESC,
+  CASE WHEN ? = 'created_at' THEN u.created_at END ASC,
+  u.created_at DESC,
+  u.uuid DESC
+LIMIT ? OFFSET ?`
+
+	sqliteCountURLsByUserID = `SELECT COUNT(*) FROM urls AS u
+` + sqliteURLFilter
+
+	sqliteExportURLsByUserID = `SELECT u.uuid, u.long_url, u.short_code, u.created_at, u.deleted_at, u.expires_at, u.expired,
+  (SELECT COUNT(*) FROM clicks WHERE url_uuid = u.uuid) AS clicks
+FROM urls AS u
+WHERE u.user_uuid = ? AND (? IS NULL OR (u.created_at, u.uuid) < (?, ?))
+ORDER BY u.created_at DESC, u.uuid DESC LIMIT ?`
+
+	sqliteUpdateURL = `UPDATE urls
+SET long_url = ?, updated_at = ?
+WHERE short_code = ? AND user_uuid = ? AND deleted_at IS NULL
+RETURNING uuid, long_url, short_code, user_uuid, updated_at`
+
+	sqliteDeleteURLsByUserID = `UPDATE urls
+SET deleted_at = ?
+WHERE user_uuid = ? AND deleted_at IS NULL AND short_code IN (`
+
+	sqliteDeleteURLsByUserIDs = `UPDATE urls
+SET deleted_at = ?
+WHERE deleted_at IS NULL AND (user_uuid, short_code) IN (VALUES `
+
+	sqliteRestoreURLsByUserID = `UPDATE urls
+SET deleted_at = NULL
+WHERE user_uuid = ? AND deleted_at >= ? AND short_code IN (`
+
+	sqlitePurgeURLs = `DELETE FROM urls WHERE deleted_at < ?`
+
+	sqliteExpireURLs = `UPDATE urls
+SET expired = TRUE
+WHERE expires_at <= ? AND expired = FALSE`
+
+	sqliteCreateClick = `INSERT INTO clicks (uuid, url_uuid, referrer, user_agent, ip_hash, created_at)
+VALUES (?, ?, ?, ?, ?, ?)`
+
+	sqliteGetClicksSummary = `SELECT COUNT(*), COUNT(DISTINCT ip_hash) FROM clicks WHERE url_uuid = ?`
+
+	sqliteGetDailyClicks = `SELECT date(created_at) AS day, COUNT(*), COUNT(DISTINCT ip_hash)
+FROM clicks
+WHERE url_uuid = ? AND created_at >= ?
+GROUP BY day
+ORDER BY day`
+
+	sqliteNextShortCodeSequence = `UPDATE short_code_seq SET value = value + 1 WHERE id = 1 RETURNING value`
+
+	sqliteCreateAPIToken = `INSERT INTO api_tokens (uuid, user_uuid, name, prefix, token_hash, scopes)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING created_at`
+
+	sqliteGetAPITokenByHash = `SELECT uuid, user_uuid, name, prefix, scopes, created_at
+FROM api_tokens
+WHERE token_hash = ? AND revoked_at IS NULL`
+
+	sqliteGetAPITokensByUserID = `SELECT uuid, user_uuid, name, prefix, scopes, created_at
+FROM api_tokens
+WHERE user_uuid = ? AND revoked_at IS NULL
+ORDER BY created_at DESC`
+
+	sqliteRevokeAPIToken = `UPDATE api_tokens
+SET revoked_at = ?
+WHERE uuid = ? AND user_uuid = ? AND revoked_at IS NULL`
+
+	sqliteEnqueueDeleteTask = `INSERT INTO delete_tasks (uuid, user_uuid, short_codes, run_at, created_at, trace_parent)
+VALUES (?, ?, ?, ?, ?, ?)`
+
+	sqliteClaimDeleteTasks = `UPDATE delete_tasks
+SET run_at = ?, attempts = attempts + 1
+WHERE uuid IN (
+  SELECT uuid FROM delete_tasks
+  WHERE failed_at IS NULL AND run_at <= ?
+  ORDER BY run_at, created_at
+  LIMIT ?
+)
+RETURNING uuid, user_uuid, short_codes, attempts, last_error, run_at, created_at, trace_parent`
+
+	sqliteCompleteDeleteTasks = `DELETE FROM delete_tasks WHERE uuid IN (`
+
+	sqliteRetryDeleteTask = `UPDATE delete_tasks
+SET run_at = ?, last_error = ?
+WHERE uuid = ?`
+
+	sqliteFailDeleteTask = `UPDATE delete_tasks
+SET failed_at = ?, last_error = ?
+WHERE uuid = ?`
+
+	sqliteCountDeleteTasks = `SELECT
+  COUNT(*) FILTER (WHERE failed_at IS NULL),
+  COUNT(*) FILTER (WHERE failed_at IS NOT NULL)
+FROM delete_tasks`
+
+	sqliteSaveDeleteJob = `INSERT INTO delete_jobs (uuid, user_uuid, status, short_codes, deleted, skipped, error, created_at, finished_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (uuid) DO UPDATE
+SET status = excluded.status, deleted = excluded.deleted, skipped = excluded.skipped,
+  error = excluded.error, finished_at = excluded.finished_at`
+
+	sqliteGetDeleteJob = `SELECT uuid, user_uuid, status, short_codes, deleted, skipped, error, created_at, finished_at
+FROM delete_jobs
+WHERE uuid = ?`
+
+	sqlitePurgeDeleteJobs = `DELETE FROM delete_jobs
+WHERE finished_at < ?`
+
+	sqliteCountURLs = `SELECT COUNT(*)
+FROM urls
+WHERE deleted_at IS NULL AND expired = FALSE`
+
+	sqliteCountUsers = `SELECT COUNT(DISTINCT user_uuid)
+FROM urls
+WHERE deleted_at IS NULL AND expired = FALSE`
+)
+
+// SQLiteRepo is a repository for SQLite database operations
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+// IsSQLiteDSN reports whether the DSN selects the SQLite repository
+func IsSQLiteDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, SQLiteScheme) || strings.HasPrefix(dsn, SQLiteFileScheme)
+}
+
+// NewSQLiteRepository opens the SQLite database and applies pending migrations
+func NewSQLiteRepository(ctx context.Context, dsn string) (Database, error) {
+	db, err := sql.Open(SQLiteDriver, strings.TrimPrefix(dsn, SQLiteScheme))
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: SQLite serializes writers anyway, a single connection also keeps per-connection pragmas in effect
+	db.SetMaxOpenConns(1)
+
+	pragmas := []string{
+		"PRAGMA foreign_keys = ON",
+		"PRAGMA journal_mode = WAL",
+		"PRAGMA busy_timeout = " + strconv.FormatInt(SQLiteBusyTimeout.Milliseconds(), 10),
+	}
+
+	for _, pragma := range pragmas {
+		if _, err = db.ExecContext(ctx, pragma); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	if err = migrateSQLite(ctx, db, migrations.Migrations); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteRepo{db: db}, nil
+}
+
+// CreateURL creates a new URL record
+func (s *SQLiteRepo) CreateURL(ctx context.Context, url URL) (*URL, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "create_url", time.Now())
+
+	row := s.db.QueryRowContext(ctx, sqliteCreateURL,
+		url.UUID, url.LongURL, url.ShortCode, toNullUUID(url.UserUUID), toSQLiteTime(url.ExpiresAt))
+
+	var result URL
+	if err := row.Scan(&result.UUID, &result.LongURL, &result.ShortCode); err != nil {
+		return nil, mapSQLiteError(err)
+	}
+
+	return &result, nil
+}
+
+// CreateURLs creates new URL records in a single transaction and returns the stored record of each,
+// records of already shortened long URLs are returned instead of created
+func (s *SQLiteRepo) CreateURLs(ctx context.Context, urls []URL) ([]URL, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "create_urls", time.Now())
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	records := make([]URL, 0, len(urls))
+
+	for _, url := range urls {
+		var record URL
+
+		err = tx.QueryRowContext(ctx, sqliteCreateURL,
+			url.UUID, url.LongURL, url.ShortCode, toNullUUID(url.UserUUID), toSQLiteTime(url.ExpiresAt)).Scan(
+			&record.UUID, &record.LongURL, &record.ShortCode)
+		if err != nil {
+			return nil, mapSQLiteError(err)
+		}
+
+		records = append(records, record)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// GetURLByShortCode returns a URL record by short code
+func (s *SQLiteRepo) GetURLByShortCode(ctx context.Context, shortCode string) (*URL, bool) {
+	defer metrics.ObserveRepository(SQLiteBackend, "get_url_by_short_code", time.Now())
+
+	var url URL
+	var userUUID uuid.NullUUID
+	var deletedAt, expiresAt sql.NullString
+
+	err := s.db.QueryRowContext(ctx, sqliteGetURLByShortCode, shortCode).Scan(
+		&url.UUID, &url.LongURL, &url.ShortCode, &userUUID, &deletedAt, &expiresAt, &url.Expired)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.FromContext(ctx).Error().Err(err).Msgf("Error loading URL %s", shortCode)
+		}
+		return nil, false
+	}
+
+	url.UserUUID = userUUID.UUID
+	url.DeletedAt = fromSQLiteTime(deletedAt)
+	url.ExpiresAt = fromSQLiteTime(expiresAt)
+
+	return &url, true
+}
+
+// GetURLsByLongURLs returns URL records with any of the long URLs
+func (s *SQLiteRepo) GetURLsByLongURLs(ctx context.Context, longURLs []string) ([]URL, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "get_urls_by_long_urls", time.Now())
+
+	if len(longURLs) == 0 {
+		return []URL{}, nil
+	}
+
+	args := make([]interface{}, 0, len(longURLs))
+	for _, longURL := range longURLs {
+		args = append(args, longURL)
+	}
+
+	query := sqliteGetURLsByLongURLs + strings.TrimSuffix(strings.Repeat("?,", len(longURLs)), ",") + ")"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	urls := make([]URL, 0, len(longURLs))
+
+	for rows.Next() {
+		var url URL
+		var userUUID uuid.NullUUID
+		if err = rows.Scan(&url.UUID, &url.LongURL, &url.ShortCode, &userUUID); err != nil {
+			return nil, err
+		}
+		url.UserUUID = userUUID.UUID
+		urls = append(urls, url)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+// GetURLsByUserID returns URL records by user ID
+func (s *SQLiteRepo) GetURLsByUserID(ctx context.Context, id uuid.UUID, limit, offset int64) ([]URL, int, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "get_urls_by_user_id", time.Now())
+
+	rows, err := s.db.QueryContext(ctx, sqliteGetURLsByUserID, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	urls := make([]URL, 0)
+	var total int
+
+	for rows.Next() {
+		url := URL{UserUUID: id}
+		var createdAt sql.NullString
+		if err = rows.Scan(&url.UUID, &url.LongURL, &url.ShortCode, &createdAt, &total); err != nil {
+			return nil, 0, err
+		}
+		url.CreatedAt = fromSQLiteTime(createdAt)
+		urls = append(urls, url)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return urls, total, nil
+}
+
+// GetURLsByUserIDAfter returns URL records by user ID following the cursor, newest first
+func (s *SQLiteRepo) GetURLsByUserIDAfter(ctx context.Context, id uuid.UUID, after URLCursor, limit int64) ([]URL, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "get_urls_by_user_id_after", time.Now())
+
+	rows, err := s.db.QueryContext(ctx, sqliteGetURLsByUserIDAfter, id, toSQLiteTime(after.CreatedAt), after.UUID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	urls := make([]URL, 0)
+
+	for rows.Next() {
+		url := URL{UserUUID: id}
+		var createdAt sql.NullString
+		if err = rows.Scan(&url.UUID, &url.LongURL, &url.ShortCode, &createdAt); err != nil {
+			return nil, err
+		}
+		url.CreatedAt = fromSQLiteTime(createdAt)
+		urls = append(urls, url)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+// FindURLsByUserID returns URL records of the user matching the filter
+func (s *SQLiteRepo) FindURLsByUserID(ctx context.Context, filter URLFilter) ([]URLListItem, int, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "find_urls_by_user_id", time.Now())
+
+	pattern := likePattern(filter.Query)
+	createdAfter := toSQLiteTime(filter.CreatedAfter)
+	createdBefore := toSQLiteTime(filter.CreatedBefore)
+	args := []interface{}{
+		filter.UserUUID, filter.Deleted, filter.Expired,
+		pattern, pattern, pattern,
+		createdAfter, createdAfter, createdBefore, createdBefore,
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, sqliteCountURLsByUserID, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Sort, filter.Sort, filter.Sort, filter.Limit, filter.Offset)
+
+	rows, err := s.db.QueryContext(ctx, sqliteFindURLsByUserID, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]URLListItem, 0)
+	for rows.Next() {
+		var item URLListItem
+		var createdAt, deletedAt, expiresAt sql.NullString
+
+		err = rows.Scan(&item.UUID, &item.LongURL, &item.ShortCode, &createdAt, &deletedAt, &expiresAt,
+			&item.Expired, &item.Clicks)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		item.UserUUID = filter.UserUUID
+		item.CreatedAt = fromSQLiteTime(createdAt)
+		item.DeletedAt = fromSQLiteTime(deletedAt)
+		item.ExpiresAt = fromSQLiteTime(expiresAt)
+		items = append(items, item)
+	}
+
+	return items, total, rows.Err()
+}
+
+// ExportURLsByUserID returns URL records of the user after the cursor including deleted and expired ones, newest first,
+// a zero cursor starts from the newest record
+func (s *SQLiteRepo) ExportURLsByUserID(ctx context.Context, id uuid.UUID, after URLCursor, limit int64) ([]URLListItem, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "export_urls_by_user_id", time.Now())
+
+	createdAt := toSQLiteTime(after.CreatedAt)
+
+	rows, err := s.db.QueryContext(ctx, sqliteExportURLsByUserID, id, createdAt, createdAt, after.UUID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]URLListItem, 0)
+	for rows.Next() {
+		item := URLListItem{URL: URL{UserUUID: id}}
+		var createdAt, deletedAt, expiresAt sql.NullString
+
+		err = rows.Scan(&item.UUID, &item.LongURL, &item.ShortCode, &createdAt, &deletedAt, &expiresAt,
+			&item.Expired, &item.Clicks)
+		if err != nil {
+			return nil, err
+		}
+
+		item.CreatedAt = fromSQLiteTime(createdAt)
+		item.DeletedAt = fromSQLiteTime(deletedAt)
+		item.ExpiresAt = fromSQLiteTime(expiresAt)
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// UpdateURL updates the long URL of a URL record owned by the user
+func (s *SQLiteRepo) UpdateURL(ctx context.Context, url URL) (*URL, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "update_url", time.Now())
+
+	var result URL
+	var userUUID uuid.NullUUID
+	var updatedAt sql.NullString
+
+	err := s.db.QueryRowContext(ctx, sqliteUpdateURL,
+		url.LongURL, toSQLiteTime(time.Now()), url.ShortCode, url.UserUUID).Scan(
+		&result.UUID, &result.LongURL, &result.ShortCode, &userUUID, &updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.ErrShortLinkNotFound
+		}
+		return nil, mapSQLiteError(err)
+	}
+
+	result.UserUUID = userUUID.UUID
+	result.UpdatedAt = fromSQLiteTime(updatedAt)
+
+	return &result, nil
+}
+
+// DeleteURLsByUserID deletes URL records by user ID and returns the deleted short codes
+func (s *SQLiteRepo) DeleteURLsByUserID(ctx context.Context, id uuid.UUID, shortCodes []string) ([]string, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "delete_urls_by_user_id", time.Now())
+
+	if len(shortCodes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(shortCodes)+2)
+	args = append(args, toSQLiteTime(time.Now()), id)
+	for _, shortCode := range shortCodes {
+		args = append(args, shortCode)
+	}
+
+	query := sqliteDeleteURLsByUserID + strings.TrimSuffix(strings.Repeat("?,", len(shortCodes)), ",") + ") RETURNING short_code"
+
+	return s.queryShortCodes(ctx, query, args...)
+}
+
+// DeleteURLsByUserIDs marks URL records of many users as deleted with a single statement
+func (s *SQLiteRepo) DeleteURLsByUserIDs(ctx context.Context, shortCodes map[uuid.UUID][]string) (map[uuid.UUID][]string, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "delete_urls_by_user_ids", time.Now())
+
+	deleted := make(map[uuid.UUID][]string)
+
+	args := []interface{}{toSQLiteTime(time.Now())}
+	for id, codes := range shortCodes {
+		for _, shortCode := range codes {
+			args = append(args, id, shortCode)
+		}
+	}
+
+	pairs := (len(args) - 1) / 2
+	if pairs == 0 {
+		return deleted, nil
+	}
+
+	query := sqliteDeleteURLsByUserIDs + strings.TrimSuffix(strings.Repeat("(?,?),", pairs), ",") + ") RETURNING user_uuid, short_code"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var shortCode string
+		if err = rows.Scan(&id, &shortCode); err != nil {
+			return nil, err
+		}
+		deleted[id] = append(deleted[id], shortCode)
+	}
+
+	return deleted, rows.Err()
+}
+
+// RestoreURLsByUserID restores URL records of the user deleted since the given time
+func (s *SQLiteRepo) RestoreURLsByUserID(ctx context.Context, id uuid.UUID, shortCodes []string, deletedSince time.Time) ([]string, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "restore_urls_by_user_id", time.Now())
+
+	if len(shortCodes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(shortCodes)+2)
+	args = append(args, id, toSQLiteTime(deletedSince))
+	for _, shortCode := range shortCodes {
+		args = append(args, shortCode)
+	}
+
+	query := sqliteRestoreURLsByUserID + strings.TrimSuffix(strings.Repeat("?,", len(shortCodes)), ",") + ") RETURNING short_code"
+
+	return s.queryShortCodes(ctx, query, args...)
+}
+
+// queryShortCodes runs a statement returning short codes of the affected records
+func (s *SQLiteRepo) queryShortCodes(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shortCodes []string
+	for rows.Next() {
+		var shortCode string
+		if err = rows.Scan(&shortCode); err != nil {
+			return nil, err
+		}
+		shortCodes = append(shortCodes, shortCode)
+	}
+
+	return shortCodes, rows.Err()
+}
+
+// PurgeURLs permanently removes URL records deleted before the given time, clicks are removed by the cascade
+func (s *SQLiteRepo) PurgeURLs(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "purge_urls", time.Now())
+
+	result, err := s.db.ExecContext(ctx, sqlitePurgeURLs, toSQLiteTime(deletedBefore))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// ExpireURLs marks URL records with passed expiration time as expired
+func (s *SQLiteRepo) ExpireURLs(ctx context.Context) (int64, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "expire_urls", time.Now())
+
+	result, err := s.db.ExecContext(ctx, sqliteExpireURLs, toSQLiteTime(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// CreateClicks stores click events in a single transaction
+func (s *SQLiteRepo) CreateClicks(ctx context.Context, clicks []Click) error {
+	defer metrics.ObserveRepository(SQLiteBackend, "create_clicks", time.Now())
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, click := range clicks {
+		_, err = tx.ExecContext(ctx, sqliteCreateClick,
+			uuid.New(), click.URLUUID, click.Referrer, click.UserAgent, click.IPHash, toSQLiteTime(click.CreatedAt))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetClickStats returns aggregated clicks statistics of a URL record, daily series starts from since
+func (s *SQLiteRepo) GetClickStats(ctx context.Context, urlID uuid.UUID, since time.Time) (*ClickStats, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "get_click_stats", time.Now())
+
+	stats := &ClickStats{Daily: []DailyClicks{}}
+
+	err := s.db.QueryRowContext(ctx, sqliteGetClicksSummary, urlID).Scan(&stats.Total, &stats.Unique)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqliteGetDailyClicks, urlID, toSQLiteTime(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day string
+		var daily DailyClicks
+		if err = rows.Scan(&day, &daily.Total, &daily.Unique); err != nil {
+			return nil, err
+		}
+
+		if daily.Date, err = time.Parse(time.DateOnly, day); err != nil {
+			return nil, err
+		}
+
+		stats.Daily = append(stats.Daily, daily)
+	}
+
+	return stats, rows.Err()
+}
+
+// NextSequence returns the next value of the short code sequence
+func (s *SQLiteRepo) NextSequence(ctx context.Context) (int64, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "next_sequence", time.Now())
+
+	var value int64
+	err := s.db.QueryRowContext(ctx, sqliteNextShortCodeSequence).Scan(&value)
+
+	return value, err
+}
+
+// CreateAPIToken stores a new API token
+func (s *SQLiteRepo) CreateAPIToken(ctx context.Context, token APIToken) (*APIToken, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "create_api_token", time.Now())
+
+	var createdAt sql.NullString
+	err := s.db.QueryRowContext(ctx, sqliteCreateAPIToken,
+		token.UUID, token.UserUUID, token.Name, token.Prefix, token.Hash,
+		toSQLiteList(token.Scopes)).Scan(&createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	token.CreatedAt = fromSQLiteTime(createdAt)
+	return &token, nil
+}
+
+// GetAPITokenByHash returns an active API token by the hash of its secret
+func (s *SQLiteRepo) GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, bool) {
+	defer metrics.ObserveRepository(SQLiteBackend, "get_api_token_by_hash", time.Now())
+
+	token, err := scanAPIToken(s.db.QueryRowContext(ctx, sqliteGetAPITokenByHash, hash))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.FromContext(ctx).Error().Err(err).Msg("Error loading API token")
+		}
+		return nil, false
+	}
+
+	token.Hash = hash
+	return token, true
+}
+
+// GetAPITokensByUserID returns active API tokens of the user
+func (s *SQLiteRepo) GetAPITokensByUserID(ctx context.Context, id uuid.UUID) ([]APIToken, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "get_api_tokens_by_user_id", time.Now())
+
+	rows, err := s.db.QueryContext(ctx, sqliteGetAPITokensByUserID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]APIToken, 0)
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// RevokeAPIToken revokes an active API token owned by the user
+func (s *SQLiteRepo) RevokeAPIToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	defer metrics.ObserveRepository(SQLiteBackend, "revoke_api_token", time.Now())
+
+	result, err := s.db.ExecContext(ctx, sqliteRevokeAPIToken, toSQLiteTime(time.Now()), tokenID, userID)
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return errors.ErrAPITokenNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDeleteTask stores a new delete task
+func (s *SQLiteRepo) EnqueueDeleteTask(ctx context.Context, task DeleteTask) error {
+	defer metrics.ObserveRepository(SQLiteBackend, "enqueue_delete_task", time.Now())
+
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+	if task.RunAt.IsZero() {
+		task.RunAt = task.CreatedAt
+	}
+
+	_, err := s.db.ExecContext(ctx, sqliteEnqueueDeleteTask,
+		task.UUID, task.UserUUID, toSQLiteList(task.ShortCodes),
+		toSQLiteTime(task.RunAt), toSQLiteTime(task.CreatedAt), task.TraceParent)
+
+	return err
+}
+
+// ClaimDeleteTasks leases due delete tasks until the given time, the longest waiting first
+func (s *SQLiteRepo) ClaimDeleteTasks(ctx context.Context, now, leaseUntil time.Time, limit int64) ([]DeleteTask, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "claim_delete_tasks", time.Now())
+
+	rows, err := s.db.QueryContext(ctx, sqliteClaimDeleteTasks, toSQLiteTime(leaseUntil), toSQLiteTime(now), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := make([]DeleteTask, 0)
+	for rows.Next() {
+		var task DeleteTask
+		var shortCodes string
+		var runAt, createdAt sql.NullString
+
+		err = rows.Scan(&task.UUID, &task.UserUUID, &shortCodes, &task.Attempts, &task.LastError, &runAt, &createdAt, &task.TraceParent)
+		if err != nil {
+			return nil, err
+		}
+
+		if task.ShortCodes, err = fromSQLiteList(shortCodes); err != nil {
+			return nil, err
+		}
+		task.RunAt = fromSQLiteTime(runAt)
+		task.CreatedAt = fromSQLiteTime(createdAt)
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// CompleteDeleteTasks removes processed delete tasks
+func (s *SQLiteRepo) CompleteDeleteTasks(ctx context.Context, ids []uuid.UUID) error {
+	defer metrics.ObserveRepository(SQLiteBackend, "complete_delete_tasks", time.Now())
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	query := sqliteCompleteDeleteTasks + strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + ")"
+
+	_, err := s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// RetryDeleteTask schedules the next attempt of a delete task
+func (s *SQLiteRepo) RetryDeleteTask(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error {
+	defer metrics.ObserveRepository(SQLiteBackend, "retry_delete_task", time.Now())
+
+	_, err := s.db.ExecContext(ctx, sqliteRetryDeleteTask, toSQLiteTime(runAt), lastError, id)
+	return err
+}
+
+// FailDeleteTask moves a delete task to the dead letters
+func (s *SQLiteRepo) FailDeleteTask(ctx context.Context, id uuid.UUID, lastError string) error {
+	defer metrics.ObserveRepository(SQLiteBackend, "fail_delete_task", time.Now())
+
+	_, err := s.db.ExecContext(ctx, sqliteFailDeleteTask, toSQLiteTime(time.Now()), lastError, id)
+	return err
+}
+
+// CountDeleteTasks counts pending and dead-lettered delete tasks
+func (s *SQLiteRepo) CountDeleteTasks(ctx context.Context) (DeleteTaskStats, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "count_delete_tasks", time.Now())
+
+	var stats DeleteTaskStats
+	err := s.db.QueryRowContext(ctx, sqliteCountDeleteTasks).Scan(&stats.Pending, &stats.Failed)
+
+	return stats, err
+}
+
+// SaveDeleteJob stores the state of a delete job, skipped short codes are stored as JSON
+func (s *SQLiteRepo) SaveDeleteJob(ctx context.Context, job DeleteJob) error {
+	defer metrics.ObserveRepository(SQLiteBackend, "save_delete_job", time.Now())
+
+	skipped, err := json.Marshal(orEmpty(job.Skipped))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, sqliteSaveDeleteJob,
+		job.UUID, job.UserUUID, job.Status, toSQLiteList(job.ShortCodes), toSQLiteList(job.Deleted), string(skipped),
+		job.Error, toSQLiteTime(job.CreatedAt), toSQLiteTime(job.FinishedAt))
+
+	return err
+}
+
+// GetDeleteJob returns the state of a delete job
+func (s *SQLiteRepo) GetDeleteJob(ctx context.Context, id uuid.UUID) (*DeleteJob, bool) {
+	defer metrics.ObserveRepository(SQLiteBackend, "get_delete_job", time.Now())
+
+	var job DeleteJob
+	var shortCodes, deleted, skipped string
+	var createdAt, finishedAt sql.NullString
+
+	err := s.db.QueryRowContext(ctx, sqliteGetDeleteJob, id).Scan(
+		&job.UUID, &job.UserUUID, &job.Status, &shortCodes, &deleted, &skipped, &job.Error, &createdAt, &finishedAt)
+	if err == nil {
+		job.ShortCodes, err = fromSQLiteList(shortCodes)
+	}
+	if err == nil {
+		job.Deleted, err = fromSQLiteList(deleted)
+	}
+	if err == nil {
+		err = json.Unmarshal([]byte(skipped), &job.Skipped)
+	}
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.FromContext(ctx).Error().Err(err).Msg("Error loading delete job")
+		}
+		return nil, false
+	}
+
+	job.CreatedAt = fromSQLiteTime(createdAt)
+	job.FinishedAt = fromSQLiteTime(finishedAt)
+
+	return &job, true
+}
+
+// PurgeDeleteJobs removes delete jobs finished before the given time
+func (s *SQLiteRepo) PurgeDeleteJobs(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "purge_delete_jobs", time.Now())
+
+	result, err := s.db.ExecContext(ctx, sqlitePurgeDeleteJobs, toSQLiteTime(finishedBefore))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// CountURLs counts active URL records, neither deleted nor expired
+func (s *SQLiteRepo) CountURLs(ctx context.Context) (int64, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "count_urls", time.Now())
+
+	var count int64
+	err := s.db.QueryRowContext(ctx, sqliteCountURLs).Scan(&count)
+
+	return count, err
+}
+
+// CountUsers counts distinct users owning active URL records,
+// links created without a user store a NULL owner, which is not counted
+func (s *SQLiteRepo) CountUsers(ctx context.Context) (int64, error) {
+	defer metrics.ObserveRepository(SQLiteBackend, "count_users", time.Now())
+
+	var count int64
+	err := s.db.QueryRowContext(ctx, sqliteCountUsers).Scan(&count)
+
+	return count, err
+}
+
+// Ping checks the database connection
+func (s *SQLiteRepo) Ping(ctx context.Context) error {
+	defer metrics.ObserveRepository(SQLiteBackend, "ping", time.Now())
+
+	return s.db.PingContext(ctx)
+}
+
+// Close closes the database
+func (s *SQLiteRepo) Close() {
+	s.db.Close()
+}
+
+// mapSQLiteError converts SQLite unique constraint errors into application errors
+func mapSQLiteError(err error) error {
+	_, column, ok := strings.Cut(err.Error(), sqliteUniqueViolation)
+	if !ok {
+		return err
+	}
+	column, _, _ = strings.Cut(column, " ")
+
+	switch column {
+	case "urls.short_code":
+		return errors.ErrShortCodeAlreadyExists
+	case "urls.long_url":
+		return errors.ErrURLAlreadyExists
+	}
+
+	return err
+}
+
+// scanAPIToken scans an API token row, scopes are stored as a JSON array
+func scanAPIToken(row interface{ Scan(dest ...any) error }) (*APIToken, error) {
+	var token APIToken
+	var scopes string
+	var createdAt sql.NullString
+
+	err := row.Scan(&token.UUID, &token.UserUUID, &token.Name, &token.Prefix, &scopes, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Scopes, err = fromSQLiteList(scopes); err != nil {
+		return nil, err
+	}
+	token.CreatedAt = fromSQLiteTime(createdAt)
+
+	return &token, nil
+}
+
+// toSQLiteTime converts time into a nullable stored timestamp
+func toSQLiteTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: t.UTC().Format(SQLiteTimeLayout), Valid: true}
+}
+
+// fromSQLiteTime converts a nullable stored timestamp into time
+func fromSQLiteTime(s sql.NullString) time.Time {
+	if !s.Valid {
+		return time.Time{}
+	}
+
+	// NOTE: fractional seconds are optional when parsing, so CURRENT_TIMESTAMP values are accepted too
+	t, err := time.Parse(time.DateTime, s.String)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// toSQLiteList encodes a list into a JSON array, SQLite has no array type like the PostgreSQL columns
+func toSQLiteList(values []string) string {
+	if values == nil {
+		values = []string{}
+	}
+
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// fromSQLiteList decodes a JSON array list, an empty column is an empty list
+func fromSQLiteList(s string) ([]string, error) {
+	values := []string{}
+	if s == "" {
+		return values, nil
+	}
+
+	if err := json.Unmarshal([]byte(s), &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// toNullUUID converts a zero UUID into NULL
+func toNullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
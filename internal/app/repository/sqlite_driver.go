@@ -0,0 +1,9 @@
+package repository
+
+import (
+	// NOTE: the driver is registered under SQLiteDriver, it is the only place to change when switching drivers
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteDriver is the database/sql driver name of the SQLite repository
+const SQLiteDriver = "sqlite"
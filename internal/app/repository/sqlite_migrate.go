@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GooseVersionTable is the migrations version table shared with the goose CLI
+const GooseVersionTable = "goose_db_version"
+
+// gooseUp is the marker of the up section of a goose migration
+const gooseUp = "-- +goose Up"
+
+// gooseDown is the marker of the down section of a goose migration
+const gooseDown = "-- +goose Down"
+
+// migration is a single goose SQL migration
+type migration struct {
+	version int64
+	name    string
+	up      string
+}
+
+// migrateSQLite applies pending goose migrations, the version table stays compatible with the goose CLI
+func migrateSQLite(ctx context.Context, db *sql.DB, migrations fs.FS) error {
+	pending, err := readMigrations(migrations)
+	if err != nil {
+		return err
+	}
+
+	if _, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+GooseVersionTable+` (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		version_id INTEGER NOT NULL,
+		is_applied INTEGER NOT NULL,
+		tstamp TIMESTAMP DEFAULT (datetime('now'))
+	)`); err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		if applied[m.version] {
+			continue
+		}
+
+		if err = applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+// appliedVersions returns the applied migration versions, the latest record of a version wins
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int64]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version_id, is_applied FROM `+GooseVersionTable+` ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		if err = rows.Scan(&version, &isApplied); err != nil {
+			return nil, err
+		}
+		applied[version] = isApplied
+	}
+
+	return applied, rows.Err()
+}
+
+// applyMigration runs the up section and records the version in a single transaction
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, m.up); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `INSERT INTO `+GooseVersionTable+` (version_id, is_applied) VALUES (?, 1)`, m.version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// readMigrations reads goose SQL migrations ordered by version
+func readMigrations(migrations fs.FS) ([]migration, error) {
+	paths, err := fs.Glob(migrations, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]migration, 0, len(paths))
+	for _, p := range paths {
+		name := path.Base(p)
+
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: missing version prefix", name)
+		}
+
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %w", name, err)
+		}
+
+		content, err := fs.ReadFile(migrations, p)
+		if err != nil {
+			return nil, err
+		}
+
+		_, up, ok := strings.Cut(string(content), gooseUp)
+		if !ok {
+			return nil, fmt.Errorf("migration %s: missing %q section", name, gooseUp)
+		}
+		up, _, _ = strings.Cut(up, gooseDown)
+
+		result = append(result, migration{version: version, name: name, up: strings.TrimSpace(up)})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].version < result[j].version
+	})
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		expected []migration
+		error    bool
+	}{
+		{
+			name: "Ordered by version",
+			files: fstest.MapFS{
+				"2_second.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id INTEGER);\n\n-- +goose Down\nDROP TABLE b;\n")},
+				"1_first.sql":  {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n")},
+				"README.md":    {Data: []byte("ignored")},
+			},
+			expected: []migration{
+				{version: 1, name: "1_first.sql", up: "CREATE TABLE a (id INTEGER);"},
+				{version: 2, name: "2_second.sql", up: "CREATE TABLE b (id INTEGER);"},
+			},
+		},
+		{
+			name:  "Missing version",
+			files: fstest.MapFS{"first.sql": {Data: []byte("-- +goose Up\nSELECT 1;")}},
+			error: true,
+		},
+		{
+			name:  "Invalid version",
+			files: fstest.MapFS{"v1_first.sql": {Data: []byte("-- +goose Up\nSELECT 1;")}},
+			error: true,
+		},
+		{
+			name:  "Missing up section",
+			files: fstest.MapFS{"1_first.sql": {Data: []byte("SELECT 1;")}},
+			error: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := readMigrations(tt.files)
+			if tt.error {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_migrateSQLite(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open(SQLiteDriver, t.TempDir()+"/migrate-test.db")
+	require.NoError(t, err)
+	defer db.Close()
+
+	files := fstest.MapFS{
+		"1_first.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n-- +goose Down\nDROP TABLE a;\n")},
+	}
+
+	require.NoError(t, migrateSQLite(ctx, db, files))
+
+	// NOTE: applied migrations are skipped, a second CREATE TABLE would fail
+	require.NoError(t, migrateSQLite(ctx, db, files))
+
+	files["2_second.sql"] = &fstest.MapFile{Data: []byte("-- +goose Up\nCREATE TABLE b (id INTEGER);\n")}
+	require.NoError(t, migrateSQLite(ctx, db, files))
+
+	var versions []int64
+	rows, err := db.QueryContext(ctx, "SELECT version_id FROM "+GooseVersionTable+" WHERE is_applied = 1 ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		require.NoError(t, rows.Scan(&version))
+		versions = append(versions, version)
+	}
+	assert.Equal(t, []int64{1, 2}, versions)
+
+	files["3_broken.sql"] = &fstest.MapFile{Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n")}
+	assert.Error(t, migrateSQLite(ctx, db, files))
+}
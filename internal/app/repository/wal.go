@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shortly/internal/app/errors"
+)
+
+// WALSuffix is appended to the file storage path to get the write-ahead log path
+const WALSuffix = ".wal"
+
+// SyncAlways fsyncs the write-ahead log after every appended entry
+const SyncAlways = "always"
+
+// SyncInterval fsyncs the write-ahead log periodically
+const SyncInterval = "interval"
+
+// SyncNever leaves flushing of the write-ahead log to the operating system
+const SyncNever = "never"
+
+// OpCreate is the journal operation of created URL records
+const OpCreate = "create"
+
+// OpUpdate is the journal operation of updated URL records
+const OpUpdate = "update"
+
+// OpDelete is the journal operation of deleted URL records
+const OpDelete = "delete"
+
+// OpRestore is the journal operation of restored URL records
+const OpRestore = "restore"
+
+// OpPurge is the journal operation of permanently removed URL records
+const OpPurge = "purge"
+
+// OpCreateToken is the journal operation of created API tokens
+const OpCreateToken = "create_token"
+
+// OpRevokeToken is the journal operation of revoked API tokens
+const OpRevokeToken = "revoke_token"
+
+// OpEnqueueTask is the journal operation of queued delete tasks
+const OpEnqueueTask = "enqueue_task"
+
+// OpUpdateTask is the journal operation of claimed, retried and dead-lettered delete tasks
+const OpUpdateTask = "update_task"
+
+// OpCompleteTask is the journal operation of processed delete tasks
+const OpCompleteTask = "complete_task"
+
+// OpSaveJob is the journal operation of created and updated delete jobs
+const OpSaveJob = "save_job"
+
+// OpPurgeJobs is the journal operation of removed finished delete jobs
+const OpPurgeJobs = "purge_jobs"
+
+// OpSequence is the journal operation of short code sequence increments
+const OpSequence = "sequence"
+
+// JournalEntry is a single mutation of the in-memory repository state
+type JournalEntry struct {
+	Op         string       `json:"op"`
+	URLs       []URL        `json:"urls,omitempty"`
+	Tokens     []APIToken   `json:"tokens,omitempty"`
+	Tasks      []DeleteTask `json:"tasks,omitempty"`
+	Jobs       []DeleteJob  `json:"jobs,omitempty"`
+	UserUUID   uuid.UUID    `json:"user_uuid,omitempty"`
+	ShortCodes []string     `json:"short_codes,omitempty"`
+	DeletedAt  time.Time    `json:"deleted_at,omitempty"`
+	Sequence   int64        `json:"sequence,omitempty"`
+}
+
+// Journal is an interface for recording in-memory repository mutations
+type Journal interface {
+	Append(entry JournalEntry) error
+}
+
+// WAL is an interface for the write-ahead log of the in-memory repository
+type WAL interface {
+	Journal
+	// Replay passes every logged entry to fn, a torn entry at the end of the log is discarded
+	Replay(fn func(entry JournalEntry)) error
+	// Compact runs the snapshot function and truncates the log once it succeeds
+	Compact(snapshot func() error) error
+	Sync() error
+	Close() error
+}
+
+type walRepo struct {
+	mu         sync.Mutex
+	filePath   string
+	syncPolicy string
+	file       *os.File
+}
+
+// NewWAL creates a new write-ahead log instance, the file is opened on first use
+func NewWAL(filePath, syncPolicy string) WAL {
+	return &walRepo{filePath: filePath, syncPolicy: syncPolicy}
+}
+
+// Append writes the entry to the end of the log
+func (w *walRepo) Append(entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err = w.open(); err != nil {
+		return err
+	}
+
+	if _, err = w.file.Write(append(line, '\n')); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	if w.syncPolicy == SyncAlways {
+		if err = w.file.Sync(); err != nil {
+			return errors.ErrFailedToWriteToFile
+		}
+	}
+
+	return nil
+}
+
+// Replay reads the log from the beginning
+func (w *walRepo) Replay(fn func(entry JournalEntry)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return errors.ErrorFailedToReadFromFile
+	}
+
+	reader := bufio.NewReader(w.file)
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return errors.ErrorFailedToReadFromFile
+		}
+
+		// NOTE: a crash in the middle of a write leaves an incomplete last line, it was never acknowledged
+		complete := len(line) > 0 && line[len(line)-1] == '\n'
+
+		var entry JournalEntry
+		if !complete || json.Unmarshal(bytes.TrimSpace(line), &entry) != nil {
+			break
+		}
+
+		fn(entry)
+		offset += int64(len(line))
+	}
+
+	if err := w.file.Truncate(offset); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	return nil
+}
+
+// Compact truncates the log after the snapshot is written, appends wait until it completes
+func (w *walRepo) Compact(snapshot func() error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := snapshot(); err != nil {
+		return err
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	if err := w.file.Truncate(0); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	if err := w.file.Sync(); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	return nil
+}
+
+// Sync flushes the log to the disk
+func (w *walRepo) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	if err := w.file.Sync(); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	return nil
+}
+
+// Close flushes and closes the log file
+func (w *walRepo) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	defer func() { w.file = nil }()
+
+	if err := w.file.Sync(); err != nil {
+		w.file.Close()
+		return errors.ErrFailedToWriteToFile
+	}
+
+	if err := w.file.Close(); err != nil {
+		return errors.ErrFailedToWriteToFile
+	}
+
+	return nil
+}
+
+// open opens the log file for appending if it is not open yet
+func (w *walRepo) open() error {
+	if w.file != nil {
+		return nil
+	}
+
+	file, err := os.OpenFile(w.filePath, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.ErrFailedToOpenFile
+	}
+
+	w.file = file
+	return nil
+}
@@ -0,0 +1,148 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/repository/wal.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/repository/wal.go -destination=internal/app/repository/wal_mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJournal is a mock of Journal interface.
+type MockJournal struct {
+	ctrl     *gomock.Controller
+	recorder *MockJournalMockRecorder
+	isgomock struct{}
+}
+
+// MockJournalMockRecorder is the mock recorder for MockJournal.
+type MockJournalMockRecorder struct {
+	mock *MockJournal
+}
+
+// NewMockJournal creates a new mock instance.
+func NewMockJournal(ctrl *gomock.Controller) *MockJournal {
+	mock := &MockJournal{ctrl: ctrl}
+	mock.recorder = &MockJournalMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJournal) EXPECT() *MockJournalMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockJournal) Append(entry JournalEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockJournalMockRecorder) Append(entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockJournal)(nil).Append), entry)
+}
+
+// MockWAL is a mock of WAL interface.
+type MockWAL struct {
+	ctrl     *gomock.Controller
+	recorder *MockWALMockRecorder
+	isgomock struct{}
+}
+
+// MockWALMockRecorder is the mock recorder for MockWAL.
+type MockWALMockRecorder struct {
+	mock *MockWAL
+}
+
+// NewMockWAL creates a new mock instance.
+func NewMockWAL(ctrl *gomock.Controller) *MockWAL {
+	mock := &MockWAL{ctrl: ctrl}
+	mock.recorder = &MockWALMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWAL) EXPECT() *MockWALMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockWAL) Append(entry JournalEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockWALMockRecorder) Append(entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockWAL)(nil).Append), entry)
+}
+
+// Close mocks base method.
+func (m *MockWAL) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockWALMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockWAL)(nil).Close))
+}
+
+// Compact mocks base method.
+func (m *MockWAL) Compact(snapshot func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockWALMockRecorder) Compact(snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockWAL)(nil).Compact), snapshot)
+}
+
+// Replay mocks base method.
+func (m *MockWAL) Replay(fn func(JournalEntry)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockWALMockRecorder) Replay(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockWAL)(nil).Replay), fn)
+}
+
+// Sync mocks base method.
+func (m *MockWAL) Sync() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockWALMockRecorder) Sync() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockWAL)(nil).Sync))
+}
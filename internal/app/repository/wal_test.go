@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/errors"
+)
+
+func Test_WAL_AppendReplay(t *testing.T) {
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	deletedAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	entries := []JournalEntry{
+		{Op: OpCreate, URLs: []URL{{UUID: UUID, LongURL: "http://example.com", ShortCode: "abcd1234", UserUUID: UserUUID}}},
+		{Op: OpDelete, UserUUID: UserUUID, ShortCodes: []string{"abcd1234"}, DeletedAt: deletedAt},
+	}
+
+	tests := []struct {
+		name       string
+		syncPolicy string
+		tail       string
+	}{
+		{
+			name:       "Sync always",
+			syncPolicy: SyncAlways,
+		},
+		{
+			name:       "Sync never",
+			syncPolicy: SyncNever,
+		},
+		{
+			name:       "Torn tail",
+			syncPolicy: SyncAlways,
+			tail:       `{"op":"create","urls":[{"uuid":"6455bd07`,
+		},
+		{
+			name:       "Corrupted tail",
+			syncPolicy: SyncAlways,
+			tail:       "garbage\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := t.TempDir() + "/store-test.json" + WALSuffix
+
+			wal := NewWAL(filePath, tt.syncPolicy)
+			for _, entry := range entries {
+				require.NoError(t, wal.Append(entry))
+			}
+			require.NoError(t, wal.Close())
+
+			if tt.tail != "" {
+				file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_APPEND, 0644)
+				require.NoError(t, err)
+				_, err = file.WriteString(tt.tail)
+				require.NoError(t, err)
+				require.NoError(t, file.Close())
+			}
+
+			var replayed []JournalEntry
+			wal = NewWAL(filePath, tt.syncPolicy)
+			require.NoError(t, wal.Replay(func(entry JournalEntry) {
+				replayed = append(replayed, entry)
+			}))
+			assert.Equal(t, entries, replayed)
+
+			// NOTE: entries appended after a torn tail must stay readable
+			require.NoError(t, wal.Append(entries[0]))
+			require.NoError(t, wal.Close())
+
+			replayed = nil
+			require.NoError(t, NewWAL(filePath, tt.syncPolicy).Replay(func(entry JournalEntry) {
+				replayed = append(replayed, entry)
+			}))
+			assert.Equal(t, append(entries, entries[0]), replayed)
+		})
+	}
+}
+
+func Test_WAL_Compact(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot func() error
+		expected error
+		size     int64
+	}{
+		{
+			name:     "Success",
+			snapshot: func() error { return nil },
+			expected: nil,
+			size:     0,
+		},
+		{
+			name:     "Snapshot failure",
+			snapshot: func() error { return errors.ErrFailedToWriteToFile },
+			expected: errors.ErrFailedToWriteToFile,
+			size:     -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := t.TempDir() + "/store-test.json" + WALSuffix
+
+			wal := NewWAL(filePath, SyncAlways)
+			require.NoError(t, wal.Append(JournalEntry{Op: OpCreate, URLs: []URL{{ShortCode: "abcd1234"}}}))
+
+			before, err := os.Stat(filePath)
+			require.NoError(t, err)
+
+			err = wal.Compact(tt.snapshot)
+			assert.Equal(t, tt.expected, err)
+
+			after, err := os.Stat(filePath)
+			require.NoError(t, err)
+
+			if tt.size < 0 {
+				assert.Equal(t, before.Size(), after.Size())
+			} else {
+				assert.Equal(t, tt.size, after.Size())
+			}
+
+			require.NoError(t, wal.Close())
+		})
+	}
+}
+
+func Test_WAL_OpenFailure(t *testing.T) {
+	wal := NewWAL(t.TempDir()+"/missing/store-test.json"+WALSuffix, SyncAlways)
+
+	assert.Equal(t, errors.ErrFailedToOpenFile, wal.Append(JournalEntry{Op: OpCreate}))
+	assert.Equal(t, errors.ErrFailedToOpenFile, wal.Replay(func(JournalEntry) {}))
+	assert.NoError(t, wal.Sync())
+	assert.NoError(t, wal.Close())
+}
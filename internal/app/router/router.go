@@ -1,6 +1,7 @@
 package router
 
 import (
+	"net"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -8,8 +9,14 @@ import (
 
 	"shortly/internal/app/api"
 	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/health"
 	"shortly/internal/app/middleware/auth"
 	"shortly/internal/app/middleware/compress"
+	"shortly/internal/app/middleware/metrics"
+	"shortly/internal/app/middleware/ratelimit"
+	"shortly/internal/app/middleware/tracing"
+	"shortly/internal/app/middleware/trusted"
 	"shortly/internal/app/repository"
 	"shortly/internal/app/service"
 	"shortly/internal/app/worker"
@@ -17,49 +24,144 @@ import (
 )
 
 // NewRouter creates a new router instance
-func NewRouter(cfg *config.Config, repo repository.Repository, worker worker.Worker, appLogger *logger.Logger) http.Handler {
+func NewRouter(cfg *config.Config, repo repository.Repository, shortener *service.URLService, worker worker.Worker, recorder worker.ClickRecorder, appLogger *logger.Logger) http.Handler {
 	rand := service.NewSecureRandom()
-	shortener := service.NewURLService(cfg, repo, rand, worker)
-	shortenerHandler := api.NewURLHandler(cfg, shortener)
+	analytics := service.NewAnalyticsService(cfg, repo, recorder)
+	shortenerHandler := api.NewURLHandler(cfg, shortener, analytics)
+	statsHandler := api.NewStatsHandler(analytics)
 
-	health := service.NewHealthService(repo)
+	health := service.NewHealthService(repo, healthRegistry(cfg, repo, worker))
 	healthHandler := api.NewHealthHandler(health)
+	proxies := trustedProxies(cfg, appLogger)
+	trustedNetwork := trustedSubnet(cfg, proxies, appLogger)
+
+	internalStats := service.NewInternalStatsService(cfg, repo)
+	internalHandler := api.NewInternalHandler(internalStats)
 
 	authenticator := service.NewAuthService(cfg)
+	tokens := service.NewTokenService(repo, rand)
+	tokenHandler := api.NewTokenHandler(tokens)
+	authenticate := auth.Middleware(authenticator, tokens)
+	canRead := auth.RequireScope(dto.ScopeLinksRead)
+	canWrite := auth.RequireScope(dto.ScopeLinksWrite)
+
+	limiter := ratelimit.NewMemoryStore()
+	createLimit := rateLimit(limiter, ratelimit.CreateScope, cfg.RateLimitCreate, proxies, appLogger)
+	batchLimit := rateLimit(limiter, ratelimit.BatchScope, cfg.RateLimitBatch, proxies, appLogger)
+	redirectLimit := rateLimit(limiter, ratelimit.RedirectScope, cfg.RateLimitRedirect, proxies, appLogger)
 
 	router := chi.NewRouter()
 	router.Use(
 		cors.Handler(cors.Options{
 			AllowedOrigins: []string{cfg.ClientURL},
-			AllowedMethods: []string{"GET", "POST", "OPTIONS"},
-			AllowedHeaders: []string{"Content-Type"},
+			AllowedMethods: []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders: []string{"Content-Type", "Authorization", "traceparent", "tracestate"},
 			MaxAge:         300,
 		}),
+		tracing.Middleware,
 		appLogger.Middleware,
+		metrics.Middleware,
 		compress.Middleware,
 	)
 
 	router.Get("/live", healthHandler.HandleLiveness)
 	router.Get("/ready", healthHandler.HandleReadiness)
 	router.Get("/ping", healthHandler.HandlePing)
+	router.With(trustedNetwork).Get("/health/details", healthHandler.HandleHealthDetails)
+
+	// NOTE: internal routes
+	router.Group(func(r chi.Router) {
+		r.Use(trustedNetwork)
+
+		r.Get("/api/internal/stats", internalHandler.HandleGetStats)
+	})
 
 	// NOTE: protected routes
 	router.Group(func(r chi.Router) {
-		r.Use(auth.Middleware(authenticator))
+		r.Use(authenticate)
 
-		r.Get("/api/user/urls", shortenerHandler.HandleGetUserURLs)
-		r.Delete("/api/user/urls", shortenerHandler.HandleBatchDeleteUserURLs)
+		r.With(canRead).Get("/api/user/urls", shortenerHandler.HandleGetUserURLs)
+		r.With(canRead).Get("/api/user/urls/export", shortenerHandler.HandleExportUserURLs)
+		r.With(canRead).Get("/api/v2/user/urls", shortenerHandler.HandleListUserURLs)
+		r.With(canWrite).Delete("/api/user/urls", shortenerHandler.HandleBatchDeleteUserURLs)
+		r.With(canWrite).Post("/api/user/urls/restore", shortenerHandler.HandleRestoreUserURLs)
+		r.With(canRead).Get("/api/user/jobs/{id}", shortenerHandler.HandleGetDeleteJob)
+		r.With(canWrite).Patch("/api/user/urls/{id}", shortenerHandler.HandleUpdateUserURL)
+		r.With(canRead).Get("/api/user/urls/{id}/stats", statsHandler.HandleGetURLStats)
+	})
+
+	// NOTE: API tokens are managed with the session cookie only, a leaked token cannot mint new ones
+	router.Group(func(r chi.Router) {
+		r.Use(authenticate, auth.RequireSession)
+
+		r.Post("/api/user/tokens", tokenHandler.HandleCreateToken)
+		r.Get("/api/user/tokens", tokenHandler.HandleGetTokens)
+		r.Delete("/api/user/tokens/{id}", tokenHandler.HandleRevokeToken)
 	})
 
 	// NOTE: public routes
 	router.Group(func(r chi.Router) {
-		r.Use(auth.Middleware(authenticator))
-		r.Post("/api/shorten", shortenerHandler.HandleCreateShortLink)
-		r.Get("/api/shorten/{id}", shortenerHandler.HandleGetShortLink)
-		r.Post("/api/shorten/batch", shortenerHandler.HandleBatchCreateShortLink)
-		r.Post("/", shortenerHandler.DeprecatedHandleCreateShortLink)
-		r.Get("/{id}", shortenerHandler.DeprecatedHandleGetShortLink)
+		r.Use(authenticate)
+		r.With(createLimit, canWrite).Post("/api/shorten", shortenerHandler.HandleCreateShortLink)
+		r.With(redirectLimit).Get("/api/shorten/{id}", shortenerHandler.HandleGetShortLink)
+		r.With(batchLimit, canWrite).Post("/api/shorten/batch", shortenerHandler.HandleBatchCreateShortLink)
+		r.With(batchLimit, canWrite).Post("/api/shorten/import", shortenerHandler.HandleImportShortLinks)
+		r.With(createLimit, canWrite).Post("/", shortenerHandler.DeprecatedHandleCreateShortLink)
+		r.With(redirectLimit).Get("/{id}", shortenerHandler.DeprecatedHandleGetShortLink)
 	})
 
 	return router
 }
+
+// rateLimit creates the rate limit middleware of the scope, an invalid limit is logged and disables limiting
+func rateLimit(store ratelimit.Store, scope, value string, proxies []*net.IPNet, appLogger *logger.Logger) func(http.Handler) http.Handler {
+	limit, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		appLogger.Warn().Err(err).Msgf("Rate limiting of %s routes is disabled", scope)
+	}
+
+	return ratelimit.Middleware(store, scope, limit, proxies)
+}
+
+// healthRegistry registers the health checks of the components in use, only the database takes the application down
+func healthRegistry(cfg *config.Config, repo repository.Repository, worker worker.Worker) health.Registry {
+	registry := health.NewRegistry(cfg)
+
+	if db, ok := repo.(repository.HealthChecker); ok {
+		registry.Register("database", health.CheckOptions{Critical: true}, db.Ping)
+	}
+
+	if _, ok := repo.(repository.InMemory); ok && cfg.FileStoragePath != "" {
+		registry.Register("file_storage", health.CheckOptions{}, health.WritableCheck(cfg.FileStoragePath))
+	}
+
+	registry.Register("delete_worker", health.CheckOptions{}, worker.Check)
+
+	if config.IsTLSEnabled(cfg) {
+		registry.Register("tls_certificate", health.CheckOptions{}, health.CertificateCheck(cfg.Certificate))
+	}
+
+	return registry
+}
+
+// trustedSubnet creates the middleware restricting routes to the trusted subnet,
+// an invalid subnet is logged and denies every client
+func trustedSubnet(cfg *config.Config, proxies []*net.IPNet, appLogger *logger.Logger) func(http.Handler) http.Handler {
+	network, err := trusted.ParseSubnet(cfg.TrustedSubnet)
+	if err != nil {
+		appLogger.Warn().Err(err).Msgf("Trusted subnet %q is ignored, trusted routes are denied", cfg.TrustedSubnet)
+	}
+
+	return trusted.Middleware(network, proxies)
+}
+
+// trustedProxies parses the trusted proxies once for every middleware resolving the client IP,
+// invalid proxies are logged and not trusted
+func trustedProxies(cfg *config.Config, appLogger *logger.Logger) []*net.IPNet {
+	proxies, err := trusted.ParseProxies(cfg.TrustedProxies)
+	if err != nil {
+		appLogger.Warn().Err(err).Msgf("Trusted proxies %q are ignored, proxy headers are not honoured", cfg.TrustedProxies)
+	}
+
+	return proxies
+}
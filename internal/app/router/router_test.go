@@ -2,16 +2,23 @@ package router
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/health"
 	"shortly/internal/app/repository"
+	"shortly/internal/app/service"
 	"shortly/internal/app/worker"
 	"shortly/internal/logger"
 )
@@ -26,8 +33,9 @@ func Test_HandleLiveness(t *testing.T) {
 		DSN:    cfg.DatabaseDSN,
 		Logger: appLogger,
 	})
-	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, appLogger)
-	router := NewRouter(cfg, repo, appWorker, appLogger)
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
 
 	req := httptest.NewRequest(http.MethodGet, "/live", nil)
 	w := httptest.NewRecorder()
@@ -40,6 +48,37 @@ func Test_HandleLiveness(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func Test_CORS(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		ClientURL: "http://localhost:3000",
+	}
+	appLogger := logger.NewLogger()
+	repo, _ := repository.NewRepository(ctx, &repository.Factory{
+		Logger: appLogger,
+	})
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/api/user/urls", nil)
+			req.Header.Set("Origin", cfg.ClientURL)
+			req.Header.Set("Access-Control-Request-Method", method)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, cfg.ClientURL, resp.Header.Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, method, resp.Header.Get("Access-Control-Allow-Methods"))
+		})
+	}
+}
+
 func Test_HandleReadiness(t *testing.T) {
 	ctx := context.Background()
 	cfg := &config.Config{
@@ -50,8 +89,9 @@ func Test_HandleReadiness(t *testing.T) {
 		DSN:    cfg.DatabaseDSN,
 		Logger: appLogger,
 	})
-	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, appLogger)
-	router := NewRouter(cfg, repo, appWorker, appLogger)
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
 
 	req := httptest.NewRequest(http.MethodGet, "/ready", nil)
 	w := httptest.NewRecorder()
@@ -74,8 +114,9 @@ func Test_HealthCheck(t *testing.T) {
 		DSN:    cfg.DatabaseDSN,
 		Logger: appLogger,
 	})
-	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, appLogger)
-	router := NewRouter(cfg, repo, appWorker, appLogger)
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	w := httptest.NewRecorder()
@@ -95,8 +136,9 @@ func Test_CreateShortLink(t *testing.T) {
 	}
 	appLogger := logger.NewLogger()
 	repo := repository.NewInMemoryRepository()
-	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, appLogger)
-	router := NewRouter(cfg, repo, appWorker, appLogger)
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
 
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("https://example.com"))
 	w := httptest.NewRecorder()
@@ -116,8 +158,9 @@ func Test_GetShortLink(t *testing.T) {
 	}
 	appLogger := logger.NewLogger()
 	repo := repository.NewInMemoryRepository()
-	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, appLogger)
-	router := NewRouter(cfg, repo, appWorker, appLogger)
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
 
 	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
 
@@ -138,3 +181,266 @@ func Test_GetShortLink(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
+
+func Test_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		BaseURL:         "http://localhost:8080",
+		RateLimitCreate: "1/h",
+	}
+	appLogger := logger.NewLogger()
+	repo := repository.NewInMemoryRepository()
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
+
+	codes := make([]int, 0, 2)
+	for _, url := range []string{"https://example.com", "https://github.com"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(`{"url":"`+url+`"}`))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+
+		if w.Code == http.StatusTooManyRequests {
+			assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		}
+	}
+
+	assert.Equal(t, []int{http.StatusCreated, http.StatusTooManyRequests}, codes)
+}
+
+func Test_APITokens(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		BaseURL:   "http://localhost:8080",
+		SecretKey: "jwt-secret-key",
+	}
+	appLogger := logger.NewLogger()
+	repo := repository.NewInMemoryRepository()
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
+
+	// NOTE: the token is issued within a cookie session
+	req := httptest.NewRequest(http.MethodPost, "/api/user/tokens", strings.NewReader(`{"name":"ci","scopes":["links:read"]}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created dto.CreateAPITokenResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    string
+		expected int
+	}{
+		{
+			name:     "Read with links:read",
+			method:   http.MethodGet,
+			path:     "/api/user/urls",
+			token:    created.Token,
+			expected: http.StatusNoContent,
+		},
+		{
+			name:     "Write without links:write",
+			method:   http.MethodPost,
+			path:     "/api/shorten",
+			body:     `{"url":"https://example.com"}`,
+			token:    created.Token,
+			expected: http.StatusForbidden,
+		},
+		{
+			name:     "Restore without links:write",
+			method:   http.MethodPost,
+			path:     "/api/user/urls/restore",
+			body:     `["abcd0001"]`,
+			token:    created.Token,
+			expected: http.StatusForbidden,
+		},
+		{
+			name:     "Unknown delete job",
+			method:   http.MethodGet,
+			path:     "/api/user/jobs/6455bd07-e431-4851-af3c-4f703f720001",
+			token:    created.Token,
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "Token management with a token",
+			method:   http.MethodGet,
+			path:     "/api/user/tokens",
+			token:    created.Token,
+			expected: http.StatusForbidden,
+		},
+		{
+			name:     "Unknown token",
+			method:   http.MethodGet,
+			path:     "/api/user/urls",
+			token:    "shk_unknown",
+			expected: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			assert.Empty(t, w.Result().Cookies())
+		})
+	}
+}
+
+func Test_HealthDetails(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		FileStoragePath:       filepath.Join(t.TempDir(), "storage.json"),
+		HealthCheckTimeout:    time.Second,
+		HealthCacheTTL:        time.Second,
+		DeleteQueueSaturation: 100,
+		TrustedSubnet:         "10.0.0.0/8",
+	}
+	appLogger := logger.NewLogger()
+	repo := repository.NewInMemoryRepository()
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
+
+	tests := []struct {
+		name       string
+		path       string
+		remoteAddr string
+		expected   int
+	}{
+		{
+			name:       "Readiness",
+			path:       "/ready",
+			remoteAddr: "192.168.1.1:1000",
+			expected:   http.StatusOK,
+		},
+		{
+			name:       "Details from the trusted subnet",
+			path:       "/health/details",
+			remoteAddr: "10.1.2.3:1000",
+			expected:   http.StatusOK,
+		},
+		{
+			name:       "Details from an untrusted network",
+			path:       "/health/details",
+			remoteAddr: "192.168.1.1:1000",
+			expected:   http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.remoteAddr
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expected, w.Code)
+			if tt.expected != http.StatusOK || tt.path != "/health/details" {
+				return
+			}
+
+			var report dto.HealthReport
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+			assert.Equal(t, health.StatusUp, report.Status)
+			assert.Contains(t, report.Components, "file_storage")
+			assert.Contains(t, report.Components, "delete_worker")
+			assert.NotContains(t, report.Components, "database")
+		})
+	}
+}
+
+func Test_InternalStats(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		TrustedSubnet:  "10.0.0.0/8",
+		TrustedProxies: "172.16.0.0/12",
+	}
+	appLogger := logger.NewLogger()
+	repo := repository.NewInMemoryRepository()
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	router := NewRouter(cfg, repo, newShortener(cfg, repo, appWorker), appWorker, appRecorder, appLogger)
+
+	_, err := repo.CreateURLs(ctx, []repository.URL{
+		{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: uuid.New()},
+		{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: uuid.New()},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		realIP     string
+		expected   int
+	}{
+		{
+			name:       "Trusted subnet",
+			remoteAddr: "10.1.2.3:1000",
+			expected:   http.StatusOK,
+		},
+		{
+			name:       "Trusted subnet behind a trusted proxy",
+			remoteAddr: "172.16.0.1:1000",
+			realIP:     "10.1.2.3",
+			expected:   http.StatusOK,
+		},
+		{
+			name:       "Untrusted network behind a trusted proxy",
+			remoteAddr: "172.16.0.1:1000",
+			realIP:     "192.168.1.1",
+			expected:   http.StatusForbidden,
+		},
+		{
+			name:       "Spoofed real IP",
+			remoteAddr: "192.168.1.1:1000",
+			realIP:     "10.1.2.3",
+			expected:   http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/internal/stats", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expected, w.Code)
+			if tt.expected != http.StatusOK {
+				return
+			}
+
+			var stats dto.InternalStatsResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&stats))
+			assert.Equal(t, dto.InternalStatsResponse{
+				URLs:     2,
+				Users:    2,
+				Backends: map[string]dto.BackendStats{repository.InMemoryBackend: {URLs: 2, Users: 2}},
+			}, stats)
+		})
+	}
+}
+
+// newShortener builds the URL service the application shares between the HTTP and gRPC servers
+func newShortener(cfg *config.Config, repo repository.Repository, appWorker worker.Worker) *service.URLService {
+	rand := service.NewSecureRandom()
+	return service.NewURLService(cfg, repo, rand, service.NewShortCodeGenerator(cfg, repo, rand), appWorker)
+}
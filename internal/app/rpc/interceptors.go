@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"context"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/middleware/auth"
+	"shortly/internal/app/service"
+	"shortly/internal/logger"
+)
+
+// AuthorizationMetadata is the metadata key of the authorization token
+const AuthorizationMetadata = "authorization"
+
+// RequestIDMetadata is the metadata key of the request ID, an incoming ID is kept and a missing one is generated
+const RequestIDMetadata = "x-request-id"
+
+// LoggingInterceptor is an interceptor for logging calls, it attaches a logger with the request ID to the call context
+func LoggingInterceptor(appLogger *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		id := logger.ParseRequestID(firstMetadata(ctx, RequestIDMetadata))
+		// NOTE: the header is best effort, a failure to send it must not fail the call
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, id))
+
+		ctx, reqLogger := appLogger.WithRequest(ctx, id)
+
+		resp, err := handler(ctx, req)
+
+		var remoteAddr string
+		if p, ok := peer.FromContext(ctx); ok {
+			remoteAddr = p.Addr.String()
+		}
+
+		reqLogger.Info().
+			Str("method", info.FullMethod).
+			Str("user_uuid", logger.RequestUser(ctx)).
+			Dur("duration", time.Since(start)).
+			Str("code", status.Code(err).String()).
+			Str("remote_addr", remoteAddr).
+			Str("user_agent", firstMetadata(ctx, "user-agent")).
+			Msg("call")
+
+		return resp, err
+	}
+}
+
+// RecoveryInterceptor is an interceptor turning a panic of a call into an internal error, the panic is logged
+func RecoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			logger.FromContext(ctx).Error().
+				Interface("panic", p).
+				Bytes("stack", debug.Stack()).
+				Msgf("Recovered from panic in %s", info.FullMethod)
+			err = status.Error(codes.Internal, errors.ErrInternalServer.Error())
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// AuthInterceptor is an interceptor for authentication by the authorization metadata carrying "Bearer <token>",
+// the token is the JWT of the authentication cookie, the public methods are called without authentication
+func AuthInterceptor(authenticator service.Authenticator, public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if slices.Contains(public, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		token, ok := bearerToken(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, errors.ErrMissingToken.Error())
+		}
+
+		// NOTE: unlike the cookie, an invalid token is rejected instead of minting a new user
+		userID, err := authenticator.Verify(token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, errors.ErrInvalidToken.Error())
+		}
+
+		ctx = context.WithValue(ctx, dto.CurrentUser, userID)
+		ctx = context.WithValue(ctx, dto.Authenticated, true)
+		ctx = logger.WithUser(ctx, userID.String())
+
+		return handler(ctx, req)
+	}
+}
+
+// bearerToken returns the token of the authorization metadata
+func bearerToken(ctx context.Context) (string, bool) {
+	value := firstMetadata(ctx, AuthorizationMetadata)
+	if len(value) < len(auth.BearerScheme) || !strings.EqualFold(value[:len(auth.BearerScheme)], auth.BearerScheme) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(value[len(auth.BearerScheme):])
+
+	return token, token != ""
+}
+
+// firstMetadata returns the first value of the incoming metadata key, empty when missing
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,204 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/rpc/pb"
+	"shortly/internal/app/service"
+	"shortly/internal/logger"
+)
+
+// stubShortener records the current user of Shorten and Resolve calls and panics on DeleteUserURLs
+type stubShortener struct {
+	pb.UnimplementedShortenerServer
+
+	userID        uuid.UUID
+	authenticated bool
+}
+
+func (s *stubShortener) Shorten(ctx context.Context, _ *pb.ShortenRequest) (*pb.ShortenResponse, error) {
+	s.userID, _ = ctx.Value(dto.CurrentUser).(uuid.UUID)
+	s.authenticated, _ = ctx.Value(dto.Authenticated).(bool)
+	return &pb.ShortenResponse{}, nil
+}
+
+func (s *stubShortener) Resolve(ctx context.Context, _ *pb.ResolveRequest) (*pb.ResolveResponse, error) {
+	s.userID, _ = ctx.Value(dto.CurrentUser).(uuid.UUID)
+	return &pb.ResolveResponse{}, nil
+}
+
+func (s *stubShortener) DeleteUserURLs(context.Context, *pb.DeleteUserURLsRequest) (*pb.DeleteUserURLsResponse, error) {
+	panic("boom")
+}
+
+// dialStub serves the stub with the interceptors of the server over an in-memory connection
+func dialStub(t *testing.T, stub *stubShortener, authenticator service.Authenticator, appLogger *logger.Logger) pb.ShortenerClient {
+	listener := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		LoggingInterceptor(appLogger),
+		RecoveryInterceptor,
+		AuthInterceptor(authenticator, pb.Shortener_Resolve_FullMethodName),
+	))
+	pb.RegisterShortenerServer(server, stub)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewShortenerClient(conn)
+}
+
+func Test_AuthInterceptor(t *testing.T) {
+	authenticator := service.NewAuthService(&config.Config{SecretKey: "secret"})
+	stub := &stubShortener{}
+	client := dialStub(t, stub, authenticator, logger.NewLogger())
+
+	UserUUID := uuid.New()
+	token, err := authenticator.Generate(UserUUID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		authorization string
+		code          codes.Code
+		message       string
+		userID        uuid.UUID
+	}{
+		{
+			name:          "Valid token",
+			authorization: "Bearer " + token,
+			code:          codes.OK,
+			userID:        UserUUID,
+		},
+		{
+			name:          "Case-insensitive scheme",
+			authorization: "bearer " + token,
+			code:          codes.OK,
+			userID:        UserUUID,
+		},
+		{
+			name:    "Missing token",
+			code:    codes.Unauthenticated,
+			message: errors.ErrMissingToken.Error(),
+		},
+		{
+			name:          "Other scheme",
+			authorization: "Basic " + token,
+			code:          codes.Unauthenticated,
+			message:       errors.ErrMissingToken.Error(),
+		},
+		{
+			name:          "Invalid token",
+			authorization: "Bearer invalid",
+			code:          codes.Unauthenticated,
+			message:       errors.ErrInvalidToken.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*stub = stubShortener{}
+
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, AuthorizationMetadata, tt.authorization)
+			}
+
+			_, err := client.Shorten(ctx, &pb.ShortenRequest{})
+
+			assert.Equal(t, tt.code, status.Code(err))
+			if tt.message != "" {
+				assert.Equal(t, tt.message, status.Convert(err).Message())
+			}
+			assert.Equal(t, tt.userID, stub.userID)
+			assert.Equal(t, tt.code == codes.OK, stub.authenticated)
+		})
+	}
+}
+
+func Test_AuthInterceptor_PublicMethod(t *testing.T) {
+	authenticator := service.NewAuthService(&config.Config{SecretKey: "secret"})
+	stub := &stubShortener{}
+	client := dialStub(t, stub, authenticator, logger.NewLogger())
+
+	_, err := client.Resolve(context.Background(), &pb.ResolveRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uuid.Nil, stub.userID)
+}
+
+func Test_RecoveryInterceptor(t *testing.T) {
+	authenticator := service.NewAuthService(&config.Config{SecretKey: "secret"})
+	client := dialStub(t, &stubShortener{}, authenticator, logger.NewLogger())
+
+	token, err := authenticator.Generate(uuid.New())
+	require.NoError(t, err)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadata, "Bearer "+token)
+
+	_, err = client.DeleteUserURLs(ctx, &pb.DeleteUserURLsRequest{})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, errors.ErrInternalServer.Error(), status.Convert(err).Message())
+
+	// NOTE: the server keeps serving after a recovered panic
+	_, err = client.Shorten(ctx, &pb.ShortenRequest{})
+	assert.NoError(t, err)
+}
+
+func Test_LoggingInterceptor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.log")
+	appLogger, err := logger.New(logger.Options{Format: logger.FormatJSON, Output: path})
+	require.NoError(t, err)
+
+	authenticator := service.NewAuthService(&config.Config{SecretKey: "secret"})
+	client := dialStub(t, &stubShortener{}, authenticator, appLogger)
+
+	UserUUID := uuid.New()
+	token, err := authenticator.Generate(UserUUID)
+	require.NoError(t, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		AuthorizationMetadata, "Bearer "+token,
+		RequestIDMetadata, "req-1234",
+	)
+
+	var header metadata.MD
+	_, err = client.Shorten(ctx, &pb.ShortenRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-1234"}, header.Get(RequestIDMetadata))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, "req-1234", entry["request_id"])
+	assert.Equal(t, pb.Shortener_Shorten_FullMethodName, entry["method"])
+	assert.Equal(t, codes.OK.String(), entry["code"])
+	assert.Equal(t, UserUUID.String(), entry["user_uuid"])
+	assert.Contains(t, entry, "duration")
+}
@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/rpc/pb"
+	"shortly/internal/app/service"
+	"shortly/internal/logger"
+)
+
+// NewServer creates a new gRPC server serving the shortener RPCs, it uses the HTTP server certificate when TLS is enabled
+func NewServer(cfg *config.Config, shortener *service.URLService, appLogger *logger.Logger) (*grpc.Server, error) {
+	authenticator := service.NewAuthService(cfg)
+
+	// NOTE: the logger wraps the recovery, so a recovered call is logged with its internal code
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			LoggingInterceptor(appLogger),
+			RecoveryInterceptor,
+			AuthInterceptor(authenticator, pb.Shortener_Resolve_FullMethodName),
+		),
+	}
+
+	if config.IsTLSEnabled(cfg) {
+		creds, err := credentials.NewServerTLSFromFile(cfg.Certificate, cfg.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(opts...)
+	pb.RegisterShortenerServer(server, NewShortenerServer(shortener))
+
+	return server, nil
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/rpc/pb"
+	"shortly/internal/app/service"
+	"shortly/internal/app/worker"
+	"shortly/internal/logger"
+)
+
+func Test_NewServer(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		GRPCAddr: "localhost:3200",
+	}
+	appLogger := logger.NewLogger()
+	repo := repository.NewInMemoryRepository()
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+
+	rand := service.NewSecureRandom()
+	shortener := service.NewURLService(cfg, repo, rand, service.NewShortCodeGenerator(cfg, repo, rand), appWorker)
+
+	server, err := NewServer(cfg, shortener, appLogger)
+	require.NoError(t, err)
+
+	info, ok := server.GetServiceInfo()[pb.Shortener_ServiceDesc.ServiceName]
+	require.True(t, ok)
+
+	methods := make([]string, len(info.Methods))
+	for i, method := range info.Methods {
+		methods[i] = method.Name
+	}
+	assert.ElementsMatch(t, []string{"Shorten", "BatchShorten", "Resolve", "ListUserURLs", "DeleteUserURLs"}, methods)
+}
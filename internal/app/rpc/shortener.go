@@ -0,0 +1,225 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"shortly/internal/app/api/pagination"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/rpc/pb"
+	"shortly/internal/app/service"
+	"shortly/internal/logger"
+)
+
+// ShortenerServer serves the shortener RPCs with the URL service, errors are reported with gRPC status codes
+type ShortenerServer struct {
+	pb.UnimplementedShortenerServer
+
+	service *service.URLService
+}
+
+// NewShortenerServer creates a new ShortenerServer
+func NewShortenerServer(service *service.URLService) *ShortenerServer {
+	return &ShortenerServer{service: service}
+}
+
+// Shorten creates a short link, an already shortened URL is returned with the conflict flag
+func (s *ShortenerServer) Shorten(ctx context.Context, req *pb.ShortenRequest) (*pb.ShortenResponse, error) {
+	params := dto.CreateShortLinkRequest{
+		URL:        req.GetUrl(),
+		Alias:      req.GetAlias(),
+		Expiration: expiration(req.GetExpiresAt(), req.GetTtl()),
+	}
+
+	if err := params.ValidateFields(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	shortURL, err := s.service.CreateShortLink(ctx, params)
+	if err != nil {
+		if errors.Is(err, errors.ErrURLAlreadyExists) {
+			return &pb.ShortenResponse{ShortUrl: shortURL, Conflict: true}, nil
+		}
+
+		return nil, statusError(ctx, err)
+	}
+
+	return &pb.ShortenResponse{ShortUrl: shortURL}, nil
+}
+
+// BatchShorten creates short links and reports a result per item in the request order
+func (s *ShortenerServer) BatchShorten(ctx context.Context, req *pb.BatchShortenRequest) (*pb.BatchShortenResponse, error) {
+	params := make(dto.BatchCreateShortLinkRequest, len(req.GetItems()))
+	for i, item := range req.GetItems() {
+		params[i] = dto.BatchCreateShortLinkParams{
+			CorrelationID: item.GetCorrelationId(),
+			OriginalURL:   item.GetOriginalUrl(),
+			Alias:         item.GetAlias(),
+			Expiration:    expiration(item.GetExpiresAt(), item.GetTtl()),
+		}
+	}
+
+	if err := params.ValidateFields(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	results, err := s.service.CreateShortLinks(ctx, params, req.GetAtomic())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	response := &pb.BatchShortenResponse{Results: make([]*pb.BatchShortenResult, len(results))}
+	for i, result := range results {
+		response.Results[i] = &pb.BatchShortenResult{
+			CorrelationId: result.CorrelationID,
+			ShortUrl:      result.ShortURL,
+			Conflict:      result.Conflict,
+			Error:         result.Error,
+		}
+	}
+
+	return response, nil
+}
+
+// Resolve returns the original URL of a short code, deleted and expired links are not found
+func (s *ShortenerServer) Resolve(ctx context.Context, req *pb.ResolveRequest) (*pb.ResolveResponse, error) {
+	if req.GetShortCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, errors.ErrShortCodeEmpty.Error())
+	}
+
+	result, found := s.service.GetShortLink(ctx, req.GetShortCode())
+	if !found {
+		return nil, status.Error(codes.NotFound, errors.ErrShortLinkNotFound.Error())
+	}
+
+	if !result.DeletedAt.IsZero() {
+		return nil, status.Error(codes.NotFound, errors.ErrShortLinkDeleted.Error())
+	}
+
+	if result.IsExpired(time.Now()) {
+		return nil, status.Error(codes.NotFound, errors.ErrShortLinkExpired.Error())
+	}
+
+	return &pb.ResolveResponse{OriginalUrl: result.LongURL}, nil
+}
+
+// ListUserURLs returns a filtered page of the current user links
+func (s *ShortenerServer) ListUserURLs(ctx context.Context, req *pb.ListUserURLsRequest) (*pb.ListUserURLsResponse, error) {
+	params := dto.ListUserURLsRequest{
+		Query:   req.GetQuery(),
+		Sort:    req.GetSort(),
+		Deleted: req.GetDeleted(),
+		Expired: req.GetExpired(),
+	}
+	if req.GetCreatedAfter() != nil {
+		params.CreatedAfter = req.GetCreatedAfter().AsTime()
+	}
+	if req.GetCreatedBefore() != nil {
+		params.CreatedBefore = req.GetCreatedBefore().AsTime()
+	}
+
+	if err := params.ValidateFields(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	paginator := pagination.New(req.GetPage(), req.GetPer())
+
+	urls, total, err := s.service.FindUserURLs(ctx, paginator, params)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	response := &pb.ListUserURLsResponse{
+		Urls:  make([]*pb.UserURL, len(urls)),
+		Page:  paginator.Page,
+		Per:   paginator.Per,
+		Total: int64(total),
+	}
+	for i, url := range urls {
+		response.Urls[i] = &pb.UserURL{
+			ShortUrl:    url.ShortURL,
+			ShortCode:   url.ShortCode,
+			OriginalUrl: url.OriginalURL,
+			Clicks:      url.Clicks,
+			CreatedAt:   timestamppb.New(url.CreatedAt),
+			ExpiresAt:   timestamp(url.ExpiresAt),
+			DeletedAt:   timestamp(url.DeletedAt),
+			Expired:     url.Expired,
+		}
+	}
+
+	return response, nil
+}
+
+// DeleteUserURLs queues the deletion of the current user links and returns the queued delete job
+func (s *ShortenerServer) DeleteUserURLs(ctx context.Context, req *pb.DeleteUserURLsRequest) (*pb.DeleteUserURLsResponse, error) {
+	params := dto.BatchDeleteShortLinkRequest(req.GetShortCodes())
+
+	if err := params.ValidateFields(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	job, err := s.service.DeleteUserURLs(ctx, params)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &pb.DeleteUserURLsResponse{
+		JobId:     job.ID.String(),
+		Status:    job.Status,
+		Requested: int64(job.Requested),
+		CreatedAt: timestamppb.New(job.CreatedAt),
+	}, nil
+}
+
+// statusError converts a service error to a gRPC status error with the code of the matching HTTP response,
+// unexpected errors are logged and reported without their cause
+func statusError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, errors.ErrInvalidAlias),
+		errors.Is(err, errors.ErrAliasReserved),
+		errors.Is(err, errors.ErrInvalidExpiration),
+		errors.Is(err, errors.ErrInvalidURL),
+		errors.Is(err, errors.ErrOriginalURLEmpty),
+		errors.Is(err, errors.ErrCorrelationIDEmpty),
+		errors.Is(err, errors.ErrShortCodeEmpty),
+		errors.Is(err, errors.ErrInvalidSort),
+		errors.Is(err, errors.ErrInvalidFilter),
+		errors.Is(err, errors.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errors.ErrAliasAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, errors.ErrDeleteJobNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errors.ErrInvalidUserID):
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		logger.FromContext(ctx).Error().Err(err).Msg("Error serving call")
+		return status.Error(codes.Internal, errors.ErrInternalServer.Error())
+	}
+}
+
+// expiration returns the link expiration attributes of a request, a missing timestamp means no absolute expiration
+func expiration(expiresAt *timestamppb.Timestamp, ttl int64) dto.Expiration {
+	result := dto.Expiration{TTL: ttl}
+	if expiresAt != nil {
+		t := expiresAt.AsTime()
+		result.ExpiresAt = &t
+	}
+
+	return result
+}
+
+// timestamp converts an optional time to an optional timestamp
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
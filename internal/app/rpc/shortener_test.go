@@ -0,0 +1,613 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/rpc/pb"
+	"shortly/internal/app/service"
+	"shortly/internal/app/worker"
+)
+
+func Test_Shorten(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	server := NewShortenerServer(service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker))
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f726639")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	type result struct {
+		response *pb.ShortenResponse
+		code     codes.Code
+	}
+
+	tests := []struct {
+		name     string
+		request  *pb.ShortenRequest
+		before   func()
+		expected result
+	}{
+		{
+			name:    "Success",
+			request: &pb.ShortenRequest{Url: "https://example.com"},
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID, nil)
+				rand.EXPECT().Hex().Return("abcd1234", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
+				repo.EXPECT().CreateURL(gomock.Any(), repository.URL{
+					UUID:      UUID,
+					LongURL:   "https://example.com",
+					ShortCode: "abcd1234",
+					UserUUID:  UserUUID,
+				}).Return(&repository.URL{ShortCode: "abcd1234"}, nil)
+			},
+			expected: result{
+				response: &pb.ShortenResponse{ShortUrl: "http://localhost:8080/abcd1234"},
+				code:     codes.OK,
+			},
+		},
+		{
+			name:    "Already shortened",
+			request: &pb.ShortenRequest{Url: "https://example.com"},
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID, nil)
+				rand.EXPECT().Hex().Return("abcd1234", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
+				repo.EXPECT().CreateURL(gomock.Any(), gomock.Any()).Return(&repository.URL{ShortCode: "dcba4321"}, nil)
+			},
+			expected: result{
+				response: &pb.ShortenResponse{ShortUrl: "http://localhost:8080/dcba4321", Conflict: true},
+				code:     codes.OK,
+			},
+		},
+		{
+			name:    "Alias taken",
+			request: &pb.ShortenRequest{Url: "https://example.com", Alias: "example"},
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "example").Return(&repository.URL{}, true)
+			},
+			expected: result{
+				code: codes.AlreadyExists,
+			},
+		},
+		{
+			name:    "Expiration",
+			request: &pb.ShortenRequest{Url: "https://example.com", Ttl: 60},
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID, nil)
+				rand.EXPECT().Hex().Return("abcd1234", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
+				repo.EXPECT().CreateURL(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, url repository.URL) (*repository.URL, error) {
+					assert.WithinDuration(t, time.Now().Add(time.Minute), url.ExpiresAt, time.Second)
+					return &url, nil
+				})
+			},
+			expected: result{
+				response: &pb.ShortenResponse{ShortUrl: "http://localhost:8080/abcd1234"},
+				code:     codes.OK,
+			},
+		},
+		{
+			name:    "Invalid URL",
+			request: &pb.ShortenRequest{Url: "example"},
+			before:  func() {},
+			expected: result{
+				code: codes.InvalidArgument,
+			},
+		},
+		{
+			name:    "Invalid expiration",
+			request: &pb.ShortenRequest{Url: "https://example.com", Ttl: 60, ExpiresAt: timestamppb.New(time.Now().Add(time.Hour))},
+			before:  func() {},
+			expected: result{
+				code: codes.InvalidArgument,
+			},
+		},
+		{
+			name:    "Save error",
+			request: &pb.ShortenRequest{Url: "https://example.com"},
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID, nil)
+				rand.EXPECT().Hex().Return("abcd1234", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
+				repo.EXPECT().CreateURL(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+			},
+			expected: result{
+				code: codes.Internal,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			response, err := server.Shorten(ctx, tt.request)
+
+			assert.Equal(t, tt.expected.code, status.Code(err))
+			if tt.expected.response != nil {
+				assert.True(t, proto.Equal(tt.expected.response, response), response.String())
+			}
+		})
+	}
+}
+
+func Test_BatchShorten(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	server := NewShortenerServer(service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker))
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+
+	type result struct {
+		response *pb.BatchShortenResponse
+		code     codes.Code
+	}
+
+	tests := []struct {
+		name     string
+		request  *pb.BatchShortenRequest
+		before   func()
+		expected result
+	}{
+		{
+			name: "Success",
+			request: &pb.BatchShortenRequest{Items: []*pb.BatchShortenItem{
+				{CorrelationId: "0001", OriginalUrl: "https://github.com"},
+				{CorrelationId: "0002", OriginalUrl: "https://google.com"},
+			}},
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().UUID().Return(UUID2, nil)
+				rand.EXPECT().Hex().Return("abcd0001", nil)
+				rand.EXPECT().Hex().Return("abcd0002", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0001").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0002").Return(nil, false)
+
+				urls := []repository.URL{
+					{UUID: UUID1, LongURL: "https://github.com", ShortCode: "abcd0001"},
+					{UUID: UUID2, LongURL: "https://google.com", ShortCode: "abcd0002"},
+				}
+				repo.EXPECT().CreateURLs(gomock.Any(), urls).Return(urls, nil)
+			},
+			expected: result{
+				response: &pb.BatchShortenResponse{Results: []*pb.BatchShortenResult{
+					{CorrelationId: "0001", ShortUrl: "http://localhost:8080/abcd0001"},
+					{CorrelationId: "0002", ShortUrl: "http://localhost:8080/abcd0002"},
+				}},
+				code: codes.OK,
+			},
+		},
+		{
+			name: "Alias taken in atomic mode",
+			request: &pb.BatchShortenRequest{
+				Items:  []*pb.BatchShortenItem{{CorrelationId: "0001", OriginalUrl: "https://github.com", Alias: "github"}},
+				Atomic: true,
+			},
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "github").Return(&repository.URL{}, true)
+			},
+			expected: result{
+				code: codes.AlreadyExists,
+			},
+		},
+		{
+			name: "Missing correlation ID",
+			request: &pb.BatchShortenRequest{Items: []*pb.BatchShortenItem{
+				{OriginalUrl: "https://github.com"},
+			}},
+			before: func() {},
+			expected: result{
+				code: codes.InvalidArgument,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			response, err := server.BatchShorten(context.Background(), tt.request)
+
+			assert.Equal(t, tt.expected.code, status.Code(err))
+			if tt.expected.response != nil {
+				assert.True(t, proto.Equal(tt.expected.response, response), response.String())
+			}
+		})
+	}
+}
+
+func Test_Resolve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	server := NewShortenerServer(service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker))
+
+	type result struct {
+		response *pb.ResolveResponse
+		code     codes.Code
+		message  string
+	}
+
+	tests := []struct {
+		name      string
+		shortCode string
+		before    func()
+		expected  result
+	}{
+		{
+			name:      "Success",
+			shortCode: "abcd1234",
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(&repository.URL{LongURL: "https://example.com"}, true)
+			},
+			expected: result{
+				response: &pb.ResolveResponse{OriginalUrl: "https://example.com"},
+				code:     codes.OK,
+			},
+		},
+		{
+			name:      "Not found",
+			shortCode: "abcd1234",
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
+			},
+			expected: result{
+				code:    codes.NotFound,
+				message: errors.ErrShortLinkNotFound.Error(),
+			},
+		},
+		{
+			name:      "Deleted",
+			shortCode: "abcd1234",
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(&repository.URL{DeletedAt: time.Now()}, true)
+			},
+			expected: result{
+				code:    codes.NotFound,
+				message: errors.ErrShortLinkDeleted.Error(),
+			},
+		},
+		{
+			name:      "Expired",
+			shortCode: "abcd1234",
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(&repository.URL{ExpiresAt: time.Now().Add(-time.Minute)}, true)
+			},
+			expected: result{
+				code:    codes.NotFound,
+				message: errors.ErrShortLinkExpired.Error(),
+			},
+		},
+		{
+			name:   "Empty short code",
+			before: func() {},
+			expected: result{
+				code:    codes.InvalidArgument,
+				message: errors.ErrShortCodeEmpty.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			response, err := server.Resolve(context.Background(), &pb.ResolveRequest{ShortCode: tt.shortCode})
+
+			assert.Equal(t, tt.expected.code, status.Code(err))
+			if tt.expected.message != "" {
+				assert.Equal(t, tt.expected.message, status.Convert(err).Message())
+			}
+			if tt.expected.response != nil {
+				assert.True(t, proto.Equal(tt.expected.response, response), response.String())
+			}
+		})
+	}
+}
+
+func Test_ListUserURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	server := NewShortenerServer(service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker))
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	createdAfter := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	type result struct {
+		response *pb.ListUserURLsResponse
+		code     codes.Code
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		request  *pb.ListUserURLsRequest
+		before   func()
+		expected result
+	}{
+		{
+			name: "Success",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			request: &pb.ListUserURLsRequest{
+				Page:         2,
+				Per:          1,
+				Query:        " google ",
+				Sort:         dto.SortClicksDesc,
+				CreatedAfter: timestamppb.New(createdAfter),
+			},
+			before: func() {
+				repo.EXPECT().FindURLsByUserID(gomock.Any(), repository.URLFilter{
+					UserUUID:     UserUUID,
+					Query:        "google",
+					Sort:         repository.SortClicksDesc,
+					CreatedAfter: createdAfter,
+					Limit:        1,
+					Offset:       1,
+				}).Return([]repository.URLListItem{
+					{
+						URL: repository.URL{
+							UUID:      UUID1,
+							LongURL:   "https://google.com",
+							ShortCode: "abcd0001",
+							CreatedAt: createdAt,
+						},
+						Clicks: 3,
+					},
+				}, 3, nil)
+			},
+			expected: result{
+				response: &pb.ListUserURLsResponse{
+					Urls: []*pb.UserURL{
+						{
+							ShortUrl:    "http://localhost:8080/abcd0001",
+							ShortCode:   "abcd0001",
+							OriginalUrl: "https://google.com",
+							Clicks:      3,
+							CreatedAt:   timestamppb.New(createdAt),
+						},
+					},
+					Page:  2,
+					Per:   1,
+					Total: 3,
+				},
+				code: codes.OK,
+			},
+		},
+		{
+			name:    "Invalid sort",
+			ctx:     context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			request: &pb.ListUserURLsRequest{Sort: "original_url"},
+			before:  func() {},
+			expected: result{
+				code: codes.InvalidArgument,
+			},
+		},
+		{
+			name:    "Without user",
+			ctx:     context.Background(),
+			request: &pb.ListUserURLsRequest{},
+			before:  func() {},
+			expected: result{
+				code: codes.Unauthenticated,
+			},
+		},
+		{
+			name:    "Repository error",
+			ctx:     context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			request: &pb.ListUserURLsRequest{},
+			before: func() {
+				repo.EXPECT().FindURLsByUserID(gomock.Any(), gomock.Any()).Return(nil, 0, assert.AnError)
+			},
+			expected: result{
+				code: codes.Internal,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			response, err := server.ListUserURLs(tt.ctx, tt.request)
+
+			assert.Equal(t, tt.expected.code, status.Code(err))
+			if tt.expected.response != nil {
+				assert.True(t, proto.Equal(tt.expected.response, response), response.String())
+			}
+		})
+	}
+}
+
+func Test_DeleteUserURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockDatabase(ctrl)
+	rand := service.NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	server := NewShortenerServer(service.NewURLService(cfg, repo, rand, service.NewHexGenerator(rand), appWorker))
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	JobUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	type result struct {
+		response *pb.DeleteUserURLsResponse
+		code     codes.Code
+	}
+
+	tests := []struct {
+		name       string
+		shortCodes []string
+		before     func()
+		expected   result
+	}{
+		{
+			name:       "Success",
+			shortCodes: []string{"abcd0001", "abcd0002"},
+			before: func() {
+				appWorker.EXPECT().Add(gomock.Any(), dto.BatchDeleteParams{
+					UserID:     UserUUID,
+					ShortCodes: []string{"abcd0001", "abcd0002"},
+				}).Return(worker.DeleteJob{
+					ID:         JobUUID,
+					UserID:     UserUUID,
+					ShortCodes: []string{"abcd0001", "abcd0002"},
+					Status:     dto.JobStatusQueued,
+					CreatedAt:  createdAt,
+				}, nil)
+			},
+			expected: result{
+				response: &pb.DeleteUserURLsResponse{
+					JobId:     JobUUID.String(),
+					Status:    dto.JobStatusQueued,
+					Requested: 2,
+					CreatedAt: timestamppb.New(createdAt),
+				},
+				code: codes.OK,
+			},
+		},
+		{
+			name:   "Empty short codes",
+			before: func() {},
+			expected: result{
+				code: codes.InvalidArgument,
+			},
+		},
+		{
+			name:       "Queue error",
+			shortCodes: []string{"abcd0001"},
+			before: func() {
+				appWorker.EXPECT().Add(gomock.Any(), gomock.Any()).Return(worker.DeleteJob{}, assert.AnError)
+			},
+			expected: result{
+				code: codes.Internal,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			response, err := server.DeleteUserURLs(ctx, &pb.DeleteUserURLsRequest{ShortCodes: tt.shortCodes})
+
+			assert.Equal(t, tt.expected.code, status.Code(err))
+			if tt.expected.response != nil {
+				assert.True(t, proto.Equal(tt.expected.response, response), response.String())
+			}
+		})
+	}
+}
+
+func Test_statusError(t *testing.T) {
+	type result struct {
+		code    codes.Code
+		message string
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected result
+	}{
+		{
+			name:     "Invalid alias",
+			err:      errors.ErrInvalidAlias,
+			expected: result{code: codes.InvalidArgument, message: errors.ErrInvalidAlias.Error()},
+		},
+		{
+			name:     "Invalid expiration",
+			err:      errors.ErrInvalidExpiration,
+			expected: result{code: codes.InvalidArgument, message: errors.ErrInvalidExpiration.Error()},
+		},
+		{
+			name:     "Invalid cursor",
+			err:      errors.ErrInvalidCursor,
+			expected: result{code: codes.InvalidArgument, message: errors.ErrInvalidCursor.Error()},
+		},
+		{
+			name:     "Alias taken",
+			err:      errors.ErrAliasAlreadyExists,
+			expected: result{code: codes.AlreadyExists, message: errors.ErrAliasAlreadyExists.Error()},
+		},
+		{
+			name:     "Delete job not found",
+			err:      errors.ErrDeleteJobNotFound,
+			expected: result{code: codes.NotFound, message: errors.ErrDeleteJobNotFound.Error()},
+		},
+		{
+			name:     "Invalid user",
+			err:      errors.ErrInvalidUserID,
+			expected: result{code: codes.Unauthenticated, message: errors.ErrInvalidUserID.Error()},
+		},
+		{
+			name:     "Internal",
+			err:      assert.AnError,
+			expected: result{code: codes.Internal, message: errors.ErrInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := status.Convert(statusError(context.Background(), tt.err))
+
+			assert.Equal(t, tt.expected.code, err.Code())
+			assert.Equal(t, tt.expected.message, err.Message())
+		})
+	}
+}
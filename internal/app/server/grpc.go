@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+
+	"shortly/internal/app/config"
+)
+
+// GRPCServer is an interface for gRPC server
+type GRPCServer interface {
+	Run() error
+	Shutdown(ctx context.Context) error
+}
+
+type grpcServer struct {
+	addr       string
+	grpcServer *grpc.Server
+}
+
+// NewGRPCServer creates a new gRPC server instance listening on the gRPC address of the config
+func NewGRPCServer(cfg *config.Config, server *grpc.Server) GRPCServer {
+	return &grpcServer{
+		addr:       cfg.GRPCAddr,
+		grpcServer: server,
+	}
+}
+
+// Run starts the gRPC server, it returns nil once the server is shut down
+func (g *grpcServer) Run() error {
+	listener, err := net.Listen("tcp", g.addr)
+	if err != nil {
+		return err
+	}
+
+	return g.grpcServer.Serve(listener)
+}
+
+// Shutdown stops the gRPC server gracefully, pending calls are cancelled once the context is done
+func (g *grpcServer) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		g.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
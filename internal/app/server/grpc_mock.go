@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/server/grpc.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/server/grpc.go -destination=internal/app/server/grpc_mock.go -package=server
+//
+
+// Package server is a generated GoMock package.
+package server
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGRPCServer is a mock of GRPCServer interface.
+type MockGRPCServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockGRPCServerMockRecorder
+	isgomock struct{}
+}
+
+// MockGRPCServerMockRecorder is the mock recorder for MockGRPCServer.
+type MockGRPCServerMockRecorder struct {
+	mock *MockGRPCServer
+}
+
+// NewMockGRPCServer creates a new mock instance.
+func NewMockGRPCServer(ctrl *gomock.Controller) *MockGRPCServer {
+	mock := &MockGRPCServer{ctrl: ctrl}
+	mock.recorder = &MockGRPCServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGRPCServer) EXPECT() *MockGRPCServerMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockGRPCServer) Run() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockGRPCServerMockRecorder) Run() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockGRPCServer)(nil).Run))
+}
+
+// Shutdown mocks base method.
+func (m *MockGRPCServer) Shutdown(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shutdown", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockGRPCServerMockRecorder) Shutdown(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockGRPCServer)(nil).Shutdown), ctx)
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"shortly/internal/app/config"
+)
+
+func Test_NewGRPCServer(t *testing.T) {
+	cfg := &config.Config{
+		GRPCAddr: "localhost:3200",
+	}
+	server := grpc.NewServer()
+
+	srv := NewGRPCServer(cfg, server)
+	assert.NotNil(t, srv)
+
+	s, ok := srv.(*grpcServer)
+	assert.True(t, ok)
+
+	assert.Equal(t, cfg.GRPCAddr, s.addr)
+	assert.Same(t, server, s.grpcServer)
+}
+
+func Test_GRPCServer_RunAndShutdown(t *testing.T) {
+	cfg := &config.Config{
+		GRPCAddr: "localhost:3281",
+	}
+	srv := NewGRPCServer(cfg, grpc.NewServer())
+
+	runErrCh := make(chan error, 1)
+	go func() {
+		runErrCh <- srv.Run()
+	}()
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", cfg.GRPCAddr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := srv.Shutdown(ctx)
+	assert.NoError(t, err)
+
+	err = <-runErrCh
+	assert.NoError(t, err)
+}
+
+func Test_GRPCServer_RunInvalidAddress(t *testing.T) {
+	srv := NewGRPCServer(&config.Config{GRPCAddr: "localhost:invalid"}, grpc.NewServer())
+
+	assert.Error(t, srv.Run())
+}
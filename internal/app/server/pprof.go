@@ -9,6 +9,7 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	"shortly/internal/app/config"
+	"shortly/internal/app/metrics"
 )
 
 // PprofServer is an interface for pprof server
@@ -31,6 +32,7 @@ func NewPprofServer(cfg *config.Config) PprofServer {
 	handler.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	handler.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	handler.HandleFunc("/debug/pprof/heap", pprof.Handler("heap").ServeHTTP)
+	handler.Handle("/metrics", metrics.Handler())
 
 	return &pprofServer{
 		httpServer: &http.Server{
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -51,3 +52,23 @@ func Test_PprofServer_RunAndShutdown(t *testing.T) {
 	err = <-runErrCh
 	assert.NoError(t, err)
 }
+
+func Test_PprofServer_Metrics(t *testing.T) {
+	cfg := &config.Config{
+		ProfilerAddr: "localhost:2080",
+	}
+
+	srv, ok := NewPprofServer(cfg).(*pprofServer)
+	assert.True(t, ok)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+
+	srv.httpServer.Handler.ServeHTTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+}
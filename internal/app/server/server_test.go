@@ -20,6 +20,7 @@ import (
 	"shortly/internal/app/config"
 	"shortly/internal/app/repository"
 	"shortly/internal/app/router"
+	"shortly/internal/app/service"
 	"shortly/internal/app/worker"
 	"shortly/internal/logger"
 	"shortly/internal/spec"
@@ -35,8 +36,11 @@ func Test_NewServer(t *testing.T) {
 		DSN:    cfg.DatabaseDSN,
 		Logger: appLogger,
 	})
-	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, appLogger)
-	appRouter := router.NewRouter(cfg, repo, appWorker, appLogger)
+	appWorker := worker.NewDeleteWorker(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	appRecorder := worker.NewClickRecorder(ctx, cfg, repo, worker.NewScheduler(ctx, cfg, appLogger), appLogger)
+	rand := service.NewSecureRandom()
+	shortener := service.NewURLService(cfg, repo, rand, service.NewShortCodeGenerator(cfg, repo, rand), appWorker)
+	appRouter := router.NewRouter(cfg, repo, shortener, appWorker, appRecorder, appLogger)
 
 	srv := NewServer(cfg, appRouter)
 	assert.NotNil(t, srv)
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/worker"
+)
+
+// DefaultStatsDays is the default number of days in the daily clicks series
+const DefaultStatsDays = 30
+
+// MaxStatsDays is the maximum number of days in the daily clicks series
+const MaxStatsDays = 365
+
+// MaxReferrerLength is the maximum length of a stored referrer
+const MaxReferrerLength = 2048
+
+// MaxUserAgentLength is the maximum length of a stored user agent
+const MaxUserAgentLength = 512
+
+// AnalyticsService is a service for click analytics
+type AnalyticsService struct {
+	cfg      *config.Config
+	repo     repository.Repository
+	recorder worker.ClickRecorder
+}
+
+// NewAnalyticsService creates a new analytics service instance
+func NewAnalyticsService(cfg *config.Config, repo repository.Repository, recorder worker.ClickRecorder) *AnalyticsService {
+	return &AnalyticsService{
+		cfg:      cfg,
+		repo:     repo,
+		recorder: recorder,
+	}
+}
+
+// Track records a click on the short link asynchronously
+func (s *AnalyticsService) Track(url *repository.URL, event dto.ClickEvent) {
+	s.recorder.Record(repository.Click{
+		URLUUID:   url.UUID,
+		Referrer:  truncate(event.Referrer, MaxReferrerLength),
+		UserAgent: truncate(event.UserAgent, MaxUserAgentLength),
+		IPHash:    s.hashIP(event.RemoteAddr),
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// GetURLStats returns clicks statistics of the current user short link
+func (s *AnalyticsService) GetURLStats(ctx context.Context, shortCode string, days int) (*dto.URLStatsResponse, error) {
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	url, found := s.repo.GetURLByShortCode(ctx, shortCode)
+	if !found || url.UserUUID != currentUserID {
+		return nil, errors.ErrShortLinkNotFound
+	}
+
+	if days < 1 || days > MaxStatsDays {
+		days = DefaultStatsDays
+	}
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+
+	stats, err := s.repo.GetClickStats(ctx, url.UUID, since)
+	if err != nil {
+		return nil, errors.ErrFailedToLoadStats
+	}
+
+	daily := make([]dto.DailyClicksResponse, len(stats.Daily))
+	for i, day := range stats.Daily {
+		daily[i] = dto.DailyClicksResponse{
+			Date:           day.Date.Format(time.DateOnly),
+			Clicks:         day.Total,
+			UniqueVisitors: day.Unique,
+		}
+	}
+
+	return &dto.URLStatsResponse{
+		ShortURL:       fmt.Sprintf("%s/%s", s.cfg.BaseURL, url.ShortCode),
+		OriginalURL:    url.LongURL,
+		TotalClicks:    stats.Total,
+		UniqueVisitors: stats.Unique,
+		Daily:          daily,
+	}, nil
+}
+
+// hashIP returns a keyed hash of the client IP, so raw addresses are never stored
+func (s *AnalyticsService) hashIP(remoteAddr string) string {
+	ip, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		ip = remoteAddr
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.cfg.SecretKey))
+	mac.Write([]byte(ip))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// truncate cuts the string to the given length in bytes, dropping broken UTF-8 sequences
+func truncate(value string, length int) string {
+	if len(value) > length {
+		value = value[:length]
+	}
+	return strings.ToValidUTF8(value, "")
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/worker"
+)
+
+func Test_AnalyticsService_Track(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		SecretKey: "jwt-secret-key",
+	}
+	repo := repository.NewMockRepository(ctrl)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	service := NewAnalyticsService(cfg, repo, recorder)
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	url := &repository.URL{UUID: UUID, ShortCode: "abcd1234"}
+
+	var clicks []repository.Click
+	recorder.EXPECT().Record(gomock.Any()).Do(func(click repository.Click) {
+		clicks = append(clicks, click)
+	}).Times(3)
+
+	service.Track(url, dto.ClickEvent{Referrer: "https://google.com", UserAgent: "Mozilla/5.0", RemoteAddr: "192.168.0.1:5000"})
+	service.Track(url, dto.ClickEvent{RemoteAddr: "192.168.0.1:6000"})
+	service.Track(url, dto.ClickEvent{RemoteAddr: "192.168.0.2:5000"})
+
+	assert.Len(t, clicks, 3)
+	assert.Equal(t, UUID, clicks[0].URLUUID)
+	assert.Equal(t, "https://google.com", clicks[0].Referrer)
+	assert.Equal(t, "Mozilla/5.0", clicks[0].UserAgent)
+	assert.NotContains(t, clicks[0].IPHash, "192.168.0.1")
+	assert.Equal(t, clicks[0].IPHash, clicks[1].IPHash)
+	assert.NotEqual(t, clicks[0].IPHash, clicks[2].IPHash)
+}
+
+func Test_AnalyticsService_GetURLStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+	repo := repository.NewMockRepository(ctrl)
+	recorder := worker.NewMockClickRecorder(ctrl)
+	service := NewAnalyticsService(cfg, repo, recorder)
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		before   func()
+		expected *dto.URLStatsResponse
+		error    error
+	}{
+		{
+			name: "Success",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID1),
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(&repository.URL{
+					UUID:      UUID,
+					LongURL:   "https://example.com",
+					ShortCode: "abcd1234",
+					UserUUID:  UserUUID1,
+				}, true)
+				repo.EXPECT().GetClickStats(gomock.Any(), UUID, gomock.Any()).Return(&repository.ClickStats{
+					Total:  3,
+					Unique: 2,
+					Daily: []repository.DailyClicks{
+						{Date: day, Total: 3, Unique: 2},
+					},
+				}, nil)
+			},
+			expected: &dto.URLStatsResponse{
+				ShortURL:       "http://localhost:8080/abcd1234",
+				OriginalURL:    "https://example.com",
+				TotalClicks:    3,
+				UniqueVisitors: 2,
+				Daily: []dto.DailyClicksResponse{
+					{Date: "2025-03-01", Clicks: 3, UniqueVisitors: 2},
+				},
+			},
+		},
+		{
+			name: "Not owned",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID2),
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(&repository.URL{
+					UUID:      UUID,
+					ShortCode: "abcd1234",
+					UserUUID:  UserUUID1,
+				}, true)
+			},
+			error: errors.ErrShortLinkNotFound,
+		},
+		{
+			name: "Not found",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID1),
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
+			},
+			error: errors.ErrShortLinkNotFound,
+		},
+		{
+			name: "Repository error",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID1),
+			before: func() {
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(&repository.URL{
+					UUID:      UUID,
+					ShortCode: "abcd1234",
+					UserUUID:  UserUUID1,
+				}, true)
+				repo.EXPECT().GetClickStats(gomock.Any(), UUID, gomock.Any()).Return(nil, assert.AnError)
+			},
+			error: errors.ErrFailedToLoadStats,
+		},
+		{
+			name:   "Without user",
+			ctx:    context.Background(),
+			before: func() {},
+			error:  errors.ErrInvalidUserID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			stats, err := service.GetURLStats(tt.ctx, "abcd1234", 0)
+
+			assert.Equal(t, tt.error, err)
+			assert.Equal(t, tt.expected, stats)
+		})
+	}
+}
+
+func Test_truncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abcdef", 3))
+	assert.Equal(t, "abc", truncate("abc", 10))
+	assert.Equal(t, "a", truncate("aя", 2))
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+)
+
+// ExportPageSize is the number of links loaded from the repository at once
+const ExportPageSize = 1000
+
+// ExportUserURLs walks all links of the current user including deleted and expired ones, newest first,
+// and passes them to the report page by page, so the whole collection is never held in memory
+func (s *URLService) ExportUserURLs(ctx context.Context, report func([]dto.UserURLResponse) error) error {
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		return errors.ErrInvalidUserID
+	}
+
+	var cursor repository.URLCursor
+
+	for {
+		items, err := s.repo.ExportURLsByUserID(ctx, currentUserID, cursor, ExportPageSize)
+		if err != nil {
+			return errors.ErrFailedToLoadUserUrls
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+
+		results := make([]dto.UserURLResponse, len(items))
+		for i, item := range items {
+			results[i] = dto.UserURLResponse{
+				ShortURL:    fmt.Sprintf("%s/%s", s.cfg.BaseURL, item.ShortCode),
+				ShortCode:   item.ShortCode,
+				OriginalURL: item.LongURL,
+				Clicks:      item.Clicks,
+				CreatedAt:   item.CreatedAt,
+				ExpiresAt:   optionalTime(item.ExpiresAt),
+				DeletedAt:   optionalTime(item.DeletedAt),
+				Expired:     item.Expired,
+			}
+		}
+
+		if err = report(results); err != nil {
+			return err
+		}
+
+		if len(items) < ExportPageSize {
+			return nil
+		}
+
+		// NOTE: a zero cursor restarts from the newest record, records without creation time are the oldest anyway
+		last := items[len(items)-1]
+		if last.CreatedAt.IsZero() {
+			return nil
+		}
+		cursor = repository.URLCursor{CreatedAt: last.CreatedAt, UUID: last.UUID}
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/worker"
+)
+
+func Test_ExportUserURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), worker.NewMockWorker(ctrl))
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	page := make([]repository.URLListItem, ExportPageSize)
+	for i := range page {
+		page[i] = repository.URLListItem{URL: repository.URL{
+			UUID:      uuid.New(),
+			LongURL:   "https://example.com",
+			ShortCode: "abcd0001",
+			CreatedAt: createdAt.Add(-time.Duration(i) * time.Second),
+		}}
+	}
+	last := page[len(page)-1]
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		before   func()
+		pages    []int
+		expected error
+	}{
+		{
+			name: "Pages are walked with the cursor",
+			ctx:  ctx,
+			before: func() {
+				repo.EXPECT().ExportURLsByUserID(ctx, UserUUID, repository.URLCursor{}, int64(ExportPageSize)).Return(page, nil)
+				repo.EXPECT().ExportURLsByUserID(ctx, UserUUID, repository.URLCursor{CreatedAt: last.CreatedAt, UUID: last.UUID}, int64(ExportPageSize)).
+					Return([]repository.URLListItem{{URL: repository.URL{ShortCode: "abcd0002", DeletedAt: createdAt}, Clicks: 3}}, nil)
+			},
+			pages:    []int{ExportPageSize, 1},
+			expected: nil,
+		},
+		{
+			name: "Full last page",
+			ctx:  ctx,
+			before: func() {
+				repo.EXPECT().ExportURLsByUserID(ctx, UserUUID, repository.URLCursor{}, int64(ExportPageSize)).Return(page, nil)
+				repo.EXPECT().ExportURLsByUserID(ctx, UserUUID, repository.URLCursor{CreatedAt: last.CreatedAt, UUID: last.UUID}, int64(ExportPageSize)).
+					Return([]repository.URLListItem{}, nil)
+			},
+			pages:    []int{ExportPageSize},
+			expected: nil,
+		},
+		{
+			name: "Storage error",
+			ctx:  ctx,
+			before: func() {
+				repo.EXPECT().ExportURLsByUserID(ctx, UserUUID, repository.URLCursor{}, int64(ExportPageSize)).Return(nil, errors.ErrFailedToLoadUserUrls)
+			},
+			expected: errors.ErrFailedToLoadUserUrls,
+		},
+		{
+			name:     "Anonymous user",
+			ctx:      context.Background(),
+			before:   func() {},
+			expected: errors.ErrInvalidUserID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			var pages []int
+			err := service.ExportUserURLs(tt.ctx, func(urls []dto.UserURLResponse) error {
+				pages = append(pages, len(urls))
+				return nil
+			})
+
+			assert.Equal(t, tt.expected, err)
+			assert.Equal(t, tt.pages, pages)
+		})
+	}
+}
+
+func Test_ExportUserURLs_Response(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), worker.NewMockWorker(ctrl))
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	deletedAt := createdAt.Add(time.Hour)
+
+	repo.EXPECT().ExportURLsByUserID(ctx, UserUUID, repository.URLCursor{}, int64(ExportPageSize)).Return([]repository.URLListItem{
+		{URL: repository.URL{LongURL: "https://example.com", ShortCode: "abcd0001", CreatedAt: createdAt, DeletedAt: deletedAt}, Clicks: 3},
+	}, nil)
+
+	var urls []dto.UserURLResponse
+	err := service.ExportUserURLs(ctx, func(page []dto.UserURLResponse) error {
+		urls = append(urls, page...)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.UserURLResponse{{
+		ShortURL:    "http://localhost:8080/abcd0001",
+		ShortCode:   "abcd0001",
+		OriginalURL: "https://example.com",
+		Clicks:      3,
+		CreatedAt:   createdAt,
+		DeletedAt:   &deletedAt,
+	}}, urls)
+}
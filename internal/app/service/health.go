@@ -3,23 +3,29 @@ package service
 import (
 	"context"
 
+	"shortly/internal/app/dto"
+	"shortly/internal/app/health"
 	"shortly/internal/app/repository"
 )
 
 // HealthChecker is an interface for health checks
 type HealthChecker interface {
 	Ping(ctx context.Context) error
+	// Report reports the health of the application and of its registered components
+	Report(ctx context.Context) dto.HealthReport
 }
 
 // HealthService is a service for health checks
 type healthService struct {
-	repo repository.Repository
+	repo     repository.Repository
+	registry health.Registry
 }
 
 // NewHealthService creates a new health service
-func NewHealthService(repo repository.Repository) HealthChecker {
+func NewHealthService(repo repository.Repository, registry health.Registry) HealthChecker {
 	return &healthService{
-		repo: repo,
+		repo:     repo,
+		registry: registry,
 	}
 }
 
@@ -31,3 +37,8 @@ func (s *healthService) Ping(ctx context.Context) error {
 
 	return nil
 }
+
+// Report reports the health of the application and of its registered components
+func (s *healthService) Report(ctx context.Context) dto.HealthReport {
+	return s.registry.Report(ctx)
+}
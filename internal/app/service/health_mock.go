@@ -12,6 +12,7 @@ package service
 import (
 	context "context"
 	reflect "reflect"
+	dto "shortly/internal/app/dto"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -53,3 +54,17 @@ func (mr *MockHealthCheckerMockRecorder) Ping(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockHealthChecker)(nil).Ping), ctx)
 }
+
+// Report mocks base method.
+func (m *MockHealthChecker) Report(ctx context.Context) dto.HealthReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx)
+	ret0, _ := ret[0].(dto.HealthReport)
+	return ret0
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockHealthCheckerMockRecorder) Report(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockHealthChecker)(nil).Report), ctx)
+}
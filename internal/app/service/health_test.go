@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	"shortly/internal/app/dto"
+	"shortly/internal/app/health"
 	"shortly/internal/app/repository"
 )
 
@@ -16,6 +18,7 @@ func Test_NewHealthService(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := repository.NewMockRepository(ctrl)
+	registry := health.NewMockRegistry(ctrl)
 
 	tests := []struct {
 		name     string
@@ -26,21 +29,23 @@ func Test_NewHealthService(t *testing.T) {
 			name: "Success",
 			repo: repo,
 			expected: &healthService{
-				repo: repo,
+				repo:     repo,
+				registry: registry,
 			},
 		},
 		{
 			name: "Nil repository",
 			repo: nil,
 			expected: &healthService{
-				repo: nil,
+				repo:     nil,
+				registry: registry,
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := NewHealthService(tt.repo)
+			result := NewHealthService(tt.repo, registry)
 			assert.NotNil(t, result)
 			assert.Equal(t, tt.expected, result)
 		})
@@ -97,10 +102,31 @@ func Test_HealthService_Ping(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.before()
 
-			service := NewHealthService(tt.repo)
+			service := NewHealthService(tt.repo, health.NewMockRegistry(ctrl))
 			result := service.Ping(ctx)
 
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func Test_HealthService_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	registry := health.NewMockRegistry(ctrl)
+
+	expected := dto.HealthReport{
+		Status: health.StatusDegraded,
+		Components: map[string]dto.ComponentHealth{
+			"database":      {Status: health.StatusUp, Critical: true},
+			"delete_worker": {Status: health.StatusDegraded, Error: "delete queue saturated"},
+		},
+	}
+	registry.EXPECT().Report(ctx).Return(expected)
+
+	service := NewHealthService(repository.NewInMemoryRepository(), registry)
+
+	assert.Equal(t, expected, service.Report(ctx))
+}
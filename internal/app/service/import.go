@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+)
+
+// ImportChunkSize is the number of import rows stored at once
+const ImportChunkSize = 500
+
+// importState tracks original URLs and aliases seen earlier in the same import
+type importState struct {
+	shortCodes map[string]string
+	aliases    map[string]struct{}
+}
+
+// ImportShortLinks creates short links of the import rows chunk by chunk and reports the result of every row
+func (s *URLService) ImportShortLinks(ctx context.Context, rows dto.ImportReader, report func([]dto.ImportResult) error) error {
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		currentUserID = uuid.Nil
+	}
+
+	state := &importState{
+		shortCodes: make(map[string]string),
+		aliases:    make(map[string]struct{}),
+	}
+	chunk := make([]dto.ImportRow, 0, ImportChunkSize)
+
+	for {
+		row, err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+
+		// NOTE: the body cannot be read any further, rows read so far are still stored and reported
+		if err != nil {
+			results := s.importChunk(ctx, currentUserID, chunk, state)
+			results = append(results, dto.ImportResult{Line: row.Line, Status: dto.ImportStatusFailed, Error: err.Error()})
+			return report(results)
+		}
+
+		chunk = append(chunk, row)
+		if len(chunk) < ImportChunkSize {
+			continue
+		}
+
+		if err = report(s.importChunk(ctx, currentUserID, chunk, state)); err != nil {
+			return err
+		}
+		chunk = chunk[:0]
+	}
+
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	return report(s.importChunk(ctx, currentUserID, chunk, state))
+}
+
+// importChunk stores the valid rows of the chunk with a single CreateURLs call and returns a result per row
+func (s *URLService) importChunk(ctx context.Context, userID uuid.UUID, chunk []dto.ImportRow, state *importState) []dto.ImportResult {
+	results := make([]dto.ImportResult, len(chunk))
+	longURLs := make([]string, 0, len(chunk))
+	lookup := make(map[string]struct{})
+
+	for i, row := range chunk {
+		results[i] = dto.ImportResult{Line: row.Line, CorrelationID: row.CorrelationID}
+
+		if row.Err != nil {
+			results[i].Status = dto.ImportStatusInvalid
+			results[i].Error = row.Err.Error()
+			continue
+		}
+
+		if _, seen := state.shortCodes[row.OriginalURL]; seen {
+			continue
+		}
+
+		if _, listed := lookup[row.OriginalURL]; !listed {
+			lookup[row.OriginalURL] = struct{}{}
+			longURLs = append(longURLs, row.OriginalURL)
+		}
+	}
+
+	existing, err := s.repo.GetURLsByLongURLs(ctx, longURLs)
+	if err != nil {
+		return failPending(results, errors.ErrFailedToSaveURL)
+	}
+
+	for _, url := range existing {
+		state.shortCodes[url.LongURL] = url.ShortCode
+	}
+
+	urls := make([]repository.URL, 0, len(longURLs))
+	created := make([]int, 0, len(longURLs))
+	// NOTE: a row repeating an original URL of the same chunk gets the outcome of its first occurrence
+	duplicates := make(map[int]int)
+	first := make(map[string]int)
+
+	for i, row := range chunk {
+		if results[i].Status != "" {
+			continue
+		}
+
+		if shortCode, ok := state.shortCodes[row.OriginalURL]; ok {
+			results[i].Status = dto.ImportStatusExists
+			results[i].ShortURL = s.shortURL(shortCode)
+			continue
+		}
+
+		if j, ok := first[row.OriginalURL]; ok {
+			duplicates[i] = j
+			continue
+		}
+
+		if row.Alias != "" {
+			if _, seen := state.aliases[row.Alias]; seen {
+				results[i].Status = dto.ImportStatusInvalid
+				results[i].Error = errors.ErrAliasAlreadyExists.Error()
+				continue
+			}
+		}
+
+		url, err := s.newURL(ctx, userID, row.BatchCreateShortLinkParams)
+		if err != nil {
+			results[i].Status = dto.ImportStatusFailed
+			if errors.Is(err, errors.ErrAliasAlreadyExists) {
+				results[i].Status = dto.ImportStatusInvalid
+			}
+			results[i].Error = err.Error()
+			continue
+		}
+
+		if row.Alias != "" {
+			state.aliases[row.Alias] = struct{}{}
+		}
+
+		first[row.OriginalURL] = i
+		urls = append(urls, url)
+		created = append(created, i)
+	}
+
+	if len(urls) > 0 {
+		records, err := s.repo.CreateURLs(ctx, urls)
+		if err != nil {
+			// NOTE: a single conflicting row fails the whole chunk, rows are stored one by one to pinpoint it
+			s.importOneByOne(ctx, urls, created, results, state)
+		} else {
+			for k, i := range created {
+				s.importStored(&results[i], urls[k], records[k], state)
+			}
+		}
+	}
+
+	for i, j := range duplicates {
+		results[i].Status = results[j].Status
+		results[i].ShortURL = results[j].ShortURL
+		results[i].Error = results[j].Error
+
+		if results[j].Status == dto.ImportStatusCreated {
+			results[i].Status = dto.ImportStatusExists
+		}
+	}
+
+	return results
+}
+
+// importOneByOne stores URL records one by one and reports the outcome of each
+func (s *URLService) importOneByOne(ctx context.Context, urls []repository.URL, created []int, results []dto.ImportResult, state *importState) {
+	for k, i := range created {
+		record, err := s.repo.CreateURL(ctx, urls[k])
+
+		switch {
+		case err == nil:
+			s.importStored(&results[i], urls[k], *record, state)
+		case errors.Is(err, errors.ErrShortCodeAlreadyExists):
+			results[i].Status = dto.ImportStatusInvalid
+			results[i].Error = errors.ErrAliasAlreadyExists.Error()
+		default:
+			results[i].Status = dto.ImportStatusFailed
+			results[i].Error = errors.ErrFailedToSaveURL.Error()
+		}
+	}
+}
+
+// importStored reports a stored URL record, a record with another short code already existed
+func (s *URLService) importStored(result *dto.ImportResult, url, record repository.URL, state *importState) {
+	result.Status = dto.ImportStatusCreated
+	if record.ShortCode != url.ShortCode {
+		result.Status = dto.ImportStatusExists
+	}
+
+	result.ShortURL = s.shortURL(record.ShortCode)
+	state.shortCodes[record.LongURL] = record.ShortCode
+}
+
+// failPending marks rows without a result as failed
+func failPending(results []dto.ImportResult, err error) []dto.ImportResult {
+	for i := range results {
+		if results[i].Status == "" {
+			results[i].Status = dto.ImportStatusFailed
+			results[i].Error = err.Error()
+		}
+	}
+
+	return results
+}
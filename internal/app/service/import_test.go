@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/app/worker"
+)
+
+func Test_ImportShortLinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		before      func()
+		expected    []dto.ImportResult
+	}{
+		{
+			name:        "Mixed rows",
+			contentType: dto.ImportFormatCSV,
+			body: "original_url,alias,correlation_id\n" +
+				"https://github.com,,1\n" +
+				"not a url,,2\n" +
+				"https://google.com,,3\n" +
+				"https://github.com,,4\n" +
+				"https://example.com,docs,5\n",
+			before: func() {
+				repo.EXPECT().GetURLsByLongURLs(gomock.Any(), []string{"https://github.com", "https://google.com", "https://example.com"}).
+					Return([]repository.URL{{LongURL: "https://google.com", ShortCode: "goog0001"}}, nil)
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().Hex().Return("abcd0001", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0001").Return(nil, false)
+				rand.EXPECT().UUID().Return(UUID2, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "docs").Return(&repository.URL{ShortCode: "docs"}, true)
+
+				urls := []repository.URL{
+					{UUID: UUID1, LongURL: "https://github.com", ShortCode: "abcd0001", UserUUID: UserUUID},
+				}
+				repo.EXPECT().CreateURLs(gomock.Any(), urls).Return(urls, nil)
+			},
+			expected: []dto.ImportResult{
+				{Line: 2, CorrelationID: "1", Status: dto.ImportStatusCreated, ShortURL: "http://localhost:8080/abcd0001"},
+				{Line: 3, CorrelationID: "2", Status: dto.ImportStatusInvalid, Error: errors.ErrInvalidURL.Error()},
+				{Line: 4, CorrelationID: "3", Status: dto.ImportStatusExists, ShortURL: "http://localhost:8080/goog0001"},
+				{Line: 5, CorrelationID: "4", Status: dto.ImportStatusExists, ShortURL: "http://localhost:8080/abcd0001"},
+				{Line: 6, CorrelationID: "5", Status: dto.ImportStatusInvalid, Error: errors.ErrAliasAlreadyExists.Error()},
+			},
+		},
+		{
+			name:        "Conflicting chunk is stored one by one",
+			contentType: dto.ImportFormatNDJSON,
+			body: `{"original_url": "https://github.com"}` + "\n" +
+				`{"original_url": "https://google.com", "alias": "google"}` + "\n",
+			before: func() {
+				repo.EXPECT().GetURLsByLongURLs(gomock.Any(), []string{"https://github.com", "https://google.com"}).Return(nil, nil)
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().Hex().Return("abcd0001", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0001").Return(nil, false)
+				rand.EXPECT().UUID().Return(UUID2, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "google").Return(nil, false)
+
+				github := repository.URL{UUID: UUID1, LongURL: "https://github.com", ShortCode: "abcd0001", UserUUID: UserUUID}
+				google := repository.URL{UUID: UUID2, LongURL: "https://google.com", ShortCode: "google", UserUUID: UserUUID}
+
+				repo.EXPECT().CreateURLs(gomock.Any(), []repository.URL{github, google}).Return(nil, errors.ErrShortCodeAlreadyExists)
+				repo.EXPECT().CreateURL(gomock.Any(), github).Return(&repository.URL{LongURL: "https://github.com", ShortCode: "gith0001"}, nil)
+				repo.EXPECT().CreateURL(gomock.Any(), google).Return(nil, errors.ErrShortCodeAlreadyExists)
+			},
+			expected: []dto.ImportResult{
+				{Line: 1, Status: dto.ImportStatusExists, ShortURL: "http://localhost:8080/gith0001"},
+				{Line: 2, Status: dto.ImportStatusInvalid, Error: errors.ErrAliasAlreadyExists.Error()},
+			},
+		},
+		{
+			name:        "Original URL shortened meanwhile",
+			contentType: dto.ImportFormatNDJSON,
+			body:        `{"original_url": "https://github.com"}` + "\n",
+			before: func() {
+				repo.EXPECT().GetURLsByLongURLs(gomock.Any(), []string{"https://github.com"}).Return(nil, nil)
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().Hex().Return("abcd0001", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0001").Return(nil, false)
+				repo.EXPECT().CreateURLs(gomock.Any(), []repository.URL{
+					{UUID: UUID1, LongURL: "https://github.com", ShortCode: "abcd0001", UserUUID: UserUUID},
+				}).Return([]repository.URL{{LongURL: "https://github.com", ShortCode: "gith0001"}}, nil)
+			},
+			expected: []dto.ImportResult{
+				{Line: 1, Status: dto.ImportStatusExists, ShortURL: "http://localhost:8080/gith0001"},
+			},
+		},
+		{
+			name:        "Storage error",
+			contentType: dto.ImportFormatNDJSON,
+			body:        `{"original_url": "https://github.com"}` + "\n",
+			before: func() {
+				repo.EXPECT().GetURLsByLongURLs(gomock.Any(), []string{"https://github.com"}).Return(nil, errors.ErrFailedToSaveURL)
+			},
+			expected: []dto.ImportResult{
+				{Line: 1, Status: dto.ImportStatusFailed, Error: errors.ErrFailedToSaveURL.Error()},
+			},
+		},
+		{
+			name:        "Unreadable body",
+			contentType: dto.ImportFormatNDJSON,
+			body:        "not json\n" + strings.Repeat("a", dto.MaxImportLineLength+1),
+			before: func() {
+				repo.EXPECT().GetURLsByLongURLs(gomock.Any(), []string{}).Return(nil, nil)
+			},
+			expected: []dto.ImportResult{
+				{Line: 1, Status: dto.ImportStatusInvalid, Error: "invalid character 'o' in literal null (expecting 'u')"},
+				{Line: 2, Status: dto.ImportStatusFailed, Error: "bufio.Scanner: token too long"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			rows, err := dto.NewImportReader(tt.contentType, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			var results []dto.ImportResult
+			err = service.ImportShortLinks(ctx, rows, func(chunk []dto.ImportResult) error {
+				results = append(results, chunk...)
+				return nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, results)
+		})
+	}
+}
+
+func Test_ImportShortLinks_Chunks(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+
+	rand := NewSecureRandom()
+	repo := repository.NewInMemoryRepository()
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), nil)
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, uuid.New())
+
+	var body strings.Builder
+	for i := 0; i < 2*ImportChunkSize+1; i++ {
+		fmt.Fprintf(&body, "https://example.com/%d,,%d\n", i, i)
+	}
+
+	for _, status := range []string{dto.ImportStatusCreated, dto.ImportStatusExists} {
+		rows, err := dto.NewImportReader(dto.ImportFormatCSV, strings.NewReader(body.String()))
+		require.NoError(t, err)
+
+		var chunks []int
+		err = service.ImportShortLinks(ctx, rows, func(results []dto.ImportResult) error {
+			chunks = append(chunks, len(results))
+			for _, result := range results {
+				assert.Equal(t, status, result.Status)
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{ImportChunkSize, ImportChunkSize, 1}, chunks)
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/logger"
+)
+
+// InternalStats is an interface for the internal statistics of the service
+type InternalStats interface {
+	// GetStats counts the active links and their distinct owners in every storage backend in use
+	GetStats(ctx context.Context) (*dto.InternalStatsResponse, error)
+}
+
+type internalStatsService struct {
+	backend   string
+	repo      repository.Repository
+	persisted bool
+}
+
+// NewInternalStatsService creates a new internal statistics service,
+// the file storage is reported as well when the in-memory repository is persisted to it
+func NewInternalStatsService(cfg *config.Config, repo repository.Repository) InternalStats {
+	_, inMemory := repo.(repository.InMemory)
+
+	return &internalStatsService{
+		backend:   repository.Backend(repo),
+		repo:      repo,
+		persisted: inMemory && cfg.FileStoragePath != "",
+	}
+}
+
+// GetStats counts the active links and their distinct owners in every storage backend in use,
+// the totals are of the repository serving requests
+func (s *internalStatsService) GetStats(ctx context.Context) (*dto.InternalStatsResponse, error) {
+	stats, err := countBackend(ctx, s.repo)
+	if err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msgf("Error counting links of the %s backend", s.backend)
+		return nil, errors.ErrFailedToLoadStats
+	}
+
+	response := &dto.InternalStatsResponse{
+		URLs:     stats.URLs,
+		Users:    stats.Users,
+		Backends: map[string]dto.BackendStats{s.backend: stats},
+	}
+
+	// NOTE: the snapshot and the journal of the file storage hold the records of the in-memory repository,
+	// so they are not scanned again on every request
+	if s.persisted {
+		response.Backends[repository.FileBackend] = stats
+	}
+
+	return response, nil
+}
+
+// countBackend counts the active links and their distinct owners of the backend
+func countBackend(ctx context.Context, backend repository.Counter) (dto.BackendStats, error) {
+	urls, err := backend.CountURLs(ctx)
+	if err != nil {
+		return dto.BackendStats{}, err
+	}
+
+	users, err := backend.CountUsers(ctx)
+	if err != nil {
+		return dto.BackendStats{}, err
+	}
+
+	return dto.BackendStats{URLs: urls, Users: users}, nil
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/service/internal_stats.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/service/internal_stats.go -destination=internal/app/service/internal_stats_mock.go -package=service
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+	dto "shortly/internal/app/dto"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInternalStats is a mock of InternalStats interface.
+type MockInternalStats struct {
+	ctrl     *gomock.Controller
+	recorder *MockInternalStatsMockRecorder
+	isgomock struct{}
+}
+
+// MockInternalStatsMockRecorder is the mock recorder for MockInternalStats.
+type MockInternalStatsMockRecorder struct {
+	mock *MockInternalStats
+}
+
+// NewMockInternalStats creates a new mock instance.
+func NewMockInternalStats(ctrl *gomock.Controller) *MockInternalStats {
+	mock := &MockInternalStats{ctrl: ctrl}
+	mock.recorder = &MockInternalStatsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInternalStats) EXPECT() *MockInternalStatsMockRecorder {
+	return m.recorder
+}
+
+// GetStats mocks base method.
+func (m *MockInternalStats) GetStats(ctx context.Context) (*dto.InternalStatsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx)
+	ret0, _ := ret[0].(*dto.InternalStatsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockInternalStatsMockRecorder) GetStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockInternalStats)(nil).GetStats), ctx)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+)
+
+func Test_InternalStatsService_GetStats(t *testing.T) {
+	ctx := context.Background()
+
+	UserUUID1, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	UserUUID2, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+
+	urls := []repository.URL{
+		{UUID: uuid.New(), LongURL: "https://google.com", ShortCode: "abcd0001", UserUUID: UserUUID1},
+		{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "abcd0002", UserUUID: UserUUID1},
+		{UUID: uuid.New(), LongURL: "https://golang.org", ShortCode: "abcd0003", UserUUID: UserUUID2},
+	}
+
+	tests := []struct {
+		name     string
+		storage  bool
+		expected *dto.InternalStatsResponse
+	}{
+		{
+			name: "In-memory repository",
+			expected: &dto.InternalStatsResponse{
+				URLs:  3,
+				Users: 2,
+				Backends: map[string]dto.BackendStats{
+					repository.InMemoryBackend: {URLs: 3, Users: 2},
+				},
+			},
+		},
+		{
+			name:    "File storage",
+			storage: true,
+			expected: &dto.InternalStatsResponse{
+				URLs:  3,
+				Users: 2,
+				Backends: map[string]dto.BackendStats{
+					repository.InMemoryBackend: {URLs: 3, Users: 2},
+					repository.FileBackend:     {URLs: 3, Users: 2},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			if tt.storage {
+				cfg.FileStoragePath = filepath.Join(t.TempDir(), "storage.json")
+				// NOTE: the file storage is reported with the records of the repository, the older snapshot is not scanned
+				require.NoError(t, repository.NewFileRepository(cfg.FileStoragePath).Save(&repository.Memento{State: urls[:1]}))
+			}
+
+			repo := repository.NewInMemoryRepository()
+			_, err := repo.CreateURLs(ctx, urls)
+			require.NoError(t, err)
+
+			stats, err := NewInternalStatsService(cfg, repo).GetStats(ctx)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, stats)
+		})
+	}
+}
+
+func Test_InternalStatsService_GetStats_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := repository.NewMockRepository(ctrl)
+
+	tests := []struct {
+		name   string
+		before func()
+	}{
+		{
+			name: "Count URLs",
+			before: func() {
+				repo.EXPECT().CountURLs(ctx).Return(int64(0), assert.AnError)
+			},
+		},
+		{
+			name: "Count users",
+			before: func() {
+				repo.EXPECT().CountURLs(ctx).Return(int64(3), nil)
+				repo.EXPECT().CountUsers(ctx).Return(int64(0), assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			stats, err := NewInternalStatsService(&config.Config{}, repo).GetStats(ctx)
+
+			assert.Equal(t, errors.ErrFailedToLoadStats, err)
+			assert.Nil(t, stats)
+		})
+	}
+}
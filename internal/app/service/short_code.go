@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strings"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+)
+
+// HexStrategy generates random lowercase hex short codes
+const HexStrategy = "hex"
+
+// Base62Strategy generates random base62 short codes of configurable length
+const Base62Strategy = "base62"
+
+// CounterStrategy encodes a sequence value with a shuffled alphabet
+const CounterStrategy = "counter"
+
+// WordsStrategy generates human-readable word-based short codes
+const WordsStrategy = "words"
+
+// MaxShortCodeAttempts is the maximum number of attempts to find a free short code
+const MaxShortCodeAttempts = 10
+
+// Base62Alphabet is the alphabet of base62 short codes
+const Base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// MaxCounterLength is the maximum length of counter short codes: 62^10 fits into uint64
+const MaxCounterLength = 10
+
+// counterMultiplier is coprime with 62 and scatters sequential values over the code space
+const counterMultiplier uint64 = 0x9E3779B97F4A7C15
+
+// WordsNumberRange is the upper bound of the numeric suffix of word-based short codes
+const WordsNumberRange = 10000
+
+var adjectives = []string{
+	"able", "bold", "brave", "bright", "calm", "clever", "cool", "cosy",
+	"crisp", "curly", "daring", "eager", "early", "fair", "fancy", "fast",
+	"fluffy", "frosty", "gentle", "giant", "glad", "golden", "grand", "happy",
+	"hardy", "honest", "humble", "jolly", "keen", "kind", "lively", "lucky",
+	"mellow", "merry", "mighty", "misty", "modest", "noble", "polite", "proud",
+	"quick", "quiet", "rapid", "rosy", "royal", "rusty", "shiny", "silent",
+	"silly", "sleepy", "smart", "snowy", "solid", "sunny", "swift", "tidy",
+	"tiny", "vivid", "warm", "wild", "windy", "wise", "witty", "zesty",
+}
+
+var nouns = []string{
+	"apple", "badger", "bear", "beaver", "bird", "breeze", "brook", "cactus",
+	"canyon", "cedar", "cloud", "comet", "coral", "crane", "dolphin", "dune",
+	"eagle", "falcon", "fern", "finch", "forest", "fox", "galaxy", "garden",
+	"glacier", "harbor", "hawk", "island", "jaguar", "koala", "lagoon", "lake",
+	"lemon", "lion", "maple", "meadow", "moon", "moose", "ocean", "orbit",
+	"otter", "owl", "panda", "pebble", "pine", "planet", "pond", "rabbit",
+	"raven", "river", "robin", "rocket", "sparrow", "star", "stone", "storm",
+	"sun", "tiger", "tulip", "valley", "walrus", "whale", "willow", "wolf",
+}
+
+// ShortCodeGenerator is an interface for short code generation strategies
+type ShortCodeGenerator interface {
+	// Generate returns a new short code
+	Generate(ctx context.Context) (string, error)
+	// Covers reports whether the code lies in the space of generated codes, custom aliases in it are rejected
+	Covers(code string) bool
+}
+
+// NewShortCodeGenerator creates a short code generator for the configured strategy
+func NewShortCodeGenerator(cfg *config.Config, repo repository.Repository, rand SecureRandomGenerator) ShortCodeGenerator {
+	switch cfg.ShortCodeStrategy {
+	case Base62Strategy:
+		return NewBase62Generator(cfg.ShortCodeLength)
+	case CounterStrategy:
+		return NewCounterGenerator(repo, cfg.ShortCodeLength, cfg.ShortCodeSalt)
+	case WordsStrategy:
+		return NewWordsGenerator()
+	default:
+		return NewHexGenerator(rand)
+	}
+}
+
+// hexGenerator generates random hex short codes
+type hexGenerator struct {
+	rand SecureRandomGenerator
+}
+
+// NewHexGenerator creates a new hex short code generator
+func NewHexGenerator(rand SecureRandomGenerator) ShortCodeGenerator {
+	return &hexGenerator{rand: rand}
+}
+
+// Generate returns a random hex short code
+func (g *hexGenerator) Generate(_ context.Context) (string, error) {
+	return g.rand.Hex()
+}
+
+// Covers returns false, a random code taken by an alias is skipped like any other collision
+func (g *hexGenerator) Covers(_ string) bool {
+	return false
+}
+
+// base62Generator generates random base62 short codes
+type base62Generator struct {
+	length int
+}
+
+// NewBase62Generator creates a new base62 short code generator
+func NewBase62Generator(length int) ShortCodeGenerator {
+	if length <= 0 {
+		length = config.ShortCodeLength
+	}
+
+	return &base62Generator{length: length}
+}
+
+// Generate returns a random base62 short code
+func (g *base62Generator) Generate(_ context.Context) (string, error) {
+	code := make([]byte, 0, g.length)
+	buf := make([]byte, g.length*2)
+
+	// NOTE: bytes above the largest multiple of 62 are rejected to keep the distribution uniform
+	limit := byte(256 - 256%len(Base62Alphabet))
+
+	for len(code) < g.length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", errors.ErrFailedToReadRandomBytes
+		}
+
+		for _, b := range buf {
+			if b >= limit {
+				continue
+			}
+
+			code = append(code, Base62Alphabet[int(b)%len(Base62Alphabet)])
+			if len(code) == g.length {
+				break
+			}
+		}
+	}
+
+	return string(code), nil
+}
+
+// Covers returns false, a random code taken by an alias is skipped like any other collision
+func (g *base62Generator) Covers(_ string) bool {
+	return false
+}
+
+// counterGenerator encodes sequence values into short codes
+type counterGenerator struct {
+	repo     repository.Repository
+	alphabet string
+	length   int
+}
+
+// NewCounterGenerator creates a new counter short code generator
+func NewCounterGenerator(repo repository.Repository, length int, salt string) ShortCodeGenerator {
+	if length <= 0 {
+		length = config.ShortCodeLength
+	}
+
+	if length > MaxCounterLength {
+		length = MaxCounterLength
+	}
+
+	return &counterGenerator{
+		repo:     repo,
+		alphabet: shuffleAlphabet(Base62Alphabet, salt),
+		length:   length,
+	}
+}
+
+// Generate returns a short code for the next sequence value
+func (g *counterGenerator) Generate(ctx context.Context) (string, error) {
+	n, err := g.repo.NextSequence(ctx)
+	if err != nil {
+		return "", errors.ErrFailedToGenerateCode
+	}
+
+	return g.encode(uint64(n)), nil
+}
+
+// Covers reports whether the code has the length and the alphabet of sequence codes, such an alias would take
+// the code of a future sequence value
+func (g *counterGenerator) Covers(code string) bool {
+	if len(code) < g.length || len(code) > MaxCounterLength {
+		return false
+	}
+
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(g.alphabet, code[i]) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// encode maps a number to a fixed length code, the mapping is a bijection within every code length
+func (g *counterGenerator) encode(n uint64) string {
+	base := uint64(len(g.alphabet))
+
+	length := g.length
+	space := pow(base, length)
+	for n >= space && length < MaxCounterLength {
+		length++
+		space *= base
+	}
+
+	hi, lo := bits.Mul64(n%space, counterMultiplier)
+	_, n = bits.Div64(hi%space, lo, space)
+
+	code := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		code[i] = g.alphabet[n%base]
+		n /= base
+	}
+
+	return string(code)
+}
+
+// wordsGenerator generates human-readable short codes like "brave-otter-1234"
+type wordsGenerator struct{}
+
+// NewWordsGenerator creates a new word-based short code generator
+func NewWordsGenerator() ShortCodeGenerator {
+	return &wordsGenerator{}
+}
+
+// Generate returns a random word-based short code
+func (g *wordsGenerator) Generate(_ context.Context) (string, error) {
+	adjective, err := randomInt(len(adjectives))
+	if err != nil {
+		return "", err
+	}
+
+	noun, err := randomInt(len(nouns))
+	if err != nil {
+		return "", err
+	}
+
+	number, err := randomInt(WordsNumberRange)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-%s-%d", adjectives[adjective], nouns[noun], number), nil
+}
+
+// Covers returns false, a random code taken by an alias is skipped like any other collision
+func (g *wordsGenerator) Covers(_ string) bool {
+	return false
+}
+
+// randomInt returns a uniform random number in [0, n)
+func randomInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, errors.ErrFailedToReadRandomBytes
+	}
+
+	return int(v.Int64()), nil
+}
+
+// shuffleAlphabet deterministically shuffles the alphabet using the salt as a seed
+func shuffleAlphabet(alphabet, salt string) string {
+	result := []byte(alphabet)
+	if salt == "" {
+		return string(result)
+	}
+
+	sum := sha256.Sum256([]byte(salt))
+	state := binary.BigEndian.Uint64(sum[:8])
+
+	// NOTE: splitmix64 is used instead of math/rand to keep the shuffle stable across Go releases
+	next := func() uint64 {
+		state += 0x9E3779B97F4A7C15
+		z := state
+		z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+		z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+		return z ^ (z >> 31)
+	}
+
+	for i := len(result) - 1; i > 0; i-- {
+		j := int(next() % uint64(i+1))
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return string(result)
+}
+
+// pow returns base raised to the power of exp
+func pow(base uint64, exp int) uint64 {
+	result := uint64(1)
+	for i := 0; i < exp; i++ {
+		result *= base
+	}
+
+	return result
+}
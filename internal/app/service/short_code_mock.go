@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/service/short_code.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/service/short_code.go -destination=internal/app/service/short_code_mock.go -package=service
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockShortCodeGenerator is a mock of ShortCodeGenerator interface.
+type MockShortCodeGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockShortCodeGeneratorMockRecorder
+	isgomock struct{}
+}
+
+// MockShortCodeGeneratorMockRecorder is the mock recorder for MockShortCodeGenerator.
+type MockShortCodeGeneratorMockRecorder struct {
+	mock *MockShortCodeGenerator
+}
+
+// NewMockShortCodeGenerator creates a new mock instance.
+func NewMockShortCodeGenerator(ctrl *gomock.Controller) *MockShortCodeGenerator {
+	mock := &MockShortCodeGenerator{ctrl: ctrl}
+	mock.recorder = &MockShortCodeGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShortCodeGenerator) EXPECT() *MockShortCodeGeneratorMockRecorder {
+	return m.recorder
+}
+
+// Covers mocks base method.
+func (m *MockShortCodeGenerator) Covers(code string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Covers", code)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Covers indicates an expected call of Covers.
+func (mr *MockShortCodeGeneratorMockRecorder) Covers(code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Covers", reflect.TypeOf((*MockShortCodeGenerator)(nil).Covers), code)
+}
+
+// Generate mocks base method.
+func (m *MockShortCodeGenerator) Generate(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockShortCodeGeneratorMockRecorder) Generate(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockShortCodeGenerator)(nil).Generate), ctx)
+}
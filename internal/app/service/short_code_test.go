@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+)
+
+func Test_NewShortCodeGenerator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+
+	tests := []struct {
+		name     string
+		strategy string
+		expected ShortCodeGenerator
+	}{
+		{
+			name:     "Default",
+			strategy: "",
+			expected: &hexGenerator{},
+		},
+		{
+			name:     "Hex",
+			strategy: HexStrategy,
+			expected: &hexGenerator{},
+		},
+		{
+			name:     "Base62",
+			strategy: Base62Strategy,
+			expected: &base62Generator{},
+		},
+		{
+			name:     "Counter",
+			strategy: CounterStrategy,
+			expected: &counterGenerator{},
+		},
+		{
+			name:     "Words",
+			strategy: WordsStrategy,
+			expected: &wordsGenerator{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{ShortCodeStrategy: tt.strategy}
+
+			generator := NewShortCodeGenerator(cfg, repo, rand)
+
+			assert.IsType(t, tt.expected, generator)
+		})
+	}
+}
+
+func Test_HexGenerator_Generate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rand := NewMockSecureRandomGenerator(ctrl)
+	generator := NewHexGenerator(rand)
+
+	rand.EXPECT().Hex().Return("abcd1234", nil)
+
+	code, err := generator.Generate(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd1234", code)
+	assert.False(t, generator.Covers(code))
+}
+
+func Test_Base62Generator_Generate(t *testing.T) {
+	tests := []struct {
+		name     string
+		length   int
+		expected int
+	}{
+		{
+			name:     "Configured length",
+			length:   6,
+			expected: 6,
+		},
+		{
+			name:     "Default length",
+			length:   0,
+			expected: config.ShortCodeLength,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := NewBase62Generator(tt.length)
+
+			code, err := generator.Generate(context.Background())
+
+			assert.NoError(t, err)
+			assert.Len(t, code, tt.expected)
+			assert.Regexp(t, regexp.MustCompile(`^[0-9A-Za-z]+$`), code)
+			assert.False(t, generator.Covers(code))
+		})
+	}
+}
+
+func Test_CounterGenerator_Generate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	generator := NewCounterGenerator(repo, 6, "salt")
+
+	t.Run("Success", func(t *testing.T) {
+		repo.EXPECT().NextSequence(gomock.Any()).Return(int64(1), nil)
+		repo.EXPECT().NextSequence(gomock.Any()).Return(int64(2), nil)
+
+		first, err := generator.Generate(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, first, 6)
+
+		second, err := generator.Generate(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, second, 6)
+
+		assert.NotEqual(t, first, second)
+		assert.True(t, generator.Covers(first))
+	})
+
+	t.Run("Sequence error", func(t *testing.T) {
+		repo.EXPECT().NextSequence(gomock.Any()).Return(int64(0), assert.AnError)
+
+		code, err := generator.Generate(context.Background())
+
+		assert.Equal(t, errors.ErrFailedToGenerateCode, err)
+		assert.Empty(t, code)
+	})
+}
+
+func Test_CounterGenerator_encode(t *testing.T) {
+	generator := NewCounterGenerator(nil, 2, "salt").(*counterGenerator)
+
+	seen := make(map[string]struct{})
+	for n := uint64(0); n < 62*62+100; n++ {
+		code := generator.encode(n)
+		if n < 62*62 {
+			assert.Len(t, code, 2)
+		} else {
+			assert.Len(t, code, 3)
+		}
+
+		_, duplicate := seen[code]
+		assert.False(t, duplicate, "duplicate code %s for %d", code, n)
+		seen[code] = struct{}{}
+	}
+
+	other := NewCounterGenerator(nil, 2, "pepper").(*counterGenerator)
+	assert.NotEqual(t, generator.encode(42), other.encode(42))
+	assert.Equal(t, generator.encode(42), NewCounterGenerator(nil, 2, "salt").(*counterGenerator).encode(42))
+}
+
+func Test_WordsGenerator_Generate(t *testing.T) {
+	generator := NewWordsGenerator()
+
+	code, err := generator.Generate(context.Background())
+
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[a-z]+-[a-z]+-\d{1,4}$`), code)
+	assert.False(t, generator.Covers(code))
+}
+
+func Test_CounterGenerator_Covers(t *testing.T) {
+	generator := NewCounterGenerator(nil, 6, "salt")
+
+	tests := []struct {
+		code     string
+		expected bool
+	}{
+		{code: "Ab3dE9", expected: true},
+		{code: "Ab3dE9xyz0", expected: true},
+		{code: "Ab3dE", expected: false},
+		{code: "Ab3dE9xyz01", expected: false},
+		{code: "my-link", expected: false},
+		{code: "my_link1", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.expected, generator.Covers(tt.code))
+		})
+	}
+}
+
+func Test_shuffleAlphabet(t *testing.T) {
+	assert.Equal(t, Base62Alphabet, shuffleAlphabet(Base62Alphabet, ""))
+
+	shuffled := shuffleAlphabet(Base62Alphabet, "salt")
+	assert.NotEqual(t, Base62Alphabet, shuffled)
+	assert.ElementsMatch(t, []byte(Base62Alphabet), []byte(shuffled))
+	assert.Equal(t, shuffled, shuffleAlphabet(Base62Alphabet, "salt"))
+}
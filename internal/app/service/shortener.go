@@ -3,45 +3,56 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"shortly/internal/app/api/pagination"
 	"shortly/internal/app/config"
 	"shortly/internal/app/dto"
 	"shortly/internal/app/errors"
+	"shortly/internal/app/metrics"
 	"shortly/internal/app/repository"
+	"shortly/internal/app/tracing"
 	"shortly/internal/app/worker"
+	"shortly/internal/logger"
 )
 
 // URLService is a service for URL operations
 type URLService struct {
-	cfg    *config.Config
-	repo   repository.Repository
-	rand   SecureRandomGenerator
-	worker worker.Worker
+	cfg       *config.Config
+	repo      repository.Repository
+	rand      SecureRandomGenerator
+	generator ShortCodeGenerator
+	worker    worker.Worker
 }
 
 // NewURLService creates a new URL service instance
-func NewURLService(cfg *config.Config, repo repository.Repository, rand SecureRandomGenerator, worker worker.Worker) *URLService {
+func NewURLService(cfg *config.Config, repo repository.Repository, rand SecureRandomGenerator, generator ShortCodeGenerator, worker worker.Worker) *URLService {
 	return &URLService{
-		cfg:    cfg,
-		repo:   repo,
-		rand:   rand,
-		worker: worker,
+		cfg:       cfg,
+		repo:      repo,
+		rand:      rand,
+		generator: generator,
+		worker:    worker,
 	}
 }
 
 // CreateShortLink creates a new short link
-func (s *URLService) CreateShortLink(ctx context.Context, longURL string) (string, error) {
+func (s *URLService) CreateShortLink(ctx context.Context, params dto.CreateShortLinkRequest) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "URLService.CreateShortLink")
+	defer func() { tracing.End(span, err) }()
+
 	id, err := s.rand.UUID()
 	if err != nil {
 		return "", errors.ErrFailedToGenerateUUID
 	}
 
-	shortCode, err := s.generateUniqueShortCode(ctx)
+	shortCode, err := s.resolveShortCode(ctx, params.Alias)
 	if err != nil {
-		return "", errors.ErrFailedToGenerateCode
+		return "", err
 	}
 
 	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
@@ -51,13 +62,18 @@ func (s *URLService) CreateShortLink(ctx context.Context, longURL string) (strin
 
 	url := repository.URL{
 		UUID:      id,
-		LongURL:   longURL,
+		LongURL:   params.URL,
 		ShortCode: shortCode,
 		UserUUID:  currentUserID,
+		ExpiresAt: params.Deadline(time.Now()),
 	}
 
 	record, err := s.repo.CreateURL(ctx, url)
 	if err != nil {
+		if params.Alias != "" && errors.Is(err, errors.ErrShortCodeAlreadyExists) {
+			return "", errors.ErrAliasAlreadyExists
+		}
+		logger.FromContext(ctx).Error().Err(err).Msg("Error saving URL")
 		return "", errors.ErrFailedToSaveURL
 	}
 
@@ -68,63 +84,148 @@ func (s *URLService) CreateShortLink(ctx context.Context, longURL string) (strin
 	return fmt.Sprintf("%s/%s", s.cfg.BaseURL, shortCode), nil
 }
 
-// CreateShortLinks creates new short links
-func (s *URLService) CreateShortLinks(ctx context.Context, params []dto.BatchCreateShortLinkParams) ([]dto.BatchCreateShortLinkResponse, error) {
-	longURLs := make([]repository.URL, 0, len(params))
-	results := make([]dto.BatchCreateShortLinkResponse, 0, len(params))
+// CreateShortLinks creates new short links and returns a result per item in the request order.
+// In atomic mode a failing item fails the whole batch and nothing is stored,
+// otherwise items with a taken alias are reported with an error and the rest is stored
+func (s *URLService) CreateShortLinks(ctx context.Context, params []dto.BatchCreateShortLinkParams, atomic bool) (_ []dto.BatchCreateShortLinkResponse, err error) {
+	ctx, span := tracing.Start(ctx, "URLService.CreateShortLinks")
+	defer func() { tracing.End(span, err) }()
+
+	results := make([]dto.BatchCreateShortLinkResponse, len(params))
+	urls := make([]repository.URL, 0, len(params))
+	// NOTE: pending holds the result index of every prepared URL record
+	pending := make([]int, 0, len(params))
+
+	aliases := make(map[string]struct{})
 
 	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
 	if !ok {
 		currentUserID = uuid.Nil
 	}
 
-	for _, param := range params {
-		id, err := s.rand.UUID()
-		if err != nil {
-			return nil, errors.ErrFailedToGenerateUUID
-		}
+	for i, param := range params {
+		results[i].CorrelationID = param.CorrelationID
 
-		shortCode, err := s.generateUniqueShortCode(ctx)
+		url, err := s.newBatchURL(ctx, currentUserID, param, aliases)
 		if err != nil {
-			return nil, errors.ErrFailedToGenerateCode
+			if atomic || !errors.Is(err, errors.ErrAliasAlreadyExists) && !errors.Is(err, errors.ErrInvalidAlias) {
+				return nil, err
+			}
+			results[i].Error = err.Error()
+			continue
 		}
 
-		url := repository.URL{
-			UUID:      id,
-			LongURL:   param.OriginalURL,
-			ShortCode: shortCode,
-			UserUUID:  currentUserID,
+		urls = append(urls, url)
+		pending = append(pending, i)
+	}
+
+	if len(urls) == 0 {
+		return results, nil
+	}
+
+	records, err := s.repo.CreateURLs(ctx, urls)
+	if err != nil {
+		if !errors.Is(err, errors.ErrShortCodeAlreadyExists) {
+			logger.FromContext(ctx).Error().Err(err).Msgf("Error saving batch of %d URLs", len(urls))
+			return nil, errors.ErrFailedToSaveURL
+		}
+		if atomic && len(aliases) > 0 {
+			return nil, errors.ErrAliasAlreadyExists
+		}
+		if atomic {
+			return nil, errors.ErrFailedToSaveURL
 		}
-		longURLs = append(longURLs, url)
 
-		results = append(results, dto.BatchCreateShortLinkResponse{
-			CorrelationID: param.CorrelationID,
-			ShortURL:      fmt.Sprintf("%s/%s", s.cfg.BaseURL, shortCode),
-		})
+		// NOTE: a single taken alias fails the whole transaction, records are stored one by one to pinpoint it
+		s.createOneByOne(ctx, urls, pending, params, results)
+		return results, nil
 	}
 
-	if err := s.repo.CreateURLs(ctx, longURLs); err != nil {
-		return nil, errors.ErrFailedToSaveURL
+	for k, record := range records {
+		results[pending[k]].ShortURL = s.shortURL(record.ShortCode)
+		results[pending[k]].Conflict = record.ShortCode != urls[k].ShortCode
 	}
 
 	return results, nil
 }
 
+// newBatchURL creates a URL record of a batch item, an alias repeated within the batch is taken
+func (s *URLService) newBatchURL(ctx context.Context, userID uuid.UUID, param dto.BatchCreateShortLinkParams, aliases map[string]struct{}) (repository.URL, error) {
+	if param.Alias != "" {
+		if _, seen := aliases[param.Alias]; seen {
+			return repository.URL{}, errors.ErrAliasAlreadyExists
+		}
+		aliases[param.Alias] = struct{}{}
+	}
+
+	return s.newURL(ctx, userID, param)
+}
+
+// createOneByOne stores URL records one by one and reports the outcome of each
+func (s *URLService) createOneByOne(ctx context.Context, urls []repository.URL, pending []int, params []dto.BatchCreateShortLinkParams, results []dto.BatchCreateShortLinkResponse) {
+	for k, i := range pending {
+		record, err := s.repo.CreateURL(ctx, urls[k])
+
+		switch {
+		case err == nil:
+			results[i].ShortURL = s.shortURL(record.ShortCode)
+			results[i].Conflict = record.ShortCode != urls[k].ShortCode
+		case params[i].Alias != "" && errors.Is(err, errors.ErrShortCodeAlreadyExists):
+			results[i].Error = errors.ErrAliasAlreadyExists.Error()
+		default:
+			logger.FromContext(ctx).Error().Err(err).Msgf("Error saving URL of batch item %s", params[i].CorrelationID)
+			results[i].Error = errors.ErrFailedToSaveURL.Error()
+		}
+	}
+}
+
+// newURL creates a URL record of a validated batch item
+func (s *URLService) newURL(ctx context.Context, userID uuid.UUID, param dto.BatchCreateShortLinkParams) (repository.URL, error) {
+	id, err := s.rand.UUID()
+	if err != nil {
+		return repository.URL{}, errors.ErrFailedToGenerateUUID
+	}
+
+	shortCode, err := s.resolveShortCode(ctx, param.Alias)
+	if err != nil {
+		return repository.URL{}, err
+	}
+
+	return repository.URL{
+		UUID:      id,
+		LongURL:   param.OriginalURL,
+		ShortCode: shortCode,
+		UserUUID:  userID,
+		ExpiresAt: param.Deadline(time.Now()),
+	}, nil
+}
+
+// shortURL returns the short URL of the short code
+func (s *URLService) shortURL(shortCode string) string {
+	return fmt.Sprintf("%s/%s", s.cfg.BaseURL, shortCode)
+}
+
 // GetShortLink returns a short link by short code
 func (s *URLService) GetShortLink(ctx context.Context, shortCode string) (*repository.URL, bool) {
+	ctx, span := tracing.Start(ctx, "URLService.GetShortLink")
+	defer span.End()
+
 	return s.repo.GetURLByShortCode(ctx, shortCode)
 }
 
-// GetUserURLs returns user URLs
-func (s *URLService) GetUserURLs(ctx context.Context, pagination *pagination.Pagination) ([]dto.GetUserURLsResponse, int, error) {
+// GetUserURLs returns user URLs and the cursor of the next page, which is empty on the last page
+func (s *URLService) GetUserURLs(ctx context.Context, pagination *pagination.Pagination) (_ []dto.GetUserURLsResponse, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "URLService.GetUserURLs")
+	defer func() { tracing.End(span, err) }()
+
 	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
 	if !ok {
-		return nil, 0, errors.ErrInvalidUserID
+		return nil, "", errors.ErrInvalidUserID
 	}
 
-	urls, total, err := s.repo.GetURLsByUserID(ctx, currentUserID, pagination.Per, pagination.Offset())
+	urls, next, err := s.userURLsPage(ctx, currentUserID, pagination)
 	if err != nil {
-		return nil, 0, errors.ErrFailedToLoadUserUrls
+		return nil, "", err
 	}
 
 	results := make([]dto.GetUserURLsResponse, len(urls))
@@ -135,28 +236,255 @@ func (s *URLService) GetUserURLs(ctx context.Context, pagination *pagination.Pag
 		}
 	}
 
+	return results, next, nil
+}
+
+// userURLsPage loads a page of user URLs by the cursor or by the page number and the cursor of the next page
+func (s *URLService) userURLsPage(ctx context.Context, userID uuid.UUID, p *pagination.Pagination) ([]repository.URL, string, error) {
+	var urls []repository.URL
+	var more bool
+
+	if p.Cursor == "" {
+		var total int
+		var err error
+
+		urls, total, err = s.repo.GetURLsByUserID(ctx, userID, p.Per, p.Offset())
+		if err != nil {
+			logger.FromContext(ctx).Error().Err(err).Msg("Error loading user URLs")
+			return nil, "", errors.ErrFailedToLoadUserUrls
+		}
+
+		more = p.Offset()+int64(len(urls)) < int64(total)
+	} else {
+		createdAt, id, err := pagination.DecodeCursor(p.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// NOTE: one extra record tells whether the next page exists without counting the whole set
+		urls, err = s.repo.GetURLsByUserIDAfter(ctx, userID, repository.URLCursor{CreatedAt: createdAt, UUID: id}, p.Per+1)
+		if err != nil {
+			logger.FromContext(ctx).Error().Err(err).Msg("Error loading user URLs")
+			return nil, "", errors.ErrFailedToLoadUserUrls
+		}
+
+		if more = int64(len(urls)) > p.Per; more {
+			urls = urls[:p.Per]
+		}
+	}
+
+	if !more || len(urls) == 0 {
+		return urls, "", nil
+	}
+
+	last := urls[len(urls)-1]
+
+	return urls, pagination.EncodeCursor(last.CreatedAt, last.UUID), nil
+}
+
+// FindUserURLs returns a filtered page of the current user URLs and the number of matching URLs
+func (s *URLService) FindUserURLs(ctx context.Context, pagination *pagination.Pagination, params dto.ListUserURLsRequest) (_ []dto.UserURLResponse, _ int, err error) {
+	ctx, span := tracing.Start(ctx, "URLService.FindUserURLs")
+	defer func() { tracing.End(span, err) }()
+
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		return nil, 0, errors.ErrInvalidUserID
+	}
+
+	items, total, err := s.repo.FindURLsByUserID(ctx, repository.URLFilter{
+		UserUUID:      currentUserID,
+		Query:         params.Query,
+		Deleted:       params.Deleted,
+		Expired:       params.Expired,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		Sort:          params.Sort,
+		Limit:         pagination.Per,
+		Offset:        pagination.Offset(),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msg("Error finding user URLs")
+		return nil, 0, errors.ErrFailedToLoadUserUrls
+	}
+
+	results := make([]dto.UserURLResponse, len(items))
+	for i, item := range items {
+		results[i] = dto.UserURLResponse{
+			ShortURL:    fmt.Sprintf("%s/%s", s.cfg.BaseURL, item.ShortCode),
+			ShortCode:   item.ShortCode,
+			OriginalURL: item.LongURL,
+			Clicks:      item.Clicks,
+			CreatedAt:   item.CreatedAt,
+			ExpiresAt:   optionalTime(item.ExpiresAt),
+			DeletedAt:   optionalTime(item.DeletedAt),
+			Expired:     item.Expired,
+		}
+	}
+
 	return results, total, nil
 }
 
-// DeleteUserURLs deletes user URLs
-func (s *URLService) DeleteUserURLs(ctx context.Context, params dto.BatchDeleteShortLinkRequest) error {
+// UpdateUserURL changes the original URL of a user short link
+func (s *URLService) UpdateUserURL(ctx context.Context, shortCode string, params dto.UpdateShortLinkRequest) (_ *dto.GetUserURLsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "URLService.UpdateUserURL")
+	defer func() { tracing.End(span, err) }()
+
 	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
 	if !ok {
-		return errors.ErrInvalidUserID
+		return nil, errors.ErrInvalidUserID
+	}
+
+	url, err := s.repo.UpdateURL(ctx, repository.URL{
+		LongURL:   params.URL,
+		ShortCode: shortCode,
+		UserUUID:  currentUserID,
+	})
+	if err != nil {
+		if errors.Is(err, errors.ErrShortLinkNotFound) || errors.Is(err, errors.ErrURLAlreadyExists) {
+			return nil, err
+		}
+		logger.FromContext(ctx).Error().Err(err).Msgf("Error updating URL %s", shortCode)
+		return nil, errors.ErrFailedToUpdateURL
 	}
 
-	s.worker.Add(dto.BatchDeleteParams{
+	return &dto.GetUserURLsResponse{
+		ShortURL:    fmt.Sprintf("%s/%s", s.cfg.BaseURL, url.ShortCode),
+		OriginalURL: url.LongURL,
+	}, nil
+}
+
+// DeleteUserURLs queues deletion of user URLs and returns the delete job
+func (s *URLService) DeleteUserURLs(ctx context.Context, params dto.BatchDeleteShortLinkRequest) (_ *dto.DeleteJobResponse, err error) {
+	ctx, span := tracing.Start(ctx, "URLService.DeleteUserURLs")
+	defer func() { tracing.End(span, err) }()
+
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	job, err := s.worker.Add(ctx, dto.BatchDeleteParams{
 		UserID:     currentUserID,
 		ShortCodes: params,
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return newDeleteJobResponse(job), nil
 }
 
-// generateUniqueShortCode generates a unique short code
-func (s *URLService) generateUniqueShortCode(ctx context.Context) (string, error) {
-	for {
-		shortCode, err := s.rand.Hex()
+// GetDeleteJob returns the state of a delete job of the current user
+func (s *URLService) GetDeleteJob(ctx context.Context, id string) (_ *dto.DeleteJobResponse, err error) {
+	ctx, span := tracing.Start(ctx, "URLService.GetDeleteJob")
+	defer func() { tracing.End(span, err) }()
+
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.ErrDeleteJobNotFound
+	}
+
+	job, ok := s.worker.Job(ctx, currentUserID, jobID)
+	if !ok {
+		return nil, errors.ErrDeleteJobNotFound
+	}
+
+	return newDeleteJobResponse(job), nil
+}
+
+// newDeleteJobResponse describes the delete job with the number of deleted and skipped short codes
+func newDeleteJobResponse(job worker.DeleteJob) *dto.DeleteJobResponse {
+	result := &dto.DeleteJobResponse{
+		ID:        job.ID,
+		Status:    job.Status,
+		Requested: len(job.ShortCodes),
+		Deleted:   len(job.Deleted),
+		Skipped:   job.Skipped,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+	}
+
+	for _, skipped := range job.Skipped {
+		switch skipped.Reason {
+		case dto.SkipReasonNotOwned:
+			result.NotOwned++
+		case dto.SkipReasonNotFound:
+			result.NotFound++
+		case dto.SkipReasonAlreadyDeleted:
+			result.AlreadyDeleted++
+		}
+	}
+
+	if !job.FinishedAt.IsZero() {
+		result.FinishedAt = &job.FinishedAt
+	}
+
+	return result
+}
+
+// RestoreUserURLs restores user URLs deleted within the grace period and returns the restored short codes
+func (s *URLService) RestoreUserURLs(ctx context.Context, params dto.BatchRestoreShortLinkRequest) (_ *dto.BatchRestoreShortLinkResponse, err error) {
+	ctx, span := tracing.Start(ctx, "URLService.RestoreUserURLs")
+	defer func() { tracing.End(span, err) }()
+
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	gracePeriod := s.cfg.RestoreGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = config.RestoreGracePeriod
+	}
+
+	restored, err := s.repo.RestoreURLsByUserID(ctx, currentUserID, params, time.Now().Add(-gracePeriod))
+	if err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msg("Error restoring user URLs")
+		return nil, errors.ErrFailedToRestoreURLs
+	}
+
+	if restored == nil {
+		restored = []string{}
+	}
+
+	return &dto.BatchRestoreShortLinkResponse{Restored: restored}, nil
+}
+
+// resolveShortCode returns the custom alias if it is free, otherwise generates a unique short code
+func (s *URLService) resolveShortCode(ctx context.Context, alias string) (string, error) {
+	if alias == "" {
+		shortCode, err := s.generateUniqueShortCode(ctx)
+		if err != nil {
+			logger.FromContext(ctx).Error().Err(err).Msg("Error generating short code")
+			return "", errors.ErrFailedToGenerateCode
+		}
+		return shortCode, nil
+	}
+
+	if s.generator.Covers(alias) {
+		return "", errors.ErrInvalidAlias
+	}
+
+	if _, exists := s.repo.GetURLByShortCode(ctx, alias); exists {
+		return "", errors.ErrAliasAlreadyExists
+	}
+
+	return alias, nil
+}
+
+// generateUniqueShortCode generates a unique short code, codes taken by aliases or by a previous strategy are skipped
+func (s *URLService) generateUniqueShortCode(ctx context.Context) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "URLService.generateUniqueShortCode")
+	defer func() { tracing.End(span, err) }()
+
+	for attempt := 0; attempt < MaxShortCodeAttempts; attempt++ {
+		shortCode, err := s.generator.Generate(ctx)
 		if err != nil {
 			return "", err
 		}
@@ -164,5 +492,20 @@ func (s *URLService) generateUniqueShortCode(ctx context.Context) (string, error
 		if _, exists := s.repo.GetURLByShortCode(ctx, shortCode); !exists {
 			return shortCode, nil
 		}
+
+		metrics.ShortCodeRetries.Inc()
+		logger.FromContext(ctx).Debug().Msgf("Short code %s collision on attempt %d", shortCode, attempt+1)
+		span.AddEvent("short code collision", trace.WithAttributes(attribute.Int("attempt", attempt+1)))
 	}
+
+	return "", errors.ErrFailedToGenerateCode
+}
+
+// optionalTime converts zero time into nil, so it is omitted from responses
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
 }
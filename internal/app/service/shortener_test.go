@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -34,7 +35,7 @@ func Test_CreateShortLink(t *testing.T) {
 	repo := repository.NewMockRepository(ctrl)
 	rand := NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	service := NewURLService(cfg, repo, rand, appWorker)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
 
 	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
 	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
@@ -57,14 +58,14 @@ func Test_CreateShortLink(t *testing.T) {
 			before: func() {
 				rand.EXPECT().UUID().Return(UUID1, nil)
 				rand.EXPECT().Hex().Return("abcd1234", nil)
-				repo.EXPECT().GetURLByShortCode(ctx, "abcd1234").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
 
 				url := repository.URL{
 					UUID:      UUID1,
 					LongURL:   "https://example.com",
 					ShortCode: "abcd1234",
 				}
-				repo.EXPECT().CreateURL(ctx, url).Return(&url, nil)
+				repo.EXPECT().CreateURL(gomock.Any(), url).Return(&url, nil)
 			},
 			expected: result{
 				shortCode: "abcd1234",
@@ -78,7 +79,7 @@ func Test_CreateShortLink(t *testing.T) {
 			before: func() {
 				rand.EXPECT().UUID().Return(UUID2, nil)
 				rand.EXPECT().Hex().Return("abcd1234", nil)
-				repo.EXPECT().GetURLByShortCode(ctx, "abcd1234").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
 
 				existingURL := repository.URL{
 					UUID:      UUID1,
@@ -86,7 +87,7 @@ func Test_CreateShortLink(t *testing.T) {
 					ShortCode: "abab0001",
 				}
 
-				repo.EXPECT().CreateURL(ctx, repository.URL{
+				repo.EXPECT().CreateURL(gomock.Any(), repository.URL{
 					UUID:      UUID2,
 					LongURL:   "https://example.com",
 					ShortCode: "abcd1234",
@@ -98,6 +99,83 @@ func Test_CreateShortLink(t *testing.T) {
 				error:     errors.ErrURLAlreadyExists,
 			},
 		},
+		{
+			name: "Success with alias",
+			body: strings.NewReader(`{"url":"https://example.com/spring","alias":"spring-sale"}`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "spring-sale").Return(nil, false)
+
+				url := repository.URL{
+					UUID:      UUID1,
+					LongURL:   "https://example.com/spring",
+					ShortCode: "spring-sale",
+				}
+				repo.EXPECT().CreateURL(gomock.Any(), url).Return(&url, nil)
+			},
+			expected: result{
+				shortCode: "spring-sale",
+				shortURL:  "http://localhost:8080/spring-sale",
+				error:     nil,
+			},
+		},
+		{
+			name: "Success with expiration time",
+			body: strings.NewReader(`{"url":"https://example.com","expires_at":"2999-01-01T00:00:00Z"}`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().Hex().Return("abcd1234", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(nil, false)
+
+				url := repository.URL{
+					UUID:      UUID1,
+					LongURL:   "https://example.com",
+					ShortCode: "abcd1234",
+					ExpiresAt: time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC),
+				}
+				repo.EXPECT().CreateURL(gomock.Any(), url).Return(&url, nil)
+			},
+			expected: result{
+				shortCode: "abcd1234",
+				shortURL:  "http://localhost:8080/abcd1234",
+				error:     nil,
+			},
+		},
+		{
+			name: "Alias already exists",
+			body: strings.NewReader(`{"url":"https://example.com/spring","alias":"spring-sale"}`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "spring-sale").Return(&repository.URL{
+					UUID:      UUID2,
+					LongURL:   "https://example.com/other",
+					ShortCode: "spring-sale",
+				}, true)
+			},
+			expected: result{
+				shortCode: "",
+				shortURL:  "",
+				error:     errors.ErrAliasAlreadyExists,
+			},
+		},
+		{
+			name: "Alias taken concurrently",
+			body: strings.NewReader(`{"url":"https://example.com/spring","alias":"spring-sale"}`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "spring-sale").Return(nil, false)
+				repo.EXPECT().CreateURL(gomock.Any(), repository.URL{
+					UUID:      UUID1,
+					LongURL:   "https://example.com/spring",
+					ShortCode: "spring-sale",
+				}).Return(nil, errors.ErrShortCodeAlreadyExists)
+			},
+			expected: result{
+				shortCode: "",
+				shortURL:  "",
+				error:     errors.ErrAliasAlreadyExists,
+			},
+		},
 		{
 			name: "Error generating UUID",
 			body: strings.NewReader(`{"url":"https://example.com"}`),
@@ -123,6 +201,20 @@ func Test_CreateShortLink(t *testing.T) {
 				error:     errors.ErrFailedToGenerateCode,
 			},
 		},
+		{
+			name: "Error all short codes taken",
+			body: strings.NewReader(`{"url":"https://example.com"}`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().Hex().Return("abcd1234", nil).Times(MaxShortCodeAttempts)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd1234").Return(&repository.URL{}, true).Times(MaxShortCodeAttempts)
+			},
+			expected: result{
+				shortCode: "",
+				shortURL:  "",
+				error:     errors.ErrFailedToGenerateCode,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -134,7 +226,7 @@ func Test_CreateShortLink(t *testing.T) {
 			err := json.NewDecoder(r.Body).Decode(&req)
 			assert.NoError(t, err)
 
-			shortURL, err := service.CreateShortLink(ctx, req.URL)
+			shortURL, err := service.CreateShortLink(ctx, req)
 
 			assert.Equal(t, tt.expected.shortURL, shortURL)
 			assert.Equal(t, tt.expected.error, err)
@@ -142,6 +234,57 @@ func Test_CreateShortLink(t *testing.T) {
 	}
 }
 
+func Test_CreateShortLink_CounterGenerator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+
+	ctx := context.Background()
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+
+	t.Run("Code taken by an alias", func(t *testing.T) {
+		repo := repository.NewMockRepository(ctrl)
+		rand := NewMockSecureRandomGenerator(ctrl)
+		service := NewURLService(cfg, repo, rand, NewCounterGenerator(repo, 6, "salt"), worker.NewMockWorker(ctrl))
+
+		rand.EXPECT().UUID().Return(UUID, nil)
+		repo.EXPECT().NextSequence(gomock.Any()).Return(int64(1), nil)
+		repo.EXPECT().NextSequence(gomock.Any()).Return(int64(2), nil)
+
+		// NOTE: the code of the first sequence value was taken as an alias before the strategy switch
+		taken, _ := NewCounterGenerator(nil, 6, "salt").(*counterGenerator)
+		repo.EXPECT().GetURLByShortCode(gomock.Any(), taken.encode(1)).Return(&repository.URL{ShortCode: taken.encode(1)}, true)
+		repo.EXPECT().GetURLByShortCode(gomock.Any(), taken.encode(2)).Return(nil, false)
+
+		url := repository.URL{
+			UUID:      UUID,
+			LongURL:   "https://example.com",
+			ShortCode: taken.encode(2),
+		}
+		repo.EXPECT().CreateURL(gomock.Any(), url).Return(&url, nil)
+
+		shortURL, err := service.CreateShortLink(ctx, dto.CreateShortLinkRequest{URL: "https://example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://localhost:8080/"+taken.encode(2), shortURL)
+	})
+
+	t.Run("Alias in the code space", func(t *testing.T) {
+		repo := repository.NewMockRepository(ctrl)
+		rand := NewMockSecureRandomGenerator(ctrl)
+		service := NewURLService(cfg, repo, rand, NewCounterGenerator(repo, 6, "salt"), worker.NewMockWorker(ctrl))
+
+		rand.EXPECT().UUID().Return(UUID, nil)
+
+		_, err := service.CreateShortLink(ctx, dto.CreateShortLinkRequest{URL: "https://example.com", Alias: "promo2024"})
+
+		assert.ErrorIs(t, err, errors.ErrInvalidAlias)
+	})
+}
+
 func TestURLService_CreateShortLinks(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -156,22 +299,22 @@ func TestURLService_CreateShortLinks(t *testing.T) {
 	repo := repository.NewMockRepository(ctrl)
 	rand := NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	service := NewURLService(cfg, repo, rand, appWorker)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
 
 	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
 	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
 
-	type result struct {
-		shortCode string
-		shortURL  string
-		error     error
-	}
+	github := repository.URL{UUID: UUID1, LongURL: "https://github.com", ShortCode: "abcd0001"}
+	google := repository.URL{UUID: UUID2, LongURL: "https://google.com", ShortCode: "abcd0002"}
+	githubAlias := repository.URL{UUID: UUID1, LongURL: "https://github.com", ShortCode: "github"}
 
 	tests := []struct {
 		name     string
 		body     io.Reader
+		atomic   bool
 		before   func()
-		expected []result
+		expected []dto.BatchCreateShortLinkResponse
+		error    error
 	}{
 		{
 			name: "Success",
@@ -184,49 +327,152 @@ func TestURLService_CreateShortLinks(t *testing.T) {
 				rand.EXPECT().UUID().Return(UUID2, nil)
 				rand.EXPECT().Hex().Return("abcd0001", nil)
 				rand.EXPECT().Hex().Return("abcd0002", nil)
-				repo.EXPECT().GetURLByShortCode(ctx, "abcd0001").Return(nil, false)
-				repo.EXPECT().GetURLByShortCode(ctx, "abcd0002").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0001").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0002").Return(nil, false)
 
-				urls := []repository.URL{
-					{
-						UUID:      UUID1,
-						LongURL:   "https://github.com",
-						ShortCode: "abcd0001",
-					},
-					{
-						UUID:      UUID2,
-						LongURL:   "https://google.com",
-						ShortCode: "abcd0002",
-					},
-				}
-				repo.EXPECT().CreateURLs(ctx, urls)
+				urls := []repository.URL{github, google}
+				repo.EXPECT().CreateURLs(gomock.Any(), urls).Return(urls, nil)
 			},
-			expected: []result{
-				{
-					shortCode: "abcd0001",
-					shortURL:  "http://localhost:8080/abcd0001",
-					error:     nil,
-				},
-				{
-					shortCode: "abcd0002",
-					shortURL:  "http://localhost:8080/abcd0002",
-					error:     nil,
-				},
+			expected: []dto.BatchCreateShortLinkResponse{
+				{CorrelationID: "0001", ShortURL: "http://localhost:8080/abcd0001"},
+				{CorrelationID: "0002", ShortURL: "http://localhost:8080/abcd0002"},
+			},
+		},
+		{
+			name: "Success with alias",
+			body: strings.NewReader(`[
+				{"correlation_id": "0001", "original_url": "https://github.com", "alias": "github"},
+				{"correlation_id": "0002", "original_url": "https://google.com"}
+			]`),
+			atomic: true,
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().UUID().Return(UUID2, nil)
+				rand.EXPECT().Hex().Return("abcd0002", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "github").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0002").Return(nil, false)
+
+				urls := []repository.URL{githubAlias, google}
+				repo.EXPECT().CreateURLs(gomock.Any(), urls).Return(urls, nil)
+			},
+			expected: []dto.BatchCreateShortLinkResponse{
+				{CorrelationID: "0001", ShortURL: "http://localhost:8080/github"},
+				{CorrelationID: "0002", ShortURL: "http://localhost:8080/abcd0002"},
 			},
 		},
+		{
+			name: "Existing original URL is reported as conflict",
+			body: strings.NewReader(`[
+				{"correlation_id": "0001", "original_url": "https://github.com"},
+				{"correlation_id": "0002", "original_url": "https://google.com"}
+			]`),
+			atomic: true,
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().UUID().Return(UUID2, nil)
+				rand.EXPECT().Hex().Return("abcd0001", nil)
+				rand.EXPECT().Hex().Return("abcd0002", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0001").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0002").Return(nil, false)
+
+				repo.EXPECT().CreateURLs(gomock.Any(), []repository.URL{github, google}).Return([]repository.URL{
+					{UUID: uuid.New(), LongURL: "https://github.com", ShortCode: "gith0001"},
+					google,
+				}, nil)
+			},
+			expected: []dto.BatchCreateShortLinkResponse{
+				{CorrelationID: "0001", ShortURL: "http://localhost:8080/gith0001", Conflict: true},
+				{CorrelationID: "0002", ShortURL: "http://localhost:8080/abcd0002"},
+			},
+		},
+		{
+			name: "Duplicate alias in atomic batch",
+			body: strings.NewReader(`[
+				{"correlation_id": "0001", "original_url": "https://github.com", "alias": "github"},
+				{"correlation_id": "0002", "original_url": "https://google.com", "alias": "github"}
+			]`),
+			atomic: true,
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "github").Return(nil, false)
+			},
+			error: errors.ErrAliasAlreadyExists,
+		},
+		{
+			name: "Duplicate and taken aliases in batch",
+			body: strings.NewReader(`[
+				{"correlation_id": "0001", "original_url": "https://github.com", "alias": "github"},
+				{"correlation_id": "0002", "original_url": "https://google.com", "alias": "github"},
+				{"correlation_id": "0003", "original_url": "https://example.com", "alias": "example"}
+			]`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().UUID().Return(UUID2, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "github").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "example").Return(&repository.URL{ShortCode: "example"}, true)
+
+				urls := []repository.URL{githubAlias}
+				repo.EXPECT().CreateURLs(gomock.Any(), urls).Return(urls, nil)
+			},
+			expected: []dto.BatchCreateShortLinkResponse{
+				{CorrelationID: "0001", ShortURL: "http://localhost:8080/github"},
+				{CorrelationID: "0002", Error: errors.ErrAliasAlreadyExists.Error()},
+				{CorrelationID: "0003", Error: errors.ErrAliasAlreadyExists.Error()},
+			},
+		},
+		{
+			name: "Alias taken while storing atomic batch",
+			body: strings.NewReader(`[
+				{"correlation_id": "0001", "original_url": "https://github.com", "alias": "github"}
+			]`),
+			atomic: true,
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "github").Return(nil, false)
+				repo.EXPECT().CreateURLs(gomock.Any(), []repository.URL{githubAlias}).Return(nil, errors.ErrShortCodeAlreadyExists)
+			},
+			error: errors.ErrAliasAlreadyExists,
+		},
+		{
+			name: "Alias taken while storing batch",
+			body: strings.NewReader(`[
+				{"correlation_id": "0001", "original_url": "https://github.com", "alias": "github"},
+				{"correlation_id": "0002", "original_url": "https://google.com"}
+			]`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().UUID().Return(UUID2, nil)
+				rand.EXPECT().Hex().Return("abcd0002", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "github").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0002").Return(nil, false)
+
+				repo.EXPECT().CreateURLs(gomock.Any(), []repository.URL{githubAlias, google}).Return(nil, errors.ErrShortCodeAlreadyExists)
+				repo.EXPECT().CreateURL(gomock.Any(), githubAlias).Return(nil, errors.ErrShortCodeAlreadyExists)
+				repo.EXPECT().CreateURL(gomock.Any(), google).Return(&google, nil)
+			},
+			expected: []dto.BatchCreateShortLinkResponse{
+				{CorrelationID: "0001", Error: errors.ErrAliasAlreadyExists.Error()},
+				{CorrelationID: "0002", ShortURL: "http://localhost:8080/abcd0002"},
+			},
+		},
+		{
+			name: "Storage error",
+			body: strings.NewReader(`[{"correlation_id": "0001", "original_url": "https://github.com"}]`),
+			before: func() {
+				rand.EXPECT().UUID().Return(UUID1, nil)
+				rand.EXPECT().Hex().Return("abcd0001", nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0001").Return(nil, false)
+				repo.EXPECT().CreateURLs(gomock.Any(), []repository.URL{github}).Return(nil, errors.ErrFailedToSaveURL)
+			},
+			error: errors.ErrFailedToSaveURL,
+		},
 		{
 			name: "Error generating UUID",
 			body: strings.NewReader(`[{"correlation_id": "0001", "original_url": "https://github.com"}]`),
 			before: func() {
 				rand.EXPECT().UUID().Return(uuid.UUID{}, errors.ErrFailedToGenerateUUID)
 			},
-			expected: []result{
-				{
-					shortCode: "",
-					shortURL:  "",
-					error:     errors.ErrFailedToGenerateUUID,
-				},
-			},
+			error: errors.ErrFailedToGenerateUUID,
 		},
 		{
 			name: "Error generating short code",
@@ -235,13 +481,7 @@ func TestURLService_CreateShortLinks(t *testing.T) {
 				rand.EXPECT().UUID().Return(UUID1, nil)
 				rand.EXPECT().Hex().Return("", errors.ErrFailedToReadRandomBytes)
 			},
-			expected: []result{
-				{
-					shortCode: "",
-					shortURL:  "",
-					error:     errors.ErrFailedToGenerateCode,
-				},
-			},
+			error: errors.ErrFailedToGenerateCode,
 		},
 	}
 
@@ -254,12 +494,10 @@ func TestURLService_CreateShortLinks(t *testing.T) {
 			err := json.NewDecoder(r.Body).Decode(&req)
 			assert.NoError(t, err)
 
-			shortURLs, err := service.CreateShortLinks(ctx, req)
+			shortURLs, err := service.CreateShortLinks(ctx, req, tt.atomic)
 
-			for i, shortURL := range shortURLs {
-				assert.Equal(t, tt.expected[i].shortURL, shortURL.ShortURL)
-				assert.Equal(t, tt.expected[i].error, err)
-			}
+			assert.Equal(t, tt.error, err)
+			assert.Equal(t, tt.expected, shortURLs)
 		})
 	}
 }
@@ -278,7 +516,7 @@ func Test_GetShortLink(t *testing.T) {
 	repo := repository.NewMockRepository(ctrl)
 	rand := NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	service := NewURLService(cfg, repo, rand, appWorker)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
 
 	type result struct {
 		url   *repository.URL
@@ -313,7 +551,7 @@ func Test_GetShortLink(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			repo.EXPECT().GetURLByShortCode(ctx, tt.shortCode).Return(tt.expected.url, tt.expected.found)
+			repo.EXPECT().GetURLByShortCode(gomock.Any(), tt.shortCode).Return(tt.expected.url, tt.expected.found)
 
 			url, found := service.GetShortLink(ctx, tt.shortCode)
 
@@ -336,35 +574,35 @@ func Test_GetUserURLs(t *testing.T) {
 	repo := repository.NewMockRepository(ctrl)
 	rand := NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	service := NewURLService(cfg, repo, rand, appWorker)
-	paginator := pagination.Pagination{
-		Page: 1,
-		Per:  25,
-	}
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
 	limit := int64(25)
 	offset := int64(0)
 
 	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
 	UUID2, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
 	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	cursor := pagination.EncodeCursor(createdAt, UUID1)
 
 	type result struct {
 		urls  []dto.GetUserURLsResponse
-		total int
+		next  string
 		error error
 	}
 
 	tests := []struct {
-		name     string
-		ctx      context.Context
-		before   func(ctx context.Context)
-		expected result
+		name      string
+		ctx       context.Context
+		paginator pagination.Pagination
+		before    func(ctx context.Context)
+		expected  result
 	}{
 		{
-			name: "Success",
-			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			name:      "Success",
+			ctx:       context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			paginator: pagination.Pagination{Page: 1, Per: 25},
 			before: func(ctx context.Context) {
-				repo.EXPECT().GetURLsByUserID(ctx, UserUUID, limit, offset).Return([]repository.URL{
+				repo.EXPECT().GetURLsByUserID(gomock.Any(), UserUUID, limit, offset).Return([]repository.URL{
 					{
 						UUID:      UUID1,
 						LongURL:   "https://google.com",
@@ -388,41 +626,128 @@ func Test_GetUserURLs(t *testing.T) {
 						OriginalURL: "https://github.com",
 					},
 				},
-				total: 2,
 				error: nil,
 			},
 		},
 		{
-			name: "No URLs found",
-			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			name:      "No URLs found",
+			ctx:       context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			paginator: pagination.Pagination{Page: 1, Per: 25},
 			before: func(ctx context.Context) {
-				repo.EXPECT().GetURLsByUserID(ctx, UserUUID, limit, offset).Return(nil, 0, nil)
+				repo.EXPECT().GetURLsByUserID(gomock.Any(), UserUUID, limit, offset).Return(nil, 0, nil)
 			},
 			expected: result{
 				urls:  []dto.GetUserURLsResponse{},
-				total: 0,
 				error: nil,
 			},
 		},
 		{
-			name: "Error loading user URLs",
-			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			name:      "Error loading user URLs",
+			ctx:       context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			paginator: pagination.Pagination{Page: 1, Per: 25},
 			before: func(ctx context.Context) {
-				repo.EXPECT().GetURLsByUserID(ctx, UserUUID, limit, offset).Return(nil, 0, errors.ErrFailedToLoadUserUrls)
+				repo.EXPECT().GetURLsByUserID(gomock.Any(), UserUUID, limit, offset).Return(nil, 0, errors.ErrFailedToLoadUserUrls)
 			},
 			expected: result{
 				urls:  nil,
-				total: 0,
 				error: errors.ErrFailedToLoadUserUrls,
 			},
 		},
 		{
-			name:   "Error invalid user ID",
-			ctx:    context.Background(),
-			before: func(_ context.Context) {},
+			name:      "More URLs by page",
+			ctx:       context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			paginator: pagination.Pagination{Page: 1, Per: 1},
+			before: func(ctx context.Context) {
+				repo.EXPECT().GetURLsByUserID(gomock.Any(), UserUUID, int64(1), int64(0)).Return([]repository.URL{
+					{
+						UUID:      UUID1,
+						LongURL:   "https://google.com",
+						ShortCode: "abcd0001",
+						CreatedAt: createdAt,
+					},
+				}, 2, nil)
+			},
+			expected: result{
+				urls: []dto.GetUserURLsResponse{
+					{
+						ShortURL:    "http://localhost:8080/abcd0001",
+						OriginalURL: "https://google.com",
+					},
+				},
+				next: cursor,
+			},
+		},
+		{
+			name:      "More URLs by cursor",
+			ctx:       context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			paginator: pagination.Pagination{Page: 1, Per: 1, Cursor: pagination.EncodeCursor(createdAt.Add(time.Hour), UUID2)},
+			before: func(ctx context.Context) {
+				after := repository.URLCursor{CreatedAt: createdAt.Add(time.Hour), UUID: UUID2}
+				repo.EXPECT().GetURLsByUserIDAfter(gomock.Any(), UserUUID, after, int64(2)).Return([]repository.URL{
+					{
+						UUID:      UUID1,
+						LongURL:   "https://google.com",
+						ShortCode: "abcd0001",
+						CreatedAt: createdAt,
+					},
+					{
+						UUID:      UUID2,
+						LongURL:   "https://github.com",
+						ShortCode: "abcd0002",
+						CreatedAt: createdAt.Add(-time.Hour),
+					},
+				}, nil)
+			},
+			expected: result{
+				urls: []dto.GetUserURLsResponse{
+					{
+						ShortURL:    "http://localhost:8080/abcd0001",
+						OriginalURL: "https://google.com",
+					},
+				},
+				next: cursor,
+			},
+		},
+		{
+			name:      "Last page by cursor",
+			ctx:       context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			paginator: pagination.Pagination{Page: 1, Per: 25, Cursor: cursor},
+			before: func(ctx context.Context) {
+				after := repository.URLCursor{CreatedAt: createdAt, UUID: UUID1}
+				repo.EXPECT().GetURLsByUserIDAfter(gomock.Any(), UserUUID, after, int64(26)).Return([]repository.URL{
+					{
+						UUID:      UUID2,
+						LongURL:   "https://github.com",
+						ShortCode: "abcd0002",
+						CreatedAt: createdAt.Add(-time.Hour),
+					},
+				}, nil)
+			},
+			expected: result{
+				urls: []dto.GetUserURLsResponse{
+					{
+						ShortURL:    "http://localhost:8080/abcd0002",
+						OriginalURL: "https://github.com",
+					},
+				},
+			},
+		},
+		{
+			name:      "Invalid cursor",
+			ctx:       context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			paginator: pagination.Pagination{Page: 1, Per: 25, Cursor: "not a cursor"},
+			before:    func(_ context.Context) {},
+			expected: result{
+				error: errors.ErrInvalidCursor,
+			},
+		},
+		{
+			name:      "Error invalid user ID",
+			ctx:       context.Background(),
+			paginator: pagination.Pagination{Page: 1, Per: 25},
+			before:    func(_ context.Context) {},
 			expected: result{
 				urls:  nil,
-				total: 0,
 				error: errors.ErrInvalidUserID,
 			},
 		},
@@ -432,10 +757,232 @@ func Test_GetUserURLs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.before(tt.ctx)
 
-			urls, total, err := service.GetUserURLs(tt.ctx, &paginator)
+			urls, next, err := service.GetUserURLs(tt.ctx, &tt.paginator)
+
+			assert.Equal(t, tt.expected.urls, urls)
+			assert.Equal(t, tt.expected.next, next)
+			assert.Equal(t, tt.expected.error, err)
+		})
+	}
+}
+
+func Test_FindUserURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		Addr:      "localhost:8080",
+		BaseURL:   "http://localhost:8080",
+		ClientURL: "http://localhost:8080",
+	}
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
+	paginator := pagination.Pagination{
+		Page: 2,
+		Per:  10,
+	}
+
+	UUID1, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	deletedAt := createdAt.Add(time.Hour)
+
+	params := dto.ListUserURLsRequest{
+		Query:        "google",
+		Sort:         dto.SortClicksDesc,
+		Deleted:      true,
+		CreatedAfter: createdAt,
+	}
+	filter := repository.URLFilter{
+		UserUUID:     UserUUID,
+		Query:        "google",
+		Sort:         repository.SortClicksDesc,
+		Deleted:      true,
+		CreatedAfter: createdAt,
+		Limit:        10,
+		Offset:       10,
+	}
+
+	type result struct {
+		urls  []dto.UserURLResponse
+		total int
+		error error
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		before   func(ctx context.Context)
+		expected result
+	}{
+		{
+			name: "Success",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			before: func(ctx context.Context) {
+				repo.EXPECT().FindURLsByUserID(gomock.Any(), filter).Return([]repository.URLListItem{
+					{
+						URL: repository.URL{
+							UUID:      UUID1,
+							LongURL:   "https://google.com",
+							ShortCode: "abcd0001",
+							CreatedAt: createdAt,
+							DeletedAt: deletedAt,
+						},
+						Clicks: 3,
+					},
+				}, 11, nil)
+			},
+			expected: result{
+				urls: []dto.UserURLResponse{
+					{
+						ShortURL:    "http://localhost:8080/abcd0001",
+						ShortCode:   "abcd0001",
+						OriginalURL: "https://google.com",
+						Clicks:      3,
+						CreatedAt:   createdAt,
+						DeletedAt:   &deletedAt,
+					},
+				},
+				total: 11,
+			},
+		},
+		{
+			name: "Error",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			before: func(ctx context.Context) {
+				repo.EXPECT().FindURLsByUserID(gomock.Any(), filter).Return(nil, 0, errors.ErrFailedToLoadUserUrls)
+			},
+			expected: result{
+				error: errors.ErrFailedToLoadUserUrls,
+			},
+		},
+		{
+			name:   "Invalid user",
+			ctx:    context.Background(),
+			before: func(ctx context.Context) {},
+			expected: result{
+				error: errors.ErrInvalidUserID,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before(tt.ctx)
+
+			urls, total, err := service.FindUserURLs(tt.ctx, &paginator, params)
 
+			assert.Equal(t, tt.expected.error, err)
 			assert.Equal(t, tt.expected.urls, urls)
 			assert.Equal(t, tt.expected.total, total)
+		})
+	}
+}
+
+func Test_UpdateUserURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		Addr:      "localhost:8080",
+		BaseURL:   "http://localhost:8080",
+		ClientURL: "http://localhost:8080",
+	}
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
+
+	UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+
+	params := dto.UpdateShortLinkRequest{URL: "https://example.com"}
+	url := repository.URL{
+		LongURL:   "https://example.com",
+		ShortCode: "abcd0001",
+		UserUUID:  UserUUID,
+	}
+
+	type result struct {
+		response *dto.GetUserURLsResponse
+		error    error
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		before   func(ctx context.Context)
+		expected result
+	}{
+		{
+			name: "Success",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			before: func(ctx context.Context) {
+				repo.EXPECT().UpdateURL(gomock.Any(), url).Return(&repository.URL{
+					UUID:      UUID,
+					LongURL:   "https://example.com",
+					ShortCode: "abcd0001",
+					UserUUID:  UserUUID,
+				}, nil)
+			},
+			expected: result{
+				response: &dto.GetUserURLsResponse{
+					ShortURL:    "http://localhost:8080/abcd0001",
+					OriginalURL: "https://example.com",
+				},
+			},
+		},
+		{
+			name: "Error not found",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			before: func(ctx context.Context) {
+				repo.EXPECT().UpdateURL(gomock.Any(), url).Return(nil, errors.ErrShortLinkNotFound)
+			},
+			expected: result{
+				error: errors.ErrShortLinkNotFound,
+			},
+		},
+		{
+			name: "Error URL already exists",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			before: func(ctx context.Context) {
+				repo.EXPECT().UpdateURL(gomock.Any(), url).Return(nil, errors.ErrURLAlreadyExists)
+			},
+			expected: result{
+				error: errors.ErrURLAlreadyExists,
+			},
+		},
+		{
+			name: "Error updating URL",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			before: func(ctx context.Context) {
+				repo.EXPECT().UpdateURL(gomock.Any(), url).Return(nil, assert.AnError)
+			},
+			expected: result{
+				error: errors.ErrFailedToUpdateURL,
+			},
+		},
+		{
+			name:   "Error invalid user ID",
+			ctx:    context.Background(),
+			before: func(_ context.Context) {},
+			expected: result{
+				error: errors.ErrInvalidUserID,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before(tt.ctx)
+
+			response, err := service.UpdateUserURL(tt.ctx, "abcd0001", params)
+
+			assert.Equal(t, tt.expected.response, response)
 			assert.Equal(t, tt.expected.error, err)
 		})
 	}
@@ -454,35 +1001,227 @@ func Test_DeleteUserURLs(t *testing.T) {
 	repo := repository.NewMockRepository(ctrl)
 	rand := NewMockSecureRandomGenerator(ctrl)
 	appWorker := worker.NewMockWorker(ctrl)
-	service := NewURLService(cfg, repo, rand, appWorker)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
 
 	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	JobUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name     string
 		ctx      context.Context
 		before   func()
 		params   dto.BatchDeleteShortLinkRequest
-		expected error
+		expected *dto.DeleteJobResponse
+		error    error
 	}{
 		{
 			name: "Success",
 			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
 			before: func() {
-				appWorker.EXPECT().Add(dto.BatchDeleteParams{
+				appWorker.EXPECT().Add(gomock.Any(), dto.BatchDeleteParams{
 					UserID:     UserUUID,
 					ShortCodes: []string{"abcd0001", "abcd0002"},
-				})
+				}).Return(worker.DeleteJob{
+					ID:         JobUUID,
+					UserID:     UserUUID,
+					Status:     dto.JobStatusQueued,
+					ShortCodes: []string{"abcd0001", "abcd0002"},
+					CreatedAt:  createdAt,
+				}, nil)
 			},
-			params:   []string{"abcd0001", "abcd0002"},
-			expected: nil,
+			params: []string{"abcd0001", "abcd0002"},
+			expected: &dto.DeleteJobResponse{
+				ID:        JobUUID,
+				Status:    dto.JobStatusQueued,
+				Requested: 2,
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "Error queueing",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, UserUUID),
+			before: func() {
+				appWorker.EXPECT().Add(gomock.Any(), dto.BatchDeleteParams{
+					UserID:     UserUUID,
+					ShortCodes: []string{"abcd0001"},
+				}).Return(worker.DeleteJob{}, errors.ErrFailedToDeleteURLs)
+			},
+			params: []string{"abcd0001"},
+			error:  errors.ErrFailedToDeleteURLs,
 		},
 		{
-			name:     "Error invalid user ID",
-			ctx:      context.WithValue(context.Background(), dto.CurrentUser, nil),
-			before:   func() {},
+			name:   "Error invalid user ID",
+			ctx:    context.WithValue(context.Background(), dto.CurrentUser, nil),
+			before: func() {},
+			params: []string{"abcd0001", "abcd0002"},
+			error:  errors.ErrInvalidUserID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			job, err := service.DeleteUserURLs(tt.ctx, tt.params)
+			assert.Equal(t, tt.error, err)
+			assert.Equal(t, tt.expected, job)
+		})
+	}
+}
+
+func Test_GetDeleteJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL: "http://localhost:8080",
+	}
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	JobUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	finishedAt := createdAt.Add(time.Second)
+
+	skipped := []dto.SkippedShortCode{
+		{ShortCode: "abcd0002", Reason: dto.SkipReasonNotOwned},
+		{ShortCode: "abcd0003", Reason: dto.SkipReasonNotFound},
+		{ShortCode: "abcd0004", Reason: dto.SkipReasonAlreadyDeleted},
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		before   func()
+		expected *dto.DeleteJobResponse
+		error    error
+	}{
+		{
+			name: "Done",
+			id:   JobUUID.String(),
+			before: func() {
+				appWorker.EXPECT().Job(gomock.Any(), UserUUID, JobUUID).Return(worker.DeleteJob{
+					ID:         JobUUID,
+					UserID:     UserUUID,
+					Status:     dto.JobStatusDone,
+					ShortCodes: []string{"abcd0001", "abcd0002", "abcd0003", "abcd0004"},
+					Deleted:    []string{"abcd0001"},
+					Skipped:    skipped,
+					CreatedAt:  createdAt,
+					FinishedAt: finishedAt,
+				}, true)
+			},
+			expected: &dto.DeleteJobResponse{
+				ID:             JobUUID,
+				Status:         dto.JobStatusDone,
+				Requested:      4,
+				Deleted:        1,
+				NotOwned:       1,
+				NotFound:       1,
+				AlreadyDeleted: 1,
+				Skipped:        skipped,
+				CreatedAt:      createdAt,
+				FinishedAt:     &finishedAt,
+			},
+		},
+		{
+			name: "Not found",
+			id:   JobUUID.String(),
+			before: func() {
+				appWorker.EXPECT().Job(gomock.Any(), UserUUID, JobUUID).Return(worker.DeleteJob{}, false)
+			},
+			error: errors.ErrDeleteJobNotFound,
+		},
+		{
+			name:   "Invalid ID",
+			id:     "not-a-uuid",
+			before: func() {},
+			error:  errors.ErrDeleteJobNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			job, err := service.GetDeleteJob(ctx, tt.id)
+			assert.Equal(t, tt.error, err)
+			assert.Equal(t, tt.expected, job)
+		})
+	}
+}
+
+func Test_RestoreUserURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		BaseURL:            "http://localhost:8080",
+		RestoreGracePeriod: time.Hour,
+	}
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+	appWorker := worker.NewMockWorker(ctrl)
+	service := NewURLService(cfg, repo, rand, NewHexGenerator(rand), appWorker)
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, UserUUID)
+
+	// NOTE: only records deleted within the grace period are restored
+	deletedSince := gomock.Cond(func(since time.Time) bool {
+		return time.Since(since) >= time.Hour && time.Since(since) < time.Hour+time.Minute
+	})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		before   func()
+		params   dto.BatchRestoreShortLinkRequest
+		expected *dto.BatchRestoreShortLinkResponse
+		error    error
+	}{
+		{
+			name: "Success",
+			ctx:  ctx,
+			before: func() {
+				repo.EXPECT().RestoreURLsByUserID(gomock.Any(), UserUUID, []string{"abcd0001", "abcd0002"}, deletedSince).
+					Return([]string{"abcd0001"}, nil)
+			},
 			params:   []string{"abcd0001", "abcd0002"},
-			expected: errors.ErrInvalidUserID,
+			expected: &dto.BatchRestoreShortLinkResponse{Restored: []string{"abcd0001"}},
+		},
+		{
+			name: "Nothing restored",
+			ctx:  ctx,
+			before: func() {
+				repo.EXPECT().RestoreURLsByUserID(gomock.Any(), UserUUID, []string{"abcd0002"}, deletedSince).Return(nil, nil)
+			},
+			params:   []string{"abcd0002"},
+			expected: &dto.BatchRestoreShortLinkResponse{Restored: []string{}},
+		},
+		{
+			name: "Storage error",
+			ctx:  ctx,
+			before: func() {
+				repo.EXPECT().RestoreURLsByUserID(gomock.Any(), UserUUID, []string{"abcd0001"}, deletedSince).
+					Return(nil, errors.ErrFailedToRestoreURLs)
+			},
+			params: []string{"abcd0001"},
+			error:  errors.ErrFailedToRestoreURLs,
+		},
+		{
+			name:   "Error invalid user ID",
+			ctx:    context.WithValue(context.Background(), dto.CurrentUser, nil),
+			before: func() {},
+			params: []string{"abcd0001"},
+			error:  errors.ErrInvalidUserID,
 		},
 	}
 
@@ -490,8 +1229,9 @@ func Test_DeleteUserURLs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.before()
 
-			err := service.DeleteUserURLs(tt.ctx, tt.params)
-			assert.Equal(t, tt.expected, err)
+			result, err := service.RestoreUserURLs(tt.ctx, tt.params)
+			assert.Equal(t, tt.error, err)
+			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+)
+
+// APITokenPrefix marks API tokens, so leaked tokens are recognizable by secret scanners
+const APITokenPrefix = "shk_"
+
+// APITokenBytesLength is the number of random bytes of an API token secret
+const APITokenBytesLength = 32
+
+// APITokenDisplayLength is the number of leading token characters kept to tell tokens apart
+const APITokenDisplayLength = len(APITokenPrefix) + 8
+
+// TokenResolver is an interface for resolving API tokens presented by clients
+type TokenResolver interface {
+	Resolve(ctx context.Context, token string) (*repository.APIToken, error)
+}
+
+// TokenService is a service for API token operations
+type TokenService struct {
+	repo repository.Repository
+	rand SecureRandomGenerator
+}
+
+// NewTokenService creates a new API token service instance
+func NewTokenService(repo repository.Repository, rand SecureRandomGenerator) *TokenService {
+	return &TokenService{repo: repo, rand: rand}
+}
+
+// Create issues a new API token for the current user, the secret is returned only once
+func (s *TokenService) Create(ctx context.Context, params dto.CreateAPITokenRequest) (*dto.CreateAPITokenResponse, error) {
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	id, err := s.rand.UUID()
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, APITokenBytesLength)
+	if _, err = rand.Read(secret); err != nil {
+		return nil, errors.ErrFailedToReadRandomBytes
+	}
+
+	token := APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	created, err := s.repo.CreateAPIToken(ctx, repository.APIToken{
+		UUID:     id,
+		UserUUID: currentUserID,
+		Name:     params.Name,
+		Prefix:   token[:APITokenDisplayLength],
+		Hash:     HashAPIToken(token),
+		Scopes:   params.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CreateAPITokenResponse{
+		APITokenResponse: toAPITokenResponse(created),
+		Token:            token,
+	}, nil
+}
+
+// List returns active API tokens of the current user
+func (s *TokenService) List(ctx context.Context) ([]dto.APITokenResponse, error) {
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	tokens, err := s.repo.GetAPITokensByUserID(ctx, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.APITokenResponse, 0, len(tokens))
+	for i := range tokens {
+		results = append(results, toAPITokenResponse(&tokens[i]))
+	}
+
+	return results, nil
+}
+
+// Revoke revokes an API token of the current user
+func (s *TokenService) Revoke(ctx context.Context, id uuid.UUID) error {
+	currentUserID, ok := (ctx.Value(dto.CurrentUser)).(uuid.UUID)
+	if !ok {
+		return errors.ErrInvalidUserID
+	}
+
+	return s.repo.RevokeAPIToken(ctx, currentUserID, id)
+}
+
+// Resolve returns the active API token matching the presented secret
+func (s *TokenService) Resolve(ctx context.Context, token string) (*repository.APIToken, error) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return nil, errors.ErrInvalidToken
+	}
+
+	result, ok := s.repo.GetAPITokenByHash(ctx, HashAPIToken(token))
+	if !ok {
+		return nil, errors.ErrInvalidToken
+	}
+
+	return result, nil
+}
+
+// HashAPIToken returns the hash an API token is stored by, the secret is random enough to make a salt unnecessary
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// toAPITokenResponse converts an API token into its description
+func toAPITokenResponse(token *repository.APIToken) dto.APITokenResponse {
+	return dto.APITokenResponse{
+		UUID:      token.UUID.String(),
+		Name:      token.Name,
+		Prefix:    token.Prefix,
+		Scopes:    token.Scopes,
+		CreatedAt: token.CreatedAt,
+	}
+}
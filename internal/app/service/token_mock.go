@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/service/token.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/service/token.go -destination=internal/app/service/token_mock.go -package=service
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+	repository "shortly/internal/app/repository"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTokenResolver is a mock of TokenResolver interface.
+type MockTokenResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenResolverMockRecorder is the mock recorder for MockTokenResolver.
+type MockTokenResolverMockRecorder struct {
+	mock *MockTokenResolver
+}
+
+// NewMockTokenResolver creates a new mock instance.
+func NewMockTokenResolver(ctrl *gomock.Controller) *MockTokenResolver {
+	mock := &MockTokenResolver{ctrl: ctrl}
+	mock.recorder = &MockTokenResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenResolver) EXPECT() *MockTokenResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockTokenResolver) Resolve(ctx context.Context, token string) (*repository.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, token)
+	ret0, _ := ret[0].(*repository.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockTokenResolverMockRecorder) Resolve(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockTokenResolver)(nil).Resolve), ctx, token)
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+)
+
+func Test_TokenService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+	service := NewTokenService(repo, rand)
+
+	userID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	tokenID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	params := dto.CreateAPITokenRequest{Name: "ci", Scopes: []string{dto.ScopeLinksWrite}}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		prepare func()
+		wantErr error
+	}{
+		{
+			name:    "Missing user",
+			ctx:     context.Background(),
+			prepare: func() {},
+			wantErr: errors.ErrInvalidUserID,
+		},
+		{
+			name: "UUID generation failure",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, userID),
+			prepare: func() {
+				rand.EXPECT().UUID().Return(uuid.Nil, errors.ErrFailedToGenerateUUID)
+			},
+			wantErr: errors.ErrFailedToGenerateUUID,
+		},
+		{
+			name: "Repository failure",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, userID),
+			prepare: func() {
+				rand.EXPECT().UUID().Return(tokenID, nil)
+				repo.EXPECT().CreateAPIToken(gomock.Any(), gomock.Any()).Return(nil, errors.ErrFailedToWriteToFile)
+			},
+			wantErr: errors.ErrFailedToWriteToFile,
+		},
+		{
+			name: "Success",
+			ctx:  context.WithValue(context.Background(), dto.CurrentUser, userID),
+			prepare: func() {
+				rand.EXPECT().UUID().Return(tokenID, nil)
+				repo.EXPECT().CreateAPIToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, token repository.APIToken) (*repository.APIToken, error) {
+						token.CreatedAt = createdAt
+						return &token, nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			result, err := service.Create(tt.ctx, params)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tokenID.String(), result.UUID)
+			assert.Equal(t, "ci", result.Name)
+			assert.Equal(t, []string{dto.ScopeLinksWrite}, result.Scopes)
+			assert.Equal(t, createdAt, result.CreatedAt)
+			assert.True(t, strings.HasPrefix(result.Token, APITokenPrefix))
+			assert.Equal(t, result.Token[:APITokenDisplayLength], result.Prefix)
+		})
+	}
+}
+
+func Test_TokenService_Create_StoresHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	rand := NewMockSecureRandomGenerator(ctrl)
+	service := NewTokenService(repo, rand)
+
+	userID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, userID)
+
+	var stored repository.APIToken
+	rand.EXPECT().UUID().Return(uuid.New(), nil)
+	repo.EXPECT().CreateAPIToken(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, token repository.APIToken) (*repository.APIToken, error) {
+			stored = token
+			return &token, nil
+		})
+
+	result, err := service.Create(ctx, dto.CreateAPITokenRequest{Name: "ci", Scopes: dto.Scopes})
+	assert.NoError(t, err)
+
+	assert.Equal(t, userID, stored.UserUUID)
+	assert.Equal(t, HashAPIToken(result.Token), stored.Hash)
+	assert.NotContains(t, stored.Hash, result.Token)
+}
+
+func Test_TokenService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	service := NewTokenService(repo, NewMockSecureRandomGenerator(ctrl))
+
+	userID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	tokenID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, userID)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		prepare  func()
+		expected []dto.APITokenResponse
+		wantErr  error
+	}{
+		{
+			name:    "Missing user",
+			ctx:     context.Background(),
+			prepare: func() {},
+			wantErr: errors.ErrInvalidUserID,
+		},
+		{
+			name: "Repository failure",
+			ctx:  ctx,
+			prepare: func() {
+				repo.EXPECT().GetAPITokensByUserID(gomock.Any(), userID).Return(nil, errors.ErrFailedToOpenFile)
+			},
+			wantErr: errors.ErrFailedToOpenFile,
+		},
+		{
+			name: "Success",
+			ctx:  ctx,
+			prepare: func() {
+				repo.EXPECT().GetAPITokensByUserID(gomock.Any(), userID).Return([]repository.APIToken{
+					{UUID: tokenID, Name: "ci", Prefix: "shk_abcdefgh", Hash: "secret", Scopes: dto.Scopes},
+				}, nil)
+			},
+			expected: []dto.APITokenResponse{
+				{UUID: tokenID.String(), Name: "ci", Prefix: "shk_abcdefgh", Scopes: dto.Scopes},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			result, err := service.List(tt.ctx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_TokenService_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	service := NewTokenService(repo, NewMockSecureRandomGenerator(ctrl))
+
+	userID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	tokenID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+
+	err := service.Revoke(context.Background(), tokenID)
+	assert.ErrorIs(t, err, errors.ErrInvalidUserID)
+
+	ctx := context.WithValue(context.Background(), dto.CurrentUser, userID)
+
+	repo.EXPECT().RevokeAPIToken(gomock.Any(), userID, tokenID).Return(errors.ErrAPITokenNotFound)
+	assert.ErrorIs(t, service.Revoke(ctx, tokenID), errors.ErrAPITokenNotFound)
+
+	repo.EXPECT().RevokeAPIToken(gomock.Any(), userID, tokenID).Return(nil)
+	assert.NoError(t, service.Revoke(ctx, tokenID))
+}
+
+func Test_TokenService_Resolve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	service := NewTokenService(repo, NewMockSecureRandomGenerator(ctrl))
+
+	userID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	token := &repository.APIToken{UserUUID: userID, Scopes: dto.Scopes}
+
+	tests := []struct {
+		name     string
+		token    string
+		prepare  func()
+		expected *repository.APIToken
+		wantErr  error
+	}{
+		{
+			name:    "Foreign token",
+			token:   "eyJhbGciOiJIUzI1NiJ9",
+			prepare: func() {},
+			wantErr: errors.ErrInvalidToken,
+		},
+		{
+			name:  "Unknown or revoked token",
+			token: "shk_unknown",
+			prepare: func() {
+				repo.EXPECT().GetAPITokenByHash(gomock.Any(), HashAPIToken("shk_unknown")).Return(nil, false)
+			},
+			wantErr: errors.ErrInvalidToken,
+		},
+		{
+			name:  "Active token",
+			token: "shk_active",
+			prepare: func() {
+				repo.EXPECT().GetAPITokenByHash(gomock.Any(), HashAPIToken("shk_active")).Return(token, true)
+			},
+			expected: token,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			result, err := service.Resolve(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_HashAPIToken(t *testing.T) {
+	assert.Len(t, HashAPIToken("shk_token"), 64)
+	assert.Equal(t, HashAPIToken("shk_token"), HashAPIToken("shk_token"))
+	assert.NotEqual(t, HashAPIToken("shk_token"), HashAPIToken("shk_other"))
+}
@@ -0,0 +1,168 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/version"
+	"shortly/internal/logger"
+)
+
+// TracerName is the instrumentation name of the application spans
+const TracerName = "shortly"
+
+// ServiceName is the service name reported with the spans
+const ServiceName = "shortly"
+
+// TraceParentHeader is the W3C trace context header
+const TraceParentHeader = "traceparent"
+
+// Provider is an interface for the tracer provider
+type Provider interface {
+	// Shutdown flushes the pending spans and stops the exporter
+	Shutdown(ctx context.Context) error
+}
+
+type provider struct {
+	tp     *sdktrace.TracerProvider
+	closer io.Closer
+}
+
+// Setup creates the tracer provider and installs it together with the W3C trace context propagator,
+// spans are exported via OTLP when an endpoint is configured and to the trace file or stdout otherwise,
+// unless the export is disabled
+func Setup(ctx context.Context, cfg *config.Config, logger *logger.Logger) (Provider, error) {
+	p := &provider{}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch {
+	case cfg.TracingDisabled:
+		// NOTE: spans are still created, so trace IDs are logged and propagated to the delete tasks
+		logger.Info().Msg("Tracing export is disabled")
+	case cfg.OTLPEndpoint != "":
+		exporter, err = otlptracehttp.New(ctx, otlpOption(cfg.OTLPEndpoint))
+		logger.Info().Msgf("Tracing exports spans via OTLP to %s", cfg.OTLPEndpoint)
+	case cfg.TraceFile != "":
+		var file *os.File
+		file, err = os.OpenFile(cfg.TraceFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		p.closer = file
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		logger.Info().Msgf("Tracing exports spans to %s", cfg.TraceFile)
+	default:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		logger.Info().Msg("Tracing exports spans to stdout, set OTLP_ENDPOINT or TRACE_FILE to export them elsewhere")
+	}
+	if err != nil {
+		if p.closer != nil {
+			p.closer.Close()
+		}
+		return nil, err
+	}
+
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(version.NewVersion().Version()),
+		semconv.DeploymentEnvironment(cfg.AppEnv),
+	)
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		// NOTE: the sampling decision of the caller is honoured, so a sampled trace is never broken up
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TraceSampleRatio))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	p.tp = sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(p.tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return p, nil
+}
+
+// Shutdown flushes the pending spans and closes the trace file
+func (p *provider) Shutdown(ctx context.Context) error {
+	err := p.tp.Shutdown(ctx)
+
+	if p.closer != nil {
+		if closeErr := p.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// Start starts a span of the application tracer
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, opts...)
+}
+
+// RecordError records the error on the span and marks the span as failed, a nil error is ignored
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// End records the error on the span and ends the span, meant to be deferred with the named error result
+func End(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
+
+// TraceParent returns the W3C traceparent of the span in the context, empty without a span
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	return carrier.Get(TraceParentHeader)
+}
+
+// SpanContext parses the W3C traceparent, the span context is invalid for an empty or malformed value
+func SpanContext(traceParent string) trace.SpanContext {
+	if traceParent == "" {
+		return trace.SpanContext{}
+	}
+
+	carrier := propagation.MapCarrier{TraceParentHeader: traceParent}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+
+	return trace.SpanContextFromContext(ctx)
+}
+
+// otlpOption returns the exporter endpoint option, the endpoint is either a URL or a host and port
+func otlpOption(endpoint string) otlptracehttp.Option {
+	if strings.Contains(endpoint, "://") {
+		return otlptracehttp.WithEndpointURL(endpoint)
+	}
+
+	return otlptracehttp.WithEndpoint(endpoint)
+}
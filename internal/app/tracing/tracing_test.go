@@ -0,0 +1,187 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"shortly/internal/app/config"
+	"shortly/internal/logger"
+)
+
+func Test_Setup(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      func(dir string) *config.Config
+		expected bool
+		error    bool
+	}{
+		{
+			name: "Trace file",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{TraceFile: filepath.Join(dir, "traces.json"), TraceSampleRatio: 1}
+			},
+			expected: true,
+		},
+		{
+			name: "Not sampled",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{TraceFile: filepath.Join(dir, "traces.json"), TraceSampleRatio: 0}
+			},
+			expected: false,
+		},
+		{
+			name: "Invalid trace file",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{TraceFile: filepath.Join(dir, "missing", "traces.json")}
+			},
+			error: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg(t.TempDir())
+
+			p, err := Setup(context.Background(), cfg, logger.NewLogger())
+			if tt.error {
+				assert.Error(t, err)
+				assert.Nil(t, p)
+				return
+			}
+			require.NoError(t, err)
+
+			_, span := Start(context.Background(), "test span")
+			span.End()
+
+			require.NoError(t, p.Shutdown(context.Background()))
+
+			data, err := os.ReadFile(cfg.TraceFile)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, len(data) > 0)
+			if tt.expected {
+				assert.Contains(t, string(data), "test span")
+			}
+		})
+	}
+}
+
+func Test_Setup_Stdout(t *testing.T) {
+	// NOTE: the exporter writes to the stdout set when it is created
+	stdout := os.Stdout
+	file, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	require.NoError(t, err)
+	os.Stdout = file
+	t.Cleanup(func() {
+		os.Stdout = stdout
+		file.Close()
+	})
+
+	p, err := Setup(context.Background(), &config.Config{TraceSampleRatio: 1}, logger.NewLogger())
+	require.NoError(t, err)
+
+	_, span := Start(context.Background(), "test span")
+	span.End()
+
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	data, err := os.ReadFile(file.Name())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "test span")
+}
+
+func Test_Setup_Disabled(t *testing.T) {
+	p, err := Setup(context.Background(), &config.Config{TracingDisabled: true, TraceSampleRatio: 1}, logger.NewLogger())
+	require.NoError(t, err)
+
+	_, span := Start(context.Background(), "test span")
+	span.End()
+
+	// NOTE: spans are not exported, but keep their trace IDs for the logs and the propagation
+	assert.True(t, span.SpanContext().IsValid())
+	assert.True(t, span.SpanContext().IsSampled())
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func Test_RecordError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{
+			name:     "Error",
+			err:      assert.AnError,
+			expected: codes.Error,
+		},
+		{
+			name:     "Nil error",
+			err:      nil,
+			expected: codes.Unset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			_, span := tp.Tracer(TracerName).Start(context.Background(), "test span")
+			RecordError(span, tt.err)
+			span.End()
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tt.expected, spans[0].Status().Code)
+		})
+	}
+}
+
+func Test_TraceParent(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer(TracerName).Start(context.Background(), "test span")
+	defer span.End()
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		valid bool
+	}{
+		{
+			name:  "With span",
+			ctx:   ctx,
+			valid: true,
+		},
+		{
+			name:  "Without span",
+			ctx:   context.Background(),
+			valid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceParent := TraceParent(tt.ctx)
+			sc := SpanContext(traceParent)
+
+			assert.Equal(t, tt.valid, traceParent != "")
+			assert.Equal(t, tt.valid, sc.IsValid())
+			if tt.valid {
+				assert.Equal(t, span.SpanContext().TraceID(), sc.TraceID())
+				assert.Equal(t, span.SpanContext().SpanID(), sc.SpanID())
+				assert.True(t, sc.IsRemote())
+			}
+		})
+	}
+}
+
+func Test_SpanContext_Malformed(t *testing.T) {
+	assert.False(t, SpanContext("00-invalid").IsValid())
+}
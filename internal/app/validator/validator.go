@@ -2,10 +2,29 @@ package validator
 
 import (
 	"net/url"
+	"regexp"
+	"strings"
 
 	"shortly/internal/app/errors"
 )
 
+// MinAliasLength is the minimum length of a custom alias
+const MinAliasLength = 3
+
+// MaxAliasLength is the maximum length of a custom alias
+const MaxAliasLength = 64
+
+// aliasPattern is a pattern of characters allowed in a custom alias
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// reservedAliases are the aliases which clash with application routes
+var reservedAliases = map[string]struct{}{
+	"api":   {},
+	"live":  {},
+	"ready": {},
+	"ping":  {},
+}
+
 // Validate checks if the URL is valid
 func Validate(rawURL string) error {
 	parsedURL, err := url.ParseRequestURI(rawURL)
@@ -16,3 +35,20 @@ func Validate(rawURL string) error {
 
 	return nil
 }
+
+// ValidateAlias checks if the custom alias is valid
+func ValidateAlias(alias string) error {
+	if len(alias) < MinAliasLength || len(alias) > MaxAliasLength {
+		return errors.ErrInvalidAlias
+	}
+
+	if !aliasPattern.MatchString(alias) {
+		return errors.ErrInvalidAlias
+	}
+
+	if _, ok := reservedAliases[strings.ToLower(alias)]; ok {
+		return errors.ErrAliasReserved
+	}
+
+	return nil
+}
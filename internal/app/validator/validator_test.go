@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -54,3 +55,54 @@ func Test_Validate(t *testing.T) {
 		})
 	}
 }
+
+func Test_ValidateAlias(t *testing.T) {
+	tests := []struct {
+		name     string
+		alias    string
+		expected error
+	}{
+		{
+			name:     "Valid alias",
+			alias:    "spring-sale",
+			expected: nil,
+		},
+		{
+			name:     "Valid alias with underscore and digits",
+			alias:    "Sale_2025",
+			expected: nil,
+		},
+		{
+			name:     "Too short",
+			alias:    "ab",
+			expected: errors.ErrInvalidAlias,
+		},
+		{
+			name:     "Too long",
+			alias:    strings.Repeat("a", MaxAliasLength+1),
+			expected: errors.ErrInvalidAlias,
+		},
+		{
+			name:     "Forbidden characters",
+			alias:    "spring/sale",
+			expected: errors.ErrInvalidAlias,
+		},
+		{
+			name:     "Non-ASCII characters",
+			alias:    "распродажа",
+			expected: errors.ErrInvalidAlias,
+		},
+		{
+			name:     "Reserved alias",
+			alias:    "Ping",
+			expected: errors.ErrAliasReserved,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAlias(tt.alias)
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/logger"
+)
+
+// ClickQueueSize is the size of the click events queue
+const ClickQueueSize = 1000
+
+// ClickBatchSize is the maximum number of click events stored at once
+const ClickBatchSize = 100
+
+// ClickFlushInterval is the interval between click events flushes
+const ClickFlushInterval = time.Second
+
+// ClickDrainTimeout is the time queued click events are stored for on shutdown
+const ClickDrainTimeout = 5 * time.Second
+
+// ClickQueue is the name of the click events queue in the scheduler
+const ClickQueue = "clicks"
+
+// ClickRecorder is an interface for the click events recorder
+type ClickRecorder interface {
+	Record(click repository.Click)
+}
+
+type clickRecorder struct {
+	ctx       context.Context
+	repo      repository.Repository
+	queue     chan repository.Click
+	scheduler Scheduler
+	logger    *logger.Logger
+}
+
+// NewClickRecorder creates a new click events recorder instance, queued events are flushed by the scheduler
+// every ClickFlushInterval or as soon as a batch is full, and drained when it stops
+func NewClickRecorder(ctx context.Context, cfg *config.Config, repo repository.Repository, scheduler Scheduler, logger *logger.Logger) ClickRecorder {
+	c := &clickRecorder{
+		ctx:       ctx,
+		repo:      repo,
+		queue:     make(chan repository.Click, ClickQueueSize),
+		scheduler: scheduler,
+		logger:    logger,
+	}
+
+	scheduler.Queue(ClickQueue, QueueOptions{
+		Workers:      1,
+		PollInterval: ClickFlushInterval,
+		DrainTimeout: ClickDrainTimeout,
+	}, NewJob(ClickQueue, c.flush))
+
+	logger.Info().Msgf("Click recorder scheduled in %s environment", cfg.AppEnv)
+
+	return c
+}
+
+// Record enqueues a click event, the event is dropped when the queue is full to keep redirects fast
+func (c *clickRecorder) Record(click repository.Click) {
+	select {
+	case <-c.ctx.Done():
+		c.logger.Warn().Msg("Click recorder is stopped")
+	case c.queue <- click:
+		if len(c.queue) >= ClickBatchSize {
+			c.scheduler.Notify(ClickQueue)
+		}
+	default:
+		c.logger.Warn().Msg("Click recorder queue is full, event dropped")
+	}
+}
+
+// flush stores a batch of queued events, it returns errors.ErrQueueEmpty when no events are queued
+func (c *clickRecorder) flush(ctx context.Context) error {
+	batch := c.take()
+	if len(batch) == 0 {
+		return errors.ErrQueueEmpty
+	}
+
+	if err := c.repo.CreateClicks(ctx, batch); err != nil {
+		c.logger.Error().Err(err).Msgf("Error storing %d click events", len(batch))
+		return err
+	}
+
+	return nil
+}
+
+// take dequeues up to ClickBatchSize events without waiting for more
+func (c *clickRecorder) take() []repository.Click {
+	batch := make([]repository.Click, 0, ClickBatchSize)
+
+	for len(batch) < ClickBatchSize {
+		select {
+		case click := <-c.queue:
+			batch = append(batch, click)
+		default:
+			return batch
+		}
+	}
+
+	return batch
+}
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/worker/click_recorder.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/worker/click_recorder.go -destination=internal/app/worker/click_recorder_mock.go -package=worker
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+	repository "shortly/internal/app/repository"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClickRecorder is a mock of ClickRecorder interface.
+type MockClickRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockClickRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockClickRecorderMockRecorder is the mock recorder for MockClickRecorder.
+type MockClickRecorderMockRecorder struct {
+	mock *MockClickRecorder
+}
+
+// NewMockClickRecorder creates a new mock instance.
+func NewMockClickRecorder(ctrl *gomock.Controller) *MockClickRecorder {
+	mock := &MockClickRecorder{ctrl: ctrl}
+	mock.recorder = &MockClickRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClickRecorder) EXPECT() *MockClickRecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockClickRecorder) Record(click repository.Click) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", click)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockClickRecorderMockRecorder) Record(click any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockClickRecorder)(nil).Record), click)
+}
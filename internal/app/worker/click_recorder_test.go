@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/repository"
+	"shortly/internal/logger"
+)
+
+func Test_NewClickRecorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+	scheduler := NewMockScheduler(ctrl)
+	scheduler.EXPECT().Queue(ClickQueue, QueueOptions{
+		Workers:      1,
+		PollInterval: ClickFlushInterval,
+		DrainTimeout: ClickDrainTimeout,
+	}, gomock.Any())
+
+	recorder := NewClickRecorder(context.Background(), cfg, repository.NewMockRepository(ctrl), scheduler, logger.NewLogger())
+	assert.NotNil(t, recorder)
+}
+
+func Test_ClickRecorder_Record(t *testing.T) {
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+	appLogger := logger.NewLogger()
+
+	URLUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	click := repository.Click{
+		URLUUID:   URLUUID,
+		Referrer:  "https://google.com",
+		UserAgent: "Mozilla/5.0",
+		IPHash:    "hash",
+		CreatedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name   string
+		before func(repo *repository.MockRepository, stored *[]repository.Click, mu *sync.Mutex)
+	}{
+		{
+			name: "Flushes on shutdown",
+			before: func(repo *repository.MockRepository, stored *[]repository.Click, mu *sync.Mutex) {
+				repo.EXPECT().CreateClicks(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, clicks []repository.Click) error {
+					mu.Lock()
+					defer mu.Unlock()
+					*stored = append(*stored, clicks...)
+					return nil
+				}).MinTimes(1)
+			},
+		},
+		{
+			name: "Error",
+			before: func(repo *repository.MockRepository, _ *[]repository.Click, _ *sync.Mutex) {
+				repo.EXPECT().CreateClicks(gomock.Any(), gomock.Any()).Return(assert.AnError).MinTimes(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var mu sync.Mutex
+			var stored []repository.Click
+
+			repo := repository.NewMockRepository(ctrl)
+			tt.before(repo, &stored, &mu)
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			scheduler := NewScheduler(ctx, cfg, appLogger)
+			r := NewClickRecorder(ctx, cfg, repo, scheduler, appLogger)
+			require.NoError(t, scheduler.Start())
+			r.Record(click)
+			r.Record(click)
+
+			cancel()
+			require.NoError(t, scheduler.Stop())
+
+			mu.Lock()
+			defer mu.Unlock()
+			if stored != nil {
+				assert.Equal(t, []repository.Click{click, click}, stored)
+			}
+		})
+	}
+}
+
+func Test_ClickRecorder_Flush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+	repo := repository.NewMockRepository(ctrl)
+	scheduler := NewMockScheduler(ctrl)
+	scheduler.EXPECT().Queue(ClickQueue, gomock.Any(), gomock.Any())
+
+	r := NewClickRecorder(ctx, cfg, repo, scheduler, logger.NewLogger()).(*clickRecorder)
+
+	assert.ErrorIs(t, r.flush(ctx), errors.ErrQueueEmpty)
+
+	// NOTE: a full batch wakes up the queue worker instead of waiting for the next flush
+	scheduler.EXPECT().Notify(ClickQueue).MinTimes(1)
+	for i := 0; i < ClickBatchSize+1; i++ {
+		r.Record(repository.Click{URLUUID: uuid.New()})
+	}
+
+	var sizes []int
+	repo.EXPECT().CreateClicks(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, clicks []repository.Click) error {
+		sizes = append(sizes, len(clicks))
+		return nil
+	}).Times(2)
+
+	require.NoError(t, r.flush(ctx))
+	require.NoError(t, r.flush(ctx))
+	assert.ErrorIs(t, r.flush(ctx), errors.ErrQueueEmpty)
+	assert.Equal(t, []int{ClickBatchSize, 1}, sizes)
+}
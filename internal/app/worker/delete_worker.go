@@ -2,103 +2,408 @@ package worker
 
 import (
 	"context"
-	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"shortly/internal/app/config"
 	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/health"
+	"shortly/internal/app/metrics"
 	"shortly/internal/app/repository"
+	"shortly/internal/app/tracing"
 	"shortly/internal/logger"
 )
 
-// QueueSize is the size of the worker queue
-const QueueSize = 100
+// DeleteTaskLease is how long a claimed delete task is hidden from other workers,
+// tasks of a crashed instance are picked up again after it
+const DeleteTaskLease = 5 * time.Minute
+
+// MaxRetryBackoff caps the delay between delete task attempts
+const MaxRetryBackoff = 10 * time.Minute
+
+// DeleteWorkerStallTimeout is how long the delete queue may go without a poll before the workers are reported stalled
+const DeleteWorkerStallTimeout = time.Minute
+
+// DeleteQueue is the name of the delete queue in the scheduler
+const DeleteQueue = "delete"
 
 // Worker is an interface for the delete worker
 type Worker interface {
-	Start()
-	Stop()
-	Add(req dto.BatchDeleteParams)
+	// Add stores the request in the delete queue and returns the initial state of its job
+	Add(ctx context.Context, req dto.BatchDeleteParams) (DeleteJob, error)
+	// Job returns the state of the delete job if it belongs to the user
+	Job(ctx context.Context, userID, jobID uuid.UUID) (DeleteJob, bool)
+	// Check reports the workers down when they stopped polling the queue and degraded when the queue is saturated
+	Check(ctx context.Context) error
 }
 
 type worker struct {
-	ctx    context.Context
-	cfg    *config.Config
-	repo   repository.Repository
-	queue  chan dto.BatchDeleteParams
-	logger *logger.Logger
-	wg     sync.WaitGroup
+	ctx       context.Context
+	cfg       *config.Config
+	repo      repository.Repository
+	jobs      JobStore
+	scheduler Scheduler
+	logger    *logger.Logger
+
+	// heartbeat is the time in Unix nanoseconds a worker last polled the queue
+	heartbeat atomic.Int64
 }
 
-// NewDeleteWorker creates a new delete worker instance
-func NewDeleteWorker(ctx context.Context, cfg *config.Config, repo repository.Repository, logger *logger.Logger) Worker {
-	queue := make(chan dto.BatchDeleteParams, QueueSize)
+// NewDeleteWorker creates a new delete worker instance processing the durable delete queue of the repository,
+// the queue is registered in the scheduler and processed once it is started
+func NewDeleteWorker(ctx context.Context, cfg *config.Config, repo repository.Repository, scheduler Scheduler, logger *logger.Logger) Worker {
+	w := &worker{
+		ctx:       ctx,
+		cfg:       cfg,
+		repo:      repo,
+		jobs:      NewJobStore(repo),
+		scheduler: scheduler,
+		logger:    logger,
+	}
+	w.heartbeat.Store(time.Now().UnixNano())
+
+	scheduler.Queue(DeleteQueue, QueueOptions{
+		Workers:      cfg.DeleteWorkers,
+		DrainTimeout: cfg.DeleteDrainTimeout,
+	}, NewJob(DeleteQueue, w.consume))
 
-	return &worker{
-		ctx:    ctx,
-		cfg:    cfg,
-		repo:   repo,
-		queue:  queue,
-		logger: logger,
+	scheduler.OnStart(w.start)
+
+	return w
+}
+
+// Add stores the request in the delete queue and wakes up a worker
+func (w *worker) Add(ctx context.Context, req dto.BatchDeleteParams) (DeleteJob, error) {
+	if req.JobID == uuid.Nil {
+		req.JobID = uuid.New()
 	}
+
+	job := DeleteJob{
+		ID:         req.JobID,
+		UserID:     req.UserID,
+		Status:     dto.JobStatusQueued,
+		ShortCodes: unique(req.ShortCodes),
+		CreatedAt:  time.Now(),
+	}
+
+	// NOTE: the job is tracked before the task is stored, so a worker claiming it at once finds the job
+	if err := w.jobs.Create(ctx, job); err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msgf("Error storing delete job for user %s", req.UserID)
+		return DeleteJob{}, errors.ErrFailedToDeleteURLs
+	}
+
+	err := w.repo.EnqueueDeleteTask(ctx, repository.DeleteTask{
+		UUID:       job.ID,
+		UserUUID:   job.UserID,
+		ShortCodes: job.ShortCodes,
+		RunAt:      job.CreatedAt,
+		CreatedAt:  job.CreatedAt,
+		// NOTE: the task carries the trace of the request, so its processing is traced together with it
+		TraceParent: tracing.TraceParent(ctx),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msgf("Error queueing delete of URLs for user %s", req.UserID)
+		w.updateJob(ctx, job.ID, func(job *DeleteJob) {
+			job.Status = dto.JobStatusFailed
+			job.Error = errors.ErrFailedToDeleteURLs.Error()
+			job.FinishedAt = time.Now()
+		})
+		return DeleteJob{}, errors.ErrFailedToDeleteURLs
+	}
+
+	metrics.DeleteQueueDepth.Inc()
+	w.scheduler.Notify(DeleteQueue)
+
+	return job, nil
 }
 
-// Start starts the application worker
-func (w *worker) Start() {
-	w.logger.Info().Msgf("Worker starting in %s environment", w.cfg.AppEnv)
+// Job returns the state of the delete job if it belongs to the user
+func (w *worker) Job(ctx context.Context, userID, jobID uuid.UUID) (DeleteJob, bool) {
+	job, ok := w.jobs.Get(ctx, jobID)
+	if !ok || job.UserID != userID {
+		return DeleteJob{}, false
+	}
 
-	w.wg.Add(1)
-	go w.run()
+	return job, true
 }
 
-// Stop stops the application worker
-func (w *worker) Stop() {
-	w.wg.Wait()
+// Check reports the workers down when they stopped polling the queue and degraded when the queue is saturated
+func (w *worker) Check(ctx context.Context) error {
+	if time.Since(time.Unix(0, w.heartbeat.Load())) > DeleteWorkerStallTimeout {
+		return errors.ErrDeleteWorkerStalled
+	}
+
+	stats, err := w.repo.CountDeleteTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	if w.cfg.DeleteQueueSaturation > 0 && stats.Pending > w.cfg.DeleteQueueSaturation {
+		return health.Degraded(errors.ErrDeleteQueueSaturated)
+	}
+
+	return nil
 }
 
-// Add adds a new request to the worker queue
-func (w *worker) Add(req dto.BatchDeleteParams) {
-	select {
-	case <-w.ctx.Done():
-		w.logger.Warn().Msg("Worker is stopped")
-	default:
-		w.queue <- req
+// consume processes a batch of due tasks, it reports an empty queue when no task is due
+func (w *worker) consume(ctx context.Context) error {
+	ctx = logger.WithContext(ctx, w.logger)
+
+	w.heartbeat.Store(time.Now().UnixNano())
+	defer func() {
+		w.heartbeat.Store(time.Now().UnixNano())
+	}()
+
+	if !w.process(ctx) {
+		return errors.ErrQueueEmpty
 	}
+
+	return nil
 }
 
-func (w *worker) run() {
-	defer w.wg.Done()
-	for {
+// process claims due tasks, waits for more up to the batch window and performs them as a single batch,
+// it reports whether a task was claimed
+func (w *worker) process(ctx context.Context) bool {
+	size := max(w.cfg.DeleteBatchSize, 1)
+
+	tasks := w.claim(ctx, size)
+	if len(tasks) == 0 {
+		return false
+	}
+
+	if len(tasks) < size && w.cfg.DeleteBatchWindow > 0 {
+		timer := time.NewTimer(w.cfg.DeleteBatchWindow)
+		defer timer.Stop()
+
 		select {
 		case <-w.ctx.Done():
-			return
-		case req := <-w.queue:
-			w.perform(req)
+		case <-timer.C:
+			tasks = append(tasks, w.claim(ctx, size-len(tasks))...)
+		}
+	}
+
+	w.perform(ctx, tasks)
+	w.observe(ctx)
+
+	return true
+}
+
+// claim leases up to limit due tasks
+func (w *worker) claim(ctx context.Context, limit int) []repository.DeleteTask {
+	now := time.Now()
+
+	tasks, err := w.repo.ClaimDeleteTasks(ctx, now, now.Add(DeleteTaskLease), int64(limit))
+	if err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msg("Error claiming delete tasks")
+		return nil
+	}
+
+	return tasks
+}
+
+// perform merges the short codes of the tasks per user and deletes them with a single repository call,
+// a short code requested by several tasks is reported as deleted by the first one
+func (w *worker) perform(ctx context.Context, tasks []repository.DeleteTask) {
+	ctx, span := w.trace(ctx, tasks)
+	defer span.End()
+
+	ctx = logger.WithContext(ctx, logger.FromContext(ctx).WithTrace(ctx))
+
+	shortCodes := make(map[uuid.UUID][]string)
+	for _, task := range tasks {
+		w.updateJob(ctx, task.UUID, func(job *DeleteJob) {
+			job.Status = dto.JobStatusRunning
+		})
+		shortCodes[task.UserUUID] = unique(shortCodes[task.UserUUID], task.ShortCodes)
+	}
+
+	metrics.DeleteBatchSize.Observe(float64(len(tasks)))
+
+	deleted, err := w.repo.DeleteURLsByUserIDs(ctx, shortCodes)
+	if err != nil {
+		tracing.RecordError(span, err)
+		for _, task := range tasks {
+			w.retry(ctx, task, err)
+		}
+		return
+	}
+
+	pending := make(map[uuid.UUID]map[string]struct{}, len(deleted))
+	for userID, codes := range deleted {
+		pending[userID] = make(map[string]struct{}, len(codes))
+		for _, code := range codes {
+			pending[userID][code] = struct{}{}
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(tasks))
+	for _, task := range tasks {
+		var taskDeleted []string
+		for _, code := range task.ShortCodes {
+			if _, ok := pending[task.UserUUID][code]; ok {
+				delete(pending[task.UserUUID], code)
+				taskDeleted = append(taskDeleted, code)
+			}
+		}
+
+		metrics.DeleteRequestsProcessed.Inc()
+		logger.FromContext(ctx).Info().Str("job_id", task.UUID.String()).Msgf("Deleted URLs for user %s: %v", task.UserUUID, taskDeleted)
+
+		skipped := w.skipped(ctx, task.UserUUID, task.ShortCodes, taskDeleted)
+		w.updateJob(ctx, task.UUID, func(job *DeleteJob) {
+			job.Status = dto.JobStatusDone
+			job.Deleted = taskDeleted
+			job.Skipped = skipped
+			job.FinishedAt = time.Now()
+		})
+
+		ids = append(ids, task.UUID)
+	}
+
+	if err = w.repo.CompleteDeleteTasks(ctx, ids); err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msgf("Error completing %d delete tasks", len(ids))
+	}
+}
+
+// trace starts the span of a batch, the span continues the trace of a single task and links to the traces of a batch
+func (w *worker) trace(ctx context.Context, tasks []repository.DeleteTask) (context.Context, trace.Span) {
+	links := make([]trace.Link, 0, len(tasks))
+	for _, task := range tasks {
+		if sc := tracing.SpanContext(task.TraceParent); sc.IsValid() {
+			links = append(links, trace.Link{SpanContext: sc})
+		}
+	}
+
+	if len(tasks) == 1 && len(links) == 1 {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, links[0].SpanContext)
+	}
+
+	return tracing.Start(ctx, "DeleteWorker.perform",
+		trace.WithLinks(links...),
+		trace.WithAttributes(attribute.Int("delete.batch_size", len(tasks))),
+	)
+}
+
+// retry schedules the next attempt of the failed task with an exponential backoff,
+// the task is moved to the dead letters after the last attempt
+func (w *worker) retry(ctx context.Context, task repository.DeleteTask, cause error) {
+	if task.Attempts >= w.cfg.DeleteMaxAttempts {
+		metrics.DeleteRequestsFailed.Inc()
+		logger.FromContext(ctx).Error().Err(cause).Msgf("Delete task %s of user %s failed after %d attempts, moved to dead letters: %v",
+			task.UUID, task.UserUUID, task.Attempts, task.ShortCodes)
+
+		if err := w.repo.FailDeleteTask(ctx, task.UUID, cause.Error()); err != nil {
+			logger.FromContext(ctx).Error().Err(err).Msgf("Error failing delete task %s", task.UUID)
 		}
+
+		w.updateJob(ctx, task.UUID, func(job *DeleteJob) {
+			job.Status = dto.JobStatusFailed
+			job.Error = errors.ErrFailedToDeleteURLs.Error()
+			job.FinishedAt = time.Now()
+		})
+		return
+	}
+
+	metrics.DeleteRequestsRetried.Inc()
+	delay := backoff(w.cfg.DeleteRetryBackoff, task.Attempts)
+	logger.FromContext(ctx).Warn().Err(cause).Msgf("Delete task %s attempt %d failed, retrying in %s", task.UUID, task.Attempts, delay)
+
+	if err := w.repo.RetryDeleteTask(ctx, task.UUID, time.Now().Add(delay), cause.Error()); err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msgf("Error scheduling retry of delete task %s", task.UUID)
 	}
+
+	w.updateJob(ctx, task.UUID, func(job *DeleteJob) {
+		job.Status = dto.JobStatusQueued
+	})
 }
 
-func (w *worker) perform(req dto.BatchDeleteParams) {
-	shortCodes := unique(req.ShortCodes)
-	err := w.repo.DeleteURLsByUserID(w.ctx, req.UserID, shortCodes)
+// updateJob applies fn to the stored job, a job state which failed to save is only logged,
+// the deletion itself is tracked by the delete task
+func (w *worker) updateJob(ctx context.Context, id uuid.UUID, fn func(job *DeleteJob)) {
+	if err := w.jobs.Update(ctx, id, fn); err != nil {
+		logger.FromContext(ctx).Error().Err(err).Msgf("Error updating delete job %s", id)
+	}
+}
 
+// start updates the queue metrics of the delete tasks restored on start
+func (w *worker) start(ctx context.Context) error {
+	w.observe(logger.WithContext(ctx, w.logger))
+	return nil
+}
+
+// observe updates the queue metrics from the repository
+func (w *worker) observe(ctx context.Context) {
+	stats, err := w.repo.CountDeleteTasks(ctx)
 	if err != nil {
-		w.logger.Error().Err(err).Msgf("Error deleting URLs for user %s", req.UserID)
-	} else {
-		w.logger.Info().Msgf("Deleted URLs for user %s: %v", req.UserID, shortCodes)
+		logger.FromContext(ctx).Error().Err(err).Msg("Error counting delete tasks")
+		return
 	}
+
+	metrics.DeleteQueueDepth.Set(float64(stats.Pending))
+	metrics.DeleteDeadLetters.Set(float64(stats.Failed))
 }
 
-// unique returns a unique slice of short codes
-func unique(shortCodes []string) []string {
-	codesMap := make(map[string]struct{})
+// backoff returns the delay before the retry following the given attempt, doubled on every attempt up to the cap
+func backoff(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, MaxRetryBackoff)
+}
 
+// skipped reports why the short codes missing from the deleted ones were left untouched
+func (w *worker) skipped(ctx context.Context, userID uuid.UUID, shortCodes, deleted []string) []dto.SkippedShortCode {
+	done := make(map[string]struct{}, len(deleted))
+	for _, code := range deleted {
+		done[code] = struct{}{}
+	}
+
+	var skipped []dto.SkippedShortCode
 	for _, code := range shortCodes {
-		codesMap[code] = struct{}{}
+		if _, ok := done[code]; ok {
+			continue
+		}
+
+		reason := dto.SkipReasonAlreadyDeleted
+		url, found := w.repo.GetURLByShortCode(ctx, code)
+		switch {
+		case !found:
+			reason = dto.SkipReasonNotFound
+		case url.UserUUID != userID:
+			reason = dto.SkipReasonNotOwned
+		}
+
+		skipped = append(skipped, dto.SkippedShortCode{ShortCode: code, Reason: reason})
 	}
 
-	uniqueCodes := make([]string, 0, len(codesMap))
-	for code := range codesMap {
-		uniqueCodes = append(uniqueCodes, code)
+	return skipped
+}
+
+// unique merges slices of short codes into a unique one in the order of their first occurrence
+func unique(shortCodes ...[]string) []string {
+	size := 0
+	for _, codes := range shortCodes {
+		size += len(codes)
+	}
+
+	codesMap := make(map[string]struct{}, size)
+
+	uniqueCodes := make([]string, 0, size)
+	for _, codes := range shortCodes {
+		for _, code := range codes {
+			if _, ok := codesMap[code]; ok {
+				continue
+			}
+			codesMap[code] = struct{}{}
+			uniqueCodes = append(uniqueCodes, code)
+		}
 	}
 
 	return uniqueCodes
@@ -10,9 +10,11 @@
 package worker
 
 import (
+	context "context"
 	reflect "reflect"
 	dto "shortly/internal/app/dto"
 
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,37 +43,45 @@ func (m *MockWorker) EXPECT() *MockWorkerMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockWorker) Add(req dto.BatchDeleteParams) {
+func (m *MockWorker) Add(ctx context.Context, req dto.BatchDeleteParams) (DeleteJob, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Add", req)
+	ret := m.ctrl.Call(m, "Add", ctx, req)
+	ret0, _ := ret[0].(DeleteJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockWorkerMockRecorder) Add(req any) *gomock.Call {
+func (mr *MockWorkerMockRecorder) Add(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockWorker)(nil).Add), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockWorker)(nil).Add), ctx, req)
 }
 
-// Start mocks base method.
-func (m *MockWorker) Start() {
+// Check mocks base method.
+func (m *MockWorker) Check(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Start")
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// Start indicates an expected call of Start.
-func (mr *MockWorkerMockRecorder) Start() *gomock.Call {
+// Check indicates an expected call of Check.
+func (mr *MockWorkerMockRecorder) Check(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockWorker)(nil).Start))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockWorker)(nil).Check), ctx)
 }
 
-// Stop mocks base method.
-func (m *MockWorker) Stop() {
+// Job mocks base method.
+func (m *MockWorker) Job(ctx context.Context, userID, jobID uuid.UUID) (DeleteJob, bool) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Stop")
+	ret := m.ctrl.Call(m, "Job", ctx, userID, jobID)
+	ret0, _ := ret[0].(DeleteJob)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
 }
 
-// Stop indicates an expected call of Stop.
-func (mr *MockWorkerMockRecorder) Stop() *gomock.Call {
+// Job indicates an expected call of Job.
+func (mr *MockWorkerMockRecorder) Job(ctx, userID, jobID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockWorker)(nil).Stop))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Job", reflect.TypeOf((*MockWorker)(nil).Job), ctx, userID, jobID)
 }
@@ -3,40 +3,50 @@ package worker
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
+	"sync"
 	"testing"
 	"time"
 
 	"shortly/internal/app/config"
 	"shortly/internal/app/dto"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/health"
+	"shortly/internal/app/metrics"
 	"shortly/internal/app/repository"
 	"shortly/internal/logger"
 )
 
 func Test_worker_StartAndStop(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	cfg := &config.Config{
-		AppEnv: "test",
+		AppEnv:        "test",
+		DeleteWorkers: 2,
 	}
-	repo := repository.NewMockRepository(ctrl)
+	repo := repository.NewInMemoryRepository()
 	appLogger := logger.NewLogger()
-	deleteWorker := NewDeleteWorker(ctx, cfg, repo, appLogger)
+	scheduler := NewScheduler(ctx, cfg, appLogger)
+	NewDeleteWorker(ctx, cfg, repo, scheduler, appLogger)
 
 	assert.NotPanics(t, func() {
-		deleteWorker.Start()
+		assert.NoError(t, scheduler.Start())
 	})
 
 	assert.NotPanics(t, func() {
 		cancel()
+		assert.NoError(t, scheduler.Stop())
 	})
 }
 
-func Test_DeleteWorker_Perform(t *testing.T) {
+func Test_DeleteWorker_Add(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -44,52 +54,474 @@ func Test_DeleteWorker_Perform(t *testing.T) {
 		AppEnv: "test",
 	}
 	repo := repository.NewMockRepository(ctrl)
-	appLogger := logger.NewLogger()
+	expectDeleteJobs(repo)
+	scheduler := NewMockScheduler(ctrl)
 
 	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	JobUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+
+	type result struct {
+		status string
+		error  error
+	}
 
 	tests := []struct {
-		name   string
-		params dto.BatchDeleteParams
-		before func()
+		name     string
+		before   func()
+		expected result
 	}{
 		{
 			name: "Success",
-			params: dto.BatchDeleteParams{
-				UserID:     UserUUID,
-				ShortCodes: []string{"abcd1234"},
-			},
 			before: func() {
-				repo.EXPECT().DeleteURLsByUserID(gomock.Any(), UserUUID, []string{"abcd1234"}).Return(nil)
+				repo.EXPECT().EnqueueDeleteTask(gomock.Any(), gomock.Cond(func(task repository.DeleteTask) bool {
+					return task.UUID == JobUUID && task.UserUUID == UserUUID &&
+						assert.ObjectsAreEqual([]string{"abcd0001", "abcd0002"}, task.ShortCodes) && !task.RunAt.IsZero()
+				})).Return(nil)
+				scheduler.EXPECT().Notify(DeleteQueue)
+			},
+			expected: result{
+				status: dto.JobStatusQueued,
 			},
 		},
 		{
 			name: "Error",
-			params: dto.BatchDeleteParams{
+			before: func() {
+				repo.EXPECT().EnqueueDeleteTask(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+			expected: result{
+				status: dto.JobStatusFailed,
+				error:  errors.ErrFailedToDeleteURLs,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler.EXPECT().Queue(DeleteQueue, gomock.Any(), gomock.Any())
+			scheduler.EXPECT().OnStart(gomock.Any())
+			tt.before()
+
+			w := NewDeleteWorker(context.Background(), cfg, repo, scheduler, logger.NewLogger())
+			_, err := w.Add(context.Background(), dto.BatchDeleteParams{
+				JobID:      JobUUID,
 				UserID:     UserUUID,
+				ShortCodes: []string{"abcd0001", "abcd0002", "abcd0001"},
+			})
+
+			assert.Equal(t, tt.expected.error, err)
+
+			job, ok := w.Job(context.Background(), UserUUID, JobUUID)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected.status, job.Status)
+			assert.Equal(t, []string{"abcd0001", "abcd0002"}, job.ShortCodes)
+		})
+	}
+}
+
+func Test_DeleteWorker_Add_JobStoreError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockRepository(ctrl)
+	scheduler := NewMockScheduler(ctrl)
+	scheduler.EXPECT().Queue(DeleteQueue, gomock.Any(), gomock.Any())
+	scheduler.EXPECT().OnStart(gomock.Any())
+
+	// NOTE: no task is enqueued for a job which could not be stored, its status could never be reported
+	repo.EXPECT().SaveDeleteJob(gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+	w := NewDeleteWorker(context.Background(), &config.Config{AppEnv: "test"}, repo, scheduler, logger.NewLogger())
+	_, err := w.Add(context.Background(), dto.BatchDeleteParams{
+		JobID:      uuid.New(),
+		UserID:     uuid.New(),
+		ShortCodes: []string{"abcd0001"},
+	})
+
+	assert.Equal(t, errors.ErrFailedToDeleteURLs, err)
+}
+
+func Test_DeleteWorker_Perform(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		AppEnv:             "test",
+		DeleteMaxAttempts:  3,
+		DeleteRetryBackoff: time.Minute,
+	}
+	repo := repository.NewMockRepository(ctrl)
+	expectDeleteJobs(repo)
+	appLogger := logger.NewLogger()
+
+	UserUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174001")
+	OtherUUID, _ := uuid.Parse("123e4567-e89b-12d3-a456-426614174002")
+	TaskUUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720001")
+	Task2UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720002")
+	Task3UUID, _ := uuid.Parse("6455bd07-e431-4851-af3c-4f703f720003")
+
+	type result struct {
+		status  string
+		deleted []string
+		skipped []dto.SkippedShortCode
+		error   string
+	}
+
+	tests := []struct {
+		name     string
+		tasks    []repository.DeleteTask
+		before   func()
+		counter  prometheus.Counter
+		expected []result
+	}{
+		{
+			name: "Success",
+			tasks: []repository.DeleteTask{{
+				UUID:       TaskUUID,
+				UserUUID:   UserUUID,
+				ShortCodes: []string{"abcd1234", "abcd0002", "abcd0003", "abcd0004"},
+				Attempts:   1,
+			}},
+			before: func() {
+				repo.EXPECT().DeleteURLsByUserIDs(gomock.Any(), map[uuid.UUID][]string{
+					UserUUID: {"abcd1234", "abcd0002", "abcd0003", "abcd0004"},
+				}).Return(map[uuid.UUID][]string{UserUUID: {"abcd1234"}}, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0002").Return(nil, false)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0003").Return(&repository.URL{UserUUID: OtherUUID}, true)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0004").Return(&repository.URL{UserUUID: UserUUID}, true)
+				repo.EXPECT().CompleteDeleteTasks(gomock.Any(), []uuid.UUID{TaskUUID}).Return(nil)
+			},
+			counter: metrics.DeleteRequestsProcessed,
+			expected: []result{{
+				status:  dto.JobStatusDone,
+				deleted: []string{"abcd1234"},
+				skipped: []dto.SkippedShortCode{
+					{ShortCode: "abcd0002", Reason: dto.SkipReasonNotFound},
+					{ShortCode: "abcd0003", Reason: dto.SkipReasonNotOwned},
+					{ShortCode: "abcd0004", Reason: dto.SkipReasonAlreadyDeleted},
+				},
+			}},
+		},
+		{
+			name: "Coalesced",
+			tasks: []repository.DeleteTask{
+				{UUID: TaskUUID, UserUUID: UserUUID, ShortCodes: []string{"abcd0001", "abcd0002"}, Attempts: 1},
+				{UUID: Task2UUID, UserUUID: OtherUUID, ShortCodes: []string{"abcd0003"}, Attempts: 1},
+				{UUID: Task3UUID, UserUUID: UserUUID, ShortCodes: []string{"abcd0002", "abcd0004"}, Attempts: 1},
+			},
+			before: func() {
+				repo.EXPECT().DeleteURLsByUserIDs(gomock.Any(), map[uuid.UUID][]string{
+					UserUUID:  {"abcd0001", "abcd0002", "abcd0004"},
+					OtherUUID: {"abcd0003"},
+				}).Return(map[uuid.UUID][]string{
+					UserUUID:  {"abcd0004", "abcd0002", "abcd0001"},
+					OtherUUID: {"abcd0003"},
+				}, nil)
+				repo.EXPECT().GetURLByShortCode(gomock.Any(), "abcd0002").Return(&repository.URL{UserUUID: UserUUID}, true)
+				repo.EXPECT().CompleteDeleteTasks(gomock.Any(), []uuid.UUID{TaskUUID, Task2UUID, Task3UUID}).Return(nil)
+			},
+			counter: metrics.DeleteRequestsProcessed,
+			expected: []result{
+				{status: dto.JobStatusDone, deleted: []string{"abcd0001", "abcd0002"}},
+				{status: dto.JobStatusDone, deleted: []string{"abcd0003"}},
+				{
+					status:  dto.JobStatusDone,
+					deleted: []string{"abcd0004"},
+					skipped: []dto.SkippedShortCode{{ShortCode: "abcd0002", Reason: dto.SkipReasonAlreadyDeleted}},
+				},
+			},
+		},
+		{
+			name: "Retry",
+			tasks: []repository.DeleteTask{{
+				UUID:       TaskUUID,
+				UserUUID:   UserUUID,
 				ShortCodes: []string{"abcd1234"},
+				Attempts:   2,
+			}},
+			before: func() {
+				repo.EXPECT().DeleteURLsByUserIDs(gomock.Any(), map[uuid.UUID][]string{UserUUID: {"abcd1234"}}).
+					Return(nil, assert.AnError)
+				repo.EXPECT().RetryDeleteTask(gomock.Any(), TaskUUID, gomock.Cond(func(runAt time.Time) bool {
+					delay := time.Until(runAt)
+					return delay > time.Minute && delay <= 2*time.Minute
+				}), assert.AnError.Error()).Return(nil)
 			},
+			counter: metrics.DeleteRequestsRetried,
+			expected: []result{{
+				status: dto.JobStatusQueued,
+			}},
+		},
+		{
+			name: "Dead letter",
+			tasks: []repository.DeleteTask{{
+				UUID:       TaskUUID,
+				UserUUID:   UserUUID,
+				ShortCodes: []string{"abcd1234"},
+				Attempts:   3,
+			}},
 			before: func() {
-				repo.EXPECT().DeleteURLsByUserID(gomock.Any(), UserUUID, []string{"abcd1234"}).Return(assert.AnError)
+				repo.EXPECT().DeleteURLsByUserIDs(gomock.Any(), map[uuid.UUID][]string{UserUUID: {"abcd1234"}}).
+					Return(nil, assert.AnError)
+				repo.EXPECT().FailDeleteTask(gomock.Any(), TaskUUID, assert.AnError.Error()).Return(nil)
 			},
+			counter: metrics.DeleteRequestsFailed,
+			expected: []result{{
+				status: dto.JobStatusFailed,
+				error:  "failed to delete URLs",
+			}},
 		},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(_ *testing.T) {
+		t.Run(tt.name, func(t *testing.T) {
 			tt.before()
+			before := testutil.ToFloat64(tt.counter)
 
-			ctx, cancel := context.WithCancel(context.Background())
+			w := NewDeleteWorker(context.Background(), cfg, repo, NewScheduler(context.Background(), cfg, appLogger), appLogger).(*worker)
+			for _, task := range tt.tasks {
+				require.NoError(t, w.jobs.Create(context.Background(), DeleteJob{ID: task.UUID, UserID: task.UserUUID, Status: dto.JobStatusQueued}))
+			}
 
-			w := NewDeleteWorker(ctx, cfg, repo, appLogger)
-			w.Start()
-			w.Add(tt.params)
+			w.perform(context.Background(), tt.tasks)
 
-			time.Sleep(50 * time.Millisecond)
+			assert.Equal(t, before+float64(len(tt.tasks)), testutil.ToFloat64(tt.counter))
 
-			assert.NotPanics(t, func() {
-				cancel()
-			})
+			for i, task := range tt.tasks {
+				job, ok := w.Job(context.Background(), task.UserUUID, task.UUID)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expected[i].status, job.Status)
+				assert.Equal(t, tt.expected[i].deleted, job.Deleted)
+				assert.Equal(t, tt.expected[i].skipped, job.Skipped)
+				assert.Equal(t, tt.expected[i].error, job.Error)
+			}
+
+			_, ok := w.Job(context.Background(), OtherUUID, TaskUUID)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func Test_DeleteWorker_Batch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &config.Config{
+		AppEnv:            "test",
+		DeleteMaxAttempts: 3,
+		DeleteBatchSize:   2,
+		DeleteBatchWindow: time.Second,
+	}
+	repo := repository.NewInMemoryRepository()
+
+	UserUUID := uuid.New()
+	tasks := make([]repository.DeleteTask, 0, 3)
+	for i := 0; i < cap(tasks); i++ {
+		task := repository.DeleteTask{UUID: uuid.New(), UserUUID: UserUUID, ShortCodes: []string{uuid.NewString()}, RunAt: time.Now()}
+		require.NoError(t, repo.EnqueueDeleteTask(ctx, task))
+		tasks = append(tasks, task)
+	}
+
+	w := NewDeleteWorker(ctx, cfg, repo, NewScheduler(ctx, cfg, logger.NewLogger()), logger.NewLogger()).(*worker)
+	before := testutil.ToFloat64(metrics.DeleteRequestsProcessed)
+
+	// NOTE: a full batch is flushed at once without waiting for the window
+	start := time.Now()
+	assert.True(t, w.process(ctx))
+	assert.Less(t, time.Since(start), cfg.DeleteBatchWindow)
+	assert.Equal(t, before+2, testutil.ToFloat64(metrics.DeleteRequestsProcessed))
+
+	// NOTE: a partial batch is flushed once the worker is stopped
+	cancel()
+	assert.True(t, w.process(context.Background()))
+	assert.Equal(t, before+3, testutil.ToFloat64(metrics.DeleteRequestsProcessed))
+	assert.False(t, w.process(context.Background()))
+
+	stats, err := repo.CountDeleteTasks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, repository.DeleteTaskStats{}, stats)
+}
+
+func Test_DeleteWorker_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	cfg := &config.Config{
+		AppEnv:                "test",
+		DeleteQueueSaturation: 100,
+	}
+	repo := repository.NewMockRepository(ctrl)
+	scheduler := NewMockScheduler(ctrl)
+	scheduler.EXPECT().Queue(DeleteQueue, gomock.Any(), gomock.Any())
+	scheduler.EXPECT().OnStart(gomock.Any())
+
+	w := NewDeleteWorker(ctx, cfg, repo, scheduler, logger.NewLogger()).(*worker)
+
+	tests := []struct {
+		name      string
+		heartbeat time.Time
+		before    func()
+		expected  error
+		degraded  bool
+	}{
+		{
+			name:      "Healthy",
+			heartbeat: time.Now(),
+			before: func() {
+				repo.EXPECT().CountDeleteTasks(gomock.Any()).Return(repository.DeleteTaskStats{Pending: 100}, nil)
+			},
+		},
+		{
+			name:      "Saturated",
+			heartbeat: time.Now(),
+			before: func() {
+				repo.EXPECT().CountDeleteTasks(gomock.Any()).Return(repository.DeleteTaskStats{Pending: 101}, nil)
+			},
+			expected: errors.ErrDeleteQueueSaturated,
+			degraded: true,
+		},
+		{
+			name:      "Repository error",
+			heartbeat: time.Now(),
+			before: func() {
+				repo.EXPECT().CountDeleteTasks(gomock.Any()).Return(repository.DeleteTaskStats{}, assert.AnError)
+			},
+			expected: assert.AnError,
+		},
+		{
+			name:      "Stalled",
+			heartbeat: time.Now().Add(-2 * DeleteWorkerStallTimeout),
+			before:    func() {},
+			expected:  errors.ErrDeleteWorkerStalled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+			w.heartbeat.Store(tt.heartbeat.UnixNano())
+
+			err := w.Check(ctx)
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+			assert.Equal(t, tt.degraded, health.IsDegraded(err))
+		})
+	}
+}
+
+func Test_DeleteWorker_Queue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &config.Config{
+		AppEnv:             "test",
+		DeleteWorkers:      2,
+		DeleteMaxAttempts:  3,
+		DeleteDrainTimeout: time.Second,
+	}
+	repo := repository.NewInMemoryRepository()
+
+	UserUUID := uuid.New()
+	_, err := repo.CreateURLs(ctx, []repository.URL{
+		{UUID: uuid.New(), LongURL: "https://example.com/1", ShortCode: "abcd0001", UserUUID: UserUUID},
+		{UUID: uuid.New(), LongURL: "https://example.com/2", ShortCode: "abcd0002", UserUUID: UserUUID},
+	})
+	require.NoError(t, err)
+
+	scheduler := NewScheduler(ctx, cfg, logger.NewLogger())
+	w := NewDeleteWorker(ctx, cfg, repo, scheduler, logger.NewLogger())
+	require.NoError(t, scheduler.Start())
+
+	job, err := w.Add(ctx, dto.BatchDeleteParams{UserID: UserUUID, ShortCodes: []string{"abcd0001"}})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		job, _ = w.Job(ctx, UserUUID, job.ID)
+		return job.Status == dto.JobStatusDone
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"abcd0001"}, job.Deleted)
+
+	cancel()
+	require.NoError(t, scheduler.Stop())
+
+	stats, err := repo.CountDeleteTasks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, repository.DeleteTaskStats{}, stats)
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.DeleteQueueDepth))
+}
+
+func Test_DeleteWorker_Drain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cfg := &config.Config{
+		AppEnv:             "test",
+		DeleteWorkers:      1,
+		DeleteMaxAttempts:  1,
+		DeleteDrainTimeout: time.Second,
+	}
+	repo := repository.NewInMemoryRepository()
+
+	UserUUID := uuid.New()
+	urls := make([]repository.URL, 0, 3)
+	for i := 0; i < cap(urls); i++ {
+		url := repository.URL{UUID: uuid.New(), LongURL: uuid.NewString(), ShortCode: uuid.NewString(), UserUUID: UserUUID}
+		urls = append(urls, url)
+
+		err := repo.EnqueueDeleteTask(ctx, repository.DeleteTask{UUID: uuid.New(), UserUUID: UserUUID, ShortCodes: []string{url.ShortCode}})
+		require.NoError(t, err)
+	}
+	_, err := repo.CreateURLs(ctx, urls)
+	require.NoError(t, err)
+
+	// NOTE: the worker is stopped before it starts, so queued tasks are processed by the drain
+	cancel()
+
+	scheduler := NewScheduler(ctx, cfg, logger.NewLogger())
+	NewDeleteWorker(ctx, cfg, repo, scheduler, logger.NewLogger())
+	require.NoError(t, scheduler.Start())
+	require.NoError(t, scheduler.Stop())
+
+	for _, url := range urls {
+		stored, ok := repo.GetURLByShortCode(context.Background(), url.ShortCode)
+		require.True(t, ok)
+		assert.False(t, stored.DeletedAt.IsZero())
+	}
+
+	stats, err := repo.CountDeleteTasks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, repository.DeleteTaskStats{}, stats)
+}
+
+func Test_backoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempt  int
+		expected time.Duration
+	}{
+		{
+			name:     "First attempt",
+			attempt:  1,
+			expected: time.Second,
+		},
+		{
+			name:     "Doubled on every attempt",
+			attempt:  4,
+			expected: 8 * time.Second,
+		},
+		{
+			name:     "Capped",
+			attempt:  100,
+			expected: MaxRetryBackoff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, backoff(time.Second, tt.attempt))
 		})
 	}
 }
@@ -120,7 +552,122 @@ func Test_DeleteWorker_Unique(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := unique(tt.shortCodes)
-			assert.ElementsMatch(t, tt.expected, result)
+			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func Test_DeleteWorker_UniqueMerge(t *testing.T) {
+	tests := []struct {
+		name       string
+		shortCodes [][]string
+		expected   []string
+	}{
+		{
+			name:       "Disjoint lists",
+			shortCodes: [][]string{{"code1", "code2"}, {"code3"}},
+			expected:   []string{"code1", "code2", "code3"},
+		},
+		{
+			name:       "Overlapping lists",
+			shortCodes: [][]string{{"code1", "code2"}, {"code2", "code3", "code1"}},
+			expected:   []string{"code1", "code2", "code3"},
+		},
+		{
+			name:       "Nil list",
+			shortCodes: [][]string{nil, {"code1"}},
+			expected:   []string{"code1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := unique(tt.shortCodes...)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_DeleteWorker_Trace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	traceParent1 := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	traceParent2 := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	type result struct {
+		parent string
+		links  int
+	}
+
+	tests := []struct {
+		name     string
+		tasks    []repository.DeleteTask
+		expected result
+	}{
+		{
+			name:  "Single task",
+			tasks: []repository.DeleteTask{{TraceParent: traceParent1}},
+			expected: result{
+				parent: "00f067aa0ba902b7",
+				links:  1,
+			},
+		},
+		{
+			name:  "Batch",
+			tasks: []repository.DeleteTask{{TraceParent: traceParent1}, {TraceParent: traceParent2}, {}},
+			expected: result{
+				links: 2,
+			},
+		},
+		{
+			name:  "Without trace",
+			tasks: []repository.DeleteTask{{}},
+			expected: result{
+				links: 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(recorder.Ended())
+			w := &worker{}
+
+			_, span := w.trace(context.Background(), tt.tasks)
+			span.End()
+
+			spans := recorder.Ended()
+			require.Len(t, spans, before+1)
+
+			assert.Equal(t, "DeleteWorker.perform", spans[before].Name())
+			assert.Len(t, spans[before].Links(), tt.expected.links)
+			if tt.expected.parent != "" {
+				assert.Equal(t, tt.expected.parent, spans[before].Parent().SpanID().String())
+			} else {
+				assert.False(t, spans[before].Parent().IsValid())
+			}
+		})
+	}
+}
+
+// expectDeleteJobs backs the job store of the mocked repository with a map
+func expectDeleteJobs(repo *repository.MockRepository) {
+	var mu sync.Mutex
+	jobs := make(map[uuid.UUID]repository.DeleteJob)
+
+	repo.EXPECT().SaveDeleteJob(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, job repository.DeleteJob) error {
+			mu.Lock()
+			defer mu.Unlock()
+			jobs[job.UUID] = job
+			return nil
+		}).AnyTimes()
+	repo.EXPECT().GetDeleteJob(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id uuid.UUID) (*repository.DeleteJob, bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			job, ok := jobs[id]
+			return &job, ok
+		}).AnyTimes()
+}
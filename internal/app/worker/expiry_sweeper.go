@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/repository"
+	"shortly/internal/logger"
+)
+
+// Sweeper is an interface for periodic maintenance jobs run by the scheduler
+type Sweeper interface {
+	Job
+	// Schedule returns the run times of the job
+	Schedule() Schedule
+}
+
+type expirySweeper struct {
+	repo     repository.Repository
+	logger   *logger.Logger
+	interval time.Duration
+}
+
+// NewExpirySweeper creates a new expired links sweeper instance
+func NewExpirySweeper(cfg *config.Config, repo repository.Repository, logger *logger.Logger) Sweeper {
+	interval := cfg.ExpirySweepInterval
+	if interval <= 0 {
+		interval = config.ExpirySweepInterval
+	}
+
+	logger.Info().Msgf("Expiry sweeper scheduled with %s interval", interval)
+
+	return &expirySweeper{
+		repo:     repo,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Name returns the job name
+func (s *expirySweeper) Name() string {
+	return "expiry_sweeper"
+}
+
+// Schedule returns the sweep interval
+func (s *expirySweeper) Schedule() Schedule {
+	return Every(s.interval)
+}
+
+// Run marks expired links
+func (s *expirySweeper) Run(ctx context.Context) error {
+	count, err := s.repo.ExpireURLs(ctx)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		s.logger.Info().Msgf("Expired %d URLs", count)
+	}
+
+	return nil
+}
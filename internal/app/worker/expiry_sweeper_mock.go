@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/worker/expiry_sweeper.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/worker/expiry_sweeper.go -destination=internal/app/worker/expiry_sweeper_mock.go -package=worker
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSweeper is a mock of Sweeper interface.
+type MockSweeper struct {
+	ctrl     *gomock.Controller
+	recorder *MockSweeperMockRecorder
+	isgomock struct{}
+}
+
+// MockSweeperMockRecorder is the mock recorder for MockSweeper.
+type MockSweeperMockRecorder struct {
+	mock *MockSweeper
+}
+
+// NewMockSweeper creates a new mock instance.
+func NewMockSweeper(ctrl *gomock.Controller) *MockSweeper {
+	mock := &MockSweeper{ctrl: ctrl}
+	mock.recorder = &MockSweeperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSweeper) EXPECT() *MockSweeperMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockSweeper) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockSweeperMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockSweeper)(nil).Name))
+}
+
+// Run mocks base method.
+func (m *MockSweeper) Run(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockSweeperMockRecorder) Run(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockSweeper)(nil).Run), ctx)
+}
+
+// Schedule mocks base method.
+func (m *MockSweeper) Schedule() Schedule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule")
+	ret0, _ := ret[0].(Schedule)
+	return ret0
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockSweeperMockRecorder) Schedule() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockSweeper)(nil).Schedule))
+}
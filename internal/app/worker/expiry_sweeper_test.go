@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/repository"
+	"shortly/internal/logger"
+)
+
+func Test_ExpirySweeper_Schedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	repo := repository.NewMockRepository(ctrl)
+	appLogger := logger.NewLogger()
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		expected time.Time
+	}{
+		{
+			name:     "Configured interval",
+			cfg:      &config.Config{ExpirySweepInterval: time.Minute},
+			expected: now.Add(time.Minute),
+		},
+		{
+			name:     "Default interval",
+			cfg:      &config.Config{},
+			expected: now.Add(config.ExpirySweepInterval),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sweeper := NewExpirySweeper(tt.cfg, repo, appLogger)
+
+			assert.Equal(t, "expiry_sweeper", sweeper.Name())
+			assert.Equal(t, tt.expected, sweeper.Schedule().Next(now))
+		})
+	}
+}
+
+func Test_ExpirySweeper_Run(t *testing.T) {
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+	appLogger := logger.NewLogger()
+
+	tests := []struct {
+		name     string
+		before   func(repo *repository.MockRepository)
+		expected error
+	}{
+		{
+			name: "Success",
+			before: func(repo *repository.MockRepository) {
+				repo.EXPECT().ExpireURLs(gomock.Any()).Return(int64(2), nil)
+			},
+		},
+		{
+			name: "Error",
+			before: func(repo *repository.MockRepository) {
+				repo.EXPECT().ExpireURLs(gomock.Any()).Return(int64(0), assert.AnError)
+			},
+			expected: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repository.NewMockRepository(ctrl)
+			tt.before(repo)
+
+			s := NewExpirySweeper(cfg, repo, appLogger)
+			assert.Equal(t, tt.expected, s.Run(context.Background()))
+		})
+	}
+}
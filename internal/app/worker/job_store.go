@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/repository"
+)
+
+// JobRetention is how long finished delete jobs are kept in the job store
+const JobRetention = 24 * time.Hour
+
+// DeleteJob is the state of an asynchronous batch deletion
+type DeleteJob struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Status     string
+	ShortCodes []string
+	Deleted    []string
+	Skipped    []dto.SkippedShortCode
+	Error      string
+	CreatedAt  time.Time
+	FinishedAt time.Time
+}
+
+// JobStore is an interface for the delete jobs state store
+type JobStore interface {
+	Create(ctx context.Context, job DeleteJob) error
+	Get(ctx context.Context, id uuid.UUID) (DeleteJob, bool)
+	// Update applies fn to the stored job, unknown jobs are ignored
+	Update(ctx context.Context, id uuid.UUID, fn func(job *DeleteJob)) error
+}
+
+type jobStore struct {
+	repo repository.Repository
+}
+
+// NewJobStore creates a new delete jobs store, job states are kept in the repository next to the delete tasks,
+// so they survive restarts and are shared by all instances, finished jobs are removed by the purger
+func NewJobStore(repo repository.Repository) JobStore {
+	return &jobStore{repo: repo}
+}
+
+// Create stores a new job
+func (s *jobStore) Create(ctx context.Context, job DeleteJob) error {
+	return s.repo.SaveDeleteJob(ctx, toJobRecord(job))
+}
+
+// Get returns the stored job
+func (s *jobStore) Get(ctx context.Context, id uuid.UUID) (DeleteJob, bool) {
+	record, ok := s.repo.GetDeleteJob(ctx, id)
+	if !ok {
+		return DeleteJob{}, false
+	}
+
+	return fromJobRecord(*record), true
+}
+
+// Update applies fn to the stored job, a job is updated only by the worker processing its task
+func (s *jobStore) Update(ctx context.Context, id uuid.UUID, fn func(job *DeleteJob)) error {
+	job, ok := s.Get(ctx, id)
+	if !ok {
+		return nil
+	}
+
+	fn(&job)
+
+	return s.repo.SaveDeleteJob(ctx, toJobRecord(job))
+}
+
+// toJobRecord converts a job into its repository record
+func toJobRecord(job DeleteJob) repository.DeleteJob {
+	var skipped []repository.SkippedShortCode
+	for _, code := range job.Skipped {
+		skipped = append(skipped, repository.SkippedShortCode{ShortCode: code.ShortCode, Reason: code.Reason})
+	}
+
+	return repository.DeleteJob{
+		UUID:       job.ID,
+		UserUUID:   job.UserID,
+		Status:     job.Status,
+		ShortCodes: job.ShortCodes,
+		Deleted:    job.Deleted,
+		Skipped:    skipped,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
+
+// fromJobRecord converts a repository record into a job, an empty list of deleted codes is reported as missing
+// whichever backend stored it
+func fromJobRecord(record repository.DeleteJob) DeleteJob {
+	var skipped []dto.SkippedShortCode
+	for _, code := range record.Skipped {
+		skipped = append(skipped, dto.SkippedShortCode{ShortCode: code.ShortCode, Reason: code.Reason})
+	}
+
+	job := DeleteJob{
+		ID:         record.UUID,
+		UserID:     record.UserUUID,
+		Status:     record.Status,
+		ShortCodes: record.ShortCodes,
+		Deleted:    record.Deleted,
+		Skipped:    skipped,
+		Error:      record.Error,
+		CreatedAt:  record.CreatedAt,
+		FinishedAt: record.FinishedAt,
+	}
+
+	if len(job.Deleted) == 0 {
+		job.Deleted = nil
+	}
+
+	return job
+}
@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/dto"
+	"shortly/internal/app/repository"
+)
+
+func Test_JobStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewJobStore(repository.NewInMemoryRepository())
+
+	job := DeleteJob{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		Status:     dto.JobStatusQueued,
+		ShortCodes: []string{"abcd0001"},
+		CreatedAt:  time.Now(),
+	}
+	require.NoError(t, store.Create(ctx, job))
+
+	actual, ok := store.Get(ctx, job.ID)
+	assert.True(t, ok)
+	assert.Equal(t, job, actual)
+
+	require.NoError(t, store.Update(ctx, job.ID, func(job *DeleteJob) {
+		job.Status = dto.JobStatusDone
+		job.Deleted = []string{"abcd0001"}
+		job.Skipped = []dto.SkippedShortCode{{ShortCode: "abcd0002", Reason: dto.SkipReasonNotFound}}
+	}))
+
+	actual, ok = store.Get(ctx, job.ID)
+	assert.True(t, ok)
+	assert.Equal(t, dto.JobStatusDone, actual.Status)
+	assert.Equal(t, []string{"abcd0001"}, actual.Deleted)
+	assert.Equal(t, []dto.SkippedShortCode{{ShortCode: "abcd0002", Reason: dto.SkipReasonNotFound}}, actual.Skipped)
+
+	assert.NoError(t, store.Update(ctx, uuid.New(), func(job *DeleteJob) {
+		job.Status = dto.JobStatusDone
+	}))
+
+	_, ok = store.Get(ctx, uuid.New())
+	assert.False(t, ok)
+}
+
+func Test_JobStore_Purge(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	store := NewJobStore(repo)
+
+	stale := DeleteJob{ID: uuid.New(), Status: dto.JobStatusDone, FinishedAt: time.Now().Add(-JobRetention - time.Minute)}
+	running := DeleteJob{ID: uuid.New(), Status: dto.JobStatusRunning, CreatedAt: time.Now().Add(-JobRetention - time.Minute)}
+	recent := DeleteJob{ID: uuid.New(), Status: dto.JobStatusDone, FinishedAt: time.Now()}
+
+	for _, job := range []DeleteJob{stale, running, recent} {
+		require.NoError(t, store.Create(ctx, job))
+	}
+
+	count, err := repo.PurgeDeleteJobs(ctx, time.Now().Add(-JobRetention))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, ok := store.Get(ctx, stale.ID)
+	assert.False(t, ok)
+
+	_, ok = store.Get(ctx, running.ID)
+	assert.True(t, ok)
+
+	_, ok = store.Get(ctx, recent.ID)
+	assert.True(t, ok)
+}
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/repository"
+	"shortly/internal/logger"
+)
+
+type purger struct {
+	repo      repository.Repository
+	logger    *logger.Logger
+	schedule  Schedule
+	retention time.Duration
+}
+
+// NewPurger creates a new deleted links purger instance, links deleted longer than the retention ago are removed permanently,
+// it runs on the cron schedule of the config or at the purge interval
+func NewPurger(cfg *config.Config, repo repository.Repository, logger *logger.Logger) Sweeper {
+	interval := cfg.PurgeInterval
+	if interval <= 0 {
+		interval = config.PurgeInterval
+	}
+
+	retention := cfg.PurgeRetention
+	if retention <= 0 {
+		retention = config.PurgeRetention
+	}
+
+	schedule := Every(interval)
+	if cfg.PurgeSchedule != "" {
+		parsed, err := ParseSchedule(cfg.PurgeSchedule)
+		if err != nil {
+			logger.Warn().Err(err).Msgf("Invalid purge schedule %q, falling back to %s interval", cfg.PurgeSchedule, interval)
+		} else {
+			schedule = parsed
+		}
+	}
+
+	logger.Info().Msgf("Purger scheduled with %s retention", retention)
+
+	return &purger{
+		repo:      repo,
+		logger:    logger,
+		schedule:  schedule,
+		retention: retention,
+	}
+}
+
+// Name returns the job name
+func (p *purger) Name() string {
+	return "purger"
+}
+
+// Schedule returns the purge schedule
+func (p *purger) Schedule() Schedule {
+	return p.schedule
+}
+
+// Run removes links deleted before the retention cutoff and delete jobs finished longer than JobRetention ago
+func (p *purger) Run(ctx context.Context) error {
+	count, err := p.repo.PurgeURLs(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		p.logger.Info().Msgf("Purged %d deleted URLs", count)
+	}
+
+	jobs, err := p.repo.PurgeDeleteJobs(ctx, time.Now().Add(-JobRetention))
+	if err != nil {
+		return err
+	}
+
+	if jobs > 0 {
+		p.logger.Info().Msgf("Purged %d finished delete jobs", jobs)
+	}
+
+	return nil
+}
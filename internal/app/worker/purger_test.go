@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/repository"
+	"shortly/internal/logger"
+)
+
+func Test_Purger_Schedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 10, 17, 12, 30, 0, 0, time.UTC)
+	repo := repository.NewMockRepository(ctrl)
+	appLogger := logger.NewLogger()
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		expected time.Time
+	}{
+		{
+			name:     "Interval",
+			cfg:      &config.Config{PurgeInterval: time.Minute},
+			expected: now.Add(time.Minute),
+		},
+		{
+			name:     "Default interval",
+			cfg:      &config.Config{},
+			expected: now.Add(config.PurgeInterval),
+		},
+		{
+			name:     "Cron schedule",
+			cfg:      &config.Config{PurgeInterval: time.Minute, PurgeSchedule: "0 3 * * *"},
+			expected: time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Invalid schedule",
+			cfg:      &config.Config{PurgeInterval: time.Minute, PurgeSchedule: "daily"},
+			expected: now.Add(time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPurger(tt.cfg, repo, appLogger)
+
+			assert.Equal(t, "purger", p.Name())
+			assert.Equal(t, tt.expected, p.Schedule().Next(now))
+		})
+	}
+}
+
+func Test_Purger_Run(t *testing.T) {
+	cfg := &config.Config{
+		AppEnv:         "test",
+		PurgeRetention: time.Hour,
+	}
+	appLogger := logger.NewLogger()
+
+	// NOTE: records deleted before the retention cutoff are purged
+	cutoff := gomock.Cond(func(deletedBefore time.Time) bool {
+		return time.Since(deletedBefore) >= time.Hour && time.Since(deletedBefore) < time.Hour+time.Minute
+	})
+	// NOTE: delete jobs finished before the job retention cutoff are purged
+	jobCutoff := gomock.Cond(func(finishedBefore time.Time) bool {
+		return time.Since(finishedBefore) >= JobRetention && time.Since(finishedBefore) < JobRetention+time.Minute
+	})
+
+	tests := []struct {
+		name     string
+		before   func(repo *repository.MockRepository)
+		expected error
+	}{
+		{
+			name: "Success",
+			before: func(repo *repository.MockRepository) {
+				repo.EXPECT().PurgeURLs(gomock.Any(), cutoff).Return(int64(2), nil)
+				repo.EXPECT().PurgeDeleteJobs(gomock.Any(), jobCutoff).Return(int64(1), nil)
+			},
+		},
+		{
+			name: "Error",
+			before: func(repo *repository.MockRepository) {
+				repo.EXPECT().PurgeURLs(gomock.Any(), cutoff).Return(int64(0), assert.AnError)
+			},
+			expected: assert.AnError,
+		},
+		{
+			name: "Jobs error",
+			before: func(repo *repository.MockRepository) {
+				repo.EXPECT().PurgeURLs(gomock.Any(), cutoff).Return(int64(0), nil)
+				repo.EXPECT().PurgeDeleteJobs(gomock.Any(), jobCutoff).Return(int64(0), assert.AnError)
+			},
+			expected: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repository.NewMockRepository(ctrl)
+			tt.before(repo)
+
+			p := NewPurger(cfg, repo, appLogger)
+			assert.Equal(t, tt.expected, p.Run(context.Background()))
+		})
+	}
+}
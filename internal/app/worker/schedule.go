@@ -0,0 +1,190 @@
+package worker
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"shortly/internal/app/errors"
+)
+
+// Schedule computes run times of a scheduled job
+type Schedule interface {
+	// Next returns the first run time after the given time, the zero time means the job never runs again
+	Next(t time.Time) time.Time
+}
+
+// scheduleDescriptors are the predefined schedules accepted by ParseSchedule
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxScheduleLookahead limits the search of the next run time of a cron schedule that never matches, like February 30
+const maxScheduleLookahead = 5
+
+type interval time.Duration
+
+// Every returns a schedule running a job at a fixed interval
+func Every(d time.Duration) Schedule {
+	return interval(d)
+}
+
+// Next returns the time one interval after the given time
+func (i interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// cronSchedule is a parsed cron expression, every field is a bit set of the matching values
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// ParseSchedule parses a cron expression with minute, hour, day of month, month and day of week fields,
+// a descriptor like "@daily" or "@hourly", or a fixed interval like "@every 10m"
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if d, ok := strings.CutPrefix(spec, "@every "); ok {
+		duration, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil || duration <= 0 {
+			return nil, errors.ErrInvalidSchedule
+		}
+		return Every(duration), nil
+	}
+
+	if descriptor, ok := scheduleDescriptors[spec]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.ErrInvalidSchedule
+	}
+
+	var s cronSchedule
+	var err error
+
+	if s.minute, _, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if s.hour, _, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if s.dom, s.domAny, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if s.month, _, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if s.dow, s.dowAny, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+
+	// NOTE: both 0 and 7 stand for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	return &s, nil
+}
+
+// Next returns the first minute after the given time matching the expression
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxScheduleLookahead, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(s.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchesDay reports whether the day matches, like cron a day matches either restricted day field when both are set
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// parseField parses a comma separated list of values, ranges and steps like "1,5-10,*/15" into a bit set,
+// it reports whether the field is a wildcard
+func parseField(field string, min, max int) (uint64, bool, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		expr, stepExpr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepExpr)
+			if err != nil || n <= 0 {
+				return 0, false, errors.ErrInvalidSchedule
+			}
+			step = n
+		}
+
+		from, to := min, max
+		switch {
+		case expr == "*":
+		case strings.Contains(expr, "-"):
+			lo, hi, _ := strings.Cut(expr, "-")
+
+			var err error
+			if from, err = strconv.Atoi(lo); err != nil {
+				return 0, false, errors.ErrInvalidSchedule
+			}
+			if to, err = strconv.Atoi(hi); err != nil {
+				return 0, false, errors.ErrInvalidSchedule
+			}
+		default:
+			n, err := strconv.Atoi(expr)
+			if err != nil {
+				return 0, false, errors.ErrInvalidSchedule
+			}
+
+			from = n
+			if !hasStep {
+				to = n
+			}
+		}
+
+		if from < min || to > max || from > to {
+			return 0, false, errors.ErrInvalidSchedule
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, strings.HasPrefix(field, "*"), nil
+}
+
+// has reports whether the value is in the bit set
+func has(bits uint64, v int) bool {
+	return bits&(1<<v) != 0
+}
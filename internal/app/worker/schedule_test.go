@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/errors"
+)
+
+func Test_ParseSchedule(t *testing.T) {
+	// NOTE: Saturday
+	now := time.Date(2026, 10, 17, 12, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		spec     string
+		expected time.Time
+	}{
+		{
+			name:     "Every minute",
+			spec:     "* * * * *",
+			expected: time.Date(2026, 10, 17, 12, 8, 0, 0, time.UTC),
+		},
+		{
+			name:     "Step",
+			spec:     "*/15 * * * *",
+			expected: time.Date(2026, 10, 17, 12, 15, 0, 0, time.UTC),
+		},
+		{
+			name:     "Daily at time",
+			spec:     "0 3 * * *",
+			expected: time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "List",
+			spec:     "5,10,50 12 * * *",
+			expected: time.Date(2026, 10, 17, 12, 10, 0, 0, time.UTC),
+		},
+		{
+			name:     "Weekdays",
+			spec:     "0 9 * * 1-5",
+			expected: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Sunday as 7",
+			spec:     "0 0 * * 7",
+			expected: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Day of month or day of week",
+			spec:     "0 0 13 * 5",
+			expected: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Next year",
+			spec:     "0 0 1 1 *",
+			expected: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Hourly",
+			spec:     "@hourly",
+			expected: time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Monthly",
+			spec:     "@monthly",
+			expected: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Interval",
+			spec:     "@every 10m",
+			expected: time.Date(2026, 10, 17, 12, 17, 30, 0, time.UTC),
+		},
+		{
+			name:     "Never",
+			spec:     "0 0 30 2 *",
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, schedule.Next(now))
+		})
+	}
+}
+
+func Test_ParseSchedule_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "Empty", spec: ""},
+		{name: "Missing field", spec: "* * * *"},
+		{name: "Out of range", spec: "60 * * * *"},
+		{name: "Zero step", spec: "*/0 * * * *"},
+		{name: "Reversed range", spec: "5-1 * * * *"},
+		{name: "Not a number", spec: "a * * * *"},
+		{name: "Unknown descriptor", spec: "@often"},
+		{name: "Negative interval", spec: "@every -1m"},
+		{name: "Invalid interval", spec: "@every often"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+
+			assert.Nil(t, schedule)
+			assert.ErrorIs(t, err, errors.ErrInvalidSchedule)
+		})
+	}
+}
+
+func Test_Every(t *testing.T) {
+	now := time.Now()
+
+	assert.Equal(t, now.Add(time.Minute), Every(time.Minute).Next(now))
+}
@@ -0,0 +1,274 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/metrics"
+	"shortly/internal/app/tracing"
+	"shortly/internal/logger"
+)
+
+// QueuePollInterval is the default interval between polls of an idle queue
+const QueuePollInterval = time.Second
+
+// Job is a unit of background work run on a schedule or by the workers of a queue
+type Job interface {
+	// Name identifies the job in logs and metrics
+	Name() string
+	// Run performs the work, a queue job returns errors.ErrQueueEmpty when there is nothing to process
+	Run(ctx context.Context) error
+}
+
+// Hook is a lifecycle hook of the scheduler
+type Hook func(ctx context.Context) error
+
+// QueueOptions configures a named queue
+type QueueOptions struct {
+	// Workers is the number of workers running the queue job concurrently
+	Workers int
+	// PollInterval is the interval between runs of an idle worker, Notify wakes it up earlier
+	PollInterval time.Duration
+	// DrainTimeout is the time the queue is drained for on shutdown
+	DrainTimeout time.Duration
+}
+
+// Scheduler is an interface for the background job subsystem, jobs and hooks are registered before Start
+type Scheduler interface {
+	// Schedule runs the job on the schedule until the scheduler context is done
+	Schedule(schedule Schedule, job Job)
+	// Queue runs the job by the workers of the named queue for as long as it finds work
+	Queue(name string, opts QueueOptions, job Job)
+	// Notify wakes up an idle worker of the named queue
+	Notify(name string)
+	// OnStart registers a hook called before the jobs are started
+	OnStart(hook Hook)
+	// OnStop registers a hook called after the jobs are stopped
+	OnStop(hook Hook)
+	Start() error
+	// Stop waits for the jobs to finish and the queues to drain after the scheduler context is done
+	Stop() error
+}
+
+type scheduled struct {
+	schedule Schedule
+	job      Job
+}
+
+type queue struct {
+	name string
+	opts QueueOptions
+	job  Job
+	wake chan struct{}
+}
+
+type scheduler struct {
+	ctx     context.Context
+	cfg     *config.Config
+	logger  *logger.Logger
+	jobs    []scheduled
+	queues  map[string]*queue
+	onStart []Hook
+	onStop  []Hook
+	wg      sync.WaitGroup
+}
+
+// NewScheduler creates a new background job scheduler, jobs are stopped when the context is done
+func NewScheduler(ctx context.Context, cfg *config.Config, logger *logger.Logger) Scheduler {
+	return &scheduler{
+		ctx:    ctx,
+		cfg:    cfg,
+		logger: logger,
+		queues: make(map[string]*queue),
+	}
+}
+
+// NewJob creates a named job running the function
+func NewJob(name string, run func(ctx context.Context) error) Job {
+	return &funcJob{name: name, run: run}
+}
+
+type funcJob struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Name returns the job name
+func (j *funcJob) Name() string {
+	return j.name
+}
+
+// Run calls the job function
+func (j *funcJob) Run(ctx context.Context) error {
+	return j.run(ctx)
+}
+
+// Schedule registers a job run on the schedule
+func (s *scheduler) Schedule(schedule Schedule, job Job) {
+	s.jobs = append(s.jobs, scheduled{schedule: schedule, job: job})
+}
+
+// Queue registers a named queue, a queue registered twice replaces the previous one
+func (s *scheduler) Queue(name string, opts QueueOptions, job Job) {
+	opts.Workers = max(opts.Workers, 1)
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = QueuePollInterval
+	}
+
+	s.queues[name] = &queue{
+		name: name,
+		opts: opts,
+		job:  job,
+		wake: make(chan struct{}, opts.Workers),
+	}
+}
+
+// Notify wakes up an idle worker of the named queue, it never blocks
+func (s *scheduler) Notify(name string) {
+	q, ok := s.queues[name]
+	if !ok {
+		return
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// OnStart registers a hook called before the jobs are started
+func (s *scheduler) OnStart(hook Hook) {
+	s.onStart = append(s.onStart, hook)
+}
+
+// OnStop registers a hook called after the jobs are stopped
+func (s *scheduler) OnStop(hook Hook) {
+	s.onStop = append(s.onStop, hook)
+}
+
+// Start calls the start hooks and starts the scheduled jobs and the queue workers
+func (s *scheduler) Start() error {
+	for _, hook := range s.onStart {
+		if err := hook(s.ctx); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info().Msgf("Scheduler starting %d scheduled jobs and %d queues in %s environment", len(s.jobs), len(s.queues), s.cfg.AppEnv)
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.tick(j)
+	}
+
+	for _, q := range s.queues {
+		s.wg.Add(q.opts.Workers)
+		for i := 0; i < q.opts.Workers; i++ {
+			go s.consume(q)
+		}
+	}
+
+	return nil
+}
+
+// Stop waits for the jobs to finish and calls the stop hooks, it returns the errors of the hooks
+func (s *scheduler) Stop() error {
+	s.wg.Wait()
+
+	// NOTE: the scheduler context is done by now, so hooks get a context that is not cancelled
+	ctx := context.WithoutCancel(s.ctx)
+
+	var errs []error
+	for _, hook := range s.onStop {
+		if err := hook(ctx); err != nil {
+			s.logger.Error().Err(err).Msg("Error in scheduler stop hook")
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// tick runs the scheduled job at the times of its schedule
+func (s *scheduler) tick(j scheduled) {
+	defer s.wg.Done()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			s.logger.Warn().Msgf("Job %s has no next run time, stopped", j.job.Name())
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			ctx, span := tracing.Start(s.ctx, "job "+j.job.Name(), trace.WithNewRoot())
+			s.run(ctx, j.job)
+			span.End()
+		}
+	}
+}
+
+// consume runs the queue job until the queue is empty, then waits for a notification or the next poll
+func (s *scheduler) consume(q *queue) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(q.opts.PollInterval)
+	defer ticker.Stop()
+
+	// NOTE: queued work outlives requests, so queue jobs are not cancelled together with the scheduler
+	ctx := context.WithoutCancel(s.ctx)
+
+	for {
+		// NOTE: a busy queue never waits below, so the stop is checked before every run and the rest goes to the drain
+		if s.ctx.Err() == nil && s.run(ctx, q.job) {
+			continue
+		}
+
+		select {
+		case <-s.ctx.Done():
+			s.drain(q)
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain runs the queue job at shutdown until the queue is empty or the drain timeout passes
+func (s *scheduler) drain(q *queue) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(s.ctx), q.opts.DrainTimeout)
+	defer cancel()
+
+	for ctx.Err() == nil && s.run(ctx, q.job) {
+	}
+}
+
+// run runs the job once, it reports whether the job did some work
+func (s *scheduler) run(ctx context.Context, job Job) bool {
+	start := time.Now()
+
+	err := job.Run(ctx)
+	switch {
+	case errors.Is(err, errors.ErrQueueEmpty):
+		return false
+	case err != nil:
+		tracing.RecordError(trace.SpanFromContext(ctx), err)
+		metrics.ObserveJob(job.Name(), "error", start)
+		s.logger.Error().Err(err).Msgf("Error running job %s", job.Name())
+		return false
+	default:
+		metrics.ObserveJob(job.Name(), "success", start)
+		return true
+	}
+}
@@ -0,0 +1,181 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/worker/scheduler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/worker/scheduler.go -destination=internal/app/worker/scheduler_mock.go -package=worker
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJob is a mock of Job interface.
+type MockJob struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobMockRecorder
+	isgomock struct{}
+}
+
+// MockJobMockRecorder is the mock recorder for MockJob.
+type MockJobMockRecorder struct {
+	mock *MockJob
+}
+
+// NewMockJob creates a new mock instance.
+func NewMockJob(ctrl *gomock.Controller) *MockJob {
+	mock := &MockJob{ctrl: ctrl}
+	mock.recorder = &MockJobMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJob) EXPECT() *MockJobMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockJob) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockJobMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockJob)(nil).Name))
+}
+
+// Run mocks base method.
+func (m *MockJob) Run(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockJobMockRecorder) Run(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockJob)(nil).Run), ctx)
+}
+
+// MockScheduler is a mock of Scheduler interface.
+type MockScheduler struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerMockRecorder
+	isgomock struct{}
+}
+
+// MockSchedulerMockRecorder is the mock recorder for MockScheduler.
+type MockSchedulerMockRecorder struct {
+	mock *MockScheduler
+}
+
+// NewMockScheduler creates a new mock instance.
+func NewMockScheduler(ctrl *gomock.Controller) *MockScheduler {
+	mock := &MockScheduler{ctrl: ctrl}
+	mock.recorder = &MockSchedulerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduler) EXPECT() *MockSchedulerMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockScheduler) Notify(name string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Notify", name)
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockSchedulerMockRecorder) Notify(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockScheduler)(nil).Notify), name)
+}
+
+// OnStart mocks base method.
+func (m *MockScheduler) OnStart(hook Hook) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnStart", hook)
+}
+
+// OnStart indicates an expected call of OnStart.
+func (mr *MockSchedulerMockRecorder) OnStart(hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnStart", reflect.TypeOf((*MockScheduler)(nil).OnStart), hook)
+}
+
+// OnStop mocks base method.
+func (m *MockScheduler) OnStop(hook Hook) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnStop", hook)
+}
+
+// OnStop indicates an expected call of OnStop.
+func (mr *MockSchedulerMockRecorder) OnStop(hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnStop", reflect.TypeOf((*MockScheduler)(nil).OnStop), hook)
+}
+
+// Queue mocks base method.
+func (m *MockScheduler) Queue(name string, opts QueueOptions, job Job) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Queue", name, opts, job)
+}
+
+// Queue indicates an expected call of Queue.
+func (mr *MockSchedulerMockRecorder) Queue(name, opts, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queue", reflect.TypeOf((*MockScheduler)(nil).Queue), name, opts, job)
+}
+
+// Schedule mocks base method.
+func (m *MockScheduler) Schedule(schedule Schedule, job Job) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Schedule", schedule, job)
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockSchedulerMockRecorder) Schedule(schedule, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockScheduler)(nil).Schedule), schedule, job)
+}
+
+// Start mocks base method.
+func (m *MockScheduler) Start() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockSchedulerMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockScheduler)(nil).Start))
+}
+
+// Stop mocks base method.
+func (m *MockScheduler) Stop() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockSchedulerMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockScheduler)(nil).Stop))
+}
@@ -0,0 +1,256 @@
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/config"
+	"shortly/internal/app/errors"
+	"shortly/internal/app/metrics"
+	"shortly/internal/logger"
+)
+
+// countingQueue is a queue job processing a number of pending items one by one
+func countingQueue(name string, pending *atomic.Int64) Job {
+	return NewJob(name, func(context.Context) error {
+		if pending.Add(-1) < 0 {
+			pending.Add(1)
+			return errors.ErrQueueEmpty
+		}
+		return nil
+	})
+}
+
+func Test_Scheduler_Schedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+	s := NewScheduler(ctx, cfg, logger.NewLogger())
+
+	var runs atomic.Int64
+	s.Schedule(Every(10*time.Millisecond), NewJob("test_schedule", func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}))
+
+	before := testutil.ToFloat64(metrics.JobRuns.WithLabelValues("test_schedule", "success"))
+	require.NoError(t, s.Start())
+
+	assert.Eventually(t, func() bool {
+		return runs.Load() >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, s.Stop())
+
+	assert.Equal(t, before+float64(runs.Load()), testutil.ToFloat64(metrics.JobRuns.WithLabelValues("test_schedule", "success")))
+}
+
+func Test_Scheduler_Queue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+	s := NewScheduler(ctx, cfg, logger.NewLogger())
+
+	var pending atomic.Int64
+	s.Queue("test_queue", QueueOptions{Workers: 2, PollInterval: time.Hour}, countingQueue("test_queue", &pending))
+
+	require.NoError(t, s.Start())
+
+	// NOTE: idle workers only run again when notified, the poll interval is too long to matter
+	pending.Store(5)
+	s.Notify("test_queue")
+	s.Notify("unknown")
+
+	assert.Eventually(t, func() bool {
+		return pending.Load() == 0
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, s.Stop())
+}
+
+func Test_Scheduler_Drain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+	s := NewScheduler(ctx, cfg, logger.NewLogger())
+
+	var pending atomic.Int64
+	pending.Store(3)
+	s.Queue("test_drain", QueueOptions{DrainTimeout: time.Second}, NewJob("test_drain", func(ctx context.Context) error {
+		// NOTE: the scheduler is stopped before it starts, so only the drain processes the queue
+		if ctx.Err() != nil || pending.Load() == 0 {
+			return errors.ErrQueueEmpty
+		}
+		pending.Add(-1)
+		return nil
+	}))
+
+	cancel()
+	require.NoError(t, s.Start())
+	require.NoError(t, s.Stop())
+
+	assert.Equal(t, int64(0), pending.Load())
+}
+
+func Test_Scheduler_Drain_BusyQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+	s := NewScheduler(ctx, cfg, logger.NewLogger())
+
+	// NOTE: the queue never runs empty, so only the drain timeout ends its processing
+	var runs atomic.Int64
+	s.Queue("test_busy", QueueOptions{DrainTimeout: 50 * time.Millisecond}, NewJob("test_busy", func(context.Context) error {
+		runs.Add(1)
+		time.Sleep(time.Millisecond)
+		return nil
+	}))
+
+	require.NoError(t, s.Start())
+	assert.Eventually(t, func() bool {
+		return runs.Load() > 0
+	}, time.Second, 5*time.Millisecond)
+
+	stopped := make(chan error, 1)
+	go func() {
+		cancel()
+		stopped <- s.Stop()
+	}()
+
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler did not stop a busy queue after the drain timeout")
+	}
+}
+
+func Test_Scheduler_Hooks(t *testing.T) {
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+
+	tests := []struct {
+		name      string
+		onStart   Hook
+		onStop    Hook
+		startErr  error
+		stopErr   error
+		scheduled bool
+	}{
+		{
+			name:      "Success",
+			onStart:   func(context.Context) error { return nil },
+			onStop:    func(context.Context) error { return nil },
+			scheduled: true,
+		},
+		{
+			name:     "Start hook error",
+			onStart:  func(context.Context) error { return assert.AnError },
+			onStop:   func(context.Context) error { return nil },
+			startErr: assert.AnError,
+		},
+		{
+			name:      "Stop hook error",
+			onStart:   func(context.Context) error { return nil },
+			onStop:    func(context.Context) error { return assert.AnError },
+			stopErr:   assert.AnError,
+			scheduled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			s := NewScheduler(ctx, cfg, logger.NewLogger())
+
+			var started, stopped, ran atomic.Bool
+			s.OnStart(func(ctx context.Context) error {
+				started.Store(true)
+				return tt.onStart(ctx)
+			})
+			s.OnStop(func(ctx context.Context) error {
+				// NOTE: stop hooks run with a live context after the jobs are stopped
+				assert.NoError(t, ctx.Err())
+				stopped.Store(true)
+				return tt.onStop(ctx)
+			})
+			s.Schedule(Every(time.Millisecond), NewJob("test_hooks", func(context.Context) error {
+				ran.Store(true)
+				return nil
+			}))
+
+			assert.ErrorIs(t, s.Start(), tt.startErr)
+
+			if tt.scheduled {
+				assert.Eventually(t, ran.Load, time.Second, time.Millisecond)
+			}
+
+			cancel()
+			err := s.Stop()
+			if tt.stopErr != nil {
+				assert.ErrorIs(t, err, tt.stopErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.True(t, started.Load())
+			assert.True(t, stopped.Load())
+			assert.Equal(t, tt.scheduled, ran.Load())
+		})
+	}
+}
+
+func Test_Scheduler_Run(t *testing.T) {
+	cfg := &config.Config{
+		AppEnv: "test",
+	}
+	s := NewScheduler(context.Background(), cfg, logger.NewLogger()).(*scheduler)
+
+	tests := []struct {
+		name     string
+		err      error
+		result   string
+		expected bool
+	}{
+		{name: "Success", err: nil, result: "success", expected: true},
+		{name: "Error", err: assert.AnError, result: "error", expected: false},
+		{name: "Queue empty", err: errors.ErrQueueEmpty, result: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := NewJob("test_run", func(context.Context) error { return tt.err })
+
+			before := testutil.CollectAndCount(metrics.JobRuns)
+			if tt.result != "" {
+				before = int(testutil.ToFloat64(metrics.JobRuns.WithLabelValues("test_run", tt.result)))
+			}
+
+			assert.Equal(t, tt.expected, s.run(context.Background(), job))
+
+			if tt.result != "" {
+				assert.Equal(t, float64(before+1), testutil.ToFloat64(metrics.JobRuns.WithLabelValues("test_run", tt.result)))
+			} else {
+				assert.Equal(t, before, testutil.CollectAndCount(metrics.JobRuns))
+			}
+		})
+	}
+}
@@ -1,13 +1,20 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"os"
+	"sync/atomic"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/pkgerrors"
+	"go.opentelemetry.io/otel/trace"
+
+	"shortly/internal/app/errors"
 )
 
 // LogLevel is the log level for the logger. Default is Info level
@@ -15,23 +22,110 @@ const (
 	LogLevel = 1
 )
 
+// FormatJSON is the format of one JSON object per line
+const FormatJSON = "json"
+
+// FormatConsole is the colored human readable format
+const FormatConsole = "console"
+
+// OutputStdout is the output of the standard output
+const OutputStdout = "stdout"
+
+// OutputStderr is the output of the standard error
+const OutputStderr = "stderr"
+
+// ConsoleTimeFormat is the timestamp format of the console output
+const ConsoleTimeFormat = "2006-01-02 15:04:05"
+
+// RequestIDHeader is the header of the request ID, an incoming ID is kept and a missing one is generated
+const RequestIDHeader = "X-Request-ID"
+
+// MaxRequestIDLength limits the length of an incoming request ID
+const MaxRequestIDLength = 128
+
+// Options configures the logger
+type Options struct {
+	// Format is either json or console
+	Format string
+	// Level is a level name like debug, info, warn or error
+	Level string
+	// Output is stdout, stderr or the path of a file the logs are appended to
+	Output string
+}
+
 // Logger is a logger structure for the application
 type Logger struct {
 	log zerolog.Logger
 }
 
-// NewLogger creates a new logger instance
+type loggerKey struct{}
+
+type requestKey struct{}
+
+// request is the state of a request shared with the access log, the user is known only after authentication
+type request struct {
+	id     string
+	userID atomic.Value
+}
+
+var defaultLogger atomic.Pointer[Logger]
+
+// NewLogger creates a new logger instance writing to stdout in the console format
 func NewLogger() *Logger {
+	return newLogger(os.Stdout, FormatConsole, zerolog.Level(LogLevel))
+}
+
+// New creates a new logger instance with the options, empty options fall back to the console format,
+// the info level and stdout
+func New(opts Options) (*Logger, error) {
+	level := zerolog.Level(LogLevel)
+	if opts.Level != "" {
+		var err error
+		if level, err = zerolog.ParseLevel(opts.Level); err != nil || level == zerolog.NoLevel {
+			return nil, errors.ErrInvalidLogLevel
+		}
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = FormatConsole
+	}
+	if format != FormatJSON && format != FormatConsole {
+		return nil, errors.ErrInvalidLogFormat
+	}
+
+	var output io.Writer
+	switch opts.Output {
+	case "", OutputStdout:
+		output = os.Stdout
+	case OutputStderr:
+		output = os.Stderr
+	default:
+		// NOTE: the file is kept open for the lifetime of the process
+		file, err := os.OpenFile(opts.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		output = file
+	}
+
+	return newLogger(output, format, level), nil
+}
+
+// newLogger creates a logger writing to the output in the format
+func newLogger(output io.Writer, format string, level zerolog.Level) *Logger {
 	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
-	zerolog.TimeFieldFormat = "2006-01-02 15:04:05"
+	zerolog.TimeFieldFormat = time.RFC3339
 
-	var output io.Writer = zerolog.ConsoleWriter{
-		Out:        os.Stdout,
-		TimeFormat: zerolog.TimeFieldFormat,
+	if format == FormatConsole {
+		output = zerolog.ConsoleWriter{
+			Out:        output,
+			TimeFormat: ConsoleTimeFormat,
+		}
 	}
 
 	log := zerolog.New(output).
-		Level(zerolog.Level(LogLevel)).
+		Level(level).
 		With().
 		Timestamp().
 		Logger()
@@ -39,6 +133,93 @@ func NewLogger() *Logger {
 	return &Logger{log: log}
 }
 
+// SetDefault sets the logger returned by FromContext outside of requests
+func SetDefault(l *Logger) {
+	defaultLogger.Store(l)
+}
+
+// WithContext returns a copy of the context carrying the logger
+func WithContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the request-scoped logger of the context, or the default logger outside of requests
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+		return l
+	}
+
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+
+	l := NewLogger()
+	defaultLogger.CompareAndSwap(nil, l)
+
+	return defaultLogger.Load()
+}
+
+// RequestID returns the ID of the request, empty outside of requests
+func RequestID(ctx context.Context) string {
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		return req.id
+	}
+
+	return ""
+}
+
+// RequestUser returns the user attached to the request by WithUser, empty before authentication or outside of requests
+func RequestUser(ctx context.Context) string {
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		userID, _ := req.userID.Load().(string)
+		return userID
+	}
+
+	return ""
+}
+
+// WithUser returns a copy of the context with the user attached to its logger and reported in the access log
+func WithUser(ctx context.Context, userID string) context.Context {
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		req.userID.Store(userID)
+	}
+
+	return WithContext(ctx, FromContext(ctx).With("user_uuid", userID))
+}
+
+// With returns a child logger with the field added to every event
+func (l *Logger) With(key, value string) *Logger {
+	return &Logger{log: l.log.With().Str(key, value).Logger()}
+}
+
+// WithRequest returns a copy of the context starting a request with the ID and the child logger of the request,
+// the logger is attached to the context and the user attached by WithUser is reported by RequestUser
+func (l *Logger) WithRequest(ctx context.Context, id string) (context.Context, *Logger) {
+	reqLogger := l.With("request_id", id).WithTrace(ctx)
+	ctx = context.WithValue(ctx, requestKey{}, &request{id: id})
+
+	return WithContext(ctx, reqLogger), reqLogger
+}
+
+// WithTrace returns a child logger with the trace and span IDs of the span in the context,
+// the logger itself is returned without a span
+func (l *Logger) WithTrace(ctx context.Context) *Logger {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return l
+	}
+
+	return &Logger{log: l.log.With().
+		Str("trace_id", sc.TraceID().String()).
+		Str("span_id", sc.SpanID().String()).
+		Logger()}
+}
+
+// Debug returns a Debug level event
+func (l *Logger) Debug() *zerolog.Event {
+	return l.log.Debug()
+}
+
 // Info returns an Info level event
 func (l *Logger) Info() *zerolog.Event {
 	return l.log.Info()
@@ -73,18 +254,35 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
-// Middleware is a middleware for logging requests
+// Unwrap returns the original writer, so http.ResponseController reaches flushing and deadlines
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Middleware is a middleware for logging requests, it attaches a logger with the request ID to the request context
 func (l *Logger) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+
+		id := ParseRequestID(r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx, reqLogger := l.WithRequest(r.Context(), id)
+
 		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
-		next.ServeHTTP(rw, r)
+		next.ServeHTTP(rw, r.WithContext(ctx))
 
-		l.Info().
+		var route string
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			route = rctx.RoutePattern()
+		}
+		reqLogger.Info().
 			Str("method", r.Method).
 			Str("content_type", r.Header.Get("Content-Type")).
 			Str("path", r.URL.Path).
+			Str("route", route).
+			Str("user_uuid", RequestUser(ctx)).
 			Dur("duration", time.Since(start)).
 			Int64("request_size", r.ContentLength).
 			Int("status", rw.statusCode).
@@ -94,3 +292,18 @@ func (l *Logger) Middleware(next http.Handler) http.Handler {
 			Msg("request")
 	})
 }
+
+// ParseRequestID returns the incoming request ID if it is safe to log, otherwise a new one
+func ParseRequestID(id string) string {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return uuid.NewString()
+	}
+
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == ':') {
+			return uuid.NewString()
+		}
+	}
+
+	return id
+}
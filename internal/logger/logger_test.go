@@ -2,12 +2,22 @@ package logger
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortly/internal/app/errors"
 )
 
 func Test_NewLogger(t *testing.T) {
@@ -173,3 +183,249 @@ func Test_Logger_Error(t *testing.T) {
 		})
 	}
 }
+
+func Test_New(t *testing.T) {
+	type result struct {
+		level zerolog.Level
+		error error
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected result
+	}{
+		{
+			name: "Defaults",
+			opts: Options{},
+			expected: result{
+				level: zerolog.Level(LogLevel),
+			},
+		},
+		{
+			name: "JSON debug",
+			opts: Options{Format: FormatJSON, Level: "debug", Output: OutputStderr},
+			expected: result{
+				level: zerolog.DebugLevel,
+			},
+		},
+		{
+			name: "Invalid level",
+			opts: Options{Level: "verbose"},
+			expected: result{
+				error: errors.ErrInvalidLogLevel,
+			},
+		},
+		{
+			name: "Invalid format",
+			opts: Options{Format: "xml"},
+			expected: result{
+				error: errors.ErrInvalidLogFormat,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, err := New(tt.opts)
+
+			if tt.expected.error != nil {
+				assert.ErrorIs(t, err, tt.expected.error)
+				assert.Nil(t, logger)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.level, logger.log.GetLevel())
+		})
+	}
+}
+
+func Test_New_FileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	logger, err := New(Options{Format: FormatJSON, Output: path})
+	require.NoError(t, err)
+
+	logger.Info().Str("key", "value").Msg("message")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "value", entry["key"])
+	assert.Equal(t, "message", entry["message"])
+}
+
+func Test_Logger_Debug(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    string
+		expected bool
+	}{
+		{
+			name:     "Debug level",
+			level:    "debug",
+			expected: true,
+		},
+		{
+			name:     "Info level",
+			level:    "info",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New(Options{Format: FormatJSON, Level: tt.level})
+			require.NoError(t, err)
+			logger.log = logger.log.Output(&buf)
+
+			logger.Debug().Msg("debug")
+
+			assert.Equal(t, tt.expected, strings.Contains(buf.String(), "debug"))
+		})
+	}
+}
+
+func Test_LoggerMiddleware_RequestID(t *testing.T) {
+	userID := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		requestID string
+		generated bool
+	}{
+		{
+			name:      "Incoming request ID",
+			requestID: "req-1234",
+			generated: false,
+		},
+		{
+			name:      "Missing request ID",
+			requestID: "",
+			generated: true,
+		},
+		{
+			name:      "Unsafe request ID",
+			requestID: "req\nforged",
+			generated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New(Options{Format: FormatJSON})
+			require.NoError(t, err)
+			logger.log = logger.log.Output(&buf)
+
+			var handlerRequestID string
+			var handlerLog bytes.Buffer
+
+			r := chi.NewRouter()
+			r.Use(logger.Middleware)
+			r.Get("/api/shorten/{id}", func(w http.ResponseWriter, r *http.Request) {
+				handlerRequestID = RequestID(r.Context())
+				ctx := WithUser(r.Context(), userID)
+
+				l := FromContext(ctx)
+				l.log = l.log.Output(&handlerLog)
+				l.Info().Msg("handler")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/shorten/abcd1234", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			requestID := w.Header().Get(RequestIDHeader)
+			if tt.generated {
+				assert.NotEqual(t, tt.requestID, requestID)
+				assert.NoError(t, uuid.Validate(requestID))
+			} else {
+				assert.Equal(t, tt.requestID, requestID)
+			}
+			assert.Equal(t, requestID, handlerRequestID)
+
+			var access map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &access))
+			assert.Equal(t, requestID, access["request_id"])
+			assert.Equal(t, "/api/shorten/{id}", access["route"])
+			assert.Equal(t, userID, access["user_uuid"])
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(handlerLog.Bytes(), &entry))
+			assert.Equal(t, requestID, entry["request_id"])
+			assert.Equal(t, userID, entry["user_uuid"])
+		})
+	}
+}
+
+func Test_FromContext(t *testing.T) {
+	logger := NewLogger()
+	defaultLogger := NewLogger()
+	SetDefault(defaultLogger)
+	t.Cleanup(func() {
+		SetDefault(nil)
+	})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected *Logger
+	}{
+		{
+			name:     "Request logger",
+			ctx:      WithContext(context.Background(), logger),
+			expected: logger,
+		},
+		{
+			name:     "Default logger",
+			ctx:      context.Background(),
+			expected: defaultLogger,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Same(t, tt.expected, FromContext(tt.ctx))
+		})
+	}
+}
+
+func Test_Logger_WithRequest(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(Options{Format: FormatJSON})
+	require.NoError(t, err)
+	logger.log = logger.log.Output(&buf)
+
+	userID := uuid.New().String()
+
+	ctx, reqLogger := logger.WithRequest(context.Background(), "req-1234")
+	assert.Equal(t, "req-1234", RequestID(ctx))
+	assert.Same(t, reqLogger, FromContext(ctx))
+	assert.Empty(t, RequestUser(ctx))
+
+	// NOTE: the user attached deeper in the call chain is visible through the outer context
+	WithUser(ctx, userID)
+	assert.Equal(t, userID, RequestUser(ctx))
+
+	reqLogger.Info().Msg("request")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-1234", entry["request_id"])
+}
+
+func Test_ParseRequestID(t *testing.T) {
+	assert.Equal(t, "req-1234", ParseRequestID("req-1234"))
+	assert.NoError(t, uuid.Validate(ParseRequestID("")))
+	assert.NoError(t, uuid.Validate(ParseRequestID("req\nforged")))
+	assert.NoError(t, uuid.Validate(ParseRequestID(strings.Repeat("a", MaxRequestIDLength+1))))
+}
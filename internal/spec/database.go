@@ -8,7 +8,7 @@ import (
 
 // TruncateTables truncates URLs table in the database
 func TruncateTables(ctx context.Context, dsn string) error {
-	err := RunQuery(ctx, dsn, "TRUNCATE TABLE urls RESTART IDENTITY CASCADE")
+	err := RunQuery(ctx, dsn, "TRUNCATE TABLE urls, api_tokens, delete_tasks, delete_jobs RESTART IDENTITY CASCADE")
 	if err != nil {
 		return err
 	}